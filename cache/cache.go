@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+)
+
+// Namespace cache, invalidasi dilakukan per namespace
+const (
+	NamespaceProduct = "product"
+	NamespaceBanner  = "banner"
+	NamespacePromo   = "promo"
+	NamespaceWilayah = "wilayah"
+	NamespaceSetting = "setting"
+)
+
+// Cache penyimpanan key value dengan ttl. Error backend tidak dikembalikan,
+// cache yang gagal dianggap miss sehingga request tetap jalan ke database.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+	// Generation versi namespace yang menjadi bagian dari key
+	Generation(namespace string) int64
+	// Invalidate menaikkan versi namespace sehingga semua key lama tidak terbaca lagi
+	Invalidate(namespace string)
+}
+
+func NewCache(configCache config.Cache) Cache {
+	if configCache.Driver == "redis" {
+		return NewRedisCache(configCache.RedisAddress, configCache.RedisPassword, configCache.RedisDb)
+	}
+	return NewMemoryCache(configCache.Capacity)
+}
+
+// Key membentuk key dengan versi namespace saat ini
+func Key(c Cache, namespace string, parts ...string) string {
+	return namespace + ":" + strconv.FormatInt(c.Generation(namespace), 10) + ":" + strings.Join(parts, ":")
+}
+
+// Ttl per namespace dari konfigurasi (detik), dengan nilai default
+func Ttl(configCache config.Cache, namespace string) time.Duration {
+	var ttl, defaultTtl uint
+	switch namespace {
+	case NamespaceProduct:
+		ttl, defaultTtl = configCache.ProductTtl, 60
+	case NamespaceBanner:
+		ttl, defaultTtl = configCache.BannerTtl, 600
+	case NamespacePromo:
+		ttl, defaultTtl = configCache.PromoTtl, 300
+	case NamespaceWilayah:
+		ttl, defaultTtl = configCache.WilayahTtl, 86400
+	case NamespaceSetting:
+		ttl, defaultTtl = configCache.SettingTtl, 600
+	}
+	if ttl == 0 {
+		ttl = defaultTtl
+	}
+	return time.Duration(ttl) * time.Second
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiredAt time.Time
+}
+
+// MemoryCache cache LRU di memori proses
+type MemoryCache struct {
+	mutex       sync.Mutex
+	capacity    int
+	items       map[string]*list.Element
+	order       *list.List
+	generations map[string]int64
+}
+
+func NewMemoryCache(capacity int) Cache {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &MemoryCache{
+		capacity:    capacity,
+		items:       make(map[string]*list.Element),
+		order:       list.New(),
+		generations: make(map[string]int64),
+	}
+}
+
+func (cache *MemoryCache) Get(key string) ([]byte, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*memoryEntry)
+	if time.Now().After(entry.expiredAt) {
+		cache.removeElement(element)
+		return nil, false
+	}
+	cache.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (cache *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.items[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		entry.value = value
+		entry.expiredAt = time.Now().Add(ttl)
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.items[key] = cache.order.PushFront(&memoryEntry{key: key, value: value, expiredAt: time.Now().Add(ttl)})
+	for cache.order.Len() > cache.capacity {
+		cache.removeElement(cache.order.Back())
+	}
+}
+
+func (cache *MemoryCache) Delete(key string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.items[key]; ok {
+		cache.removeElement(element)
+	}
+}
+
+func (cache *MemoryCache) Generation(namespace string) int64 {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.generations[namespace]
+}
+
+func (cache *MemoryCache) Invalidate(namespace string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.generations[namespace]++
+}
+
+func (cache *MemoryCache) removeElement(element *list.Element) {
+	cache.order.Remove(element)
+	delete(cache.items, element.Value.(*memoryEntry).key)
+}
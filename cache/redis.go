@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisKeyPrefix = "bupda:"
+	redisPoolSize  = 10
+	redisTimeout   = 2 * time.Second
+)
+
+var errRedisNil = errors.New("redis: nil")
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// RedisCache client redis minimal (protokol RESP) untuk GET, SET, DEL dan INCR
+type RedisCache struct {
+	address  string
+	password string
+	db       int
+	pool     chan *redisConn
+}
+
+func NewRedisCache(address string, password string, db int) Cache {
+	return &RedisCache{
+		address:  address,
+		password: password,
+		db:       db,
+		pool:     make(chan *redisConn, redisPoolSize),
+	}
+}
+
+func (cache *RedisCache) Get(key string) ([]byte, bool) {
+	reply, err := cache.do("GET", redisKeyPrefix+key)
+	if err != nil {
+		return nil, false
+	}
+	return []byte(reply), true
+}
+
+func (cache *RedisCache) Set(key string, value []byte, ttl time.Duration) {
+	cache.do("SET", redisKeyPrefix+key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+}
+
+func (cache *RedisCache) Delete(key string) {
+	cache.do("DEL", redisKeyPrefix+key)
+}
+
+func (cache *RedisCache) Generation(namespace string) int64 {
+	reply, err := cache.do("GET", redisKeyPrefix+"gen:"+namespace)
+	if err != nil {
+		return 0
+	}
+	generation, _ := strconv.ParseInt(reply, 10, 64)
+	return generation
+}
+
+func (cache *RedisCache) Invalidate(namespace string) {
+	cache.do("INCR", redisKeyPrefix+"gen:"+namespace)
+}
+
+func (cache *RedisCache) do(args ...string) (string, error) {
+	conn, err := cache.getConn()
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := conn.command(args...)
+	if err != nil && err != errRedisNil {
+		conn.conn.Close()
+		return "", err
+	}
+	cache.putConn(conn)
+	return reply, err
+}
+
+func (cache *RedisCache) getConn() (*redisConn, error) {
+	select {
+	case conn := <-cache.pool:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", cache.address, redisTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+	if len(cache.password) != 0 {
+		if _, err = conn.command("AUTH", cache.password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if cache.db != 0 {
+		if _, err = conn.command("SELECT", strconv.Itoa(cache.db)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (cache *RedisCache) putConn(conn *redisConn) {
+	select {
+	case cache.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+func (conn *redisConn) command(args ...string) (string, error) {
+	conn.conn.SetDeadline(time.Now().Add(redisTimeout))
+
+	var builder strings.Builder
+	builder.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		builder.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := conn.conn.Write([]byte(builder.String())); err != nil {
+		return "", err
+	}
+
+	line, err := conn.readLine()
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 {
+		return "", errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", errors.New("redis: " + line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", err
+		}
+		if size < 0 {
+			return "", errRedisNil
+		}
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(conn.reader, data); err != nil {
+			return "", err
+		}
+		return string(data[:size]), nil
+	default:
+		return "", errors.New("redis: unsupported reply " + line)
+	}
+}
+
+func (conn *redisConn) readLine() (string, error) {
+	line, err := conn.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
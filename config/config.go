@@ -12,9 +12,10 @@ type Application struct {
 }
 
 type Webserver struct {
-	Port      uint `yaml:"port"`
-	Timeout   uint `yaml:"timeout"`
-	RateLimit uint `yaml:"ratelimit"`
+	Port           uint     `yaml:"port"`
+	Timeout        uint     `yaml:"timeout"`
+	RateLimit      uint     `yaml:"ratelimit"`
+	TrustedProxies []string `yaml:"trustedproxies"` // ip/cidr proxy yang boleh mengirim X-Forwarded-For, kosong = ip koneksi langsung
 }
 
 type Database struct {
@@ -74,7 +75,10 @@ type Telegram struct {
 }
 
 type Fcm struct {
-	Serverkey string `yaml:"serverkey"`
+	ServiceAccount       string `yaml:"serviceaccount"`       // path file json service account firebase
+	Provider             string `yaml:"provider"`             // fcm (default) atau fake untuk lokal/testing
+	Timeout              uint   `yaml:"timeout"`              // detik
+	PaylaterReminderDays uint   `yaml:"paylaterreminderdays"` // hari sebelum jatuh tempo paylater, default 3
 }
 
 type Sms struct {
@@ -82,11 +86,69 @@ type Sms struct {
 	PassKey string `yaml:"passkey"`
 }
 
+type Role struct {
+	Admin   string `yaml:"admin"`
+	Courier string `yaml:"courier"`
+	// SuperAdmin role pengelola produk master lintas desa, kosong = tidak ada yang bisa mengubah
+	SuperAdmin string `yaml:"superadmin"`
+}
+
+type Otp struct {
+	Pepper string `yaml:"pepper"`
+}
+
+type Privacy struct {
+	DeletionGraceDays uint `yaml:"deletiongracedays"`
+	// IdentityPepper kunci HMAC no identitas yang disimpan di data referral
+	IdentityPepper string `yaml:"identitypepper"`
+}
+
+type Cache struct {
+	Driver        string `yaml:"driver"`
+	Capacity      int    `yaml:"capacity"`
+	RedisAddress  string `yaml:"redisaddress"`
+	RedisPassword string `yaml:"redispassword"`
+	RedisDb       int    `yaml:"redisdb"`
+	ProductTtl    uint   `yaml:"productttl"`
+	BannerTtl     uint   `yaml:"bannerttl"`
+	PromoTtl      uint   `yaml:"promottl"`
+	WilayahTtl    uint   `yaml:"wilayahttl"`
+	SettingTtl    uint   `yaml:"settingttl"`
+}
+
+type Point struct {
+	ExpiryDays uint `yaml:"expirydays"`
+}
+
+type Fulfilment struct {
+	AutoCompleteDays uint `yaml:"autocompletedays"` // hari setelah sampai sebelum order otomatis selesai
+}
+
+type Storage struct {
+	Driver          string `yaml:"driver"`          // local (default) atau s3
+	LocalDir        string `yaml:"localdir"`        // folder upload driver local
+	BaseUrl         string `yaml:"baseurl"`         // url api untuk file driver local
+	SigningKey      string `yaml:"signingkey"`      // kunci signed url driver local
+	SignedUrlExpiry uint   `yaml:"signedurlexpiry"` // detik
+	MaxSize         uint   `yaml:"maxsize"`         // byte
+	S3Endpoint      string `yaml:"s3endpoint"`
+	S3Region        string `yaml:"s3region"`
+	S3Bucket        string `yaml:"s3bucket"`
+	S3AccessKey     string `yaml:"s3accesskey"`
+	S3SecretKey     string `yaml:"s3secretkey"`
+	S3PublicUrl     string `yaml:"s3publicurl"` // cdn untuk file publik, kosong = endpoint bucket
+}
+
 type Ppob struct {
-	Username    string `yaml:"username"`
-	PpobKey     string `yaml:"ppobkey"`
-	PrepaidHost string `yaml:"prepaidhost"`
-	PostpaidUrl string `yaml:"postpaidurl"`
+	Username              string   `yaml:"username"`
+	PpobKey               string   `yaml:"ppobkey"`
+	PrepaidHost           string   `yaml:"prepaidhost"`
+	PostpaidUrl           string   `yaml:"postpaidurl"`
+	TransactionListUrl    string   `yaml:"transactionlisturl"`    // daftar transaksi harian provider untuk rekonsiliasi
+	Provider              string   `yaml:"provider"`              // iak (default) atau fake untuk lokal/testing
+	Timeout               uint     `yaml:"timeout"`               // detik
+	ReconcileAfterMinutes uint     `yaml:"reconcileafterminutes"` // menit tanpa callback sebelum dicek ke provider
+	CallbackAllowedIps    []string `yaml:"callbackallowedips"`    // ip provider yang boleh kirim callback, kosong = semua ditolak
 }
 
 type Inveli struct {
@@ -100,6 +162,7 @@ type ApplicationConfiguration struct {
 	Webserver     Webserver
 	Database      Database
 	Jwt           Jwt
+	Role          Role
 	Timezone      Timezone
 	Log           Log
 	IpaymuPayment IpaymuPayment
@@ -107,6 +170,12 @@ type ApplicationConfiguration struct {
 	Telegram      Telegram
 	Fcm           Fcm
 	Sms           Sms
+	Otp           Otp
+	Privacy       Privacy
+	Cache         Cache
+	Point         Point
+	Fulfilment    Fulfilment
+	Storage       Storage
 	Ppob          Ppob
 	Inveli        Inveli
 }
@@ -44,8 +44,9 @@ func (controller *CartControllerImplementation) CreateCart(c echo.Context) error
 
 func (controller *CartControllerImplementation) UpdateCart(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
 	request := request.ReadFromUpdateCartRequestBody(c, requestId, controller.Logger)
-	idCart := controller.CartServiceInterface.UpdateCart(requestId, request)
+	idCart := controller.CartServiceInterface.UpdateCart(requestId, idUser, request)
 	data := make(map[string]interface{})
 	data["id_cart"] = idCart
 	responses := response.Response{Code: 200, Mssg: "success", Data: data, Error: []string{}}
@@ -57,7 +58,8 @@ func (controller *CartControllerImplementation) FindCartByUser(c echo.Context) e
 	idUser := middleware.TokenClaimsIdUser(c)
 	idDesa := middleware.TokenClaimsIdDesa(c)
 	accountType := middleware.TokenClaimsAccountType(c)
-	cartResponse := controller.CartServiceInterface.FindCartByUser(requestId, idUser, accountType, idDesa)
+	idAddress := c.QueryParam("id_address")
+	cartResponse := controller.CartServiceInterface.FindCartByUser(requestId, idUser, accountType, idDesa, idAddress)
 	responses := response.Response{Code: 200, Mssg: "success", Data: cartResponse, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type FulfilmentControllerInterface interface {
+	FindFulfilmentOrders(c echo.Context) error
+	FindCouriers(c echo.Context) error
+	PackOrder(c echo.Context) error
+	AssignCourier(c echo.Context) error
+	FindCourierOrders(c echo.Context) error
+	ShipOrder(c echo.Context) error
+	DeliverOrder(c echo.Context) error
+	FindOrderTracking(c echo.Context) error
+}
+
+type FulfilmentControllerImplementation struct {
+	Logger                     *logrus.Logger
+	FulfilmentServiceInterface service.FulfilmentServiceInterface
+	UploadServiceInterface     service.UploadServiceInterface
+}
+
+func NewFulfilmentController(
+	logger *logrus.Logger,
+	fulfilmentServiceInterface service.FulfilmentServiceInterface,
+	uploadServiceInterface service.UploadServiceInterface,
+) FulfilmentControllerInterface {
+	return &FulfilmentControllerImplementation{
+		Logger:                     logger,
+		FulfilmentServiceInterface: fulfilmentServiceInterface,
+		UploadServiceInterface:     uploadServiceInterface,
+	}
+}
+
+// FindFulfilmentOrders tanpa order_status tampilkan semua order yang sedang diproses
+func (controller *FulfilmentControllerImplementation) FindFulfilmentOrders(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	orderStatus, err := strconv.Atoi(c.QueryParam("order_status"))
+	if err != nil {
+		orderStatus = -1
+	}
+	fulfilmentOrderResponses := controller.FulfilmentServiceInterface.FindFulfilmentOrders(requestId, idDesa, orderStatus)
+	responses := response.Response{Code: 200, Mssg: "success", Data: fulfilmentOrderResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *FulfilmentControllerImplementation) FindCouriers(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	courierResponses := controller.FulfilmentServiceInterface.FindCouriers(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: courierResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *FulfilmentControllerImplementation) PackOrder(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	orderRequest := request.ReadFromOrderIdRequestBody(c, requestId, controller.Logger)
+	controller.FulfilmentServiceInterface.PackOrder(requestId, idDesa, idUser, orderRequest)
+	responses := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *FulfilmentControllerImplementation) AssignCourier(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	assignCourierRequest := request.ReadFromAssignCourierRequestBody(c, requestId, controller.Logger)
+	controller.FulfilmentServiceInterface.AssignCourier(requestId, idDesa, idUser, assignCourierRequest)
+	responses := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *FulfilmentControllerImplementation) FindCourierOrders(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	fulfilmentOrderResponses := controller.FulfilmentServiceInterface.FindCourierOrders(requestId, idDesa, idUser)
+	responses := response.Response{Code: 200, Mssg: "success", Data: fulfilmentOrderResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *FulfilmentControllerImplementation) ShipOrder(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	orderRequest := request.ReadFromOrderIdRequestBody(c, requestId, controller.Logger)
+	controller.FulfilmentServiceInterface.ShipOrder(requestId, idDesa, idUser, orderRequest)
+	responses := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+// DeliverOrder foto bisa dikirim sebagai key hasil upload atau langsung sebagai file multipart
+func (controller *FulfilmentControllerImplementation) DeliverOrder(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	deliverOrderRequest := request.ReadFromDeliverOrderRequestBody(c, requestId, controller.Logger)
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		exceptions.PanicIfError(err, requestId, controller.Logger)
+		defer file.Close()
+		uploadFileResponse := controller.UploadServiceInterface.UploadFile(requestId, idUser, service.UploadCategoryBarangSampai, file)
+		deliverOrderRequest.FotoBarangSampai = uploadFileResponse.Key
+	}
+	controller.FulfilmentServiceInterface.DeliverOrder(requestId, idDesa, idUser, deliverOrderRequest)
+	responses := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *FulfilmentControllerImplementation) FindOrderTracking(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	idOrder := c.QueryParam("id_order")
+	orderTrackingResponse := controller.FulfilmentServiceInterface.FindOrderTracking(requestId, idUser, idDesa, idOrder)
+	responses := response.Response{Code: 200, Mssg: "success", Data: orderTrackingResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -14,6 +15,9 @@ import (
 type MerchantControllerInterface interface {
 	CreateMerchantApproveList(c echo.Context) error
 	FindMerchantStatusApproveByUserResponse(c echo.Context) error
+	FindMerchantApproveListByDesa(c echo.Context) error
+	ApproveMerchant(c echo.Context) error
+	RejectMerchant(c echo.Context) error
 }
 
 type MerchantControllerImplementation struct {
@@ -47,3 +51,32 @@ func (controller *MerchantControllerImplementation) FindMerchantStatusApproveByU
 	responses := response.Response{Code: 200, Mssg: "success", Data: merchantResponse, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
+
+func (controller *MerchantControllerImplementation) FindMerchantApproveListByDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	approveStatus, _ := strconv.Atoi(c.QueryParam("status"))
+	merchantResponses := controller.MerchantServiceInterface.FindMerchantApproveListByDesa(requestId, idDesa, approveStatus)
+	responses := response.Response{Code: 200, Mssg: "success", Data: merchantResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *MerchantControllerImplementation) ApproveMerchant(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idAdmin := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromApproveMerchantRequestBody(c, requestId, controller.Logger)
+	controller.MerchantServiceInterface.ApproveMerchant(requestId, idAdmin, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Approve Merchant Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *MerchantControllerImplementation) RejectMerchant(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idAdmin := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromRejectMerchantRequestBody(c, requestId, controller.Logger)
+	controller.MerchantServiceInterface.RejectMerchant(requestId, idAdmin, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Reject Merchant Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type NotificationControllerInterface interface {
+	RegisterDeviceToken(c echo.Context) error
+	UnregisterDeviceToken(c echo.Context) error
+	FindNotifications(c echo.Context) error
+	ReadNotifications(c echo.Context) error
+}
+
+type NotificationControllerImplementation struct {
+	Logger                       *logrus.Logger
+	NotificationServiceInterface service.NotificationServiceInterface
+}
+
+func NewNotificationController(
+	logger *logrus.Logger,
+	notificationServiceInterface service.NotificationServiceInterface,
+) NotificationControllerInterface {
+	return &NotificationControllerImplementation{
+		Logger:                       logger,
+		NotificationServiceInterface: notificationServiceInterface,
+	}
+}
+
+func (controller *NotificationControllerImplementation) RegisterDeviceToken(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	registerDeviceTokenRequest := request.ReadFromRegisterDeviceTokenRequestBody(c, requestId, controller.Logger)
+	controller.NotificationServiceInterface.RegisterDeviceToken(requestId, idUser, registerDeviceTokenRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *NotificationControllerImplementation) UnregisterDeviceToken(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	unregisterDeviceTokenRequest := request.ReadFromUnregisterDeviceTokenRequestBody(c, requestId, controller.Logger)
+	controller.NotificationServiceInterface.UnregisterDeviceToken(requestId, idUser, unregisterDeviceTokenRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *NotificationControllerImplementation) FindNotifications(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	notificationResponse := controller.NotificationServiceInterface.FindNotifications(requestId, idUser, page, limit)
+	responses := response.Response{Code: 200, Mssg: "success", Data: notificationResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *NotificationControllerImplementation) ReadNotifications(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	readNotificationRequest := request.ReadFromReadNotificationRequestBody(c, requestId, controller.Logger)
+	controller.NotificationServiceInterface.ReadNotifications(requestId, idUser, readNotificationRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
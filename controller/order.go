@@ -10,12 +10,14 @@ import (
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
 )
 
 type OrderControllerInterface interface {
 	CreateOrder(c echo.Context) error
 	FindOrderByUser(c echo.Context) error
 	FindOrderById(c echo.Context) error
+	FindOrderReceipt(c echo.Context) error
 	CancelOrderById(c echo.Context) error
 	CompleteOrderById(c echo.Context) error
 	UpdateOrderPaymentStatus(c echo.Context) error
@@ -77,6 +79,10 @@ func (controller *OrderControllerImplementation) CreateOrder(c echo.Context) err
 		case "postpaid_pln":
 			request := request.ReadFromCreateOrderPostpaidRequestBody(c, requestId, controller.Logger)
 			orderResponse = controller.OrderServiceInterface.CreateOrderPostpaidPln(requestId, idUser, idDesa, productType, request)
+		default:
+			// ppob generic (bpjs, e-money, voucher game, multifinance)
+			request := request.ReadFromCreateOrderPpobRequestBody(c, requestId, controller.Logger)
+			orderResponse = controller.OrderServiceInterface.CreateOrderPpob(requestId, idUser, idDesa, productType, request)
 		}
 	}
 	responses := response.Response{Code: 201, Mssg: "success", Data: orderResponse, Error: []string{}}
@@ -101,6 +107,8 @@ func (controller *OrderControllerImplementation) FindOrderByUser(c echo.Context)
 
 func (controller *OrderControllerImplementation) FindOrderById(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
 	idOrder := c.QueryParam("id_order")
 	productType := c.QueryParam("product_type")
 
@@ -108,27 +116,31 @@ func (controller *OrderControllerImplementation) FindOrderById(c echo.Context) e
 
 	switch productType {
 	case "sembako":
-		orderResponse := controller.OrderServiceInterface.FindOrderSembakoById(requestId, idOrder)
+		orderResponse := controller.OrderServiceInterface.FindOrderSembakoById(requestId, idUser, idDesa, idOrder)
 		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
 
 	case "prepaid_pulsa", "prepaid_data":
-		orderResponse := controller.OrderServiceInterface.FindOrderPrepaidPulsaById(requestId, idOrder, productType)
+		orderResponse := controller.OrderServiceInterface.FindOrderPrepaidPulsaById(requestId, idUser, idDesa, idOrder, productType)
 		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
 
 	case "prepaid_pln":
-		orderResponse := controller.OrderServiceInterface.FindOrderPrepaidPlnById(requestId, idOrder)
+		orderResponse := controller.OrderServiceInterface.FindOrderPrepaidPlnById(requestId, idUser, idDesa, idOrder)
 		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
 
 	case "postpaid_pln":
-		orderResponse := controller.OrderServiceInterface.FindOrderPostpaidPlnById(requestId, idOrder)
+		orderResponse := controller.OrderServiceInterface.FindOrderPostpaidPlnById(requestId, idUser, idDesa, idOrder)
 		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
 
 	case "postpaid_pdam":
-		orderResponse := controller.OrderServiceInterface.FindOrderPostpaidPdamById(requestId, idOrder)
+		orderResponse := controller.OrderServiceInterface.FindOrderPostpaidPdamById(requestId, idUser, idDesa, idOrder)
 		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
 
 	case "payment":
-		orderResponse := controller.OrderServiceInterface.FindOrderPaymentById(requestId, idOrder)
+		orderResponse := controller.OrderServiceInterface.FindOrderPaymentById(requestId, idUser, idDesa, idOrder)
+		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
+
+	default:
+		orderResponse := controller.OrderServiceInterface.FindOrderPpobById(requestId, idUser, idDesa, idOrder)
 		responses = response.Response{Code: 200, Mssg: "success", Data: orderResponse, Error: []string{}}
 	}
 
@@ -136,18 +148,46 @@ func (controller *OrderControllerImplementation) FindOrderById(c echo.Context) e
 
 }
 
+// FindOrderReceipt struk order, format pdf (default), text, escpos atau json
+func (controller *OrderControllerImplementation) FindOrderReceipt(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	idOrder := c.QueryParam("id_order")
+	orderReceipt := controller.OrderServiceInterface.FindOrderReceipt(requestId, idUser, idDesa, idOrder)
+	filename := "struk-" + orderReceipt.NumberOrder
+
+	switch c.QueryParam("format") {
+	case "json":
+		response := response.Response{Code: 200, Mssg: "success", Data: orderReceipt, Error: []string{}}
+		return c.JSON(http.StatusOK, response)
+	case "text":
+		return c.Blob(http.StatusOK, "text/plain; charset=utf-8", utilities.ReceiptToText(service.OrderReceiptLines(orderReceipt)))
+	case "escpos":
+		c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\""+filename+".bin\"")
+		return c.Blob(http.StatusOK, echo.MIMEOctetStream, utilities.ReceiptToEscPos(service.OrderReceiptLines(orderReceipt)))
+	default:
+		c.Response().Header().Set(echo.HeaderContentDisposition, "inline; filename=\""+filename+".pdf\"")
+		return c.Blob(http.StatusOK, "application/pdf", utilities.ReceiptToPdf(service.OrderReceiptLines(orderReceipt), service.ReceiptWidth))
+	}
+}
+
 func (controller *OrderControllerImplementation) CancelOrderById(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
 	orderRequest := request.ReadFromOrderIdRequestBody(c, requestId, controller.Logger)
-	controller.OrderServiceInterface.CancelOrderById(requestId, orderRequest)
+	controller.OrderServiceInterface.CancelOrderById(requestId, idUser, idDesa, orderRequest)
 	response := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
 	return c.JSON(http.StatusOK, response)
 }
 
 func (controller *OrderControllerImplementation) CompleteOrderById(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
 	orderRequest := request.ReadFromOrderIdRequestBody(c, requestId, controller.Logger)
-	controller.OrderServiceInterface.CompleteOrderById(requestId, orderRequest)
+	controller.OrderServiceInterface.CompleteOrderById(requestId, idUser, idDesa, orderRequest)
 	response := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
 	return c.JSON(http.StatusOK, response)
 }
@@ -162,8 +202,8 @@ func (controller *OrderControllerImplementation) UpdateOrderPaymentStatus(c echo
 
 func (controller *OrderControllerImplementation) CallbackPpobTransaction(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
-	orderRequest := request.ReadFromPpobCallbackRequestRequestBody(c, requestId, controller.Logger)
-	controller.OrderServiceInterface.CallbackPpobTransaction(requestId, orderRequest)
+	orderRequest, rawPayload := request.ReadFromPpobCallbackRequestRequestBody(c, requestId, controller.Logger)
+	controller.OrderServiceInterface.CallbackPpobTransaction(requestId, orderRequest, rawPayload, c.RealIP())
 	response := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
 	return c.JSON(http.StatusOK, response)
 }
@@ -2,32 +2,78 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
 )
 
 type PointControllerInterface interface {
 	FindPointByUser(c echo.Context) error
+	FindPointHistoryByUser(c echo.Context) error
+	FindPointRulesByDesa(c echo.Context) error
+	CreatePointRule(c echo.Context) error
+	UpdatePointRule(c echo.Context) error
 }
 
 type PointControllerImplementation struct {
+	Logger                *logrus.Logger
 	PointServiceInterface service.PointServiceInterface
 }
 
 func NewPointController(
+	logger *logrus.Logger,
 	pointServiceInterface service.PointServiceInterface) PointControllerInterface {
 	return &PointControllerImplementation{
+		Logger:                logger,
 		PointServiceInterface: pointServiceInterface,
 	}
 }
 
 func (controller *PointControllerImplementation) FindPointByUser(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
-	idUser := middleware.TokenClaimsIdDesa(c)
+	idUser := middleware.TokenClaimsIdUser(c)
 	pointResponse := controller.PointServiceInterface.FindPointByUser(requestId, idUser)
 	responses := response.Response{Code: 200, Mssg: "success", Data: pointResponse, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
+
+func (controller *PointControllerImplementation) FindPointHistoryByUser(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	pointHistoryResponse := controller.PointServiceInterface.FindPointHistoryByUser(requestId, idUser, page, limit)
+	responses := response.Response{Code: 200, Mssg: "success", Data: pointHistoryResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PointControllerImplementation) FindPointRulesByDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	pointRuleResponses := controller.PointServiceInterface.FindPointRulesByDesa(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: pointRuleResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PointControllerImplementation) CreatePointRule(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromCreatePointRuleRequestBody(c, requestId, controller.Logger)
+	controller.PointServiceInterface.CreatePointRule(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Create Point Rule Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PointControllerImplementation) UpdatePointRule(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromUpdatePointRuleRequestBody(c, requestId, controller.Logger)
+	controller.PointServiceInterface.UpdatePointRule(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Update Point Rule Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
@@ -20,6 +21,9 @@ type PpobControllerInterface interface {
 	GetPostpaidTelcoProduct(c echo.Context) error
 	InquiryPostpaidPdam(c echo.Context) error
 	InquiryPostpaidTelco(c echo.Context) error
+	FindPpobProductTypes(c echo.Context) error
+	FindPpobProducts(c echo.Context) error
+	InquiryPpob(c echo.Context) error
 }
 
 type PpobControllerImplementation struct {
@@ -38,24 +42,27 @@ func NewPpobController(
 
 func (controller *PpobControllerImplementation) GetPrepaidPulsaPriceList(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
 	numberPhone := c.QueryParam("phone")
-	pulsaPriceListResponses := controller.PpobServiceInterface.GetPrepaidPulsaPriceList(requestId, numberPhone)
+	pulsaPriceListResponses := controller.PpobServiceInterface.GetPrepaidPulsaPriceList(requestId, idDesa, numberPhone)
 	responses := response.Response{Code: 200, Mssg: "success", Data: pulsaPriceListResponses, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
 
 func (controller *PpobControllerImplementation) GetPrepaidDataPriceList(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
 	numberPhone := c.QueryParam("phone")
-	pulsaPriceListResponses := controller.PpobServiceInterface.GetPrepaidDataPriceList(requestId, numberPhone)
+	pulsaPriceListResponses := controller.PpobServiceInterface.GetPrepaidDataPriceList(requestId, idDesa, numberPhone)
 	responses := response.Response{Code: 200, Mssg: "success", Data: pulsaPriceListResponses, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
 
 func (controller *PpobControllerImplementation) GetPrepaidPlnPriceList(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
 	idPelanggan := c.QueryParam("id_pelanggan")
-	plnPriceListResponses := controller.PpobServiceInterface.GetPrepaidPlnPriceList(requestId, idPelanggan)
+	plnPriceListResponses := controller.PpobServiceInterface.GetPrepaidPlnPriceList(requestId, idDesa, idPelanggan)
 	responses := response.Response{Code: 200, Mssg: "success", Data: plnPriceListResponses, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
@@ -105,3 +112,28 @@ func (controller *PpobControllerImplementation) InquiryPostpaidTelco(c echo.Cont
 	responses := response.Response{Code: 200, Mssg: "success", Data: detailResponses, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
+
+func (controller *PpobControllerImplementation) FindPpobProductTypes(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	productTypeResponses := controller.PpobServiceInterface.FindPpobProductTypes(requestId)
+	responses := response.Response{Code: 200, Mssg: "success", Data: productTypeResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PpobControllerImplementation) FindPpobProducts(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	productType := c.QueryParam("product_type")
+	productResponses := controller.PpobServiceInterface.FindPpobProducts(requestId, idDesa, productType)
+	responses := response.Response{Code: 200, Mssg: "success", Data: productResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PpobControllerImplementation) InquiryPpob(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	productType := c.QueryParam("product_type")
+	inquiryPpobRequest := request.ReadFromInquiryPpobRequestBody(c, requestId, controller.Logger)
+	detailResponses := controller.PpobServiceInterface.InquiryPpob(requestId, productType, inquiryPpobRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: detailResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type PpobMarginControllerInterface interface {
+	FindPpobMarginsByDesa(c echo.Context) error
+	CreatePpobMargin(c echo.Context) error
+	UpdatePpobMargin(c echo.Context) error
+}
+
+type PpobMarginControllerImplementation struct {
+	Logger                    *logrus.Logger
+	PpobPriceServiceInterface service.PpobPriceServiceInterface
+}
+
+func NewPpobMarginController(
+	logger *logrus.Logger,
+	ppobPriceServiceInterface service.PpobPriceServiceInterface,
+) PpobMarginControllerInterface {
+	return &PpobMarginControllerImplementation{
+		Logger:                    logger,
+		PpobPriceServiceInterface: ppobPriceServiceInterface,
+	}
+}
+
+func (controller *PpobMarginControllerImplementation) FindPpobMarginsByDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	ppobMarginResponses := controller.PpobPriceServiceInterface.FindPpobMarginsByDesa(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: ppobMarginResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PpobMarginControllerImplementation) CreatePpobMargin(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromCreatePpobMarginRequestBody(c, requestId, controller.Logger)
+	controller.PpobPriceServiceInterface.CreatePpobMargin(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Create Ppob Margin Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *PpobMarginControllerImplementation) UpdatePpobMargin(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromUpdatePpobMarginRequestBody(c, requestId, controller.Logger)
+	controller.PpobPriceServiceInterface.UpdatePpobMargin(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Update Ppob Margin Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type PpobReconciliationControllerInterface interface {
+	FindPpobReconciliations(c echo.Context) error
+}
+
+type PpobReconciliationControllerImplementation struct {
+	Logger                        *logrus.Logger
+	PpobReconcileServiceInterface service.PpobReconcileServiceInterface
+}
+
+func NewPpobReconciliationController(
+	logger *logrus.Logger,
+	ppobReconcileServiceInterface service.PpobReconcileServiceInterface,
+) PpobReconciliationControllerInterface {
+	return &PpobReconciliationControllerImplementation{
+		Logger:                        logger,
+		PpobReconcileServiceInterface: ppobReconcileServiceInterface,
+	}
+}
+
+func (controller *PpobReconciliationControllerImplementation) FindPpobReconciliations(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	ppobReconciliationResponses := controller.PpobReconcileServiceInterface.FindPpobReconciliations(requestId)
+	responses := response.Response{Code: 200, Mssg: "success", Data: ppobReconciliationResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
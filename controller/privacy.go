@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type PrivacyControllerInterface interface {
+	ExportUserData(c echo.Context) error
+}
+
+type PrivacyControllerImplementation struct {
+	PrivacyServiceInterface service.PrivacyServiceInterface
+}
+
+func NewPrivacyController(
+	privacyServiceInterface service.PrivacyServiceInterface,
+) PrivacyControllerInterface {
+	return &PrivacyControllerImplementation{
+		PrivacyServiceInterface: privacyServiceInterface,
+	}
+}
+
+func (controller *PrivacyControllerImplementation) ExportUserData(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	if c.QueryParam("format") == "json" {
+		exportUserDataResponse := controller.PrivacyServiceInterface.ExportUserData(requestId, idUser)
+		responses := response.Response{Code: 200, Mssg: "success", Data: exportUserDataResponse, Error: []string{}}
+		return c.JSON(http.StatusOK, responses)
+	}
+
+	data := controller.PrivacyServiceInterface.ExportUserDataZip(requestId, idUser)
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\"data-pribadi.zip\"")
+	return c.Blob(http.StatusOK, "application/zip", data)
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type ProductAdminControllerInterface interface {
+	CreateProductMaster(c echo.Context) error
+	UpdateProductMaster(c echo.Context) error
+	FindProductsDesa(c echo.Context) error
+	CreateProductDesa(c echo.Context) error
+	UpdateProductDesa(c echo.Context) error
+	AdjustProductStock(c echo.Context) error
+	FindProductDesaStockHistory(c echo.Context) error
+	ExportProductsDesaCsv(c echo.Context) error
+	ImportProductsDesaCsv(c echo.Context) error
+	FindProductPriceTier(c echo.Context) error
+	UpdateProductPriceTier(c echo.Context) error
+}
+
+type ProductAdminControllerImplementation struct {
+	Logger                       *logrus.Logger
+	ProductAdminServiceInterface service.ProductAdminServiceInterface
+}
+
+func NewProductAdminController(
+	logger *logrus.Logger,
+	productAdminServiceInterface service.ProductAdminServiceInterface,
+) ProductAdminControllerInterface {
+	return &ProductAdminControllerImplementation{
+		Logger:                       logger,
+		ProductAdminServiceInterface: productAdminServiceInterface,
+	}
+}
+
+func (controller *ProductAdminControllerImplementation) CreateProductMaster(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	request := request.ReadFromCreateProductMasterRequestBody(c, requestId, controller.Logger)
+	controller.ProductAdminServiceInterface.CreateProductMaster(requestId, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Create Product Master Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) UpdateProductMaster(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	request := request.ReadFromUpdateProductMasterRequestBody(c, requestId, controller.Logger)
+	controller.ProductAdminServiceInterface.UpdateProductMaster(requestId, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Update Product Master Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) FindProductsDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	productsDesaResponse := controller.ProductAdminServiceInterface.FindProductsDesa(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: productsDesaResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) CreateProductDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromCreateProductDesaRequestBody(c, requestId, controller.Logger)
+	controller.ProductAdminServiceInterface.CreateProductDesa(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Create Product Desa Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) UpdateProductDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromUpdateProductDesaRequestBody(c, requestId, controller.Logger)
+	controller.ProductAdminServiceInterface.UpdateProductDesa(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Update Product Desa Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) AdjustProductStock(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromAdjustProductStockRequestBody(c, requestId, controller.Logger)
+	controller.ProductAdminServiceInterface.AdjustProductStock(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Adjust Product Stock Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) FindProductDesaStockHistory(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	idProductDesa := c.QueryParam("id_product_desa")
+	stockHistoryResponses := controller.ProductAdminServiceInterface.FindProductDesaStockHistory(requestId, idDesa, idProductDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: stockHistoryResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) ExportProductsDesaCsv(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	data := controller.ProductAdminServiceInterface.ExportProductsDesaCsv(requestId, idDesa)
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\"products-desa.csv\"")
+	return c.Blob(http.StatusOK, "text/csv", data)
+}
+
+func (controller *ProductAdminControllerImplementation) ImportProductsDesaCsv(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	fileHeader, err := c.FormFile("file")
+	exceptions.PanicIfBadRequest(err, requestId, []string{"file csv required"}, controller.Logger)
+	file, err := fileHeader.Open()
+	exceptions.PanicIfError(err, requestId, controller.Logger)
+	defer file.Close()
+	importResponse := controller.ProductAdminServiceInterface.ImportProductsDesaCsv(requestId, idDesa, file)
+	responses := response.Response{Code: 200, Mssg: "success", Data: importResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) FindProductPriceTier(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	idProductDesa := c.QueryParam("id_product_desa")
+	priceTierResponses := controller.ProductAdminServiceInterface.FindProductPriceTier(requestId, idDesa, idProductDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: priceTierResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductAdminControllerImplementation) UpdateProductPriceTier(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromUpdateProductPriceTierRequestBody(c, requestId, controller.Logger)
+	controller.ProductAdminServiceInterface.UpdateProductPriceTier(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Update Product Price Tier Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
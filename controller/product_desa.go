@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
 )
@@ -20,6 +21,9 @@ type ProductDesaControllerInterface interface {
 	FindProductsDesaBySubCategoryNotoken(c echo.Context) error
 	FindProductDesaById(c echo.Context) error
 	FindProductsDesaByPromo(c echo.Context) error
+	SearchProductsDesa(c echo.Context) error
+	SearchProductsDesaNotoken(c echo.Context) error
+	QuoteProductPrice(c echo.Context) error
 }
 
 type ProductDesaControllerImplementation struct {
@@ -113,3 +117,33 @@ func (controller *ProductDesaControllerImplementation) FindProductsDesaByPromo(c
 	responses := response.Response{Code: 200, Mssg: "success", Data: productsDesaResponse, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
+
+func (controller *ProductDesaControllerImplementation) SearchProductsDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	accountType := middleware.TokenClaimsAccountType(c)
+	request := request.ReadFromSearchProductsDesaRequestQuery(c, requestId, controller.Logger)
+	searchResponse := controller.ProductDesaServiceInterface.SearchProductsDesa(requestId, idDesa, accountType, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: searchResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductDesaControllerImplementation) SearchProductsDesaNotoken(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := "44dcd5ce-aa07-43da-ba04-2bff14a4d7ac"
+	accountType := 1
+	request := request.ReadFromSearchProductsDesaRequestQuery(c, requestId, controller.Logger)
+	searchResponse := controller.ProductDesaServiceInterface.SearchProductsDesa(requestId, idDesa, accountType, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: searchResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ProductDesaControllerImplementation) QuoteProductPrice(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idProductDesa := c.QueryParam("id_product")
+	qty, _ := strconv.Atoi(c.QueryParam("qty"))
+	accountType := middleware.TokenClaimsAccountType(c)
+	priceQuote := controller.ProductDesaServiceInterface.QuoteProductPrice(requestId, idProductDesa, accountType, qty)
+	responses := response.Response{Code: 200, Mssg: "success", Data: priceQuote, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
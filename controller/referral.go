@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type ReferralControllerInterface interface {
+	FindReferralStats(c echo.Context) error
+}
+
+type ReferralControllerImplementation struct {
+	Logger                   *logrus.Logger
+	ReferralServiceInterface service.ReferralServiceInterface
+}
+
+func NewReferralController(
+	logger *logrus.Logger,
+	referralServiceInterface service.ReferralServiceInterface) ReferralControllerInterface {
+	return &ReferralControllerImplementation{
+		Logger:                   logger,
+		ReferralServiceInterface: referralServiceInterface,
+	}
+}
+
+func (controller *ReferralControllerImplementation) FindReferralStats(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	referralStatsResponse := controller.ReferralServiceInterface.FindReferralStats(requestId, idUser)
+	responses := response.Response{Code: 200, Mssg: "success", Data: referralStatsResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
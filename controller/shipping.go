@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type ShippingControllerInterface interface {
+	FindShippingQuote(c echo.Context) error
+	FindShippingZone(c echo.Context) error
+	UpdateShippingZone(c echo.Context) error
+}
+
+type ShippingControllerImplementation struct {
+	Logger                   *logrus.Logger
+	ShippingServiceInterface service.ShippingServiceInterface
+}
+
+func NewShippingController(
+	logger *logrus.Logger,
+	shippingServiceInterface service.ShippingServiceInterface,
+) ShippingControllerInterface {
+	return &ShippingControllerImplementation{
+		Logger:                   logger,
+		ShippingServiceInterface: shippingServiceInterface,
+	}
+}
+
+func (controller *ShippingControllerImplementation) FindShippingQuote(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	idAddress := c.QueryParam("id_address")
+	shippingQuoteResponse := controller.ShippingServiceInterface.FindShippingQuote(requestId, idUser, idDesa, idAddress)
+	responses := response.Response{Code: 200, Mssg: "success", Data: shippingQuoteResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ShippingControllerImplementation) FindShippingZone(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	shippingZoneResponse := controller.ShippingServiceInterface.FindShippingZone(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: shippingZoneResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *ShippingControllerImplementation) UpdateShippingZone(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	updateShippingZoneRequest := request.ReadFromUpdateShippingZoneRequestBody(c, requestId, controller.Logger)
+	controller.ShippingServiceInterface.UpdateShippingZone(requestId, idDesa, updateShippingZoneRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
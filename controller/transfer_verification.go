@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type TransferVerificationControllerInterface interface {
+	SubmitBuktiBayar(c echo.Context) error
+	FindTransferVerifications(c echo.Context) error
+	VerifyTransfer(c echo.Context) error
+	ImportBankMutationCsv(c echo.Context) error
+	ImportInveliMutation(c echo.Context) error
+}
+
+type TransferVerificationControllerImplementation struct {
+	Logger                               *logrus.Logger
+	TransferVerificationServiceInterface service.TransferVerificationServiceInterface
+	UploadServiceInterface               service.UploadServiceInterface
+}
+
+func NewTransferVerificationController(
+	logger *logrus.Logger,
+	transferVerificationServiceInterface service.TransferVerificationServiceInterface,
+	uploadServiceInterface service.UploadServiceInterface,
+) TransferVerificationControllerInterface {
+	return &TransferVerificationControllerImplementation{
+		Logger:                               logger,
+		TransferVerificationServiceInterface: transferVerificationServiceInterface,
+		UploadServiceInterface:               uploadServiceInterface,
+	}
+}
+
+// SubmitBuktiBayar foto bisa dikirim sebagai key hasil upload atau langsung sebagai file multipart
+func (controller *TransferVerificationControllerImplementation) SubmitBuktiBayar(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	submitBuktiBayarRequest := request.ReadFromSubmitBuktiBayarRequestBody(c, requestId, controller.Logger)
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		exceptions.PanicIfError(err, requestId, controller.Logger)
+		defer file.Close()
+		uploadFileResponse := controller.UploadServiceInterface.UploadFile(requestId, idUser, service.UploadCategoryBuktiBayar, file)
+		submitBuktiBayarRequest.FotoBuktiBayar = uploadFileResponse.Key
+	}
+	controller.TransferVerificationServiceInterface.SubmitBuktiBayar(requestId, idUser, idDesa, submitBuktiBayarRequest)
+	responses := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *TransferVerificationControllerImplementation) FindTransferVerifications(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	transferVerificationResponses := controller.TransferVerificationServiceInterface.FindTransferVerifications(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: transferVerificationResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *TransferVerificationControllerImplementation) VerifyTransfer(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	verifyTransferRequest := request.ReadFromVerifyTransferRequestBody(c, requestId, controller.Logger)
+	controller.TransferVerificationServiceInterface.VerifyTransfer(requestId, idDesa, idUser, verifyTransferRequest)
+	responses := response.Response{Code: 201, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *TransferVerificationControllerImplementation) ImportBankMutationCsv(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	fileHeader, err := c.FormFile("file")
+	exceptions.PanicIfBadRequest(err, requestId, []string{"file csv required"}, controller.Logger)
+	file, err := fileHeader.Open()
+	exceptions.PanicIfError(err, requestId, controller.Logger)
+	defer file.Close()
+	importResponse := controller.TransferVerificationServiceInterface.ImportBankMutationCsv(requestId, idDesa, file)
+	responses := response.Response{Code: 200, Mssg: "success", Data: importResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *TransferVerificationControllerImplementation) ImportInveliMutation(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	importInveliMutationRequest := request.ReadFromImportInveliMutationRequestBody(c, requestId, controller.Logger)
+	importResponse := controller.TransferVerificationServiceInterface.ImportInveliMutation(requestId, idDesa, idUser, importInveliMutationRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: importResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
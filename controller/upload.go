@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type UploadControllerInterface interface {
+	UploadAdminFile(c echo.Context) error
+	UploadBuktiBayar(c echo.Context) error
+	UploadBarangSampai(c echo.Context) error
+	FindPrivateFile(c echo.Context) error
+}
+
+type UploadControllerImplementation struct {
+	Logger                 *logrus.Logger
+	UploadServiceInterface service.UploadServiceInterface
+}
+
+func NewUploadController(
+	logger *logrus.Logger,
+	uploadServiceInterface service.UploadServiceInterface,
+) UploadControllerInterface {
+	return &UploadControllerImplementation{
+		Logger:                 logger,
+		UploadServiceInterface: uploadServiceInterface,
+	}
+}
+
+func (controller *UploadControllerImplementation) UploadAdminFile(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	category := c.QueryParam("category")
+	fileHeader, err := c.FormFile("file")
+	exceptions.PanicIfBadRequest(err, requestId, []string{"file required"}, controller.Logger)
+	file, err := fileHeader.Open()
+	exceptions.PanicIfError(err, requestId, controller.Logger)
+	defer file.Close()
+	uploadFileResponse := controller.UploadServiceInterface.UploadAdminFile(requestId, idUser, category, file)
+	responses := response.Response{Code: 200, Mssg: "success", Data: uploadFileResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *UploadControllerImplementation) UploadBuktiBayar(c echo.Context) error {
+	return controller.uploadFile(c, service.UploadCategoryBuktiBayar)
+}
+
+func (controller *UploadControllerImplementation) UploadBarangSampai(c echo.Context) error {
+	return controller.uploadFile(c, service.UploadCategoryBarangSampai)
+}
+
+func (controller *UploadControllerImplementation) uploadFile(c echo.Context, category string) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	fileHeader, err := c.FormFile("file")
+	exceptions.PanicIfBadRequest(err, requestId, []string{"file required"}, controller.Logger)
+	file, err := fileHeader.Open()
+	exceptions.PanicIfError(err, requestId, controller.Logger)
+	defer file.Close()
+	uploadFileResponse := controller.UploadServiceInterface.UploadFile(requestId, idUser, category, file)
+	responses := response.Response{Code: 200, Mssg: "success", Data: uploadFileResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *UploadControllerImplementation) FindPrivateFile(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	expires, _ := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	body, contentType := controller.UploadServiceInterface.FindPrivateFile(requestId, c.QueryParam("key"), expires, c.QueryParam("signature"))
+	c.Response().Header().Set("Cache-Control", "private, no-store")
+	return c.Blob(http.StatusOK, contentType, body)
+}
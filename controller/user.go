@@ -16,6 +16,7 @@ type UserControllerInterface interface {
 	CreateUserSuveyed(c echo.Context) error
 	FindUserById(c echo.Context) error
 	DeleteUserById(c echo.Context) error
+	CancelDeleteUser(c echo.Context) error
 	UpdateUserPassword(c echo.Context) error
 	UpdateUserForgotPassword(c echo.Context) error
 	UpdateUserProfile(c echo.Context) error
@@ -151,6 +152,14 @@ func (controller *UserControllerImplementation) DeleteUserById(c echo.Context) e
 	return c.JSON(http.StatusOK, responses)
 }
 
+func (controller *UserControllerImplementation) CancelDeleteUser(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	cancelDeleteUserRequest := request.ReadFromCancelDeleteUserRequestBody(c, requestId, controller.Logger)
+	controller.UserServiceInterface.CancelDeleteUser(requestId, cancelDeleteUserRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "cancel delete user success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
 func (controller *UserControllerImplementation) UpdateUserPassword(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
 	idUser := middleware.TokenClaimsIdUser(c)
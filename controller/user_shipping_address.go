@@ -14,6 +14,8 @@ import (
 type UserShippingAddressControllerInterface interface {
 	FindUserShippingAddress(c echo.Context) error
 	CreateUserShippingAddress(c echo.Context) error
+	UpdateUserShippingAddress(c echo.Context) error
+	SetPrimaryUserShippingAddress(c echo.Context) error
 	DeleteUserShippingAddress(c echo.Context) error
 }
 
@@ -34,8 +36,9 @@ func NewUserShippingAddressController(
 
 func (controller *UserShippingAddressControllerImplementation) DeleteUserShippingAddress(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
 	userShippingAddressRequest := request.ReadFromUserShippingAddressRequestBody(c, requestId, controller.Logger)
-	controller.UserShippingAddressServiceInterface.DeleteUserShippingAddress(requestId, userShippingAddressRequest)
+	controller.UserShippingAddressServiceInterface.DeleteUserShippingAddress(requestId, idUser, userShippingAddressRequest)
 	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
 	return c.JSON(http.StatusOK, responses)
 }
@@ -49,6 +52,24 @@ func (controller *UserShippingAddressControllerImplementation) CreateUserShippin
 	return c.JSON(http.StatusOK, responses)
 }
 
+func (controller *UserShippingAddressControllerImplementation) UpdateUserShippingAddress(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	updateUserShippingAddressRequest := request.ReadFromUpdateUserShippingAddressRequestBody(c, requestId, controller.Logger)
+	controller.UserShippingAddressServiceInterface.UpdateUserShippingAddress(requestId, idUser, updateUserShippingAddressRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *UserShippingAddressControllerImplementation) SetPrimaryUserShippingAddress(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	setPrimaryUserShippingAddressRequest := request.ReadFromSetPrimaryUserShippingAddressRequestBody(c, requestId, controller.Logger)
+	controller.UserShippingAddressServiceInterface.SetPrimaryUserShippingAddress(requestId, idUser, setPrimaryUserShippingAddressRequest)
+	responses := response.Response{Code: 200, Mssg: "success", Data: nil, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
 func (controller *UserShippingAddressControllerImplementation) FindUserShippingAddress(c echo.Context) error {
 	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
 	idUser := middleware.TokenClaimsIdUser(c)
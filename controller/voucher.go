@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+type VoucherControllerInterface interface {
+	CheckVoucher(c echo.Context) error
+	FindVouchersByDesa(c echo.Context) error
+	CreateVoucher(c echo.Context) error
+	UpdateVoucher(c echo.Context) error
+}
+
+type VoucherControllerImplementation struct {
+	Logger                  *logrus.Logger
+	VoucherServiceInterface service.VoucherServiceInterface
+}
+
+func NewVoucherController(
+	logger *logrus.Logger,
+	voucherServiceInterface service.VoucherServiceInterface,
+) VoucherControllerInterface {
+	return &VoucherControllerImplementation{
+		Logger:                  logger,
+		VoucherServiceInterface: voucherServiceInterface,
+	}
+}
+
+func (controller *VoucherControllerImplementation) CheckVoucher(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idUser := middleware.TokenClaimsIdUser(c)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromCheckVoucherRequestBody(c, requestId, controller.Logger)
+	checkVoucherResponse := controller.VoucherServiceInterface.CheckVoucher(requestId, idUser, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: checkVoucherResponse, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *VoucherControllerImplementation) FindVouchersByDesa(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	voucherResponses := controller.VoucherServiceInterface.FindVouchersByDesa(requestId, idDesa)
+	responses := response.Response{Code: 200, Mssg: "success", Data: voucherResponses, Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *VoucherControllerImplementation) CreateVoucher(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromCreateVoucherRequestBody(c, requestId, controller.Logger)
+	controller.VoucherServiceInterface.CreateVoucher(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Create Voucher Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (controller *VoucherControllerImplementation) UpdateVoucher(c echo.Context) error {
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	idDesa := middleware.TokenClaimsIdDesa(c)
+	request := request.ReadFromUpdateVoucherRequestBody(c, requestId, controller.Logger)
+	controller.VoucherServiceInterface.UpdateVoucher(requestId, idDesa, request)
+	responses := response.Response{Code: 200, Mssg: "success", Data: "Update Voucher Success", Error: []string{}}
+	return c.JSON(http.StatusOK, responses)
+}
@@ -2,6 +2,8 @@ package exceptions
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -35,3 +37,129 @@ func RcResponse(err error, requestId string, errorString []string, logger *logru
 		panic(string(out))
 	}
 }
+
+// Domain error PPOB, dipetakan dari response code provider
+var (
+	ErrPpobPending             = errors.New("ppob transaction pending")
+	ErrPpobFailed              = errors.New("ppob transaction failed")
+	ErrPpobCustomerNotFound    = errors.New("ppob customer not found")
+	ErrPpobBillPaid            = errors.New("ppob bill already paid")
+	ErrPpobBillNotAvailable    = errors.New("ppob bill not available")
+	ErrPpobInvalidData         = errors.New("ppob invalid data")
+	ErrPpobProductUnavailable  = errors.New("ppob product unavailable")
+	ErrPpobInsufficientDeposit = errors.New("ppob insufficient deposit")
+	ErrPpobLimitExceeded       = errors.New("ppob limit exceeded")
+	ErrPpobProvider            = errors.New("ppob provider error")
+)
+
+type PpobRcError struct {
+	Rc      string
+	Message string
+	Err     error
+}
+
+func (e *PpobRcError) Error() string {
+	return fmt.Sprintf("%s (rc %s: %s)", e.Err.Error(), e.Rc, e.Message)
+}
+
+func (e *PpobRcError) Unwrap() error {
+	return e.Err
+}
+
+var iakPrepaidRc = map[string]error{
+	"39":  ErrPpobPending,
+	"06":  ErrPpobFailed,
+	"07":  ErrPpobFailed,
+	"10":  ErrPpobLimitExceeded,
+	"12":  ErrPpobLimitExceeded,
+	"13":  ErrPpobCustomerNotFound,
+	"14":  ErrPpobCustomerNotFound,
+	"16":  ErrPpobInvalidData,
+	"17":  ErrPpobInsufficientDeposit,
+	"20":  ErrPpobProductUnavailable,
+	"106": ErrPpobProductUnavailable,
+	"121": ErrPpobLimitExceeded,
+	"202": ErrPpobLimitExceeded,
+	"203": ErrPpobInvalidData,
+	"206": ErrPpobCustomerNotFound,
+	"207": ErrPpobLimitExceeded,
+	"208": ErrPpobInvalidData,
+}
+
+var iakPostpaidRc = map[string]error{
+	"39":  ErrPpobPending,
+	"01":  ErrPpobBillPaid,
+	"34":  ErrPpobBillPaid,
+	"03":  ErrPpobInvalidData,
+	"04":  ErrPpobBillNotAvailable,
+	"06":  ErrPpobBillNotAvailable,
+	"07":  ErrPpobFailed,
+	"08":  ErrPpobCustomerNotFound,
+	"09":  ErrPpobFailed,
+	"10":  ErrPpobBillNotAvailable,
+	"11":  ErrPpobInvalidData,
+	"13":  ErrPpobCustomerNotFound,
+	"14":  ErrPpobCustomerNotFound,
+	"15":  ErrPpobInvalidData,
+	"16":  ErrPpobInvalidData,
+	"17":  ErrPpobInsufficientDeposit,
+	"20":  ErrPpobProductUnavailable,
+	"41":  ErrPpobInvalidData,
+	"106": ErrPpobProductUnavailable,
+}
+
+// IakPrepaidRcError mengembalikan nil untuk rc sukses, rc yang tidak dikenal dianggap error provider
+func IakPrepaidRcError(rc string, message string) error {
+	return toPpobRcError(iakPrepaidRc, rc, message)
+}
+
+func IakPostpaidRcError(rc string, message string) error {
+	return toPpobRcError(iakPostpaidRc, rc, message)
+}
+
+func toPpobRcError(rcMap map[string]error, rc string, message string) error {
+	if rc == "00" {
+		return nil
+	}
+	err, ok := rcMap[rc]
+	if !ok {
+		err = ErrPpobProvider
+	}
+	return &PpobRcError{Rc: rc, Message: message, Err: err}
+}
+
+// IsPpobTransactionError true jika error berasal dari status transaksi (bukan error koneksi/sistem provider)
+func IsPpobTransactionError(err error) bool {
+	rcErr := &PpobRcError{}
+	if !errors.As(err, &rcErr) {
+		return false
+	}
+	return !errors.Is(err, ErrPpobProvider) && !errors.Is(err, ErrPpobInsufficientDeposit)
+}
+
+func PanicIfPpobError(err error, requestId string, logger *logrus.Logger) {
+	if err == nil {
+		return
+	}
+	rcErr := &PpobRcError{}
+	if !errors.As(err, &rcErr) {
+		PanicIfError(err, requestId, logger)
+		return
+	}
+	switch {
+	case errors.Is(err, ErrPpobBillPaid):
+		PanicPPOBHandler(err, requestId, rcErr.Message, []string{"001"}, logger)
+	case errors.Is(err, ErrPpobCustomerNotFound):
+		PanicPPOBHandler(err, requestId, rcErr.Message, []string{"002"}, logger)
+	case errors.Is(err, ErrPpobBillNotAvailable):
+		PanicPPOBHandler(err, requestId, rcErr.Message, []string{"003"}, logger)
+	case errors.Is(err, ErrPpobInvalidData):
+		PanicIfBadRequest(err, requestId, []string{"INVALID DATA"}, logger)
+	case errors.Is(err, ErrPpobProductUnavailable):
+		PanicIfBadRequest(err, requestId, []string{"produk sedang tidak tersedia"}, logger)
+	case errors.Is(err, ErrPpobLimitExceeded):
+		PanicIfBadRequest(err, requestId, []string{"transaksi melebihi batas"}, logger)
+	default:
+		PanicIfError(err, requestId, logger)
+	}
+}
@@ -7,18 +7,24 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/cache"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/controller"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	authMiddlerware "github.com/tensuqiuwulu/be-service-bupda-bali/middleware"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	fcmrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/fcm_repository"
 	invelirepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/inveli_repository"
+	ppobrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/ppob_repository"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/routes"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/storage"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
 )
 
@@ -27,6 +33,7 @@ func main() {
 
 	// Database
 	DBConn := repository.NewDatabaseConnection(&appConfig.Database)
+	repository.EnsureProductSearchIndex(DBConn)
 
 	// Timezone
 	location, err := time.LoadLocation(appConfig.Timezone.Timezone)
@@ -54,35 +61,63 @@ func main() {
 		DisablePrintStack: true,
 	}))
 	e.HTTPErrorHandler = exceptions.ErrorHandler
+	// X-Forwarded-For hanya dipercaya dari proxy yang terdaftar, dipakai rate limit dan allowlist callback
+	e.IPExtractor = authMiddlerware.IPExtractor(appConfig.Webserver.TrustedProxies)
 	e.Use(middleware.RequestID())
 
+	// Cache
+	catalogCache := cache.NewCache(appConfig.Cache)
+	fileStorage := storage.NewStorage(appConfig.Storage)
+
 	// Repository
 	merchantRepository := repository.NewMerchantRepository(&appConfig.Database)
 	userRepository := repository.NewUserRepository(&appConfig.Database)
 	userProfileRepository := repository.NewUserProfileRepository(&appConfig.Database)
 	otpManagerRepository := repository.NewOtpManagerRepository(&appConfig.Database)
-	kecamatanRepository := repository.NewKecamatanRepository(&appConfig.Database)
-	kelurahanRepository := repository.NewKelurahanRepository(&appConfig.Database)
-	desaRepository := repository.NewDesaRepository(&appConfig.Database)
-	productDesaRepository := repository.NewProductDesaRepository(&appConfig.Database)
+	kecamatanRepository := repository.NewCachedKecamatanRepository(repository.NewKecamatanRepository(&appConfig.Database), catalogCache, appConfig.Cache)
+	kelurahanRepository := repository.NewCachedKelurahanRepository(repository.NewKelurahanRepository(&appConfig.Database), catalogCache, appConfig.Cache)
+	desaRepository := repository.NewCachedDesaRepository(repository.NewDesaRepository(&appConfig.Database), catalogCache, appConfig.Cache)
+	productDesaRepository := repository.NewCachedProductDesaRepository(repository.NewProductDesaRepository(&appConfig.Database), catalogCache, appConfig.Cache)
+	productMasterRepository := repository.NewCachedProductMasterRepository(repository.NewProductMasterRepository(&appConfig.Database), catalogCache)
 	cartRepository := repository.NewCartRepository(&appConfig.Database)
-	promoRepository := repository.NewPromoRepository(&appConfig.Database)
+	promoRepository := repository.NewCachedPromoRepository(repository.NewPromoRepository(&appConfig.Database), catalogCache, appConfig.Cache)
 	pointRepository := repository.NewPointRepository(&appConfig.Database)
 	orderRepository := repository.NewOrderRepository(&appConfig.Database)
 	orderItemRepository := repository.NewOrderItemRepository(&appConfig.Database)
 	orderItemPpobRepository := repository.NewOrderItemPpobRepository(&appConfig.Database)
 	paymentChannelRepository := repository.NewPaymentChannelRepository(&appConfig.Database)
 	productDesaStockRepository := repository.NewProductDesaStockHistoryRepository(&appConfig.Database)
-	settingRepository := repository.NewSettingRepository(&appConfig.Database)
+	productUnitRepository := repository.NewProductUnitRepository(&appConfig.Database)
+	orderItemPackageRepository := repository.NewOrderItemPackageRepository(&appConfig.Database)
+	productPriceTierRepository := repository.NewProductPriceTierRepository(&appConfig.Database)
+	voucherRepository := repository.NewVoucherRepository(&appConfig.Database)
+	pointHistoryRepository := repository.NewPointHistoryRepository(&appConfig.Database)
+	pointRuleRepository := repository.NewPointRuleRepository(&appConfig.Database)
+	settingRepository := repository.NewCachedSettingRepository(repository.NewSettingRepository(&appConfig.Database), catalogCache, appConfig.Cache)
 	userShippingAddressRepository := repository.NewUserShippingAddressRepository(&appConfig.Database)
 	operatorPrefixRepository := repository.NewOperatorPrefixRepository(&appConfig.Database)
 	ppobDetailRepository := repository.NewPpobDetailRepository(&appConfig.Database)
 	infoDesaRepository := repository.NewInfoDesaRepository(&appConfig.Database)
-	bannerRepository := repository.NewBannerRepository(&appConfig.Database)
+	bannerRepository := repository.NewCachedBannerRepository(repository.NewBannerRepository(&appConfig.Database), catalogCache, appConfig.Cache)
 	inveliAPIRepository := invelirepository.NewInveliAPIRepository()
+	ppobProvider := ppobrepository.NewPpobProvider(appConfig.Ppob, logrusLogger)
+	fcmProvider := fcmrepository.NewFcmProvider(appConfig.Fcm, logrusLogger)
 	listPinjamanRepository := repository.NewListPinjamanRepository(&appConfig.Database)
 	paymentHistoryRepository := repository.NewPaymentHistoryRepository(&appConfig.Database)
 	appVersionRepository := repository.NewAppVersionRepository(&appConfig.Database)
+	formTokenRepository := repository.NewFormTokenRepository(&appConfig.Database)
+	referralRepository := repository.NewReferralRepository(&appConfig.Database)
+	ppobPriceListRepository := repository.NewPpobPriceListRepository(&appConfig.Database)
+	ppobMarginRepository := repository.NewPpobMarginRepository(&appConfig.Database)
+	ppobProductTypeRepository := repository.NewPpobProductTypeRepository(&appConfig.Database)
+	ppobReconciliationRepository := repository.NewPpobReconciliationRepository(&appConfig.Database)
+	ppobCallbackLogRepository := repository.NewPpobCallbackLogRepository(&appConfig.Database)
+	orderStatusHistoryRepository := repository.NewOrderStatusHistoryRepository(&appConfig.Database)
+	transferUniqueAmountRepository := repository.NewTransferUniqueAmountRepository(&appConfig.Database)
+	bankMutationRepository := repository.NewBankMutationRepository(&appConfig.Database)
+	shippingZoneRepository := repository.NewShippingZoneRepository(&appConfig.Database)
+	userDeviceTokenRepository := repository.NewUserDeviceTokenRepository(&appConfig.Database)
+	notificationRepository := repository.NewNotificationRepository(&appConfig.Database)
 
 	// Service
 	listPinjamanService := service.NewListPinjamanService(
@@ -102,6 +137,8 @@ func main() {
 		logrusLogger,
 		userProfileRepository,
 		merchantRepository,
+		userRepository,
+		desaRepository,
 	)
 	infoDesaService := service.NewInfoDesaService(
 		DBConn,
@@ -132,10 +169,12 @@ func main() {
 	otpManagerService := service.NewOtpManagerService(
 		DBConn,
 		appConfig.Jwt,
+		appConfig.Otp,
 		validate,
 		logrusLogger,
 		otpManagerRepository,
 		userRepository,
+		formTokenRepository,
 	)
 	kecamatanService := service.NewKecamatanService(
 		DBConn,
@@ -155,6 +194,16 @@ func main() {
 		logrusLogger,
 		desaRepository,
 	)
+	referralService := service.NewReferralService(
+		DBConn,
+		appConfig.Privacy,
+		validate,
+		logrusLogger,
+		referralRepository,
+		userRepository,
+		userProfileRepository,
+		userDeviceTokenRepository,
+	)
 	userService := service.NewUserService(
 		DBConn,
 		validate,
@@ -166,6 +215,9 @@ func main() {
 		desaRepository,
 		inveliAPIRepository,
 		authService,
+		formTokenRepository,
+		otpManagerRepository,
+		referralService,
 	)
 	productDesaService := service.NewProductDesaService(
 		DBConn,
@@ -174,6 +226,26 @@ func main() {
 		productDesaRepository,
 		orderItemRepository,
 		productDesaStockRepository,
+		productUnitRepository,
+		productPriceTierRepository,
+	)
+	productAdminService := service.NewProductAdminService(
+		DBConn,
+		validate,
+		logrusLogger,
+		productMasterRepository,
+		productDesaRepository,
+		productDesaStockRepository,
+		productPriceTierRepository,
+		promoRepository,
+	)
+	shippingService := service.NewShippingService(
+		DBConn,
+		validate,
+		logrusLogger,
+		shippingZoneRepository,
+		desaRepository,
+		userShippingAddressRepository,
 	)
 	cartService := service.NewCartService(
 		DBConn,
@@ -183,6 +255,8 @@ func main() {
 		productDesaRepository,
 		settingRepository,
 		desaRepository,
+		productPriceTierRepository,
+		shippingService,
 	)
 	promoService := service.NewPromoService(
 		DBConn,
@@ -195,6 +269,13 @@ func main() {
 		validate,
 		logrusLogger,
 		pointRepository,
+		appConfig.Point,
+		pointHistoryRepository,
+		pointRuleRepository,
+		orderRepository,
+		orderItemRepository,
+		productDesaRepository,
+		referralRepository,
 	)
 	paymentService := service.NewPaymentService(
 		DBConn,
@@ -204,6 +285,32 @@ func main() {
 		orderRepository,
 		paymentHistoryRepository,
 	)
+	voucherService := service.NewVoucherService(
+		DBConn,
+		validate,
+		logrusLogger,
+		voucherRepository,
+	)
+	ppobPriceService := service.NewPpobPriceService(
+		DBConn,
+		validate,
+		logrusLogger,
+		ppobPriceListRepository,
+		ppobMarginRepository,
+		operatorPrefixRepository,
+		ppobProvider,
+		ppobProductTypeRepository,
+	)
+	notificationService := service.NewNotificationService(
+		DBConn,
+		validate,
+		logrusLogger,
+		appConfig.Fcm,
+		fcmProvider,
+		notificationRepository,
+		userDeviceTokenRepository,
+		orderRepository,
+	)
 	orderService := service.NewOrderService(
 		DBConn,
 		validate,
@@ -223,6 +330,51 @@ func main() {
 		inveliAPIRepository,
 		listPinjamanRepository,
 		userShippingAddressRepository,
+		orderItemPackageRepository,
+		voucherService,
+		pointService,
+		ppobProvider,
+		ppobPriceService,
+		ppobProductTypeRepository,
+		ppobCallbackLogRepository,
+		orderStatusHistoryRepository,
+		transferUniqueAmountRepository,
+		shippingService,
+		notificationService,
+	)
+	fulfilmentService := service.NewFulfilmentService(
+		DBConn,
+		validate,
+		logrusLogger,
+		appConfig.Role,
+		appConfig.Fulfilment,
+		orderRepository,
+		orderItemRepository,
+		orderStatusHistoryRepository,
+		userRepository,
+		orderService,
+		appConfig.Storage,
+		fileStorage,
+		notificationService,
+	)
+	uploadService := service.NewUploadService(
+		logrusLogger,
+		appConfig.Storage,
+		fileStorage,
+	)
+	transferVerificationService := service.NewTransferVerificationService(
+		DBConn,
+		validate,
+		logrusLogger,
+		appConfig.Storage,
+		fileStorage,
+		orderRepository,
+		transferUniqueAmountRepository,
+		bankMutationRepository,
+		desaRepository,
+		userRepository,
+		inveliAPIRepository,
+		orderService,
 	)
 	paymentChannelService := service.NewPaymentChannelService(
 		DBConn,
@@ -247,12 +399,43 @@ func main() {
 		logrusLogger,
 		userShippingAddressRepository,
 	)
+	privacyService := service.NewPrivacyService(
+		DBConn,
+		appConfig.Privacy,
+		logrusLogger,
+		userRepository,
+		userProfileRepository,
+		userShippingAddressRepository,
+		cartRepository,
+		pointRepository,
+		orderRepository,
+		orderItemRepository,
+		listPinjamanRepository,
+		paymentHistoryRepository,
+		userDeviceTokenRepository,
+		notificationRepository,
+	)
 	ppobService := service.NewPpobService(
 		DBConn,
 		validate,
 		logrusLogger,
 		operatorPrefixRepository,
 		orderService,
+		ppobProvider,
+		ppobPriceService,
+		ppobProductTypeRepository,
+	)
+	ppobReconcileService := service.NewPpobReconcileService(
+		DBConn,
+		appConfig.Ppob,
+		logrusLogger,
+		orderRepository,
+		orderItemPpobRepository,
+		ppobDetailRepository,
+		ppobProductTypeRepository,
+		ppobReconciliationRepository,
+		ppobProvider,
+		orderService,
 	)
 
 	// Controller
@@ -268,6 +451,10 @@ func main() {
 		logrusLogger,
 		bannerService,
 	)
+	voucherController := controller.NewVoucherController(
+		logrusLogger,
+		voucherService,
+	)
 	merchantController := controller.NewMerchantController(
 		logrusLogger,
 		merchantService,
@@ -300,6 +487,10 @@ func main() {
 		logrusLogger,
 		productDesaService,
 	)
+	productAdminController := controller.NewProductAdminController(
+		logrusLogger,
+		productAdminService,
+	)
 	cartController := controller.NewCartController(
 		logrusLogger,
 		cartService,
@@ -308,12 +499,47 @@ func main() {
 		promoService,
 	)
 	pointController := controller.NewPointController(
+		logrusLogger,
 		pointService,
 	)
+	ppobMarginController := controller.NewPpobMarginController(
+		logrusLogger,
+		ppobPriceService,
+	)
+	ppobReconciliationController := controller.NewPpobReconciliationController(
+		logrusLogger,
+		ppobReconcileService,
+	)
+	referralController := controller.NewReferralController(
+		logrusLogger,
+		referralService,
+	)
 	orderController := controller.NewOrderController(
 		logrusLogger,
 		orderService,
 	)
+	fulfilmentController := controller.NewFulfilmentController(
+		logrusLogger,
+		fulfilmentService,
+		uploadService,
+	)
+	uploadController := controller.NewUploadController(
+		logrusLogger,
+		uploadService,
+	)
+	transferVerificationController := controller.NewTransferVerificationController(
+		logrusLogger,
+		transferVerificationService,
+		uploadService,
+	)
+	shippingController := controller.NewShippingController(
+		logrusLogger,
+		shippingService,
+	)
+	notificationController := controller.NewNotificationController(
+		logrusLogger,
+		notificationService,
+	)
 	paymentChannelController := controller.NewPaymentChannelController(
 		paymentChannelService,
 	)
@@ -328,6 +554,9 @@ func main() {
 		logrusLogger,
 		ppobService,
 	)
+	privacyController := controller.NewPrivacyController(
+		privacyService,
+	)
 	testingInveliController := controller.NewInveliTestingController(
 		logrusLogger,
 	)
@@ -336,7 +565,8 @@ func main() {
 	routes.ListPinjamanRoute(e, appConfig.Jwt, listPinjamanController)
 	routes.PaylaterRoute(e, appConfig.Jwt, paylaterController)
 	routes.BannerRoute(e, appConfig.Jwt, bannerController)
-	routes.MerchantRoute(e, appConfig.Jwt, merchantController)
+	routes.MerchantRoute(e, appConfig.Jwt, appConfig.Role, merchantController)
+	routes.VoucherRoute(e, appConfig.Jwt, appConfig.Role, voucherController)
 	routes.InfoDesaRoute(e, appConfig.Jwt, infoDesaController)
 	routes.AuthRoute(e, authController)
 	routes.OtpManagerRoute(e, otpManagerController)
@@ -345,17 +575,42 @@ func main() {
 	routes.DesaRoute(e, desaController)
 	routes.UserRoute(e, appConfig.Jwt, userController)
 	routes.ProductDesaRoute(e, appConfig.Jwt, productDesaController)
+	routes.ProductAdminRoute(e, appConfig.Jwt, appConfig.Role, productAdminController)
 	routes.CartRoute(e, appConfig.Jwt, cartController)
 	routes.PromoRoute(e, appConfig.Jwt, promoController)
-	routes.PointRoute(e, appConfig.Jwt, pointController)
-	routes.OrderRoute(e, appConfig.Jwt, orderController)
+	routes.PointRoute(e, appConfig.Jwt, appConfig.Role, pointController)
+	routes.ReferralRoute(e, appConfig.Jwt, referralController)
+	routes.PpobMarginRoute(e, appConfig.Jwt, appConfig.Role, ppobMarginController)
+	routes.PpobReconciliationRoute(e, appConfig.Jwt, appConfig.Role, ppobReconciliationController)
+	routes.OrderRoute(e, appConfig.Jwt, appConfig.Ppob, orderController)
+	routes.FulfilmentRoute(e, appConfig.Jwt, appConfig.Role, fulfilmentController)
+	routes.UploadRoute(e, appConfig.Jwt, appConfig.Role, appConfig.Storage, uploadController)
+	routes.TransferVerificationRoute(e, appConfig.Jwt, appConfig.Role, transferVerificationController)
+	routes.ShippingRoute(e, appConfig.Jwt, appConfig.Role, shippingController)
+	routes.NotificationRoute(e, appConfig.Jwt, notificationController)
 	routes.PaymentChannelRoute(e, appConfig.Jwt, paymentChannelController)
 	routes.SettingRoute(e, appConfig.Jwt, settingController)
 	routes.UserShippingAddressRoute(e, appConfig.Jwt, userShippingAddressController)
 	routes.PpobRoute(e, appConfig.Jwt, ppobController)
+	routes.PrivacyRoute(e, appConfig.Jwt, privacyController)
 	routes.InveliTestRoutes(e, testingInveliController)
 	routes.MainRoute(e)
 
+	// Scheduler, dihentikan saat shutdown sebelum koneksi database ditutup
+	stopScheduler := make(chan struct{})
+	var scheduler sync.WaitGroup
+	schedule(&scheduler, stopScheduler, time.Hour, func() {
+		orderService.CancelExpiredTransferOrders()
+		privacyService.AnonymizeDeletedUsers()
+		pointService.ExpirePoints()
+		ppobPriceService.SyncPrepaidPriceList()
+		ppobReconcileService.CreateDailyPpobReconciliation()
+		fulfilmentService.AutoCompleteDeliveredOrders()
+		notificationService.RemindPaylaterDue()
+	})
+	schedule(&scheduler, stopScheduler, 5*time.Minute, ppobReconcileService.ReconcilePendingPpobOrders)
+	schedule(&scheduler, stopScheduler, time.Minute, promoService.SyncPromoSchedule)
+
 	// Careful shutdown
 	go func() {
 		if err := e.Start(":" + strconv.Itoa(int(appConfig.Webserver.Port))); err != nil && err != http.ErrServerClosed {
@@ -374,6 +629,10 @@ func main() {
 		e.Logger.Fatal(err)
 	}
 
+	// Tunggu job yang sedang berjalan selesai
+	close(stopScheduler)
+	scheduler.Wait()
+
 	fmt.Println("Running cleanup tasks...")
 
 	// Your cleanup tasks go here
@@ -382,3 +641,21 @@ func main() {
 	fmt.Println("Echo was successful shutdown.")
 
 }
+
+// schedule menjalankan job setiap interval sampai stop ditutup
+func schedule(wg *sync.WaitGroup, stop <-chan struct{}, interval time.Duration, job func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				job()
+			}
+		}
+	}()
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type etagResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (writer *etagResponseWriter) WriteHeader(status int) {
+	writer.status = status
+}
+
+func (writer *etagResponseWriter) Write(data []byte) (int, error) {
+	return writer.body.Write(data)
+}
+
+// ETag menambahkan header ETag pada response GET 200 dan membalas 304
+// jika If-None-Match sama, dipasang sebelum Timeout
+func ETag() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			originalWriter := c.Response().Writer
+			writer := &etagResponseWriter{ResponseWriter: originalWriter, status: http.StatusOK}
+			c.Response().Writer = writer
+			defer func() {
+				c.Response().Writer = originalWriter
+			}()
+
+			if err := next(c); err != nil {
+				return err
+			}
+
+			if writer.status != http.StatusOK {
+				originalWriter.WriteHeader(writer.status)
+				_, err := originalWriter.Write(writer.body.Bytes())
+				return err
+			}
+
+			sum := sha256.Sum256(writer.body.Bytes())
+			etag := "\"" + hex.EncodeToString(sum[:16]) + "\""
+			originalWriter.Header().Set("ETag", etag)
+			if etagMatch(c.Request().Header.Get("If-None-Match"), etag) {
+				originalWriter.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+
+			originalWriter.WriteHeader(http.StatusOK)
+			_, err := originalWriter.Write(writer.body.Bytes())
+			return err
+		}
+	}
+}
+
+func etagMatch(ifNoneMatch string, etag string) bool {
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == etag || value == "*" {
+			return true
+		}
+	}
+	return false
+}
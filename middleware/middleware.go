@@ -2,13 +2,16 @@ package middleware
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	modelService "github.com/tensuqiuwulu/be-service-bupda-bali/model/service"
 )
 
@@ -20,6 +23,64 @@ func Authentication(configurationJWT config.Jwt) echo.MiddlewareFunc {
 	})
 }
 
+// Authorization membatasi route untuk role tertentu, dipasang setelah Authentication
+func Authorization(idRoles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idRole := TokenClaimsIdRole(c)
+			for _, role := range idRoles {
+				if len(role) != 0 && role == idRole {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, response.Response{Code: 403, Mssg: "Forbidden", Data: []string{}, Error: []string{"forbidden"}})
+		}
+	}
+}
+
+// IpAllowlist membatasi route untuk ip tertentu, list kosong berarti semua ip ditolak
+func IpAllowlist(ips []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			remoteIp := c.RealIP()
+			for _, ip := range ips {
+				if ip == remoteIp {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, response.Response{Code: 403, Mssg: "Forbidden", Data: []string{}, Error: []string{"forbidden"}})
+		}
+	}
+}
+
+// IPExtractor X-Forwarded-For hanya dipercaya dari proxy yang terdaftar, tanpa proxy ip diambil dari koneksi langsung
+func IPExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	trustOptions := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, trustedProxy := range trustedProxies {
+		if !strings.Contains(trustedProxy, "/") {
+			if strings.Contains(trustedProxy, ":") {
+				trustedProxy = trustedProxy + "/128"
+			} else {
+				trustedProxy = trustedProxy + "/32"
+			}
+		}
+		_, ipRange, err := net.ParseCIDR(trustedProxy)
+		if err != nil {
+			panic(fmt.Sprintf("invalid trusted proxy %s: %v", trustedProxy, err))
+		}
+		trustOptions = append(trustOptions, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(trustOptions...)
+}
+
 func RateLimit() echo.MiddlewareFunc {
 	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
 		Skipper: middleware.DefaultSkipper,
@@ -71,3 +132,10 @@ func TokenClaimsAccountType(c echo.Context) (id int) {
 	AccountType := claims.AccountType
 	return AccountType
 }
+
+func TokenClaimsIdRole(c echo.Context) (id string) {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(*modelService.TokenClaims)
+	IdRole := claims.IdRole
+	return IdRole
+}
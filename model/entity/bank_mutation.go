@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// BankMutation mutasi masuk rekening desa dari export csv bank atau inveli,
+// MutationRef unik per desa dan source supaya import ulang tidak dobel
+type BankMutation struct {
+	Id              string    `gorm:"primaryKey;column:id;"`
+	IdDesa          string    `gorm:"column:id_desa;"`
+	Source          string    `gorm:"column:source;"`
+	MutationRef     string    `gorm:"column:mutation_ref;"`
+	TransactionDate string    `gorm:"column:transaction_date;"`
+	Description     string    `gorm:"column:description;"`
+	Amount          float64   `gorm:"column:amount;"`
+	IdOrder         string    `gorm:"column:id_order;"`
+	Status          int       `gorm:"column:status;"` // 0 tidak ada order cocok, 1 cocok dan order dibayar
+	CreatedAt       time.Time `gorm:"column:created_at;"`
+}
+
+func (BankMutation) TableName() string {
+	return "bank_mutation"
+}
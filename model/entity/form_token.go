@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+type FormToken struct {
+	Id           string    `gorm:"primaryKey;column:id;"`
+	IdOtpManager string    `gorm:"column:id_otp_manager;"`
+	Phone        string    `gorm:"column:phone;"`
+	TypeOtp      int       `gorm:"column:type_otp;"`
+	ExpiredAt    time.Time `gorm:"column:expired_at;"`
+	UsedAt       null.Time `gorm:"column:used_at;"`
+	CreatedDate  time.Time `gorm:"column:created_at;"`
+}
+
+func (FormToken) TableName() string {
+	return "form_token"
+}
@@ -13,6 +13,9 @@ type MerchantApproveList struct {
 	NamaLengkap   string    `gorm:"column:nama_lengkap;"`
 	ApproveStatus int       `gorm:"column:approve_status;"`
 	MerchantName  string    `gorm:"column:merchant_name;"`
+	RejectReason  string    `gorm:"column:reject_reason;"`
+	ReviewedBy    string    `gorm:"column:reviewed_by;"`
+	ReviewedAt    null.Time `gorm:"column:reviewed_at;"`
 	CreatedAt     time.Time `gorm:"column:created_at;"`
 	UpdatedAt     null.Time `gorm:"column:updated_at;"`
 }
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Notification inbox notifikasi user, setiap push juga disimpan di sini
+type Notification struct {
+	Id          string    `gorm:"primaryKey;column:id;"`
+	IdUser      string    `gorm:"column:id_user;"`
+	Type        string    `gorm:"column:type;"`
+	Title       string    `gorm:"column:title;"`
+	Body        string    `gorm:"column:body;"`
+	IdReference string    `gorm:"column:id_reference;"`
+	IsRead      int       `gorm:"column:is_read;"`
+	ReadAt      null.Time `gorm:"column:read_at;"`
+	CreatedAt   time.Time `gorm:"column:created_at;"`
+}
+
+func (Notification) TableName() string {
+	return "notification"
+}
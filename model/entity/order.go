@@ -24,6 +24,10 @@ type Order struct {
 	PaymentPoint        float64   `gorm:"column:payment_point;"`
 	PaymentFee          float64   `gorm:"column:payment_fee;"`
 	SubTotal            float64   `gorm:"column:sub_total;"`
+	IdVoucher           string    `gorm:"column:id_voucher;"`
+	VoucherCode         string    `gorm:"column:voucher_code;"`
+	Discount            float64   `gorm:"column:discount;"`
+	ShippingDiscount    float64   `gorm:"column:shipping_discount;"`
 	TotalBill           float64   `gorm:"column:total_bill;"`
 	PaymentMethod       string    `gorm:"column:payment_method;"`
 	PaymentChannel      string    `gorm:"column:payment_channel;"`
@@ -39,6 +43,12 @@ type Order struct {
 	OrderCanceledDate   null.Time `gorm:"column:order_cancel_date;"`
 	FotoBarangSampai    string    `gorm:"column:foto_barang_sampai;"`
 	FotoBuktiBayar      string    `gorm:"column:foto_bukti_bayar;"`
+	BuktiBayarDate      null.Time `gorm:"column:bukti_bayar_date;"`
+	BuktiBayarNote      string    `gorm:"column:bukti_bayar_note;"`
+	VerifiedBy          string    `gorm:"column:verified_by;"`
+	IdCourier           string    `gorm:"column:id_courier;"`
+	DeliveredDate       null.Time `gorm:"column:delivered_date;"`
+	CodCollected        float64   `gorm:"column:cod_collected;"`
 	RefId               string    `gorm:"column:ref_id;"`
 	PaylaterPaidStatus  int       `gorm:"column:paylater_paid_status;"`
 	Longitude           float64   `gorm:"column:longitude;"`
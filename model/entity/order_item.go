@@ -20,6 +20,8 @@ type OrderItem struct {
 	Price              float64   `gorm:"column:price;"`
 	PriceAfterDiscount float64   `gorm:"column:price_after_discount;"`
 	TotalPrice         float64   `gorm:"column:total_price;"`
+	IdPriceTier        string    `gorm:"column:id_price_tier;"`
+	TierMinQty         int       `gorm:"column:tier_min_qty;"`
 	CreatedAt          time.Time `gorm:"column:created_at;"`
 }
 
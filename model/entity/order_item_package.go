@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// OrderItemPackage rincian komponen dari order item berupa paket
+type OrderItemPackage struct {
+	Id            string    `gorm:"primaryKey;column:id;"`
+	IdOrder       string    `gorm:"column:id_order;"`
+	IdOrderItem   string    `gorm:"column:id_order_item;"`
+	IdProductDesa string    `gorm:"column:id_product_desa;"`
+	NoSku         string    `gorm:"column:no_sku;"`
+	ProductName   string    `gorm:"column:product_name;"`
+	QtyPerPackage int       `gorm:"column:qty_per_package;"`
+	Qty           int       `gorm:"column:qty;"`
+	Price         float64   `gorm:"column:price;"`
+	Weight        float64   `gorm:"column:weight;"`
+	Volume        float64   `gorm:"column:volume;"`
+	CreatedAt     time.Time `gorm:"column:created_at;"`
+}
+
+func (OrderItemPackage) TableName() string {
+	return "orders_items_package"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// OrderStatusHistory riwayat perubahan status order sembako untuk tracking customer,
+// IdUser adalah user yang melakukan perubahan (admin, kurir atau customer)
+type OrderStatusHistory struct {
+	Id          string    `gorm:"primaryKey;column:id;"`
+	IdOrder     string    `gorm:"column:id_order;"`
+	IdUser      string    `gorm:"column:id_user;"`
+	OrderStatus int       `gorm:"column:order_status;"`
+	Note        string    `gorm:"column:note;"`
+	CreatedAt   time.Time `gorm:"column:created_at;"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "orders_status_history"
+}
@@ -11,6 +11,7 @@ type OtpManager struct {
 	IpAddress      string    `gorm:"column:ip_address;"`
 	Phone          string    `gorm:"column:phone;"`
 	OtpCode        string    `gorm:"column:otp_code;"`
+	TypeOtp        int       `gorm:"column:type_otp;"`
 	OtpExperiedAt  time.Time `gorm:"column:otp_experied_at;"`
 	PhoneLimit     int       `gorm:"column:phone_limit;"`
 	IpAddressLimit int       `gorm:"column:ip_address_limit;"`
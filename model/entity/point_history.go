@@ -6,14 +6,21 @@ import (
 	"gopkg.in/guregu/null.v4"
 )
 
+// Kredit menambah point dan Debit mengurangi point. Remaining adalah sisa point kredit
+// yang belum terpakai, dipakai untuk redeem FIFO dan hangus setelah ExpiredDate.
+// EntryType jenis mutasi (lihat PointEntry* di service), 0 untuk data lama.
 type PointHistory struct {
 	Id          string    `gorm:"primaryKey;column:id;"`
 	IdPoint     string    `gorm:"column:id_point;"`
 	IdUser      string    `gorm:"column:id_user;"`
+	IdOrder     string    `gorm:"column:id_order;"`
 	Debit       float64   `gorm:"column:debit;"`
 	Kredit      float64   `gorm:"column:kredit;"`
+	Remaining   float64   `gorm:"column:remaining;"`
 	Description string    `gorm:"column:description;"`
+	EntryType   int       `gorm:"column:entry_type;"`
 	TransDate   time.Time `gorm:"column:trans_date;"`
+	ExpiredDate null.Time `gorm:"column:expired_date;"`
 	CreatedDate time.Time `gorm:"column:created_at;"`
 	UpdateDate  null.Time `gorm:"column:updated_at;"`
 }
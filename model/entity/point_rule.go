@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// RuleType 1 setiap kelipatan MinAmount belanja, 2 setiap qty IdProductDesa, 3 order pertama,
+// 4 reward referrer dan 5 reward referee saat referee menyelesaikan order pertama
+type PointRule struct {
+	Id            string    `gorm:"primaryKey;column:id;"`
+	IdDesa        string    `gorm:"column:id_desa;"`
+	RuleType      int       `gorm:"column:rule_type;"`
+	IdProductDesa string    `gorm:"column:id_product_desa;"`
+	MinAmount     float64   `gorm:"column:min_amount;"`
+	Point         float64   `gorm:"column:point;"`
+	Description   string    `gorm:"column:description;"`
+	IsActive      int       `gorm:"column:is_active;"`
+	CreatedAt     time.Time `gorm:"column:created_at;"`
+	UpdatedAt     null.Time `gorm:"column:updated_at;"`
+}
+
+func (PointRule) TableName() string {
+	return "points_rule"
+}
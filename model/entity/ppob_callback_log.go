@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+// PpobCallbackLog payload mentah setiap callback ppob beserta hasil prosesnya untuk audit.
+// Outcome: processed, duplicate atau rejected
+type PpobCallbackLog struct {
+	Id        string    `gorm:"primaryKey;column:id;"`
+	RefId     string    `gorm:"column:ref_id;"`
+	Status    string    `gorm:"column:status;"`
+	RemoteIp  string    `gorm:"column:remote_ip;"`
+	Payload   string    `gorm:"column:payload;"`
+	Outcome   string    `gorm:"column:outcome;"`
+	Message   string    `gorm:"column:message;"`
+	CreatedAt time.Time `gorm:"column:created_at;"`
+}
+
+func (PpobCallbackLog) TableName() string {
+	return "ppob_callback_log"
+}
@@ -121,3 +121,24 @@ type PpobDetailPrepaidPln struct {
 func (PpobDetailPrepaidPln) TableName() string {
 	return "ppob_detail_prepaid_pln"
 }
+
+// PpobDetailGeneric detail order untuk tipe produk ppob yang diatur lewat ppob_product_type,
+// rincian tagihan ada di OrderItemPpob.BillDetail
+type PpobDetailGeneric struct {
+	Id                  string        `gorm:"primaryKey;column:id;"`
+	IdOrderItemPpob     string        `gorm:"column:id_order_item_ppob;"`
+	OrderItemPpob       OrderItemPpob `gorm:"foreignKey:IdOrderItemPpob;"`
+	CustomerId          string        `gorm:"column:customer_id;"`
+	CustomerName        string        `gorm:"column:customer_name;"`
+	Period              string        `gorm:"column:period;"`
+	Sn                  string        `gorm:"column:sn;"`
+	StatusTopUp         int           `gorm:"column:status_topup;"`
+	LastBalance         float64       `gorm:"column:last_balance;"`
+	TopupProccesingDate null.Time     `gorm:"column:topup_proccesing_date;"`
+	TopupSuccessDate    null.Time     `gorm:"column:topup_success_date;"`
+	TopupFailedDate     null.Time     `gorm:"column:topup_failed_date;"`
+}
+
+func (PpobDetailGeneric) TableName() string {
+	return "ppob_detail_generic"
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// MarginType 1 flat (rupiah), 2 persen dari harga modal.
+// ProductType dan Operator kosong berarti berlaku untuk semua
+type PpobMargin struct {
+	Id          string    `gorm:"primaryKey;column:id;"`
+	IdDesa      string    `gorm:"column:id_desa;"`
+	ProductType string    `gorm:"column:product_type;"`
+	Operator    string    `gorm:"column:operator;"`
+	MarginType  int       `gorm:"column:margin_type;"`
+	Margin      float64   `gorm:"column:margin;"`
+	IsActive    int       `gorm:"column:is_active;"`
+	CreatedAt   time.Time `gorm:"column:created_at;"`
+	UpdatedAt   null.Time `gorm:"column:updated_at;"`
+}
+
+func (PpobMargin) TableName() string {
+	return "ppob_margin"
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// PpobPriceList salinan lokal pricelist prepaid provider, ProductPrice harga modal dari provider
+type PpobPriceList struct {
+	Id                 string    `gorm:"primaryKey;column:id;"`
+	ProductCode        string    `gorm:"column:product_code;"`
+	ProductType        string    `gorm:"column:product_type;"`
+	Operator           string    `gorm:"column:operator;"`
+	ProductDescription string    `gorm:"column:product_description;"`
+	ProductNominal     string    `gorm:"column:product_nominal;"`
+	ProductDetails     string    `gorm:"column:product_details;"`
+	ProductPrice       float64   `gorm:"column:product_price;"`
+	ActivePeriod       string    `gorm:"column:active_period;"`
+	Status             string    `gorm:"column:status;"`
+	IconUrl            string    `gorm:"column:icon_url;"`
+	IsActive           int       `gorm:"column:is_active;"`
+	SyncedAt           time.Time `gorm:"column:synced_at;"`
+	CreatedAt          time.Time `gorm:"column:created_at;"`
+	UpdatedAt          null.Time `gorm:"column:updated_at;"`
+}
+
+func (PpobPriceList) TableName() string {
+	return "ppob_pricelist"
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// PpobProductType konfigurasi kategori ppob generic (bpjs, e-money, voucher game, multifinance).
+// Category "prepaid" memakai pricelist prepaid ProviderType/ProviderOperator,
+// "postpaid" memakai pricelist pascabayar ProviderType lalu inquiry sebelum order.
+// InquiryParams daftar parameter tambahan inquiry dipisah koma, mis. "month" untuk bpjs
+type PpobProductType struct {
+	Id               string    `gorm:"primaryKey;column:id;"`
+	Code             string    `gorm:"column:code;"`
+	Name             string    `gorm:"column:name;"`
+	Category         string    `gorm:"column:category;"`
+	ProviderType     string    `gorm:"column:provider_type;"`
+	ProviderOperator string    `gorm:"column:provider_operator;"`
+	CustomerIdLabel  string    `gorm:"column:customer_id_label;"`
+	InquiryParams    string    `gorm:"column:inquiry_params;"`
+	IconUrl          string    `gorm:"column:icon_url;"`
+	SortOrder        int       `gorm:"column:sort_order;"`
+	IsActive         int       `gorm:"column:is_active;"`
+	CreatedAt        time.Time `gorm:"column:created_at;"`
+	UpdatedAt        null.Time `gorm:"column:updated_at;"`
+}
+
+func (PpobProductType) TableName() string {
+	return "ppob_product_type"
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+)
+
+// PpobReconciliation laporan harian perbandingan order ppob dengan status transaksi di provider.
+// DetailJson berisi daftar order yang tidak cocok
+type PpobReconciliation struct {
+	Id                string    `gorm:"primaryKey;column:id;"`
+	ReconcileDate     time.Time `gorm:"column:reconcile_date;"`
+	TotalOrder        int       `gorm:"column:total_order;"`
+	TotalMatch        int       `gorm:"column:total_match;"`
+	TotalMismatch     int       `gorm:"column:total_mismatch;"`
+	TotalPending      int       `gorm:"column:total_pending;"`
+	TotalAmount       float64   `gorm:"column:total_amount;"`
+	ProviderAmount    float64   `gorm:"column:provider_amount;"`
+	LastBalance       float64   `gorm:"column:last_balance;"`
+	ProviderBalance   float64   `gorm:"column:provider_balance;"`
+	BalanceDifference float64   `gorm:"column:balance_difference;"`
+	DetailJson        string    `gorm:"column:detail_json;"`
+	CreatedAt         time.Time `gorm:"column:created_at;"`
+}
+
+func (PpobReconciliation) TableName() string {
+	return "ppob_reconciliation"
+}
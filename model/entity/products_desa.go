@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type ProductsDesa struct {
 	Id              string         `gorm:"primaryKey;column:id;"`
 	IdProduct       string         `gorm:"column:id_product;"`
@@ -16,6 +18,7 @@ type ProductsDesa struct {
 	PictureUrl      string         `gorm:"column:picture_url;"`
 	Thumbnail       string         `gorm:"column:thumbnail;"`
 	Description     string         `gorm:"column:description;"`
+	CreatedDate     time.Time      `gorm:"column:created_at;"`
 }
 
 func (ProductsDesa) TableName() string {
@@ -23,14 +26,15 @@ func (ProductsDesa) TableName() string {
 }
 
 type ProductsPackageItems struct {
-	Id                  string  `gorm:"primaryKey;column:id;"`
-	IdProductPackgeDesa string  `gorm:"column:id_product_primary;"`
-	IdProductItem       string  `gorm:"column:id_product_item;"`
-	NoSku               string  `gorm:"column:no_sku;"`
-	ProductName         string  `gorm:"column:product_name;"`
-	Price               float64 `gorm:"column:price;"`
-	Qty                 int     `gorm:"column:qty;"`
-	SubTotal            float64 `gorm:"column:sub_total;"`
+	Id                  string       `gorm:"primaryKey;column:id;"`
+	IdProductPackgeDesa string       `gorm:"column:id_product_primary;"`
+	IdProductItem       string       `gorm:"column:id_product_item;"`
+	ProductsDesa        ProductsDesa `gorm:"foreignKey:IdProductItem;"`
+	NoSku               string       `gorm:"column:no_sku;"`
+	ProductName         string       `gorm:"column:product_name;"`
+	Price               float64      `gorm:"column:price;"`
+	Qty                 int          `gorm:"column:qty;"`
+	SubTotal            float64      `gorm:"column:sub_total;"`
 }
 
 func (ProductsPackageItems) TableName() string {
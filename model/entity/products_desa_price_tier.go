@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// ProductsDesaPriceTier harga per satuan mulai dari MinQty untuk tipe akun tertentu
+type ProductsDesaPriceTier struct {
+	Id            string    `gorm:"primaryKey;column:id;"`
+	IdProductDesa string    `gorm:"column:id_product_desa;"`
+	AccountType   int       `gorm:"column:account_type;"`
+	MinQty        int       `gorm:"column:min_qty;"`
+	Price         float64   `gorm:"column:price;"`
+	CreatedDate   time.Time `gorm:"column:created_at;"`
+}
+
+func (ProductsDesaPriceTier) TableName() string {
+	return "products_desa_price_tier"
+}
@@ -1,16 +1,18 @@
 package entity
 
+// idx_products_master_search index FULLTEXT untuk pencarian produk, dibuat saat start oleh repository.EnsureProductSearchIndex
 type ProductsMaster struct {
 	Id            string  `gorm:"primaryKey;column:id;"`
 	IdBrand       int     `gorm:"column:id_brand;"`
 	IdCategory    int     `gorm:"column:id_category;"`
 	IdSubCategory int     `gorm:"column:id_sub_category;"`
 	IdUnit        int     `gorm:"column:id_unit;"`
-	NoSku         string  `gorm:"column:no_sku;"`
-	ProductName   string  `gorm:"column:product_name;"`
+	UnitValue     float64 `gorm:"column:unit_value;"`
+	NoSku         string  `gorm:"column:no_sku;index:idx_products_master_search,class:FULLTEXT;"`
+	ProductName   string  `gorm:"column:product_name;index:idx_products_master_search,class:FULLTEXT;"`
 	Price         float64 `gorm:"column:price;"`
 	PriceGrosir   float64 `gorm:"column:price_grosir;"`
-	Description   string  `gorm:"column:description;"`
+	Description   string  `gorm:"column:description;index:idx_products_master_search,class:FULLTEXT;"`
 	PictureUrl    string  `gorm:"column:picture_url;"`
 	Thumbnail     string  `gorm:"column:thumbnail;"`
 }
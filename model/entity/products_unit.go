@@ -0,0 +1,13 @@
+package entity
+
+// UnitType 1 berat (kg), 2 volume (liter), 0 satuan lain
+type ProductsUnit struct {
+	Id         int     `gorm:"primaryKey;column:id;"`
+	UnitName   string  `gorm:"column:unit_name;"`
+	UnitType   int     `gorm:"column:unit_type;"`
+	Conversion float64 `gorm:"column:conversion;"`
+}
+
+func (ProductsUnit) TableName() string {
+	return "products_unit"
+}
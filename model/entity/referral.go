@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Status 1 menunggu order pertama, 2 sudah diberi reward, 3 ditolak
+type Referral struct {
+	Id            string    `gorm:"primaryKey;column:id;"`
+	IdReferrer    string    `gorm:"column:id_referrer;"`
+	IdReferee     string    `gorm:"column:id_referee;"`
+	ReferralCode  string    `gorm:"column:referral_code;"`
+	NoIdentitas   string    `gorm:"column:no_identitas;"`
+	DeviceId      string    `gorm:"column:device_id;"`
+	Status        int       `gorm:"column:status;"`
+	RejectReason  string    `gorm:"column:reject_reason;"`
+	ReferrerPoint float64   `gorm:"column:referrer_point;"`
+	RefereePoint  float64   `gorm:"column:referee_point;"`
+	CreatedAt     time.Time `gorm:"column:created_at;"`
+	RewardedAt    null.Time `gorm:"column:rewarded_at;"`
+}
+
+func (Referral) TableName() string {
+	return "users_referral"
+}
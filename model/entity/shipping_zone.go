@@ -0,0 +1,17 @@
+package entity
+
+// ShippingZone lokasi depot BUPDA dan tarif ongkir per desa. Desa tanpa shipping zone
+// memakai ongkir flat Desa.Ongkir.
+type ShippingZone struct {
+	IdDesa         string  `gorm:"primaryKey;column:id_desa;"`
+	DepotLatitude  float64 `gorm:"column:depot_latitude;"`
+	DepotLongitude float64 `gorm:"column:depot_longitude;"`
+	FreeKm         float64 `gorm:"column:free_km;"`     // jarak gratis ongkir
+	BaseCost       float64 `gorm:"column:base_cost;"`   // biaya awal setelah lewat jarak gratis
+	RatePerKm      float64 `gorm:"column:rate_per_km;"` // per km (dibulatkan ke atas) setelah jarak gratis
+	MaxKm          float64 `gorm:"column:max_km;"`      // radius layanan, 0 = tanpa batas
+}
+
+func (ShippingZone) TableName() string {
+	return "shipping_zone"
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// TransferUniqueAmount reservasi nominal unik transfer per desa. Primary key (id_desa, amount)
+// menjamin tidak ada dua order terbuka di satu desa dengan nominal transfer yang sama.
+type TransferUniqueAmount struct {
+	IdDesa    string    `gorm:"primaryKey;column:id_desa;"`
+	Amount    float64   `gorm:"primaryKey;column:amount;"`
+	IdOrder   string    `gorm:"column:id_order;"`
+	ExpiredAt time.Time `gorm:"column:expired_at;"`
+	CreatedAt time.Time `gorm:"column:created_at;"`
+}
+
+func (TransferUniqueAmount) TableName() string {
+	return "transfer_unique_amount"
+}
@@ -19,6 +19,7 @@ type User struct {
 	AccountType          int       `gorm:"column:account_type;"`
 	StatusSurvey         int       `gorm:"column:status_survey;"`
 	MerchantCode         string    `gorm:"column:merchant_code;"`
+	ReferralCode         string    `gorm:"column:referral_code;"`
 	TokenDevice          string    `gorm:"column:token_device;"`
 	IsActive             int       `gorm:"column:is_active;"`
 	IsDelete             int       `gorm:"column:is_delete;"`
@@ -31,6 +32,7 @@ type User struct {
 	CreatedDate          time.Time `gorm:"column:created_at;"`
 	PaylaterApprovalDate null.Time `gorm:"column:paylater_approval_date;"`
 	ActivationDate       null.Time `gorm:"column:activation_date;"`
+	AnonymizedDate       null.Time `gorm:"column:anonymized_at;"`
 }
 
 func (User) TableName() string {
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// UserDeviceToken token FCM per perangkat login, Token unik sehingga perangkat
+// yang dipakai user lain berpindah pemilik
+type UserDeviceToken struct {
+	Id        string    `gorm:"primaryKey;column:id;"`
+	IdUser    string    `gorm:"column:id_user;"`
+	Token     string    `gorm:"column:token;"`
+	Platform  string    `gorm:"column:platform;"`
+	DeviceId  string    `gorm:"column:device_id;"`
+	CreatedAt time.Time `gorm:"column:created_at;"`
+	UpdatedAt time.Time `gorm:"column:updated_at;"`
+}
+
+func (UserDeviceToken) TableName() string {
+	return "user_device_token"
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// DiscountType 1 persen dari sub total, 2 potongan nominal, 3 gratis ongkir.
+// IdDesa kosong berarti voucher berlaku di semua desa, quota 0 berarti tanpa batas.
+type Voucher struct {
+	Id            string    `gorm:"primaryKey;column:id;"`
+	IdDesa        string    `gorm:"column:id_desa;"`
+	Code          string    `gorm:"column:code;"`
+	Title         string    `gorm:"column:title;"`
+	Description   string    `gorm:"column:description;"`
+	DiscountType  int       `gorm:"column:discount_type;"`
+	DiscountValue float64   `gorm:"column:discount_value;"`
+	MaxDiscount   float64   `gorm:"column:max_discount;"`
+	MinSpend      float64   `gorm:"column:min_spend;"`
+	QuotaTotal    int       `gorm:"column:quota_total;"`
+	QuotaPerUser  int       `gorm:"column:quota_per_user;"`
+	UsedCount     int       `gorm:"column:used_count;"`
+	StartDate     time.Time `gorm:"column:start_date;"`
+	EndDate       time.Time `gorm:"column:end_date;"`
+	IsActive      int       `gorm:"column:is_active;"`
+	CreatedAt     time.Time `gorm:"column:created_at;"`
+	UpdatedAt     null.Time `gorm:"column:updated_at;"`
+}
+
+func (Voucher) TableName() string {
+	return "voucher"
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type VoucherUsage struct {
+	Id               string    `gorm:"primaryKey;column:id;"`
+	IdVoucher        string    `gorm:"column:id_voucher;"`
+	IdUser           string    `gorm:"column:id_user;"`
+	IdOrder          string    `gorm:"column:id_order;"`
+	Discount         float64   `gorm:"column:discount;"`
+	ShippingDiscount float64   `gorm:"column:shipping_discount;"`
+	CreatedAt        time.Time `gorm:"column:created_at;"`
+}
+
+func (VoucherUsage) TableName() string {
+	return "voucher_usage"
+}
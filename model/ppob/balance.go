@@ -0,0 +1,11 @@
+package ppob
+
+type CheckBalanceResponse struct {
+	Data CheckBalanceData `json:"data"`
+}
+
+type CheckBalanceData struct {
+	Balance float64 `json:"balance"`
+	Message string  `json:"message"`
+	Rc      string  `json:"rc"`
+}
@@ -290,17 +290,3 @@ type InquiryPostpaidTelcoTagihanData struct {
 	Admin        string `json:"admin"`
 	Total        string `json:"total"`
 }
-
-type InquiryProductPostpaidPPOB struct {
-	Data struct {
-		Pasca []struct {
-			Code   string  `json:"code"`
-			Name   string  `json:"name"`
-			Status int     `json:"status"`
-			Fee    float64 `json:"fee"`
-			Komisi float64 `json:"komisi"`
-			Type   string  `json:"type"`
-		} `json:"pasca"`
-	} `json:"data"`
-	Meta []interface{} `json:"meta"`
-}
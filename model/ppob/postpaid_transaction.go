@@ -0,0 +1,45 @@
+package ppob
+
+import "encoding/json"
+
+// PostpaidTransaction response inquiry, payment dan check status pascabayar untuk semua kategori.
+// Desc disimpan mentah karena isinya berbeda untuk tiap kategori (bpjs, multifinance, dst)
+type PostpaidTransaction struct {
+	Data PostpaidTransactionData `json:"data"`
+}
+
+type PostpaidTransactionData struct {
+	TrxId        int             `json:"tr_id"`
+	Code         string          `json:"code"`
+	Datetime     string          `json:"datetime"`
+	Hp           string          `json:"hp"`
+	TrName       string          `json:"tr_name"`
+	Period       string          `json:"period"`
+	Nominal      float64         `json:"nominal"`
+	Admin        float64         `json:"admin"`
+	RefId        string          `json:"ref_id"`
+	Status       int             `json:"status"`
+	ResponseCode string          `json:"response_code"`
+	Message      string          `json:"message"`
+	Price        float64         `json:"price"`
+	SellingPrice float64         `json:"selling_price"`
+	Balance      float64         `json:"balance"`
+	NoRef        string          `json:"noref"`
+	Desc         json.RawMessage `json:"desc,omitempty"`
+}
+
+// PpobBillDetail format bill_detail_json order ppob generic
+type PpobBillDetail struct {
+	ProductType  string          `json:"product_type"`
+	Category     string          `json:"category"`
+	ProductCode  string          `json:"product_code"`
+	ProductName  string          `json:"product_name"`
+	CustomerId   string          `json:"customer_id"`
+	CustomerName string          `json:"customer_name"`
+	Period       string          `json:"period"`
+	Nominal      float64         `json:"nominal"`
+	Admin        float64         `json:"admin"`
+	Price        float64         `json:"price"`
+	SellingPrice float64         `json:"selling_price"`
+	Desc         json.RawMessage `json:"desc,omitempty"`
+}
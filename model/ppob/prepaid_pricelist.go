@@ -63,3 +63,7 @@ type TopupPrepaidPulsaResponseData struct {
 	Rc          string  `json:"rc"`
 	Sn          string  `json:"sn"`
 }
+
+type PrepaidCheckStatusResponse struct {
+	Data TopupPrepaidPulsaResponseData `json:"data"`
+}
@@ -0,0 +1,22 @@
+package ppob
+
+type TransactionListResponse struct {
+	Data TransactionListData `json:"data"`
+}
+
+type TransactionListData struct {
+	Transactions []ProviderTransaction `json:"transactions"`
+	Message      string                `json:"message"`
+	Rc           string                `json:"rc"`
+}
+
+// ProviderTransaction status 0 diproses, 1 sukses, 2 gagal
+type ProviderTransaction struct {
+	RefId       string  `json:"ref_id"`
+	CustomerId  string  `json:"customer_id"`
+	ProductCode string  `json:"product_code"`
+	Price       float64 `json:"price"`
+	Status      int     `json:"status"`
+	Sn          string  `json:"sn"`
+	Date        string  `json:"date"`
+}
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+// Qty positif untuk penambahan stok, negatif untuk pengurangan
+type AdjustProductStockRequest struct {
+	IdProductDesa string  `json:"id_product_desa" form:"id_product_desa" validate:"required"`
+	Qty           int     `json:"qty" form:"qty" validate:"required"`
+	HargaBeli     float64 `json:"harga_beli" form:"harga_beli" validate:"gte=0"`
+	Reason        string  `json:"reason" form:"reason" validate:"required"`
+}
+
+func ReadFromAdjustProductStockRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *AdjustProductStockRequest {
+	adjustProductStockRequest := &AdjustProductStockRequest{}
+	if err := c.Bind(adjustProductStockRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return adjustProductStockRequest
+}
@@ -0,0 +1,20 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type CancelDeleteUserRequest struct {
+	Phone    string `json:"phone" form:"phone" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required"`
+}
+
+func ReadFromCancelDeleteUserRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CancelDeleteUserRequest {
+	cancelDeleteUserRequest := &CancelDeleteUserRequest{}
+	if err := c.Bind(cancelDeleteUserRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return cancelDeleteUserRequest
+}
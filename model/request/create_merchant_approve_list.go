@@ -10,6 +10,31 @@ type CreateMerchantApproveListRequest struct {
 	NamaMerchant string `json:"nama_merchant" form:"nama_merchant" validate:"required"`
 }
 
+type ApproveMerchantRequest struct {
+	IdMerchantApproveList string `json:"id_merchant_approve_list" form:"id_merchant_approve_list" validate:"required"`
+}
+
+func ReadFromApproveMerchantRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *ApproveMerchantRequest {
+	approveMerchantRequest := &ApproveMerchantRequest{}
+	if err := c.Bind(approveMerchantRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return approveMerchantRequest
+}
+
+type RejectMerchantRequest struct {
+	IdMerchantApproveList string `json:"id_merchant_approve_list" form:"id_merchant_approve_list" validate:"required"`
+	RejectReason          string `json:"reject_reason" form:"reject_reason" validate:"required"`
+}
+
+func ReadFromRejectMerchantRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *RejectMerchantRequest {
+	rejectMerchantRequest := &RejectMerchantRequest{}
+	if err := c.Bind(rejectMerchantRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return rejectMerchantRequest
+}
+
 func ReadFromCreateMerchantApproveListRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateMerchantApproveListRequest {
 	createMerchantApproveListRequest := &CreateMerchantApproveListRequest{}
 	if err := c.Bind(createMerchantApproveListRequest); err != nil {
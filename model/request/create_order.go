@@ -7,14 +7,15 @@ import (
 )
 
 type CreateOrderRequest struct {
-	TotalBill        float64 `json:"total_bill" form:"total_bill" validate:"required"`
-	PaymentMethod    string  `json:"payment_method" form:"payment_method" validate:"required"`
-	PaymentChannel   string  `json:"payment_channel" form:"payment_channel" validate:"required"`
-	PaymentPoint     float64 `json:"payment_point" form:"payment_point"`
-	AlamatPengiriman string  `json:"alamat_pengiriman" form:"alamat_pengiriman" validate:"required"`
-	CatatanKurir     string  `json:"catatan_kurir" form:"catatan_kurir" validate:"required"`
-	ShippingCost     float64 `json:"shipping_cost" form:"shipping_cost"`
-	PaymentFee       float64 `json:"payment_fee" form:"payment_fee"`
+	TotalBill             float64 `json:"total_bill" form:"total_bill" validate:"required"`
+	PaymentMethod         string  `json:"payment_method" form:"payment_method" validate:"required"`
+	PaymentChannel        string  `json:"payment_channel" form:"payment_channel" validate:"required"`
+	PaymentPoint          float64 `json:"payment_point" form:"payment_point"`
+	IdUserShippingAddress string  `json:"id_user_shipping_address" form:"id_user_shipping_address" validate:"required"`
+	CatatanKurir          string  `json:"catatan_kurir" form:"catatan_kurir" validate:"required"`
+	ShippingCost          float64 `json:"shipping_cost" form:"shipping_cost"`
+	PaymentFee            float64 `json:"payment_fee" form:"payment_fee"`
+	VoucherCode           string  `json:"voucher_code" form:"voucher_code"`
 }
 
 func ReadFromCreateOrderRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateOrderRequest {
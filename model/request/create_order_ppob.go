@@ -44,3 +44,23 @@ func ReadFromCreateOrderPostpaidRequestBody(c echo.Context, requestId string, lo
 	}
 	return createOrderPostpaidRequest
 }
+
+// Ppob generic, RefId wajib untuk kategori postpaid (hasil inquiry)
+type CreateOrderPpobRequest struct {
+	ProductCode    string  `json:"product_code" form:"product_code" validate:"required"`
+	CustomerId     string  `json:"customer_id" form:"customer_id" validate:"required"`
+	RefId          string  `json:"ref_id" form:"ref_id"`
+	PaymentMethod  string  `json:"payment_method" form:"payment_method" validate:"required"`
+	PaymentChannel string  `json:"payment_channel" form:"payment_channel" validate:"required"`
+	PaymentPoint   float64 `json:"payment_point" form:"payment_point"`
+	PaymentFee     float64 `json:"payment_fee" form:"payment_fee"`
+	TotalBill      float64 `json:"total_bill" form:"total_bill" validate:"required"`
+}
+
+func ReadFromCreateOrderPpobRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateOrderPpobRequest {
+	createOrderPpobRequest := &CreateOrderPpobRequest{}
+	if err := c.Bind(createOrderPpobRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return createOrderPpobRequest
+}
@@ -0,0 +1,37 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type CreatePointRuleRequest struct {
+	RuleType      int     `json:"rule_type" form:"rule_type" validate:"required,oneof=1 2 3 4 5"`
+	IdProductDesa string  `json:"id_product_desa" form:"id_product_desa"`
+	MinAmount     float64 `json:"min_amount" form:"min_amount" validate:"gte=0"`
+	Point         float64 `json:"point" form:"point" validate:"gt=0"`
+	Description   string  `json:"description" form:"description"`
+	IsActive      int     `json:"is_active" form:"is_active" validate:"oneof=0 1"`
+}
+
+func ReadFromCreatePointRuleRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreatePointRuleRequest {
+	createPointRuleRequest := &CreatePointRuleRequest{}
+	if err := c.Bind(createPointRuleRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return createPointRuleRequest
+}
+
+type UpdatePointRuleRequest struct {
+	IdPointRule string `json:"id_point_rule" form:"id_point_rule" validate:"required"`
+	CreatePointRuleRequest
+}
+
+func ReadFromUpdatePointRuleRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdatePointRuleRequest {
+	updatePointRuleRequest := &UpdatePointRuleRequest{}
+	if err := c.Bind(updatePointRuleRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updatePointRuleRequest
+}
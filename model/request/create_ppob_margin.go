@@ -0,0 +1,36 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type CreatePpobMarginRequest struct {
+	ProductType string  `json:"product_type" form:"product_type" validate:"omitempty,oneof=pulsa data pln"`
+	Operator    string  `json:"operator" form:"operator"`
+	MarginType  int     `json:"margin_type" form:"margin_type" validate:"required,oneof=1 2"`
+	Margin      float64 `json:"margin" form:"margin" validate:"gte=0"`
+	IsActive    int     `json:"is_active" form:"is_active" validate:"oneof=0 1"`
+}
+
+func ReadFromCreatePpobMarginRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreatePpobMarginRequest {
+	createPpobMarginRequest := &CreatePpobMarginRequest{}
+	if err := c.Bind(createPpobMarginRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return createPpobMarginRequest
+}
+
+type UpdatePpobMarginRequest struct {
+	IdPpobMargin string `json:"id_ppob_margin" form:"id_ppob_margin" validate:"required"`
+	CreatePpobMarginRequest
+}
+
+func ReadFromUpdatePpobMarginRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdatePpobMarginRequest {
+	updatePpobMarginRequest := &UpdatePpobMarginRequest{}
+	if err := c.Bind(updatePpobMarginRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updatePpobMarginRequest
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type CreateProductDesaRequest struct {
+	IdProductMaster string  `json:"id_product_master" form:"id_product_master" validate:"required"`
+	Price           float64 `json:"price" form:"price" validate:"gt=0"`
+	PriceGrosir     float64 `json:"price_grosir" form:"price_grosir" validate:"gte=0"`
+	PricePromo      float64 `json:"price_promo" form:"price_promo" validate:"gte=0"`
+	IsPromo         int     `json:"is_promo" form:"is_promo" validate:"oneof=0 1"`
+	IdPromo         string  `json:"id_promo" form:"id_promo"`
+	PictureUrl      string  `json:"picture_url" form:"picture_url"`
+	Thumbnail       string  `json:"thumbnail" form:"thumbnail"`
+	Description     string  `json:"description" form:"description"`
+}
+
+func ReadFromCreateProductDesaRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateProductDesaRequest {
+	createProductDesaRequest := &CreateProductDesaRequest{}
+	if err := c.Bind(createProductDesaRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return createProductDesaRequest
+}
+
+type UpdateProductDesaRequest struct {
+	IdProductDesa string  `json:"id_product_desa" form:"id_product_desa" validate:"required"`
+	Price         float64 `json:"price" form:"price" validate:"gt=0"`
+	PriceGrosir   float64 `json:"price_grosir" form:"price_grosir" validate:"gte=0"`
+	PricePromo    float64 `json:"price_promo" form:"price_promo" validate:"gte=0"`
+	IsPromo       int     `json:"is_promo" form:"is_promo" validate:"oneof=0 1"`
+	IdPromo       string  `json:"id_promo" form:"id_promo"`
+	PictureUrl    string  `json:"picture_url" form:"picture_url"`
+	Thumbnail     string  `json:"thumbnail" form:"thumbnail"`
+	Description   string  `json:"description" form:"description"`
+}
+
+func ReadFromUpdateProductDesaRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdateProductDesaRequest {
+	updateProductDesaRequest := &UpdateProductDesaRequest{}
+	if err := c.Bind(updateProductDesaRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updateProductDesaRequest
+}
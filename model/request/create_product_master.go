@@ -0,0 +1,43 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type CreateProductMasterRequest struct {
+	IdBrand       int     `json:"id_brand" form:"id_brand"`
+	IdCategory    int     `json:"id_category" form:"id_category" validate:"required"`
+	IdSubCategory int     `json:"id_sub_category" form:"id_sub_category" validate:"required"`
+	IdUnit        int     `json:"id_unit" form:"id_unit" validate:"required"`
+	UnitValue     float64 `json:"unit_value" form:"unit_value" validate:"gte=0"`
+	NoSku         string  `json:"no_sku" form:"no_sku" validate:"required"`
+	ProductName   string  `json:"product_name" form:"product_name" validate:"required"`
+	Price         float64 `json:"price" form:"price" validate:"gte=0"`
+	PriceGrosir   float64 `json:"price_grosir" form:"price_grosir" validate:"gte=0"`
+	Description   string  `json:"description" form:"description"`
+	PictureUrl    string  `json:"picture_url" form:"picture_url"`
+	Thumbnail     string  `json:"thumbnail" form:"thumbnail"`
+}
+
+func ReadFromCreateProductMasterRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateProductMasterRequest {
+	createProductMasterRequest := &CreateProductMasterRequest{}
+	if err := c.Bind(createProductMasterRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return createProductMasterRequest
+}
+
+type UpdateProductMasterRequest struct {
+	IdProductMaster string `json:"id_product_master" form:"id_product_master" validate:"required"`
+	CreateProductMasterRequest
+}
+
+func ReadFromUpdateProductMasterRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdateProductMasterRequest {
+	updateProductMasterRequest := &UpdateProductMasterRequest{}
+	if err := c.Bind(updateProductMasterRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updateProductMasterRequest
+}
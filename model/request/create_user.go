@@ -7,13 +7,15 @@ import (
 )
 
 type CreateUserRequest struct {
-	NoIdentitas string `json:"no_identitas" form:"no_identitas" validate:"required"`
-	NamaLengkap string `json:"nama_lengkap" form:"nama_lengkap" validate:"required"`
-	Phone       string `json:"phone" form:"phone" validate:"required"`
-	Email       string `json:"email" form:"email"`
-	IdDesa      string `json:"id_desa" form:"id_desa" validate:"required"`
-	Password    string `json:"password" form:"password" validate:"required"`
-	FormToken   string `json:"form_token" form:"form_token" validate:"required"`
+	NoIdentitas  string `json:"no_identitas" form:"no_identitas" validate:"required"`
+	NamaLengkap  string `json:"nama_lengkap" form:"nama_lengkap" validate:"required"`
+	Phone        string `json:"phone" form:"phone" validate:"required"`
+	Email        string `json:"email" form:"email"`
+	IdDesa       string `json:"id_desa" form:"id_desa" validate:"required"`
+	Password     string `json:"password" form:"password" validate:"required"`
+	FormToken    string `json:"form_token" form:"form_token" validate:"required"`
+	ReferralCode string `json:"referral_code" form:"referral_code"`
+	DeviceId     string `json:"device_id" form:"device_id"`
 }
 
 func ReadFromCreateUserRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateUserRequest {
@@ -25,13 +27,15 @@ func ReadFromCreateUserRequestBody(c echo.Context, requestId string, logger *log
 }
 
 type CreateUserSurveyedRequest struct {
-	NoIdentitas string `json:"no_identitas" form:"no_identitas" validate:"required"`
-	NamaLengkap string `json:"nama_lengkap" form:"nama_lengkap" validate:"required"`
-	Phone       string `json:"phone" form:"phone" validate:"required"`
-	Email       string `json:"email" form:"email"`
-	IdDesa      string `json:"id_desa" form:"id_desa" validate:"required"`
-	Alamat      string `json:"alamat" form:"alamat" validate:"required"`
-	Password    string `json:"password" form:"password" validate:"required"`
+	NoIdentitas  string `json:"no_identitas" form:"no_identitas" validate:"required"`
+	NamaLengkap  string `json:"nama_lengkap" form:"nama_lengkap" validate:"required"`
+	Phone        string `json:"phone" form:"phone" validate:"required"`
+	Email        string `json:"email" form:"email"`
+	IdDesa       string `json:"id_desa" form:"id_desa" validate:"required"`
+	Alamat       string `json:"alamat" form:"alamat" validate:"required"`
+	Password     string `json:"password" form:"password" validate:"required"`
+	ReferralCode string `json:"referral_code" form:"referral_code"`
+	DeviceId     string `json:"device_id" form:"device_id"`
 }
 
 func ReadFromCreateUserSurveyedRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateUserSurveyedRequest {
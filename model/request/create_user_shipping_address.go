@@ -6,12 +6,13 @@ import (
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
 )
 
+// Koordinat dibatasi ke wilayah Provinsi Bali termasuk Nusa Penida
 type CreateUserShippingAddressRequest struct {
 	AlamatPengiriman string  `json:"alamat_pengiriman" form:"alamat_pengiriman" validate:"required"`
-	Latitude         float64 `json:"latitude" form:"latitude" validate:"required"`
-	Longitude        float64 `json:"longitude" form:"longitude" validate:"required"`
-	Radius           float64 `json:"radius" form:"radius"`
-	StatusPrimary    int     `json:"status_primary" form:"status_primary"`
+	Latitude         float64 `json:"latitude" form:"latitude" validate:"required,gte=-8.9,lte=-8.0"`
+	Longitude        float64 `json:"longitude" form:"longitude" validate:"required,gte=114.4,lte=115.75"`
+	Radius           float64 `json:"radius" form:"radius" validate:"gte=0"`
+	StatusPrimary    int     `json:"status_primary" form:"status_primary" validate:"oneof=0 1"`
 	Catatan          string  `json:"catatan" form:"catatan" validate:"required"`
 }
 
@@ -0,0 +1,58 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+// StartDate dan EndDate format 2006-01-02 15:04:05
+type CreateVoucherRequest struct {
+	Code          string  `json:"code" form:"code" validate:"required,alphanum,max=20"`
+	Title         string  `json:"title" form:"title" validate:"required"`
+	Description   string  `json:"description" form:"description"`
+	DiscountType  int     `json:"discount_type" form:"discount_type" validate:"required,oneof=1 2 3"`
+	DiscountValue float64 `json:"discount_value" form:"discount_value" validate:"gte=0"`
+	MaxDiscount   float64 `json:"max_discount" form:"max_discount" validate:"gte=0"`
+	MinSpend      float64 `json:"min_spend" form:"min_spend" validate:"gte=0"`
+	QuotaTotal    int     `json:"quota_total" form:"quota_total" validate:"gte=0"`
+	QuotaPerUser  int     `json:"quota_per_user" form:"quota_per_user" validate:"gte=0"`
+	StartDate     string  `json:"start_date" form:"start_date" validate:"required"`
+	EndDate       string  `json:"end_date" form:"end_date" validate:"required"`
+	IsActive      int     `json:"is_active" form:"is_active" validate:"oneof=0 1"`
+}
+
+func ReadFromCreateVoucherRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CreateVoucherRequest {
+	createVoucherRequest := &CreateVoucherRequest{}
+	if err := c.Bind(createVoucherRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return createVoucherRequest
+}
+
+type UpdateVoucherRequest struct {
+	IdVoucher string `json:"id_voucher" form:"id_voucher" validate:"required"`
+	CreateVoucherRequest
+}
+
+func ReadFromUpdateVoucherRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdateVoucherRequest {
+	updateVoucherRequest := &UpdateVoucherRequest{}
+	if err := c.Bind(updateVoucherRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updateVoucherRequest
+}
+
+type CheckVoucherRequest struct {
+	VoucherCode  string  `json:"voucher_code" form:"voucher_code" validate:"required"`
+	SubTotal     float64 `json:"sub_total" form:"sub_total" validate:"gt=0"`
+	ShippingCost float64 `json:"shipping_cost" form:"shipping_cost" validate:"gte=0"`
+}
+
+func ReadFromCheckVoucherRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *CheckVoucherRequest {
+	checkVoucherRequest := &CheckVoucherRequest{}
+	if err := c.Bind(checkVoucherRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return checkVoucherRequest
+}
@@ -0,0 +1,36 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type AssignCourierRequest struct {
+	IdOrder   string `json:"id_order" form:"id_order" validate:"required"`
+	IdCourier string `json:"id_courier" form:"id_courier" validate:"required"`
+}
+
+// DeliverOrderRequest CodCollected wajib untuk order dengan payment method cod
+type DeliverOrderRequest struct {
+	IdOrder          string  `json:"id_order" form:"id_order" validate:"required"`
+	FotoBarangSampai string  `json:"foto_barang_sampai" form:"foto_barang_sampai" validate:"required"`
+	CodCollected     float64 `json:"cod_collected" form:"cod_collected" validate:"gte=0"`
+	Note             string  `json:"note" form:"note" validate:"max=255"`
+}
+
+func ReadFromAssignCourierRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *AssignCourierRequest {
+	assignCourierRequest := &AssignCourierRequest{}
+	if err := c.Bind(assignCourierRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return assignCourierRequest
+}
+
+func ReadFromDeliverOrderRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *DeliverOrderRequest {
+	deliverOrderRequest := &DeliverOrderRequest{}
+	if err := c.Bind(deliverOrderRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return deliverOrderRequest
+}
@@ -0,0 +1,21 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type InquiryPpobRequest struct {
+	ProductCode string            `json:"product_code" form:"product_code" validate:"required"`
+	CustomerId  string            `json:"customer_id" form:"customer_id" validate:"required"`
+	Params      map[string]string `json:"params" form:"params"`
+}
+
+func ReadFromInquiryPpobRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *InquiryPpobRequest {
+	inquiryPpobRequest := &InquiryPpobRequest{}
+	if err := c.Bind(inquiryPpobRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return inquiryPpobRequest
+}
@@ -1,6 +1,9 @@
 package request
 
 import (
+	"encoding/json"
+	"io"
+
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
@@ -25,10 +28,13 @@ type PpobCallbackRequestData struct {
 	Sign        string `json:"sign"`
 }
 
-func ReadFromPpobCallbackRequestRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *PpobCallbackRequest {
+// ReadFromPpobCallbackRequestRequestBody mengembalikan juga body mentah untuk log audit callback
+func ReadFromPpobCallbackRequestRequestBody(c echo.Context, requestId string, logger *logrus.Logger) (*PpobCallbackRequest, []byte) {
 	ppobCallbackRequest := &PpobCallbackRequest{}
-	if err := c.Bind(ppobCallbackRequest); err != nil {
-		exceptions.PanicIfError(err, requestId, logger)
+	rawPayload, err := io.ReadAll(c.Request().Body)
+	exceptions.PanicIfError(err, requestId, logger)
+	if err := json.Unmarshal(rawPayload, ppobCallbackRequest); err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"invalid payload"}, logger)
 	}
-	return ppobCallbackRequest
+	return ppobCallbackRequest, rawPayload
 }
@@ -0,0 +1,20 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+// ReadNotificationRequest IdNotifications kosong berarti tandai semua sudah dibaca
+type ReadNotificationRequest struct {
+	IdNotifications []string `json:"id_notifications" form:"id_notifications" validate:"max=100,dive,required"`
+}
+
+func ReadFromReadNotificationRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *ReadNotificationRequest {
+	readNotificationRequest := &ReadNotificationRequest{}
+	if err := c.Bind(readNotificationRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return readNotificationRequest
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type RegisterDeviceTokenRequest struct {
+	Token    string `json:"token" form:"token" validate:"required,max=4096"`
+	Platform string `json:"platform" form:"platform" validate:"required,oneof=android ios web"`
+	DeviceId string `json:"device_id" form:"device_id" validate:"max=255"`
+}
+
+func ReadFromRegisterDeviceTokenRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *RegisterDeviceTokenRequest {
+	registerDeviceTokenRequest := &RegisterDeviceTokenRequest{}
+	if err := c.Bind(registerDeviceTokenRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return registerDeviceTokenRequest
+}
+
+type UnregisterDeviceTokenRequest struct {
+	Token string `json:"token" form:"token" validate:"required"`
+}
+
+func ReadFromUnregisterDeviceTokenRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UnregisterDeviceTokenRequest {
+	unregisterDeviceTokenRequest := &UnregisterDeviceTokenRequest{}
+	if err := c.Bind(unregisterDeviceTokenRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return unregisterDeviceTokenRequest
+}
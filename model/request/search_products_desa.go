@@ -0,0 +1,27 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type SearchProductsDesaRequest struct {
+	Keyword       string `query:"q" validate:"max=100"`
+	IdCategory    int    `query:"id_category"`
+	IdSubCategory int    `query:"id_sub_category"`
+	IdBrand       int    `query:"id_brand"`
+	IsPromo       bool   `query:"promo"`
+	InStock       bool   `query:"in_stock"`
+	Sort          string `query:"sort" validate:"omitempty,oneof=price_asc price_desc name_asc name_desc newest"`
+	Cursor        string `query:"cursor"`
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func ReadFromSearchProductsDesaRequestQuery(c echo.Context, requestId string, logger *logrus.Logger) *SearchProductsDesaRequest {
+	searchProductsDesaRequest := &SearchProductsDesaRequest{}
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, searchProductsDesaRequest); err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"invalid query params"}, logger)
+	}
+	return searchProductsDesaRequest
+}
@@ -8,7 +8,7 @@ import (
 
 type SendOtpBySmsRequest struct {
 	Phone   string `json:"phone" form:"phone" validate:"required"`
-	TypeOtp int    `json:"type_otp" form:"type_otp" validate:"required"`
+	TypeOtp int    `json:"type_otp" form:"type_otp" validate:"required,oneof=1 2 3"`
 }
 
 func ReadFromSendOtpBySmsRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *SendOtpBySmsRequest {
@@ -0,0 +1,19 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type SetPrimaryUserShippingAddressRequest struct {
+	IdUserShippingAddress string `json:"id_user_shipping_address" form:"id_user_shipping_address" validate:"required"`
+}
+
+func ReadFromSetPrimaryUserShippingAddressRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *SetPrimaryUserShippingAddressRequest {
+	setPrimaryUserShippingAddressRequest := &SetPrimaryUserShippingAddressRequest{}
+	if err := c.Bind(setPrimaryUserShippingAddressRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return setPrimaryUserShippingAddressRequest
+}
@@ -0,0 +1,50 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+// SubmitBuktiBayarRequest FotoBuktiBayar berisi key hasil upload bukti bayar
+type SubmitBuktiBayarRequest struct {
+	IdOrder        string `json:"id_order" form:"id_order" validate:"required"`
+	FotoBuktiBayar string `json:"foto_bukti_bayar" form:"foto_bukti_bayar" validate:"required"`
+}
+
+// VerifyTransferRequest Note wajib jika bukti bayar ditolak
+type VerifyTransferRequest struct {
+	IdOrder string `json:"id_order" form:"id_order" validate:"required"`
+	Status  string `json:"status" form:"status" validate:"required,oneof=approve reject"`
+	Note    string `json:"note" form:"note" validate:"max=255"`
+}
+
+// ImportInveliMutationRequest format tanggal yyyy-mm-dd
+type ImportInveliMutationRequest struct {
+	StartDate string `json:"start_date" form:"start_date" validate:"required"`
+	EndDate   string `json:"end_date" form:"end_date" validate:"required"`
+}
+
+func ReadFromSubmitBuktiBayarRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *SubmitBuktiBayarRequest {
+	submitBuktiBayarRequest := &SubmitBuktiBayarRequest{}
+	if err := c.Bind(submitBuktiBayarRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return submitBuktiBayarRequest
+}
+
+func ReadFromVerifyTransferRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *VerifyTransferRequest {
+	verifyTransferRequest := &VerifyTransferRequest{}
+	if err := c.Bind(verifyTransferRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return verifyTransferRequest
+}
+
+func ReadFromImportInveliMutationRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *ImportInveliMutationRequest {
+	importInveliMutationRequest := &ImportInveliMutationRequest{}
+	if err := c.Bind(importInveliMutationRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return importInveliMutationRequest
+}
@@ -0,0 +1,27 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type PriceTierRequest struct {
+	AccountType int     `json:"account_type" form:"account_type" validate:"required,oneof=1 2"`
+	MinQty      int     `json:"min_qty" form:"min_qty" validate:"required,min=1"`
+	Price       float64 `json:"price" form:"price" validate:"required,gt=0"`
+}
+
+// PriceTiers menggantikan seluruh tier produk, list kosong menghapus semua tier
+type UpdateProductPriceTierRequest struct {
+	IdProductDesa string             `json:"id_product_desa" form:"id_product_desa" validate:"required"`
+	PriceTiers    []PriceTierRequest `json:"price_tiers" form:"price_tiers" validate:"dive"`
+}
+
+func ReadFromUpdateProductPriceTierRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdateProductPriceTierRequest {
+	updateProductPriceTierRequest := &UpdateProductPriceTierRequest{}
+	if err := c.Bind(updateProductPriceTierRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updateProductPriceTierRequest
+}
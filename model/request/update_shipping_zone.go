@@ -0,0 +1,24 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type UpdateShippingZoneRequest struct {
+	DepotLatitude  float64 `json:"depot_latitude" form:"depot_latitude" validate:"required,gte=-90,lte=90"`
+	DepotLongitude float64 `json:"depot_longitude" form:"depot_longitude" validate:"required,gte=-180,lte=180"`
+	FreeKm         float64 `json:"free_km" form:"free_km" validate:"gte=0"`
+	BaseCost       float64 `json:"base_cost" form:"base_cost" validate:"gte=0"`
+	RatePerKm      float64 `json:"rate_per_km" form:"rate_per_km" validate:"gte=0"`
+	MaxKm          float64 `json:"max_km" form:"max_km" validate:"gte=0"`
+}
+
+func ReadFromUpdateShippingZoneRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdateShippingZoneRequest {
+	updateShippingZoneRequest := &UpdateShippingZoneRequest{}
+	if err := c.Bind(updateShippingZoneRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updateShippingZoneRequest
+}
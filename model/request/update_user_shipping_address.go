@@ -0,0 +1,24 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+)
+
+type UpdateUserShippingAddressRequest struct {
+	IdUserShippingAddress string  `json:"id_user_shipping_address" form:"id_user_shipping_address" validate:"required"`
+	AlamatPengiriman      string  `json:"alamat_pengiriman" form:"alamat_pengiriman" validate:"required"`
+	Latitude              float64 `json:"latitude" form:"latitude" validate:"required,gte=-8.9,lte=-8.0"`
+	Longitude             float64 `json:"longitude" form:"longitude" validate:"required,gte=114.4,lte=115.75"`
+	Radius                float64 `json:"radius" form:"radius" validate:"gte=0"`
+	Catatan               string  `json:"catatan" form:"catatan" validate:"required"`
+}
+
+func ReadFromUpdateUserShippingAddressRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *UpdateUserShippingAddressRequest {
+	updateUserShippingAddressRequest := &UpdateUserShippingAddressRequest{}
+	if err := c.Bind(updateUserShippingAddressRequest); err != nil {
+		exceptions.PanicIfError(err, requestId, logger)
+	}
+	return updateUserShippingAddressRequest
+}
@@ -9,6 +9,7 @@ import (
 type VerifyOtpRequest struct {
 	Phone   string `json:"phone" form:"phone" validate:"required"`
 	OtpCode string `json:"otp_code" form:"otp_code" validate:"required"`
+	TypeOtp int    `json:"type_otp" form:"type_otp" validate:"required,oneof=1 2 3"`
 }
 
 func ReadFromVerifyOtpRequestBody(c echo.Context, requestId string, logger *logrus.Logger) *VerifyOtpRequest {
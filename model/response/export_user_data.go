@@ -0,0 +1,148 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type ExportUserDataResponse struct {
+	ExportedAt      time.Time                           `json:"exported_at"`
+	Profile         ExportUserProfileResponse           `json:"profile"`
+	ShippingAddress []ExportUserShippingAddressResponse `json:"shipping_address"`
+	Point           float64                             `json:"point"`
+	Orders          []ExportUserOrderResponse           `json:"orders"`
+	ListPinjaman    []ListPinjamanResponse              `json:"list_pinjaman"`
+	PaymentHistory  []ExportUserPaymentHistoryResponse  `json:"payment_history"`
+}
+
+type ExportUserProfileResponse struct {
+	Id                    string    `json:"id"`
+	NoIdentitas           string    `json:"no_identitas"`
+	NamaLengkap           string    `json:"nama_lengkap"`
+	Email                 string    `json:"email"`
+	Phone                 string    `json:"phone"`
+	AlamatSesuaiIdentitas string    `json:"alamat_sesuai_identitas"`
+	NamaDesa              string    `json:"nama_desa"`
+	AccountType           int       `json:"account_type"`
+	MerchantCode          string    `json:"merchant_code"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type ExportUserShippingAddressResponse struct {
+	Id               string  `json:"id"`
+	AlamatPengiriman string  `json:"alamat_pengiriman"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Catatan          string  `json:"catatan"`
+	StatusPrimary    int     `json:"is_primary"`
+}
+
+type ExportUserOrderResponse struct {
+	Id               string                        `json:"id"`
+	NumberOrder      string                        `json:"number_order"`
+	ProductType      string                        `json:"product_type"`
+	NamaLengkap      string                        `json:"nama_lengkap"`
+	Phone            string                        `json:"phone"`
+	AlamatPengiriman string                        `json:"alamat_pengiriman"`
+	TotalBill        float64                       `json:"total_bill"`
+	PaymentMethod    string                        `json:"payment_method"`
+	PaymentStatus    int                           `json:"payment_status"`
+	OrderStatus      int                           `json:"order_status"`
+	OrderedDate      time.Time                     `json:"order_date"`
+	OrderItems       []ExportUserOrderItemResponse `json:"order_items"`
+}
+
+type ExportUserOrderItemResponse struct {
+	ProductName string  `json:"product_name"`
+	Qty         int     `json:"qty"`
+	Price       float64 `json:"price"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+type ExportUserPaymentHistoryResponse struct {
+	NoTransaksi      string    `json:"no_transaksi"`
+	JmlTagihan       float64   `json:"jml_tagihan"`
+	BungaPinjaman    float64   `json:"bunga_pinjaman"`
+	BiayaAdmin       float64   `json:"biaya_admin"`
+	Total            float64   `json:"total"`
+	TglPembayaran    time.Time `json:"tgl_pembayaran"`
+	StatusPembayaran int       `json:"status_pembayaran"`
+}
+
+func ToExportUserDataResponse(
+	userProfile *entity.UserProfile,
+	userShippingAddresses []entity.UserShippingAddress,
+	point *entity.Point,
+	orders []entity.Order,
+	orderItems map[string][]entity.OrderItem,
+	listPinjamans []entity.ListPinjaman,
+	paymentHistories []entity.PaymentHistory,
+) (exportUserDataResponse ExportUserDataResponse) {
+	exportUserDataResponse.ExportedAt = time.Now()
+
+	exportUserDataResponse.Profile.Id = userProfile.User.Id
+	exportUserDataResponse.Profile.NoIdentitas = userProfile.NoIdentitas
+	exportUserDataResponse.Profile.NamaLengkap = userProfile.NamaLengkap
+	exportUserDataResponse.Profile.Email = userProfile.Email
+	exportUserDataResponse.Profile.Phone = userProfile.User.Phone
+	exportUserDataResponse.Profile.AlamatSesuaiIdentitas = userProfile.AlamatSesuaiIdentitas
+	exportUserDataResponse.Profile.NamaDesa = userProfile.User.Desa.NamaDesa
+	exportUserDataResponse.Profile.AccountType = userProfile.User.AccountType
+	exportUserDataResponse.Profile.MerchantCode = userProfile.User.MerchantCode
+	exportUserDataResponse.Profile.CreatedAt = userProfile.User.CreatedDate
+
+	for _, userShippingAddress := range userShippingAddresses {
+		exportUserDataResponse.ShippingAddress = append(exportUserDataResponse.ShippingAddress, ExportUserShippingAddressResponse{
+			Id:               userShippingAddress.Id,
+			AlamatPengiriman: userShippingAddress.AlamatPengiriman,
+			Latitude:         userShippingAddress.Latitude,
+			Longitude:        userShippingAddress.Longitude,
+			Catatan:          userShippingAddress.Catatan,
+			StatusPrimary:    userShippingAddress.StatusPrimary,
+		})
+	}
+
+	exportUserDataResponse.Point = point.JmlPoint
+
+	for _, order := range orders {
+		orderResponse := ExportUserOrderResponse{
+			Id:               order.Id,
+			NumberOrder:      order.NumberOrder,
+			ProductType:      order.ProductType,
+			NamaLengkap:      order.NamaLengkap,
+			Phone:            order.Phone,
+			AlamatPengiriman: order.AlamatPengiriman,
+			TotalBill:        order.TotalBill,
+			PaymentMethod:    order.PaymentMethod,
+			PaymentStatus:    order.PaymentStatus,
+			OrderStatus:      order.OrderStatus,
+			OrderedDate:      order.OrderedDate,
+		}
+		for _, orderItem := range orderItems[order.Id] {
+			orderResponse.OrderItems = append(orderResponse.OrderItems, ExportUserOrderItemResponse{
+				ProductName: orderItem.ProductName,
+				Qty:         orderItem.Qty,
+				Price:       orderItem.Price,
+				TotalPrice:  orderItem.TotalPrice,
+			})
+		}
+		exportUserDataResponse.Orders = append(exportUserDataResponse.Orders, orderResponse)
+	}
+
+	exportUserDataResponse.ListPinjaman = ToListPinjamanResponses(listPinjamans)
+
+	for _, paymentHistory := range paymentHistories {
+		exportUserDataResponse.PaymentHistory = append(exportUserDataResponse.PaymentHistory, ExportUserPaymentHistoryResponse{
+			NoTransaksi:      paymentHistory.NoTransaksi,
+			JmlTagihan:       paymentHistory.JmlTagihan,
+			BungaPinjaman:    paymentHistory.BungaPinjaman,
+			BiayaAdmin:       paymentHistory.BiayaAdmin,
+			Total:            paymentHistory.Total,
+			TglPembayaran:    paymentHistory.TglPembayaran.Time,
+			StatusPembayaran: paymentHistory.StatusPembayaran,
+		})
+	}
+
+	return exportUserDataResponse
+}
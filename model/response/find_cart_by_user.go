@@ -3,10 +3,13 @@ package response
 import "github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 
 type FindCartByIdUserResponse struct {
-	SubTotal     float64    `json:"sub_total"`
-	ShippingCost float64    `json:"shipping_cost"`
-	TotalBill    float64    `json:"total_bill"`
-	CartItems    []CartItem `json:"cart_items"`
+	SubTotal        float64    `json:"sub_total"`
+	ShippingCost    float64    `json:"shipping_cost"`
+	TotalBill       float64    `json:"total_bill"`
+	IdAddress       string     `json:"id_address"`
+	DistanceKm      float64    `json:"distance_km"`
+	ShippingMessage string     `json:"shipping_message"`
+	CartItems       []CartItem `json:"cart_items"`
 }
 
 type CartItem struct {
@@ -24,13 +27,16 @@ type CartItem struct {
 	Description     string  `json:"description"`
 	PriceInfo       string  `json:"price_info"`
 	AccountType     string  `json:"account_type"`
+	TotalPrice      float64 `json:"total_price"`
+	TierMinQty      int     `json:"tier_min_qty"`
 }
 
-func ToFindCartByUserResponse(carts []entity.Cart, ShippingCost float64, AccountType int) (cartResponse FindCartByIdUserResponse) {
+// ToFindCartByUserResponse quotes berisi harga per cart id hasil service.QuotePrice
+func ToFindCartByUserResponse(carts []entity.Cart, quotes map[string]PriceQuote, ShippingCost float64) (cartResponse FindCartByIdUserResponse) {
 	var cartItems []CartItem
-	var totalPricePerItem float64
 	var subTotal float64
 	for _, cart := range carts {
+		quote := quotes[cart.Id]
 		var cartItem CartItem
 		cartItem.Id = cart.Id
 		cartItem.IdProduct = cart.IdProductDesa
@@ -40,28 +46,17 @@ func ToFindCartByUserResponse(carts []entity.Cart, ShippingCost float64, Account
 		cartItem.Qty = cart.Qty
 		cartItem.Stock = cart.ProductsDesa.StockOpname
 		cartItem.Description = cart.ProductsDesa.ProductsMaster.Description
-		cartItem.IsPromo = cart.ProductsDesa.IsPromo
-		if AccountType == 1 {
-			if cart.ProductsDesa.IsPromo == 1 {
-				cartItem.PricePromo = cart.ProductsDesa.PricePromo
-				cartItem.Price = cart.ProductsDesa.Price
-				totalPricePerItem = cart.ProductsDesa.PricePromo * float64(cart.Qty)
-				cartItem.PromoPercentage = cart.ProductsDesa.PercentagePromo
-			} else {
-				cartItem.Price = cart.ProductsDesa.Price
-				totalPricePerItem = cart.ProductsDesa.Price * float64(cart.Qty)
-				cartItem.PricePromo = 0
-				cartItem.PromoPercentage = 0
-			}
-			cartItem.AccountType = "User Biasa"
-			cartItem.PriceInfo = "Krama Harga Normal"
-		} else if AccountType == 2 {
-			cartItem.AccountType = "User Merchant"
-			cartItem.PriceInfo = "Krama Harga Grosir"
-			cartItem.Price = cart.ProductsDesa.PriceGrosir
-			totalPricePerItem = cart.ProductsDesa.PriceGrosir * float64(cart.Qty)
+		cartItem.IsPromo = quote.FlagPromo
+		cartItem.Price = quote.Price
+		cartItem.PricePromo = quote.PricePromo
+		if quote.FlagPromo == 1 {
+			cartItem.PromoPercentage = cart.ProductsDesa.PercentagePromo
 		}
-		subTotal = subTotal + totalPricePerItem
+		cartItem.TotalPrice = quote.TotalPrice
+		cartItem.TierMinQty = quote.TierMinQty
+		cartItem.AccountType = quote.AccountType
+		cartItem.PriceInfo = quote.PriceInfo
+		subTotal = subTotal + quote.TotalPrice
 		cartItems = append(cartItems, cartItem)
 	}
 
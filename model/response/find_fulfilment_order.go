@@ -0,0 +1,107 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gopkg.in/guregu/null.v4"
+)
+
+type FindFulfilmentOrderResponse struct {
+	Id               string                            `json:"id"`
+	NumberOrder      string                            `json:"number_order"`
+	NamaLengkap      string                            `json:"nama_lengkap"`
+	Phone            string                            `json:"phone"`
+	AlamatPengiriman string                            `json:"alamat_pengiriman"`
+	Catatan          string                            `json:"catatan"`
+	Latitude         float64                           `json:"latitude"`
+	Longitude        float64                           `json:"longitude"`
+	PaymentMethod    string                            `json:"payment_method"`
+	PaymentName      string                            `json:"payment_name"`
+	PaymentStatus    int                               `json:"payment_status"`
+	TotalBill        float64                           `json:"total_bill"`
+	OrderStatus      int                               `json:"order_status"`
+	IdCourier        string                            `json:"id_courier"`
+	OrderDate        time.Time                         `json:"order_date"`
+	OrderItems       []FindFulfilmentOrderItemResponse `json:"order_items"`
+}
+
+type FindFulfilmentOrderItemResponse struct {
+	NoSku       string `json:"no_sku"`
+	ProductName string `json:"product_name"`
+	Qty         int    `json:"qty"`
+}
+
+type FindCourierResponse struct {
+	IdUser      string `json:"id_user"`
+	NamaLengkap string `json:"nama_lengkap"`
+	Phone       string `json:"phone"`
+}
+
+type FindOrderTrackingResponse struct {
+	IdOrder          string                           `json:"id_order"`
+	NumberOrder      string                           `json:"number_order"`
+	OrderStatus      int                              `json:"order_status"`
+	NamaCourier      string                           `json:"nama_courier"`
+	PhoneCourier     string                           `json:"phone_courier"`
+	FotoBarangSampai string                           `json:"foto_barang_sampai"`
+	DeliveredDate    null.Time                        `json:"delivered_date"`
+	AutoCompleteDate null.Time                        `json:"auto_complete_date"`
+	Histories        []FindOrderStatusHistoryResponse `json:"histories"`
+}
+
+type FindOrderStatusHistoryResponse struct {
+	OrderStatus int       `json:"order_status"`
+	Status      string    `json:"status"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ToFindFulfilmentOrderResponses(orders []entity.Order, orderItems []entity.OrderItem) (fulfilmentOrderResponses []FindFulfilmentOrderResponse) {
+	orderItemsByOrder := map[string][]FindFulfilmentOrderItemResponse{}
+	for _, orderItem := range orderItems {
+		orderItemsByOrder[orderItem.IdOrder] = append(orderItemsByOrder[orderItem.IdOrder], FindFulfilmentOrderItemResponse{
+			NoSku:       orderItem.NoSku,
+			ProductName: orderItem.ProductName,
+			Qty:         orderItem.Qty,
+		})
+	}
+
+	fulfilmentOrderResponses = []FindFulfilmentOrderResponse{}
+	for _, order := range orders {
+		fulfilmentOrderResponse := FindFulfilmentOrderResponse{}
+		fulfilmentOrderResponse.Id = order.Id
+		fulfilmentOrderResponse.NumberOrder = order.NumberOrder
+		fulfilmentOrderResponse.NamaLengkap = order.NamaLengkap
+		fulfilmentOrderResponse.Phone = order.Phone
+		fulfilmentOrderResponse.AlamatPengiriman = order.AlamatPengiriman
+		fulfilmentOrderResponse.Catatan = order.Catatan
+		fulfilmentOrderResponse.Latitude = order.Latitude
+		fulfilmentOrderResponse.Longitude = order.Longitude
+		fulfilmentOrderResponse.PaymentMethod = order.PaymentMethod
+		fulfilmentOrderResponse.PaymentName = order.PaymentName
+		fulfilmentOrderResponse.PaymentStatus = order.PaymentStatus
+		fulfilmentOrderResponse.TotalBill = order.TotalBill
+		fulfilmentOrderResponse.OrderStatus = order.OrderStatus
+		fulfilmentOrderResponse.IdCourier = order.IdCourier
+		fulfilmentOrderResponse.OrderDate = order.OrderedDate
+		fulfilmentOrderResponse.OrderItems = orderItemsByOrder[order.Id]
+		if fulfilmentOrderResponse.OrderItems == nil {
+			fulfilmentOrderResponse.OrderItems = []FindFulfilmentOrderItemResponse{}
+		}
+		fulfilmentOrderResponses = append(fulfilmentOrderResponses, fulfilmentOrderResponse)
+	}
+	return fulfilmentOrderResponses
+}
+
+func ToFindCourierResponses(userProfiles []entity.UserProfile) (courierResponses []FindCourierResponse) {
+	courierResponses = []FindCourierResponse{}
+	for _, userProfile := range userProfiles {
+		courierResponses = append(courierResponses, FindCourierResponse{
+			IdUser:      userProfile.User.Id,
+			NamaLengkap: userProfile.NamaLengkap,
+			Phone:       userProfile.User.Phone,
+		})
+	}
+	return courierResponses
+}
@@ -10,6 +10,7 @@ type FindMerchantStatusApproveByUserResponse struct {
 	NamaLengkap   string    `json:"nama_lengkap"`
 	MerchantName  string    `json:"nama_merchant"`
 	ApproveStatus int       `json:"status_approve"`
+	RejectReason  string    `json:"alasan_ditolak"`
 	CreatedAt     time.Time `json:"tgl_pengajuan"`
 }
 
@@ -17,6 +18,34 @@ func ToFindMerchantStatusApproveByUserResponse(merchantApproveList *entity.Merch
 	merchantResponse.NamaLengkap = merchantApproveList.NamaLengkap
 	merchantResponse.MerchantName = merchantApproveList.MerchantName
 	merchantResponse.ApproveStatus = merchantApproveList.ApproveStatus
+	merchantResponse.RejectReason = merchantApproveList.RejectReason
 	merchantResponse.CreatedAt = merchantApproveList.CreatedAt
 	return merchantResponse
 }
+
+type FindMerchantApproveListResponse struct {
+	Id            string    `json:"id"`
+	IdUser        string    `json:"id_user"`
+	NamaLengkap   string    `json:"nama_lengkap"`
+	MerchantName  string    `json:"nama_merchant"`
+	ApproveStatus int       `json:"status_approve"`
+	RejectReason  string    `json:"alasan_ditolak"`
+	CreatedAt     time.Time `json:"tgl_pengajuan"`
+	ReviewedAt    time.Time `json:"tgl_review"`
+}
+
+func ToFindMerchantApproveListResponses(merchantApproveLists []entity.MerchantApproveList) (merchantResponses []FindMerchantApproveListResponse) {
+	for _, merchantApproveList := range merchantApproveLists {
+		var merchantResponse FindMerchantApproveListResponse
+		merchantResponse.Id = merchantApproveList.Id
+		merchantResponse.IdUser = merchantApproveList.IdUser
+		merchantResponse.NamaLengkap = merchantApproveList.NamaLengkap
+		merchantResponse.MerchantName = merchantApproveList.MerchantName
+		merchantResponse.ApproveStatus = merchantApproveList.ApproveStatus
+		merchantResponse.RejectReason = merchantApproveList.RejectReason
+		merchantResponse.CreatedAt = merchantApproveList.CreatedAt
+		merchantResponse.ReviewedAt = merchantApproveList.ReviewedAt.Time
+		merchantResponses = append(merchantResponses, merchantResponse)
+	}
+	return merchantResponses
+}
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type FindNotificationResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+	Total         int64          `json:"total"`
+}
+
+type Notification struct {
+	Id          string    `json:"id"`
+	Type        string    `json:"type"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	IdReference string    `json:"id_reference"`
+	IsRead      int       `json:"is_read"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ToFindNotificationResponse(notifications []entity.Notification, unread int64, page int, limit int, total int64) (notificationResponse FindNotificationResponse) {
+	notificationResponse.Notifications = []Notification{}
+	for _, notification := range notifications {
+		notificationResponse.Notifications = append(notificationResponse.Notifications, Notification{
+			Id:          notification.Id,
+			Type:        notification.Type,
+			Title:       notification.Title,
+			Body:        notification.Body,
+			IdReference: notification.IdReference,
+			IsRead:      notification.IsRead,
+			CreatedAt:   notification.CreatedAt,
+		})
+	}
+	notificationResponse.Unread = unread
+	notificationResponse.Page = page
+	notificationResponse.Limit = limit
+	notificationResponse.Total = total
+	return notificationResponse
+}
@@ -18,6 +18,9 @@ type FindOrderSembakoByIdResponse struct {
 	PaymentDueDate   time.Time            `json:"payment_due_date"`
 	SubTotal         float64              `json:"sub_total"`
 	ShippingCost     float64              `json:"shipping_cost"`
+	VoucherCode      string               `json:"voucher_code"`
+	Discount         float64              `json:"discount"`
+	ShippingDiscount float64              `json:"shipping_discount"`
 	PaymentPoint     float64              `json:"payment_point"`
 	PaymentFee       float64              `json:"payment_fee"`
 	PaymentName      string               `json:"payment_name"`
@@ -33,19 +36,32 @@ type FindOrderSembakoByIdResponse struct {
 }
 
 type OrdersItemsSembako struct {
-	Id            string  `json:"id_item_order"`
+	Id            string               `json:"id_item_order"`
+	IdProductDesa string               `json:"id_product_desa"`
+	Price         float64              `json:"price"`
+	TotalPrice    float64              `json:"total_price"`
+	ProductName   string               `json:"product_name"`
+	Description   string               `json:"description"`
+	PictureUrl    string               `json:"picture_url"`
+	Thumbnail     string               `json:"thumbnail"`
+	Qty           int                  `json:"qty"`
+	FlagPromo     int                  `json:"flag_promo"`
+	TierMinQty    int                  `json:"tier_min_qty"`
+	Weight        float64              `json:"weight"`
+	Volume        float64              `json:"volume"`
+	PackageItems  []OrdersItemsPackage `json:"package_items,omitempty"`
+}
+
+type OrdersItemsPackage struct {
 	IdProductDesa string  `json:"id_product_desa"`
-	Price         float64 `json:"price"`
-	TotalPrice    float64 `json:"total_price"`
+	NoSku         string  `json:"no_sku"`
 	ProductName   string  `json:"product_name"`
-	Description   string  `json:"description"`
-	PictureUrl    string  `json:"picture_url"`
-	Thumbnail     string  `json:"thumbnail"`
+	QtyPerPackage int     `json:"qty_per_package"`
 	Qty           int     `json:"qty"`
-	FlagPromo     int     `json:"flag_promo"`
+	Price         float64 `json:"price"`
 }
 
-func ToFindOrderSembakoByIdResponse(order *entity.Order, orderItems []entity.OrderItem, payment *entity.PaymentChannel) (orderResponse FindOrderSembakoByIdResponse) {
+func ToFindOrderSembakoByIdResponse(order *entity.Order, orderItems []entity.OrderItem, orderItemPackages []entity.OrderItemPackage, payment *entity.PaymentChannel) (orderResponse FindOrderSembakoByIdResponse) {
 	orderResponse.Id = order.Id
 	orderResponse.ProductType = order.ProductType
 	orderResponse.OrderType = order.OrderType
@@ -56,6 +72,9 @@ func ToFindOrderSembakoByIdResponse(order *entity.Order, orderItems []entity.Ord
 	orderResponse.PaymentDueDate = order.PaymentDueDate.Time
 	orderResponse.SubTotal = order.SubTotal
 	orderResponse.ShippingCost = order.ShippingCost
+	orderResponse.VoucherCode = order.VoucherCode
+	orderResponse.Discount = order.Discount
+	orderResponse.ShippingDiscount = order.ShippingDiscount
 	orderResponse.PaymentPoint = order.PaymentPoint
 	orderResponse.PaymentFee = order.PaymentFee
 	orderResponse.PaymentCash = order.PaymentCash
@@ -85,6 +104,21 @@ func ToFindOrderSembakoByIdResponse(order *entity.Order, orderItems []entity.Ord
 		orderItemResponse.Thumbnail = orderItem.Thumbnail
 		orderItemResponse.Qty = orderItem.Qty
 		orderItemResponse.FlagPromo = orderItem.FlagPromo
+		orderItemResponse.TierMinQty = orderItem.TierMinQty
+		orderItemResponse.Weight = orderItem.Weight
+		orderItemResponse.Volume = orderItem.Volume
+		for _, orderItemPackage := range orderItemPackages {
+			if orderItemPackage.IdOrderItem == orderItem.Id {
+				orderItemResponse.PackageItems = append(orderItemResponse.PackageItems, OrdersItemsPackage{
+					IdProductDesa: orderItemPackage.IdProductDesa,
+					NoSku:         orderItemPackage.NoSku,
+					ProductName:   orderItemPackage.ProductName,
+					QtyPerPackage: orderItemPackage.QtyPerPackage,
+					Qty:           orderItemPackage.Qty,
+					Price:         orderItemPackage.Price,
+				})
+			}
+		}
 		orderItemsResponses = append(orderItemsResponses, orderItemResponse)
 	}
 
@@ -136,7 +170,7 @@ func ToFindOrderPrepaidPulsaByIdResponse(order *entity.Order, orderItemPpob *ent
 	orderResponse.PaymentMethod = order.PaymentMethod
 	orderResponse.PaymentChannel = order.PaymentChannel
 	orderResponse.PaymentDueDate = order.PaymentDueDate.Time
-	orderResponse.SubTotal = orderItemPpob.TotalTagihan + 1500
+	orderResponse.SubTotal = prepaidSubTotal(orderItemPpob)
 	orderResponse.PaymentPoint = order.PaymentPoint
 	orderResponse.PaymentFee = order.PaymentFee
 	orderResponse.PaymentCash = order.PaymentCash
@@ -205,7 +239,7 @@ func ToFindOrderPrepaidPlnByIdResponse(order *entity.Order, orderItemPpob *entit
 	orderResponse.PaymentMethod = order.PaymentMethod
 	orderResponse.PaymentChannel = order.PaymentChannel
 	orderResponse.PaymentDueDate = order.PaymentDueDate.Time
-	orderResponse.SubTotal = orderItemPpob.TotalTagihan + 1500
+	orderResponse.SubTotal = prepaidSubTotal(orderItemPpob)
 	orderResponse.PaymentPoint = order.PaymentPoint
 	orderResponse.PaymentFee = order.PaymentFee
 	orderResponse.PaymentCash = order.PaymentCash
@@ -433,3 +467,78 @@ func ToFindOrderPaymentyIdResponse(order *entity.Order, payment *entity.PaymentC
 	orderResponse.BankLogo = payment.Logo
 	return orderResponse
 }
+
+// Order prepaid lama belum menyimpan selling price, saat itu margin masih tetap 1500
+func prepaidSubTotal(orderItemPpob *entity.OrderItemPpob) float64 {
+	if orderItemPpob.SellingPrice > 0 {
+		return orderItemPpob.SellingPrice
+	}
+	return orderItemPpob.TotalTagihan + 1500
+}
+
+type FindOrderPpobByIdResponse struct {
+	Id              string          `json:"id_order"`
+	ProductType     string          `json:"product_type"`
+	OrderType       int             `json:"order_type"`
+	NumberOrder     string          `json:"number_order"`
+	OrderStatus     int             `json:"order_status"`
+	PaymentMethod   string          `json:"payment_method"`
+	PaymentChannel  string          `json:"payment_channel"`
+	PaymentDueDate  time.Time       `json:"payment_due_date"`
+	SubTotal        float64         `json:"sub_total"`
+	PaymentPoint    float64         `json:"payment_point"`
+	PaymentFee      float64         `json:"payment_fee"`
+	PaymentName     string          `json:"payment_name"`
+	BankName        string          `json:"bank_name"`
+	BankLogo        string          `json:"bank_logo"`
+	PaymentNumber   string          `json:"payment_number"`
+	PaymentCash     float64         `json:"payment_cash"`
+	TotalBill       float64         `json:"total_bill"`
+	OrderDate       time.Time       `json:"order_date"`
+	OrdersItemsPpob OrdersItemsPpob `json:"order_items"`
+}
+
+type OrdersItemsPpob struct {
+	TrId         int                 `json:"tr_id"`
+	RefId        string              `json:"ref_id"`
+	ProductCode  string              `json:"product_code"`
+	IconUrl      string              `json:"icon_url"`
+	CustomerId   string              `json:"customer_id"`
+	CustomerName string              `json:"customer_name"`
+	Period       string              `json:"period"`
+	Sn           string              `json:"sn"`
+	StatusTopUp  int                 `json:"status_topup"`
+	BillDetail   ppob.PpobBillDetail `json:"bill_detail"`
+}
+
+func ToFindOrderPpobByIdResponse(order *entity.Order, orderItemPpob *entity.OrderItemPpob, detailPpobGeneric *entity.PpobDetailGeneric, payment *entity.PaymentChannel, billDetail ppob.PpobBillDetail) (orderResponse FindOrderPpobByIdResponse) {
+	orderResponse.Id = order.Id
+	orderResponse.ProductType = order.ProductType
+	orderResponse.OrderType = order.OrderType
+	orderResponse.NumberOrder = order.NumberOrder
+	orderResponse.OrderStatus = order.OrderStatus
+	orderResponse.PaymentMethod = order.PaymentMethod
+	orderResponse.PaymentChannel = order.PaymentChannel
+	orderResponse.PaymentDueDate = order.PaymentDueDate.Time
+	orderResponse.SubTotal = orderItemPpob.SellingPrice
+	orderResponse.PaymentPoint = order.PaymentPoint
+	orderResponse.PaymentFee = order.PaymentFee
+	orderResponse.PaymentCash = order.PaymentCash
+	orderResponse.TotalBill = order.TotalBill
+	orderResponse.OrderDate = order.OrderedDate
+	orderResponse.PaymentNumber = order.PaymentNo
+	orderResponse.PaymentName = order.PaymentName
+	orderResponse.BankName = payment.Name
+	orderResponse.BankLogo = payment.Logo
+	orderResponse.OrdersItemsPpob.TrId = orderItemPpob.TrId
+	orderResponse.OrdersItemsPpob.RefId = orderItemPpob.RefId
+	orderResponse.OrdersItemsPpob.ProductCode = orderItemPpob.ProductCode
+	orderResponse.OrdersItemsPpob.IconUrl = orderItemPpob.IconUrl
+	orderResponse.OrdersItemsPpob.CustomerId = detailPpobGeneric.CustomerId
+	orderResponse.OrdersItemsPpob.CustomerName = detailPpobGeneric.CustomerName
+	orderResponse.OrdersItemsPpob.Period = detailPpobGeneric.Period
+	orderResponse.OrdersItemsPpob.Sn = detailPpobGeneric.Sn
+	orderResponse.OrdersItemsPpob.StatusTopUp = detailPpobGeneric.StatusTopUp
+	orderResponse.OrdersItemsPpob.BillDetail = billDetail
+	return orderResponse
+}
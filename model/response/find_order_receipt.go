@@ -0,0 +1,64 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+// FindOrderReceiptResponse data struk yang sama untuk semua tipe produk,
+// rincian khusus produk (token, periode, materai, dll) ada di Details
+type FindOrderReceiptResponse struct {
+	NumberOrder   string                           `json:"number_order"`
+	NamaDesa      string                           `json:"nama_desa"`
+	ProductType   string                           `json:"product_type"`
+	OrderType     int                              `json:"order_type"`
+	OrderDate     time.Time                        `json:"order_date"`
+	NamaLengkap   string                           `json:"nama_lengkap"`
+	Phone         string                           `json:"phone"`
+	PaymentName   string                           `json:"payment_name"`
+	PaymentStatus int                              `json:"payment_status"`
+	OrderStatus   int                              `json:"order_status"`
+	Items         []FindOrderReceiptItemResponse   `json:"items"`
+	Details       []FindOrderReceiptDetailResponse `json:"details"`
+	SubTotal      float64                          `json:"sub_total"`
+	ShippingCost  float64                          `json:"shipping_cost"`
+	Discount      float64                          `json:"discount"`
+	PaymentFee    float64                          `json:"payment_fee"`
+	PaymentPoint  float64                          `json:"payment_point"`
+	TotalBill     float64                          `json:"total_bill"`
+}
+
+type FindOrderReceiptItemResponse struct {
+	ProductName string  `json:"product_name"`
+	Qty         int     `json:"qty"`
+	Price       float64 `json:"price"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+type FindOrderReceiptDetailResponse struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+func ToFindOrderReceiptResponse(order *entity.Order, desa *entity.Desa) (orderReceiptResponse FindOrderReceiptResponse) {
+	orderReceiptResponse.NumberOrder = order.NumberOrder
+	orderReceiptResponse.NamaDesa = desa.NamaDesa
+	orderReceiptResponse.ProductType = order.ProductType
+	orderReceiptResponse.OrderType = order.OrderType
+	orderReceiptResponse.OrderDate = order.OrderedDate
+	orderReceiptResponse.NamaLengkap = order.NamaLengkap
+	orderReceiptResponse.Phone = order.Phone
+	orderReceiptResponse.PaymentName = order.PaymentName
+	orderReceiptResponse.PaymentStatus = order.PaymentStatus
+	orderReceiptResponse.OrderStatus = order.OrderStatus
+	orderReceiptResponse.Items = []FindOrderReceiptItemResponse{}
+	orderReceiptResponse.Details = []FindOrderReceiptDetailResponse{}
+	orderReceiptResponse.SubTotal = order.SubTotal
+	orderReceiptResponse.ShippingCost = order.ShippingCost
+	orderReceiptResponse.Discount = order.Discount + order.ShippingDiscount
+	orderReceiptResponse.PaymentFee = order.PaymentFee
+	orderReceiptResponse.PaymentPoint = order.PaymentPoint
+	orderReceiptResponse.TotalBill = order.TotalBill
+	return orderReceiptResponse
+}
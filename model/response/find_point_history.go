@@ -0,0 +1,68 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type FindPointHistoryResponse struct {
+	Histories []PointHistory `json:"histories"`
+	Page      int            `json:"page"`
+	Limit     int            `json:"limit"`
+	Total     int64          `json:"total"`
+}
+
+type PointHistory struct {
+	Id          string    `json:"id"`
+	IdOrder     string    `json:"id_order"`
+	Debit       float64   `json:"debit"`
+	Kredit      float64   `json:"kredit"`
+	Description string    `json:"description"`
+	TransDate   time.Time `json:"trans_date"`
+	ExpiredDate time.Time `json:"expired_date"`
+}
+
+func ToFindPointHistoryResponse(pointHistories []entity.PointHistory, page int, limit int, total int64) (pointHistoryResponse FindPointHistoryResponse) {
+	pointHistoryResponse.Histories = []PointHistory{}
+	for _, pointHistory := range pointHistories {
+		pointHistoryResponse.Histories = append(pointHistoryResponse.Histories, PointHistory{
+			Id:          pointHistory.Id,
+			IdOrder:     pointHistory.IdOrder,
+			Debit:       pointHistory.Debit,
+			Kredit:      pointHistory.Kredit,
+			Description: pointHistory.Description,
+			TransDate:   pointHistory.TransDate,
+			ExpiredDate: pointHistory.ExpiredDate.Time,
+		})
+	}
+	pointHistoryResponse.Page = page
+	pointHistoryResponse.Limit = limit
+	pointHistoryResponse.Total = total
+	return pointHistoryResponse
+}
+
+type FindPointRuleResponse struct {
+	Id            string  `json:"id"`
+	RuleType      int     `json:"rule_type"`
+	IdProductDesa string  `json:"id_product_desa"`
+	MinAmount     float64 `json:"min_amount"`
+	Point         float64 `json:"point"`
+	Description   string  `json:"description"`
+	IsActive      int     `json:"is_active"`
+}
+
+func ToFindPointRuleResponses(pointRules []entity.PointRule) (pointRuleResponses []FindPointRuleResponse) {
+	for _, pointRule := range pointRules {
+		pointRuleResponses = append(pointRuleResponses, FindPointRuleResponse{
+			Id:            pointRule.Id,
+			RuleType:      pointRule.RuleType,
+			IdProductDesa: pointRule.IdProductDesa,
+			MinAmount:     pointRule.MinAmount,
+			Point:         pointRule.Point,
+			Description:   pointRule.Description,
+			IsActive:      pointRule.IsActive,
+		})
+	}
+	return pointRuleResponses
+}
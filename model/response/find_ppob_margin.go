@@ -0,0 +1,26 @@
+package response
+
+import "github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+
+type FindPpobMarginResponse struct {
+	Id          string  `json:"id"`
+	ProductType string  `json:"product_type"`
+	Operator    string  `json:"operator"`
+	MarginType  int     `json:"margin_type"`
+	Margin      float64 `json:"margin"`
+	IsActive    int     `json:"is_active"`
+}
+
+func ToFindPpobMarginResponses(ppobMargins []entity.PpobMargin) (ppobMarginResponses []FindPpobMarginResponse) {
+	for _, ppobMargin := range ppobMargins {
+		ppobMarginResponses = append(ppobMarginResponses, FindPpobMarginResponse{
+			Id:          ppobMargin.Id,
+			ProductType: ppobMargin.ProductType,
+			Operator:    ppobMargin.Operator,
+			MarginType:  ppobMargin.MarginType,
+			Margin:      ppobMargin.Margin,
+			IsActive:    ppobMargin.IsActive,
+		})
+	}
+	return ppobMarginResponses
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"strings"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type FindPpobProductTypeResponse struct {
+	Code            string   `json:"code"`
+	Name            string   `json:"name"`
+	Category        string   `json:"category"`
+	CustomerIdLabel string   `json:"customer_id_label"`
+	InquiryParams   []string `json:"inquiry_params"`
+	IconUrl         string   `json:"icon_url"`
+}
+
+func ToFindPpobProductTypeResponses(ppobProductTypes []entity.PpobProductType) (ppobProductTypeResponses []FindPpobProductTypeResponse) {
+	for _, ppobProductType := range ppobProductTypes {
+		ppobProductTypeResponse := FindPpobProductTypeResponse{}
+		ppobProductTypeResponse.Code = ppobProductType.Code
+		ppobProductTypeResponse.Name = ppobProductType.Name
+		ppobProductTypeResponse.Category = ppobProductType.Category
+		ppobProductTypeResponse.CustomerIdLabel = ppobProductType.CustomerIdLabel
+		ppobProductTypeResponse.InquiryParams = []string{}
+		if len(ppobProductType.InquiryParams) != 0 {
+			ppobProductTypeResponse.InquiryParams = strings.Split(ppobProductType.InquiryParams, ",")
+		}
+		ppobProductTypeResponse.IconUrl = ppobProductType.IconUrl
+		ppobProductTypeResponses = append(ppobProductTypeResponses, ppobProductTypeResponse)
+	}
+	return ppobProductTypeResponses
+}
@@ -0,0 +1,57 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type FindPpobReconciliationResponse struct {
+	Id                string                       `json:"id"`
+	ReconcileDate     string                       `json:"reconcile_date"`
+	TotalOrder        int                          `json:"total_order"`
+	TotalMatch        int                          `json:"total_match"`
+	TotalMismatch     int                          `json:"total_mismatch"`
+	TotalPending      int                          `json:"total_pending"`
+	TotalAmount       float64                      `json:"total_amount"`
+	ProviderAmount    float64                      `json:"provider_amount"`
+	LastBalance       float64                      `json:"last_balance"`
+	ProviderBalance   float64                      `json:"provider_balance"`
+	BalanceDifference float64                      `json:"balance_difference"`
+	Mismatches        []PpobReconciliationMismatch `json:"mismatches"`
+}
+
+// PpobReconciliationMismatch status 0 pending, 1 sukses, 2 gagal
+type PpobReconciliationMismatch struct {
+	NumberOrder    string  `json:"number_order"`
+	RefId          string  `json:"ref_id"`
+	ProductType    string  `json:"product_type"`
+	ProductCode    string  `json:"product_code"`
+	Status         int     `json:"status"`
+	ProviderStatus int     `json:"provider_status"`
+	Amount         float64 `json:"amount"`
+	ProviderAmount float64 `json:"provider_amount"`
+	Note           string  `json:"note"`
+}
+
+func ToFindPpobReconciliationResponses(ppobReconciliations []entity.PpobReconciliation) (ppobReconciliationResponses []FindPpobReconciliationResponse) {
+	for _, ppobReconciliation := range ppobReconciliations {
+		mismatches := []PpobReconciliationMismatch{}
+		_ = json.Unmarshal([]byte(ppobReconciliation.DetailJson), &mismatches)
+		ppobReconciliationResponses = append(ppobReconciliationResponses, FindPpobReconciliationResponse{
+			Id:                ppobReconciliation.Id,
+			ReconcileDate:     ppobReconciliation.ReconcileDate.Format("2006-01-02"),
+			TotalOrder:        ppobReconciliation.TotalOrder,
+			TotalMatch:        ppobReconciliation.TotalMatch,
+			TotalMismatch:     ppobReconciliation.TotalMismatch,
+			TotalPending:      ppobReconciliation.TotalPending,
+			TotalAmount:       ppobReconciliation.TotalAmount,
+			ProviderAmount:    ppobReconciliation.ProviderAmount,
+			LastBalance:       ppobReconciliation.LastBalance,
+			ProviderBalance:   ppobReconciliation.ProviderBalance,
+			BalanceDifference: ppobReconciliation.BalanceDifference,
+			Mismatches:        mismatches,
+		})
+	}
+	return ppobReconciliationResponses
+}
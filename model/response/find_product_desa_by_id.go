@@ -77,9 +77,9 @@ func ToFindProductDesaByIdResponse(productDesa *entity.ProductsDesa, AccountType
 			listItemResponse := ListItemsPackage{}
 			listItemResponse.Id = listItem.Id
 			listItemResponse.ProductName = listItem.ProductName
-			productDesaResponse.Description = productDesa.ProductsMaster.Description
-			productDesaResponse.PictureUrl = productDesa.ProductsMaster.PictureUrl
-			productDesaResponse.Thumbnail = productDesa.ProductsMaster.Thumbnail
+			listItemResponse.Description = listItem.ProductsDesa.ProductsMaster.Description
+			listItemResponse.PictureUrl = listItem.ProductsDesa.ProductsMaster.PictureUrl
+			listItemResponse.Thumbnail = listItem.ProductsDesa.ProductsMaster.Thumbnail
 			listItemResponse.Price = listItem.Price
 			listItemResponse.Qty = listItem.Qty
 			listItemResponse.SubTotal = listItem.SubTotal
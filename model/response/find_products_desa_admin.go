@@ -0,0 +1,109 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type FindProductsDesaAdminResponse struct {
+	Id              string  `json:"id"`
+	IdProductMaster string  `json:"id_product_master"`
+	IdType          int     `json:"id_type"`
+	IdCategory      int     `json:"id_category"`
+	IdSubCategory   int     `json:"id_sub_category"`
+	IdUnit          int     `json:"id_unit"`
+	NoSku           string  `json:"no_sku"`
+	ProductName     string  `json:"product_name"`
+	Price           float64 `json:"price"`
+	PriceGrosir     float64 `json:"price_grosir"`
+	PricePromo      float64 `json:"price_promo"`
+	PromoPercentage float64 `json:"promo_percentage"`
+	FlagPromo       int     `json:"flag_promo"`
+	PictureUrl      string  `json:"picture_url"`
+	Thumbnail       string  `json:"thumbnail"`
+	StockOpname     int     `json:"stock_opname"`
+}
+
+func ToFindProductsDesaAdminResponse(productsDesas []entity.ProductsDesa) (productsDesaResponses []FindProductsDesaAdminResponse) {
+	for _, productDesa := range productsDesas {
+		productsDesaResponse := FindProductsDesaAdminResponse{}
+		productsDesaResponse.Id = productDesa.Id
+		productsDesaResponse.IdProductMaster = productDesa.IdProduct
+		productsDesaResponse.IdType = productDesa.IdType
+		productsDesaResponse.IdCategory = productDesa.ProductsMaster.IdCategory
+		productsDesaResponse.IdSubCategory = productDesa.ProductsMaster.IdSubCategory
+		productsDesaResponse.IdUnit = productDesa.ProductsMaster.IdUnit
+		productsDesaResponse.NoSku = productDesa.ProductsMaster.NoSku
+		productsDesaResponse.ProductName = productDesa.ProductsMaster.ProductName
+		productsDesaResponse.Price = productDesa.Price
+		productsDesaResponse.PriceGrosir = productDesa.PriceGrosir
+		productsDesaResponse.PricePromo = productDesa.PricePromo
+		productsDesaResponse.PromoPercentage = productDesa.PercentagePromo
+		productsDesaResponse.FlagPromo = productDesa.IsPromo
+		if len(productDesa.PictureUrl) != 0 {
+			productsDesaResponse.PictureUrl = productDesa.PictureUrl
+			productsDesaResponse.Thumbnail = productDesa.Thumbnail
+		} else {
+			productsDesaResponse.PictureUrl = productDesa.ProductsMaster.PictureUrl
+			productsDesaResponse.Thumbnail = productDesa.ProductsMaster.Thumbnail
+		}
+		productsDesaResponse.StockOpname = productDesa.StockOpname
+		productsDesaResponses = append(productsDesaResponses, productsDesaResponse)
+	}
+	return productsDesaResponses
+}
+
+type FindProductDesaStockHistoryResponse struct {
+	Id          string    `json:"id"`
+	TransDate   time.Time `json:"trans_date"`
+	AddStockQty int       `json:"add_stock_qty"`
+	MinStockQty int       `json:"min_stock_qty"`
+	StockOpname int       `json:"stock_opname"`
+	StockFinal  int       `json:"stock_final"`
+	HargaBeli   float64   `json:"harga_beli"`
+	HargaJual   float64   `json:"harga_jual"`
+	Description string    `json:"description"`
+}
+
+func ToFindProductDesaStockHistoryResponse(productDesaStockHistories []entity.ProductDesaStockHistory) (stockHistoryResponses []FindProductDesaStockHistoryResponse) {
+	for _, stockHistory := range productDesaStockHistories {
+		stockHistoryResponse := FindProductDesaStockHistoryResponse{}
+		stockHistoryResponse.Id = stockHistory.Id
+		stockHistoryResponse.TransDate = stockHistory.TransDate
+		stockHistoryResponse.AddStockQty = stockHistory.AddStockQty
+		stockHistoryResponse.MinStockQty = stockHistory.MinStockQty
+		stockHistoryResponse.StockOpname = stockHistory.StockOpname
+		stockHistoryResponse.StockFinal = stockHistory.StockFinal
+		stockHistoryResponse.HargaBeli = stockHistory.HargaBeli
+		stockHistoryResponse.HargaJual = stockHistory.HargaJual
+		stockHistoryResponse.Description = stockHistory.Description
+		stockHistoryResponses = append(stockHistoryResponses, stockHistoryResponse)
+	}
+	return stockHistoryResponses
+}
+
+type ImportProductsDesaResponse struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+type FindProductPriceTierResponse struct {
+	Id          string  `json:"id"`
+	AccountType int     `json:"account_type"`
+	MinQty      int     `json:"min_qty"`
+	Price       float64 `json:"price"`
+}
+
+func ToFindProductPriceTierResponse(priceTiers []entity.ProductsDesaPriceTier) (priceTierResponses []FindProductPriceTierResponse) {
+	for _, priceTier := range priceTiers {
+		priceTierResponse := FindProductPriceTierResponse{}
+		priceTierResponse.Id = priceTier.Id
+		priceTierResponse.AccountType = priceTier.AccountType
+		priceTierResponse.MinQty = priceTier.MinQty
+		priceTierResponse.Price = priceTier.Price
+		priceTierResponses = append(priceTierResponses, priceTierResponse)
+	}
+	return priceTierResponses
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gopkg.in/guregu/null.v4"
+)
+
+type FindReferralStatsResponse struct {
+	ReferralCode   string                 `json:"referral_code"`
+	TotalReferral  int                    `json:"total_referral"`
+	TotalPending   int                    `json:"total_pending"`
+	TotalRewarded  int                    `json:"total_rewarded"`
+	TotalRejected  int                    `json:"total_rejected"`
+	TotalPoint     float64                `json:"total_point"`
+	ReferralDetail []FindReferralResponse `json:"referral_detail"`
+}
+
+type FindReferralResponse struct {
+	Status       int       `json:"status"`
+	RejectReason string    `json:"reject_reason"`
+	Point        float64   `json:"point"`
+	CreatedAt    time.Time `json:"created_at"`
+	RewardedAt   null.Time `json:"rewarded_at"`
+}
+
+func ToFindReferralStatsResponse(referralCode string, referrals []entity.Referral) (referralStatsResponse FindReferralStatsResponse) {
+	referralStatsResponse.ReferralCode = referralCode
+	referralStatsResponse.TotalReferral = len(referrals)
+	referralStatsResponse.ReferralDetail = []FindReferralResponse{}
+	for _, referral := range referrals {
+		switch referral.Status {
+		case 1:
+			referralStatsResponse.TotalPending++
+		case 2:
+			referralStatsResponse.TotalRewarded++
+			referralStatsResponse.TotalPoint = referralStatsResponse.TotalPoint + referral.ReferrerPoint
+		case 3:
+			referralStatsResponse.TotalRejected++
+		}
+		referralStatsResponse.ReferralDetail = append(referralStatsResponse.ReferralDetail, FindReferralResponse{
+			Status:       referral.Status,
+			RejectReason: referral.RejectReason,
+			Point:        referral.ReferrerPoint,
+			CreatedAt:    referral.CreatedAt,
+			RewardedAt:   referral.RewardedAt,
+		})
+	}
+	return referralStatsResponse
+}
@@ -0,0 +1,34 @@
+package response
+
+import "github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+
+// FindShippingQuoteResponse Flat true jika desa belum punya shipping zone dan memakai ongkir desa
+type FindShippingQuoteResponse struct {
+	IdAddress    string  `json:"id_address"`
+	DistanceKm   float64 `json:"distance_km"`
+	ShippingCost float64 `json:"shipping_cost"`
+	FreeKm       float64 `json:"free_km"`
+	MaxKm        float64 `json:"max_km"`
+	Flat         bool    `json:"flat"`
+}
+
+type FindShippingZoneResponse struct {
+	IdDesa         string  `json:"id_desa"`
+	DepotLatitude  float64 `json:"depot_latitude"`
+	DepotLongitude float64 `json:"depot_longitude"`
+	FreeKm         float64 `json:"free_km"`
+	BaseCost       float64 `json:"base_cost"`
+	RatePerKm      float64 `json:"rate_per_km"`
+	MaxKm          float64 `json:"max_km"`
+}
+
+func ToFindShippingZoneResponse(shippingZone *entity.ShippingZone) (shippingZoneResponse FindShippingZoneResponse) {
+	shippingZoneResponse.IdDesa = shippingZone.IdDesa
+	shippingZoneResponse.DepotLatitude = shippingZone.DepotLatitude
+	shippingZoneResponse.DepotLongitude = shippingZone.DepotLongitude
+	shippingZoneResponse.FreeKm = shippingZone.FreeKm
+	shippingZoneResponse.BaseCost = shippingZone.BaseCost
+	shippingZoneResponse.RatePerKm = shippingZone.RatePerKm
+	shippingZoneResponse.MaxKm = shippingZone.MaxKm
+	return shippingZoneResponse
+}
@@ -0,0 +1,61 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gopkg.in/guregu/null.v4"
+)
+
+type FindTransferVerificationResponse struct {
+	IdOrder        string    `json:"id_order"`
+	NumberOrder    string    `json:"number_order"`
+	OrderType      int       `json:"order_type"`
+	ProductType    string    `json:"product_type"`
+	NamaLengkap    string    `json:"nama_lengkap"`
+	Phone          string    `json:"phone"`
+	TotalBill      float64   `json:"total_bill"`
+	PaymentCash    float64   `json:"payment_cash"`
+	UniqueCode     float64   `json:"unique_code"`
+	PaymentNo      string    `json:"payment_no"`
+	PaymentDueDate null.Time `json:"payment_due_date"`
+	FotoBuktiBayar string    `json:"foto_bukti_bayar"`
+	BuktiBayarDate null.Time `json:"bukti_bayar_date"`
+	BuktiBayarNote string    `json:"bukti_bayar_note"`
+	OrderedDate    time.Time `json:"ordered_date"`
+}
+
+// ImportBankMutationResponse ringkasan import mutasi, Skipped berisi baris csv yang tidak bisa dibaca
+type ImportBankMutationResponse struct {
+	Total         int      `json:"total"`
+	Matched       int      `json:"matched"`
+	Unmatched     int      `json:"unmatched"`
+	Duplicate     int      `json:"duplicate"`
+	MatchedOrders []string `json:"matched_orders"`
+	Skipped       []string `json:"skipped"`
+}
+
+// ToFindTransferVerificationResponses fotoBuktiBayar url yang sudah ditandatangani per order
+func ToFindTransferVerificationResponses(orders []entity.Order, fotoBuktiBayar map[string]string) (transferVerificationResponses []FindTransferVerificationResponse) {
+	transferVerificationResponses = []FindTransferVerificationResponse{}
+	for _, order := range orders {
+		transferVerificationResponses = append(transferVerificationResponses, FindTransferVerificationResponse{
+			IdOrder:        order.Id,
+			NumberOrder:    order.NumberOrder,
+			OrderType:      order.OrderType,
+			ProductType:    order.ProductType,
+			NamaLengkap:    order.NamaLengkap,
+			Phone:          order.Phone,
+			TotalBill:      order.TotalBill,
+			PaymentCash:    order.PaymentCash,
+			UniqueCode:     order.PaymentCash - order.TotalBill,
+			PaymentNo:      order.PaymentNo,
+			PaymentDueDate: order.PaymentDueDate,
+			FotoBuktiBayar: fotoBuktiBayar[order.Id],
+			BuktiBayarDate: order.BuktiBayarDate,
+			BuktiBayarNote: order.BuktiBayarNote,
+			OrderedDate:    order.OrderedDate,
+		})
+	}
+	return transferVerificationResponses
+}
@@ -0,0 +1,69 @@
+package response
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+)
+
+type FindVoucherResponse struct {
+	Id            string    `json:"id"`
+	Code          string    `json:"code"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	DiscountType  int       `json:"discount_type"`
+	DiscountValue float64   `json:"discount_value"`
+	MaxDiscount   float64   `json:"max_discount"`
+	MinSpend      float64   `json:"min_spend"`
+	QuotaTotal    int       `json:"quota_total"`
+	QuotaPerUser  int       `json:"quota_per_user"`
+	UsedCount     int       `json:"used_count"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	IsActive      int       `json:"is_active"`
+}
+
+func ToFindVoucherResponses(vouchers []entity.Voucher) (voucherResponses []FindVoucherResponse) {
+	for _, voucher := range vouchers {
+		voucherResponse := FindVoucherResponse{}
+		voucherResponse.Id = voucher.Id
+		voucherResponse.Code = voucher.Code
+		voucherResponse.Title = voucher.Title
+		voucherResponse.Description = voucher.Description
+		voucherResponse.DiscountType = voucher.DiscountType
+		voucherResponse.DiscountValue = voucher.DiscountValue
+		voucherResponse.MaxDiscount = voucher.MaxDiscount
+		voucherResponse.MinSpend = voucher.MinSpend
+		voucherResponse.QuotaTotal = voucher.QuotaTotal
+		voucherResponse.QuotaPerUser = voucher.QuotaPerUser
+		voucherResponse.UsedCount = voucher.UsedCount
+		voucherResponse.StartDate = voucher.StartDate
+		voucherResponse.EndDate = voucher.EndDate
+		voucherResponse.IsActive = voucher.IsActive
+		voucherResponses = append(voucherResponses, voucherResponse)
+	}
+	return voucherResponses
+}
+
+type CheckVoucherResponse struct {
+	VoucherCode      string  `json:"voucher_code"`
+	Title            string  `json:"title"`
+	DiscountType     int     `json:"discount_type"`
+	Discount         float64 `json:"discount"`
+	ShippingDiscount float64 `json:"shipping_discount"`
+	SubTotal         float64 `json:"sub_total"`
+	ShippingCost     float64 `json:"shipping_cost"`
+	TotalBill        float64 `json:"total_bill"`
+}
+
+func ToCheckVoucherResponse(voucher *entity.Voucher, subTotal, shippingCost, discount, shippingDiscount float64) (checkVoucherResponse CheckVoucherResponse) {
+	checkVoucherResponse.VoucherCode = voucher.Code
+	checkVoucherResponse.Title = voucher.Title
+	checkVoucherResponse.DiscountType = voucher.DiscountType
+	checkVoucherResponse.Discount = discount
+	checkVoucherResponse.ShippingDiscount = shippingDiscount
+	checkVoucherResponse.SubTotal = subTotal
+	checkVoucherResponse.ShippingCost = shippingCost
+	checkVoucherResponse.TotalBill = subTotal - discount + shippingCost - shippingDiscount
+	return checkVoucherResponse
+}
@@ -13,7 +13,7 @@ type GetPostpaidTelcoProductResponse struct {
 	Type   string  `json:"type"`
 }
 
-func ToGetPostpaidTelcoProductResponse(telcoProducts *ppob.InquiryProductPostpaidPPOB) (telcoProductResponses []GetPostpaidTelcoProductResponse) {
+func ToGetPostpaidTelcoProductResponse(telcoProducts *ppob.PostpaidPriceListResponse) (telcoProductResponses []GetPostpaidTelcoProductResponse) {
 	for _, telcoProduct := range telcoProducts.Data.Pasca {
 		telcoProductResponse := GetPostpaidTelcoProductResponse{}
 		telcoProductResponse.Code = telcoProduct.Code
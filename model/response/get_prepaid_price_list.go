@@ -27,7 +27,7 @@ func ToGetPrepaidPriceListResponse(priceLists []ppob.PrepaidPriceList) (priceLis
 			pulsaPriceListResponse.ProductDescription = priceList.ProductDescription
 			pulsaPriceListResponse.ProductNominal = priceList.ProductNominal
 			pulsaPriceListResponse.ProductDetails = priceList.ProductDetails
-			pulsaPriceListResponse.ProductPrice = priceList.ProductPrice + 1500
+			pulsaPriceListResponse.ProductPrice = priceList.ProductPrice
 			pulsaPriceListResponse.ProductType = priceList.ProductType
 			pulsaPriceListResponse.ActivePeriod = priceList.ActivePeriod
 			pulsaPriceListResponse.Status = priceList.Status
@@ -45,7 +45,7 @@ func ToGetPrepaidDataPriceListResponse(priceLists []ppob.PrepaidPriceList) (pric
 		pulsaPriceListResponse.ProductDescription = priceList.ProductDescription
 		pulsaPriceListResponse.ProductNominal = priceList.ProductNominal
 		pulsaPriceListResponse.ProductDetails = priceList.ProductDetails
-		pulsaPriceListResponse.ProductPrice = priceList.ProductPrice + 1500
+		pulsaPriceListResponse.ProductPrice = priceList.ProductPrice
 		pulsaPriceListResponse.ProductType = priceList.ProductType
 		pulsaPriceListResponse.ActivePeriod = priceList.ActivePeriod
 		pulsaPriceListResponse.Status = priceList.Status
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+)
+
+type InquiryPpobResponse struct {
+	TrxId        int             `json:"trx_id"`
+	ProductType  string          `json:"product_type"`
+	Code         string          `json:"code"`
+	CustomerId   string          `json:"customer_id"`
+	CustomerName string          `json:"customer_name"`
+	Period       string          `json:"period"`
+	Nominal      float64         `json:"nominal"`
+	AdminFee     float64         `json:"admin_fee"`
+	Price        float64         `json:"price"`
+	SellingPrice float64         `json:"selling_price"`
+	RefId        string          `json:"ref_id"`
+	Desc         json.RawMessage `json:"desc,omitempty"`
+}
+
+func ToInquiryPpobResponse(productType string, inquiry *ppob.PostpaidTransaction, refId string) (inquiryPpobResponse InquiryPpobResponse) {
+	inquiryPpobResponse.TrxId = inquiry.Data.TrxId
+	inquiryPpobResponse.ProductType = productType
+	inquiryPpobResponse.Code = inquiry.Data.Code
+	inquiryPpobResponse.CustomerId = inquiry.Data.Hp
+	inquiryPpobResponse.CustomerName = inquiry.Data.TrName
+	inquiryPpobResponse.Period = inquiry.Data.Period
+	inquiryPpobResponse.Nominal = inquiry.Data.Nominal
+	inquiryPpobResponse.AdminFee = inquiry.Data.Admin
+	inquiryPpobResponse.Price = inquiry.Data.Price
+	inquiryPpobResponse.SellingPrice = inquiry.Data.SellingPrice
+	inquiryPpobResponse.RefId = refId
+	inquiryPpobResponse.Desc = inquiry.Data.Desc
+	return inquiryPpobResponse
+}
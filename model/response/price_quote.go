@@ -0,0 +1,16 @@
+package response
+
+// PriceQuote harga yang berlaku untuk satu produk pada qty tertentu
+type PriceQuote struct {
+	IdProductDesa string  `json:"id_product_desa"`
+	Qty           int     `json:"qty"`
+	Price         float64 `json:"price_normal"`
+	PricePromo    float64 `json:"price_promo"`
+	FlagPromo     int     `json:"flag_promo"`
+	UnitPrice     float64 `json:"unit_price"`
+	TotalPrice    float64 `json:"total_price"`
+	IdPriceTier   string  `json:"id_price_tier"`
+	TierMinQty    int     `json:"tier_min_qty"`
+	PriceInfo     string  `json:"price_info"`
+	AccountType   string  `json:"account_type"`
+}
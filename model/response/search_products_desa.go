@@ -0,0 +1,7 @@
+package response
+
+type SearchProductsDesaResponse struct {
+	Products   []FindProductsDesaResponse `json:"products"`
+	Total      int64                      `json:"total"`
+	NextCursor string                     `json:"next_cursor"`
+}
@@ -0,0 +1,10 @@
+package response
+
+// UploadFileResponse Key disimpan ke kolom foto untuk file private, Url untuk file publik
+type UploadFileResponse struct {
+	Key          string `json:"key"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Size         int    `json:"size"`
+}
@@ -11,7 +11,9 @@ type Auth struct {
 type TokenClaims struct {
 	Id          string `json:"id"`
 	IdDesa      string `json:"id_desa"`
+	IdRole      string `json:"id_role,omitempty"`
 	AccountType int    `json:"account_type"`
 	Phone       string `json:"phone"`
+	TypeOtp     int    `json:"type_otp,omitempty"`
 	jwt.StandardClaims
 }
@@ -5,6 +5,7 @@ import "time"
 type User struct {
 	Id           string
 	IdDesa       string
+	IdRole       string
 	AccountType  int
 	Phone        string
 	Password     string
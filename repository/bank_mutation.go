@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type BankMutationRepositoryInterface interface {
+	CreateBankMutation(db *gorm.DB, bankMutation *entity.BankMutation) error
+	FindBankMutationByRef(db *gorm.DB, idDesa string, source string, mutationRef string) (*entity.BankMutation, error)
+}
+
+type BankMutationRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewBankMutationRepository(
+	db *config.Database,
+) BankMutationRepositoryInterface {
+	return &BankMutationRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *BankMutationRepositoryImplementation) CreateBankMutation(db *gorm.DB, bankMutation *entity.BankMutation) error {
+	result := db.Create(bankMutation)
+	return result.Error
+}
+
+func (repository *BankMutationRepositoryImplementation) FindBankMutationByRef(db *gorm.DB, idDesa string, source string, mutationRef string) (*entity.BankMutation, error) {
+	bankMutation := &entity.BankMutation{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Where("source = ?", source).
+		Where("mutation_ref = ?", mutationRef).
+		Find(bankMutation)
+	return bankMutation, result.Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/cache"
+	"gorm.io/gorm"
+)
+
+// cacheReadThrough membaca dest dari cache, jika miss memanggil load lalu menyimpan hasilnya.
+// Query di dalam transaksi selalu langsung ke database.
+func cacheReadThrough(c cache.Cache, db *gorm.DB, key string, ttl time.Duration, dest interface{}, load func() error) error {
+	if inTransaction(db) {
+		return load()
+	}
+
+	if data, ok := c.Get(key); ok {
+		if err := json.Unmarshal(data, dest); err == nil {
+			return nil
+		}
+		c.Delete(key)
+	}
+
+	if err := load(); err != nil {
+		return err
+	}
+	if data, err := json.Marshal(dest); err == nil {
+		c.Set(key, data, ttl)
+	}
+	return nil
+}
+
+// cacheInvalidate dipanggil setelah write. Jika write di dalam transaksi, namespace dicatat di
+// transaksi dan diinvalidasi lagi oleh CommitTransaction karena request lain bisa mengisi cache
+// sebelum commit.
+func cacheInvalidate(c cache.Cache, db *gorm.DB, namespace string) {
+	c.Invalidate(namespace)
+	if inTransaction(db) {
+		pending, _ := db.Statement.Settings.LoadOrStore(cachePendingInvalidationKey, &cachePendingInvalidation{})
+		pending.(*cachePendingInvalidation).add(c, namespace)
+	}
+}
+
+const cachePendingInvalidationKey = "cache:pending_invalidation"
+
+type cachePendingInvalidation struct {
+	mutex      sync.Mutex
+	namespaces []cacheNamespace
+}
+
+type cacheNamespace struct {
+	cache     cache.Cache
+	namespace string
+}
+
+func (pending *cachePendingInvalidation) add(c cache.Cache, namespace string) {
+	pending.mutex.Lock()
+	defer pending.mutex.Unlock()
+	for _, item := range pending.namespaces {
+		if item.cache == c && item.namespace == namespace {
+			return
+		}
+	}
+	pending.namespaces = append(pending.namespaces, cacheNamespace{cache: c, namespace: namespace})
+}
+
+// CommitTransaction commit tx lalu menginvalidasi cache yang diubah di dalam transaksi.
+// Dipakai sebagai pengganti tx.Commit() untuk transaksi yang menulis ke repository ber-cache.
+func CommitTransaction(tx *gorm.DB) *gorm.DB {
+	commit := tx.Commit()
+	if commit.Error != nil {
+		return commit
+	}
+	if pending, ok := tx.Statement.Settings.Load(cachePendingInvalidationKey); ok {
+		pending := pending.(*cachePendingInvalidation)
+		pending.mutex.Lock()
+		defer pending.mutex.Unlock()
+		for _, item := range pending.namespaces {
+			item.cache.Invalidate(item.namespace)
+		}
+	}
+	return commit
+}
+
+func inTransaction(db *gorm.DB) bool {
+	_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
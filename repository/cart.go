@@ -11,6 +11,7 @@ type CartRepositoryInterface interface {
 	UpdateCart(db *gorm.DB, idCart string, cart *entity.Cart) error
 	FindCartByUser(db *gorm.DB, idUser string) ([]entity.Cart, error)
 	FindCartById(db *gorm.DB, idCart string) (*entity.Cart, error)
+	FindCartByIdUser(db *gorm.DB, idCart, idUser string) (*entity.Cart, error)
 	FindCartByProductDesa(db *gorm.DB, idUser, idProductDesa string) (*entity.Cart, error)
 	DeleteCartById(db *gorm.DB, idCart string) error
 	DeleteCartByUser(db *gorm.DB, idUser string, cart []entity.Cart) error
@@ -59,6 +60,12 @@ func (repository *CartRepositoryImplementation) FindCartById(db *gorm.DB, idCart
 	return cart, result.Error
 }
 
+func (repository *CartRepositoryImplementation) FindCartByIdUser(db *gorm.DB, idCart, idUser string) (*entity.Cart, error) {
+	cart := &entity.Cart{}
+	result := db.Find(cart, "id = ? AND id_user = ?", idCart, idUser)
+	return cart, result.Error
+}
+
 func (repository *CartRepositoryImplementation) FindCartByProductDesa(db *gorm.DB, idUser, idProductDesa string) (*entity.Cart, error) {
 	cart := &entity.Cart{}
 	result := db.Find(cart, "id_user = ? AND id_product_desa = ?", idUser, idProductDesa)
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/cache"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+// Cache untuk data katalog yang jarang berubah (banner, promo, wilayah dan setting).
+// Selain sinkronisasi jadwal promo tidak ada write dari aplikasi sehingga cache hanya bergantung pada ttl.
+
+type CachedBannerRepositoryImplementation struct {
+	BannerRepositoryInterface BannerRepositoryInterface
+	Cache                     cache.Cache
+	ConfigCache               config.Cache
+}
+
+func NewCachedBannerRepository(
+	bannerRepositoryInterface BannerRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) BannerRepositoryInterface {
+	return &CachedBannerRepositoryImplementation{
+		BannerRepositoryInterface: bannerRepositoryInterface,
+		Cache:                     c,
+		ConfigCache:               configCache,
+	}
+}
+
+func (repository *CachedBannerRepositoryImplementation) FindBannerByDesa(db *gorm.DB, idDesa string) (banners []entity.Banner, err error) {
+	key := cache.Key(repository.Cache, cache.NamespaceBanner, "desa", idDesa)
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceBanner), &banners, func() (err error) {
+		banners, err = repository.BannerRepositoryInterface.FindBannerByDesa(db, idDesa)
+		return err
+	})
+	return banners, err
+}
+
+func (repository *CachedBannerRepositoryImplementation) FindBannerAll(db *gorm.DB) (banners []entity.Banner, err error) {
+	key := cache.Key(repository.Cache, cache.NamespaceBanner, "all")
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceBanner), &banners, func() (err error) {
+		banners, err = repository.BannerRepositoryInterface.FindBannerAll(db)
+		return err
+	})
+	return banners, err
+}
+
+type CachedPromoRepositoryImplementation struct {
+	PromoRepositoryInterface PromoRepositoryInterface
+	Cache                    cache.Cache
+	ConfigCache              config.Cache
+}
+
+func NewCachedPromoRepository(
+	promoRepositoryInterface PromoRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) PromoRepositoryInterface {
+	return &CachedPromoRepositoryImplementation{
+		PromoRepositoryInterface: promoRepositoryInterface,
+		Cache:                    c,
+		ConfigCache:              configCache,
+	}
+}
+
+func (repository *CachedPromoRepositoryImplementation) FindPromo(db *gorm.DB, IdDesa string) (promos []entity.Promo, err error) {
+	key := cache.Key(repository.Cache, cache.NamespacePromo, "desa", IdDesa)
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespacePromo), &promos, func() (err error) {
+		promos, err = repository.PromoRepositoryInterface.FindPromo(db, IdDesa)
+		return err
+	})
+	return promos, err
+}
+
+func (repository *CachedPromoRepositoryImplementation) FindPromoById(db *gorm.DB, IdPromo string) (*entity.Promo, error) {
+	return repository.PromoRepositoryInterface.FindPromoById(db, IdPromo)
+}
+
+// SyncProductPromoSchedule mengubah is_promo produk desa sehingga cache produk dan promo ikut dibuang
+func (repository *CachedPromoRepositoryImplementation) SyncProductPromoSchedule(db *gorm.DB, now time.Time) (int64, error) {
+	rowsAffected, err := repository.PromoRepositoryInterface.SyncProductPromoSchedule(db, now)
+	if rowsAffected > 0 {
+		cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+		cacheInvalidate(repository.Cache, db, cache.NamespacePromo)
+	}
+	return rowsAffected, err
+}
+
+type CachedKecamatanRepositoryImplementation struct {
+	KecamatanRepositoryInterface KecamatanRepositoryInterface
+	Cache                        cache.Cache
+	ConfigCache                  config.Cache
+}
+
+func NewCachedKecamatanRepository(
+	kecamatanRepositoryInterface KecamatanRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) KecamatanRepositoryInterface {
+	return &CachedKecamatanRepositoryImplementation{
+		KecamatanRepositoryInterface: kecamatanRepositoryInterface,
+		Cache:                        c,
+		ConfigCache:                  configCache,
+	}
+}
+
+func (repository *CachedKecamatanRepositoryImplementation) FindKecamatan(db *gorm.DB) (kecamatans []entity.Kecamatan, err error) {
+	key := cache.Key(repository.Cache, cache.NamespaceWilayah, "kecamatan")
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceWilayah), &kecamatans, func() (err error) {
+		kecamatans, err = repository.KecamatanRepositoryInterface.FindKecamatan(db)
+		return err
+	})
+	return kecamatans, err
+}
+
+type CachedKelurahanRepositoryImplementation struct {
+	KelurahanRepositoryInterface KelurahanRepositoryInterface
+	Cache                        cache.Cache
+	ConfigCache                  config.Cache
+}
+
+func NewCachedKelurahanRepository(
+	kelurahanRepositoryInterface KelurahanRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) KelurahanRepositoryInterface {
+	return &CachedKelurahanRepositoryImplementation{
+		KelurahanRepositoryInterface: kelurahanRepositoryInterface,
+		Cache:                        c,
+		ConfigCache:                  configCache,
+	}
+}
+
+func (repository *CachedKelurahanRepositoryImplementation) FindKelurahanByIdKeca(db *gorm.DB, idKeca int) (kelurahans []entity.Kelurahan, err error) {
+	key := cache.Key(repository.Cache, cache.NamespaceWilayah, "kelurahan", strconv.Itoa(idKeca))
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceWilayah), &kelurahans, func() (err error) {
+		kelurahans, err = repository.KelurahanRepositoryInterface.FindKelurahanByIdKeca(db, idKeca)
+		return err
+	})
+	return kelurahans, err
+}
+
+type CachedDesaRepositoryImplementation struct {
+	DesaRepositoryInterface DesaRepositoryInterface
+	Cache                   cache.Cache
+	ConfigCache             config.Cache
+}
+
+func NewCachedDesaRepository(
+	desaRepositoryInterface DesaRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) DesaRepositoryInterface {
+	return &CachedDesaRepositoryImplementation{
+		DesaRepositoryInterface: desaRepositoryInterface,
+		Cache:                   c,
+		ConfigCache:             configCache,
+	}
+}
+
+func (repository *CachedDesaRepositoryImplementation) FindDesaByIdKelu(db *gorm.DB, idKelu int) (desas []entity.Desa, err error) {
+	key := cache.Key(repository.Cache, cache.NamespaceWilayah, "desa_kelurahan", strconv.Itoa(idKelu))
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceWilayah), &desas, func() (err error) {
+		desas, err = repository.DesaRepositoryInterface.FindDesaByIdKelu(db, idKelu)
+		return err
+	})
+	return desas, err
+}
+
+func (repository *CachedDesaRepositoryImplementation) FindDesaById(db *gorm.DB, idDesa string) (*entity.Desa, error) {
+	desa := &entity.Desa{}
+	key := cache.Key(repository.Cache, cache.NamespaceWilayah, "desa", idDesa)
+	err := cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceWilayah), desa, func() error {
+		result, err := repository.DesaRepositoryInterface.FindDesaById(db, idDesa)
+		if result != nil {
+			*desa = *result
+		}
+		return err
+	})
+	return desa, err
+}
+
+func (repository *CachedDesaRepositoryImplementation) FindOneDesaByIdKelu(db *gorm.DB, idKelu int) (*entity.Desa, error) {
+	desa := &entity.Desa{}
+	key := cache.Key(repository.Cache, cache.NamespaceWilayah, "desa_kelurahan_one", strconv.Itoa(idKelu))
+	err := cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceWilayah), desa, func() error {
+		result, err := repository.DesaRepositoryInterface.FindOneDesaByIdKelu(db, idKelu)
+		if result != nil {
+			*desa = *result
+		}
+		return err
+	})
+	return desa, err
+}
+
+type CachedSettingRepositoryImplementation struct {
+	SettingRepositoryInterface SettingRepositoryInterface
+	Cache                      cache.Cache
+	ConfigCache                config.Cache
+}
+
+func NewCachedSettingRepository(
+	settingRepositoryInterface SettingRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) SettingRepositoryInterface {
+	return &CachedSettingRepositoryImplementation{
+		SettingRepositoryInterface: settingRepositoryInterface,
+		Cache:                      c,
+		ConfigCache:                configCache,
+	}
+}
+
+func (repository *CachedSettingRepositoryImplementation) readThroughOne(db *gorm.DB, load func(db *gorm.DB) (*entity.Setting, error), parts ...string) (*entity.Setting, error) {
+	setting := &entity.Setting{}
+	key := cache.Key(repository.Cache, cache.NamespaceSetting, parts...)
+	err := cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceSetting), setting, func() error {
+		result, err := load(db)
+		if result != nil {
+			*setting = *result
+		}
+		return err
+	})
+	return setting, err
+}
+
+func (repository *CachedSettingRepositoryImplementation) FindSettingShippingCost(db *gorm.DB, idDesa string) (*entity.Setting, error) {
+	return repository.readThroughOne(db, func(db *gorm.DB) (*entity.Setting, error) {
+		return repository.SettingRepositoryInterface.FindSettingShippingCost(db, idDesa)
+	}, "shipping_cost", idDesa)
+}
+
+func (repository *CachedSettingRepositoryImplementation) FindVerAppByOS(db *gorm.DB, os int) (settings []entity.Setting, err error) {
+	key := cache.Key(repository.Cache, cache.NamespaceSetting, "ver_app", strconv.Itoa(os))
+	err = cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceSetting), &settings, func() (err error) {
+		settings, err = repository.SettingRepositoryInterface.FindVerAppByOS(db, os)
+		return err
+	})
+	return settings, err
+}
+
+func (repository *CachedSettingRepositoryImplementation) FindAndroidVersion(db *gorm.DB) (*entity.Setting, error) {
+	return repository.readThroughOne(db, repository.SettingRepositoryInterface.FindAndroidVersion, "android_version")
+}
+
+func (repository *CachedSettingRepositoryImplementation) FindIosVersion(db *gorm.DB) (*entity.Setting, error) {
+	return repository.readThroughOne(db, repository.SettingRepositoryInterface.FindIosVersion, "ios_version")
+}
@@ -0,0 +1,59 @@
+package fcmrepository
+
+import (
+	"strings"
+	"sync"
+)
+
+// fakeFcmMaxMessages pesan terlama dibuang supaya fake yang dipakai lama di development tidak terus membesar
+const fakeFcmMaxMessages = 1000
+
+// FakeFcmProviderImplementation pengirim push lokal tanpa koneksi ke firebase, untuk development dan testing.
+// Token berawalan "invalid" dianggap tidak terdaftar
+type FakeFcmProviderImplementation struct {
+	mutex    sync.Mutex
+	Err      error
+	Messages []FakeFcmMessage
+}
+
+type FakeFcmMessage struct {
+	Token   string
+	Message Message
+}
+
+func NewFakeFcmProvider() *FakeFcmProviderImplementation {
+	return &FakeFcmProviderImplementation{}
+}
+
+func (provider *FakeFcmProviderImplementation) Send(tokens []string, message Message) (invalidTokens []string, err error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if provider.Err != nil {
+		return nil, provider.Err
+	}
+	for _, token := range tokens {
+		if strings.HasPrefix(token, "invalid") {
+			invalidTokens = append(invalidTokens, token)
+			continue
+		}
+		provider.Messages = append(provider.Messages, FakeFcmMessage{Token: token, Message: message})
+	}
+	if len(provider.Messages) > fakeFcmMaxMessages {
+		provider.Messages = append([]FakeFcmMessage{}, provider.Messages[len(provider.Messages)-fakeFcmMaxMessages:]...)
+	}
+	return invalidTokens, nil
+}
+
+// SentTo pesan yang terkirim ke satu token
+func (provider *FakeFcmProviderImplementation) SentTo(token string) (messages []Message) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	for _, sent := range provider.Messages {
+		if sent.Token == token {
+			messages = append(messages, sent.Message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,250 @@
+package fcmrepository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+)
+
+const (
+	firebaseSendUrl  = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	firebaseScope    = "https://www.googleapis.com/auth/firebase.messaging"
+	firebaseTokenUrl = "https://oauth2.googleapis.com/token"
+	// access token diperbarui sebelum benar-benar kadaluarsa
+	firebaseTokenLeeway = time.Minute
+)
+
+type FirebaseProviderImplementation struct {
+	ConfigFcm config.Fcm
+	Logger    *logrus.Logger
+	Client    *http.Client
+
+	mutex          sync.Mutex
+	serviceAccount *firebaseServiceAccount
+	accessToken    string
+	tokenExpiredAt time.Time
+}
+
+func NewFirebaseProvider(configFcm config.Fcm, logger *logrus.Logger) FcmProviderInterface {
+	timeout := configFcm.Timeout
+	if timeout == 0 {
+		timeout = 10
+	}
+	return &FirebaseProviderImplementation{
+		ConfigFcm: configFcm,
+		Logger:    logger,
+		Client:    &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}
+}
+
+type firebaseServiceAccount struct {
+	ProjectId   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenUri    string `json:"token_uri"`
+}
+
+type firebaseTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type firebaseErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send FCM HTTP v1 hanya menerima satu token per request
+func (provider *FirebaseProviderImplementation) Send(tokens []string, message Message) (invalidTokens []string, err error) {
+	for _, token := range tokens {
+		invalid, err := provider.send(token, message)
+		if invalid {
+			invalidTokens = append(invalidTokens, token)
+		}
+		if err != nil {
+			return invalidTokens, err
+		}
+	}
+	return invalidTokens, nil
+}
+
+func (provider *FirebaseProviderImplementation) send(token string, message Message) (invalid bool, err error) {
+	serviceAccount, accessToken, err := provider.token()
+	if err != nil {
+		return false, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token,
+			"notification": map[string]string{
+				"title": message.Title,
+				"body":  message.Body,
+			},
+			"data": message.Data,
+			"android": map[string]interface{}{
+				"priority":     "high",
+				"notification": map[string]string{"sound": "default"},
+			},
+			"apns": map[string]interface{}{
+				"payload": map[string]interface{}{
+					"aps": map[string]string{"sound": "default"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	sendUrl := fmt.Sprintf(firebaseSendUrl, url.PathEscape(serviceAccount.ProjectId))
+	req, err := http.NewRequest(http.MethodPost, sendUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := provider.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	errorResponse := &firebaseErrorResponse{}
+	json.Unmarshal(respBody, errorResponse)
+	errorCode := errorResponse.Error.Status
+	for _, detail := range errorResponse.Error.Details {
+		if len(detail.ErrorCode) != 0 {
+			errorCode = detail.ErrorCode
+		}
+	}
+
+	switch {
+	case errorCode == "UNREGISTERED", errorCode == "SENDER_ID_MISMATCH":
+		return true, nil
+	case errorCode == "INVALID_ARGUMENT" && strings.Contains(errorResponse.Error.Message, "registration token"):
+		return true, nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		// access token ditolak, minta ulang di pengiriman berikutnya
+		provider.mutex.Lock()
+		provider.accessToken = ""
+		provider.mutex.Unlock()
+		return false, errors.New("fcm http status " + strconv.Itoa(resp.StatusCode) + ": " + string(respBody))
+	case resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return false, errors.New("fcm http status " + strconv.Itoa(resp.StatusCode) + ": " + string(respBody))
+	default:
+		provider.Logger.WithFields(logrus.Fields{"error": errorCode, "status": resp.StatusCode}).Warn("fcm send failed")
+		return false, nil
+	}
+}
+
+// token access token OAuth2 dari service account, disimpan sampai mendekati kadaluarsa
+func (provider *FirebaseProviderImplementation) token() (*firebaseServiceAccount, string, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if provider.serviceAccount == nil {
+		serviceAccount, err := readFirebaseServiceAccount(provider.ConfigFcm.ServiceAccount)
+		if err != nil {
+			return nil, "", err
+		}
+		provider.serviceAccount = serviceAccount
+	}
+	if len(provider.accessToken) != 0 && time.Now().Before(provider.tokenExpiredAt) {
+		return provider.serviceAccount, provider.accessToken, nil
+	}
+
+	tokenUri := provider.serviceAccount.TokenUri
+	if len(tokenUri) == 0 {
+		tokenUri = firebaseTokenUrl
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(provider.serviceAccount.PrivateKey))
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   provider.serviceAccount.ClientEmail,
+		"scope": firebaseScope,
+		"aud":   tokenUri,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := provider.Client.PostForm(tokenUri, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.New("fcm oauth http status " + strconv.Itoa(resp.StatusCode) + ": " + string(respBody))
+	}
+
+	tokenResponse := &firebaseTokenResponse{}
+	if err := json.Unmarshal(respBody, tokenResponse); err != nil {
+		return nil, "", err
+	}
+	if len(tokenResponse.AccessToken) == 0 {
+		return nil, "", errors.New("fcm oauth empty access token")
+	}
+	provider.accessToken = tokenResponse.AccessToken
+	provider.tokenExpiredAt = now.Add(time.Duration(tokenResponse.ExpiresIn)*time.Second - firebaseTokenLeeway)
+	return provider.serviceAccount, provider.accessToken, nil
+}
+
+func readFirebaseServiceAccount(path string) (*firebaseServiceAccount, error) {
+	if len(path) == 0 {
+		return nil, errors.New("fcm service account not configured")
+	}
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	serviceAccount := &firebaseServiceAccount{}
+	if err := json.Unmarshal(file, serviceAccount); err != nil {
+		return nil, err
+	}
+	if len(serviceAccount.ProjectId) == 0 || len(serviceAccount.ClientEmail) == 0 || len(serviceAccount.PrivateKey) == 0 {
+		return nil, errors.New("fcm service account incomplete")
+	}
+	return serviceAccount, nil
+}
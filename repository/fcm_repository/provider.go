@@ -0,0 +1,26 @@
+package fcmrepository
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+)
+
+// Message isi push notification, Data diteruskan ke aplikasi untuk navigasi
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// FcmProviderInterface kontrak pengirim push notification, invalidTokens berisi
+// token yang sudah tidak terdaftar dan boleh dihapus
+type FcmProviderInterface interface {
+	Send(tokens []string, message Message) (invalidTokens []string, err error)
+}
+
+func NewFcmProvider(configFcm config.Fcm, logger *logrus.Logger) FcmProviderInterface {
+	if configFcm.Provider == "fake" {
+		return NewFakeFcmProvider()
+	}
+	return NewFirebaseProvider(configFcm, logger)
+}
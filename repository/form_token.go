@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type FormTokenRepositoryInterface interface {
+	CreateFormToken(db *gorm.DB, formToken *entity.FormToken) error
+	FindFormTokenById(db *gorm.DB, idFormToken string) (*entity.FormToken, error)
+	UseFormToken(db *gorm.DB, idFormToken string) (int64, error)
+}
+
+type FormTokenRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewFormTokenRepository(
+	db *config.Database,
+) FormTokenRepositoryInterface {
+	return &FormTokenRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *FormTokenRepositoryImplementation) CreateFormToken(db *gorm.DB, formToken *entity.FormToken) error {
+	result := db.Create(formToken)
+	return result.Error
+}
+
+func (repository *FormTokenRepositoryImplementation) FindFormTokenById(db *gorm.DB, idFormToken string) (*entity.FormToken, error) {
+	formToken := &entity.FormToken{}
+	result := db.
+		Where("id = ?", idFormToken).
+		Find(formToken)
+	return formToken, result.Error
+}
+
+// UseFormToken menandai token sudah dipakai, rows affected 0 berarti token sudah pernah dipakai
+func (repository *FormTokenRepositoryImplementation) UseFormToken(db *gorm.DB, idFormToken string) (int64, error) {
+	result := db.
+		Model(entity.FormToken{}).
+		Where("id = ?", idFormToken).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+	return result.RowsAffected, result.Error
+}
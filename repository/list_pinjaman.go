@@ -10,6 +10,7 @@ type ListPinjamanRepositoryInterface interface {
 	CreateListPinjaman(db *gorm.DB, listPinjaman *entity.ListPinjaman) error
 	FindListPinjamanByIdUser(db *gorm.DB, idUser string) ([]entity.ListPinjaman, error)
 	FindListPinjamanById(db *gorm.DB, IdListPinjaman string) (*entity.ListPinjaman, error)
+	PseudonymizeListPinjamanByIdUser(db *gorm.DB, idUser string, pseudonym string) error
 }
 
 type ListPinjamanRepositoryImplementation struct {
@@ -54,3 +55,11 @@ func (repository *ListPinjamanRepositoryImplementation) FindListPinjamanById(db
 
 	return listPinjaman, nil
 }
+
+func (repository *ListPinjamanRepositoryImplementation) PseudonymizeListPinjamanByIdUser(db *gorm.DB, idUser string, pseudonym string) error {
+	err := db.Model(entity.ListPinjaman{}).Where("id_user = ?", idUser).Update("nik", pseudonym).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -9,6 +9,9 @@ import (
 type MerchantRepositoryInterface interface {
 	CreateMerchantApproveList(db *gorm.DB, cart *entity.MerchantApproveList) error
 	FindMerchantStatusApproveByUser(db *gorm.DB, idUser string) (*entity.MerchantApproveList, error)
+	FindMerchantApproveListByDesa(db *gorm.DB, idDesa string, approveStatus int) ([]entity.MerchantApproveList, error)
+	FindMerchantApproveListById(db *gorm.DB, idMerchantApproveList string) (*entity.MerchantApproveList, error)
+	UpdateMerchantApproveList(db *gorm.DB, idMerchantApproveList string, merchantApproveList *entity.MerchantApproveList) error
 }
 
 type MerchantRepositoryImplementation struct {
@@ -30,6 +33,34 @@ func (repository *MerchantRepositoryImplementation) CreateMerchantApproveList(db
 
 func (repository *MerchantRepositoryImplementation) FindMerchantStatusApproveByUser(db *gorm.DB, idUser string) (*entity.MerchantApproveList, error) {
 	merchantApproveList := &entity.MerchantApproveList{}
-	result := db.Where("id_user = ?", idUser).Find(merchantApproveList)
+	result := db.Where("id_user = ?", idUser).Order("created_at desc").Limit(1).Find(merchantApproveList)
 	return merchantApproveList, result.Error
 }
+
+func (repository *MerchantRepositoryImplementation) FindMerchantApproveListByDesa(db *gorm.DB, idDesa string, approveStatus int) ([]entity.MerchantApproveList, error) {
+	merchantApproveLists := []entity.MerchantApproveList{}
+	query := db.Where("id_desa = ?", idDesa)
+	if approveStatus > 0 {
+		query = query.Where("approve_status = ?", approveStatus)
+	}
+	result := query.Order("created_at asc").Find(&merchantApproveLists)
+	return merchantApproveLists, result.Error
+}
+
+func (repository *MerchantRepositoryImplementation) FindMerchantApproveListById(db *gorm.DB, idMerchantApproveList string) (*entity.MerchantApproveList, error) {
+	merchantApproveList := &entity.MerchantApproveList{}
+	result := db.Where("id = ?", idMerchantApproveList).Find(merchantApproveList)
+	return merchantApproveList, result.Error
+}
+
+func (repository *MerchantRepositoryImplementation) UpdateMerchantApproveList(db *gorm.DB, idMerchantApproveList string, merchantApproveList *entity.MerchantApproveList) error {
+	result := db.
+		Model(entity.MerchantApproveList{}).
+		Where("id = ?", idMerchantApproveList).
+		Where("approve_status = ?", 1).
+		Updates(merchantApproveList)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
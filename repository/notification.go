@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type NotificationRepositoryInterface interface {
+	CreateNotification(db *gorm.DB, notification *entity.Notification) error
+	FindNotificationByIdUser(db *gorm.DB, idUser string, limit int, offset int) ([]entity.Notification, int64, error)
+	CountUnreadNotificationByIdUser(db *gorm.DB, idUser string) (int64, error)
+	CountNotificationByReference(db *gorm.DB, idUser string, notificationType string, idReference string, since time.Time) (int64, error)
+	UpdateNotificationRead(db *gorm.DB, idUser string, idNotifications []string) error
+	DeleteNotificationByIdUser(db *gorm.DB, idUser string) error
+}
+
+type NotificationRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewNotificationRepository(
+	db *config.Database,
+) NotificationRepositoryInterface {
+	return &NotificationRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *NotificationRepositoryImplementation) CreateNotification(db *gorm.DB, notification *entity.Notification) error {
+	result := db.Create(notification)
+	return result.Error
+}
+
+func (repository *NotificationRepositoryImplementation) FindNotificationByIdUser(db *gorm.DB, idUser string, limit int, offset int) ([]entity.Notification, int64, error) {
+	notifications := []entity.Notification{}
+	var total int64
+	result := db.
+		Model(entity.Notification{}).
+		Where("id_user = ?", idUser).
+		Count(&total)
+	if result.Error != nil {
+		return notifications, total, result.Error
+	}
+
+	result = db.
+		Where("id_user = ?", idUser).
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&notifications)
+	return notifications, total, result.Error
+}
+
+func (repository *NotificationRepositoryImplementation) CountUnreadNotificationByIdUser(db *gorm.DB, idUser string) (int64, error) {
+	var total int64
+	result := db.
+		Model(entity.Notification{}).
+		Where("id_user = ?", idUser).
+		Where("is_read = ?", 0).
+		Count(&total)
+	return total, result.Error
+}
+
+func (repository *NotificationRepositoryImplementation) CountNotificationByReference(db *gorm.DB, idUser string, notificationType string, idReference string, since time.Time) (int64, error) {
+	var total int64
+	result := db.
+		Model(entity.Notification{}).
+		Where("id_user = ?", idUser).
+		Where("type = ?", notificationType).
+		Where("id_reference = ?", idReference).
+		Where("created_at >= ?", since).
+		Count(&total)
+	return total, result.Error
+}
+
+// UpdateNotificationRead idNotifications kosong berarti tandai semua notifikasi user
+func (repository *NotificationRepositoryImplementation) UpdateNotificationRead(db *gorm.DB, idUser string, idNotifications []string) error {
+	notification := make(map[string]interface{})
+	notification["is_read"] = 1
+	notification["read_at"] = time.Now()
+	query := db.
+		Model(entity.Notification{}).
+		Where("id_user = ?", idUser).
+		Where("is_read = ?", 0)
+	if len(idNotifications) != 0 {
+		query = query.Where("id IN ?", idNotifications)
+	}
+	result := query.Updates(&notification)
+	return result.Error
+}
+
+func (repository *NotificationRepositoryImplementation) DeleteNotificationByIdUser(db *gorm.DB, idUser string) error {
+	result := db.Where("id_user = ?", idUser).Delete(&entity.Notification{})
+	return result.Error
+}
@@ -8,6 +8,7 @@ import (
 
 type OperatorPrefixRepositoryInterface interface {
 	FindOperatorPrefixByPhone(db *gorm.DB, phonePrefix string) (*entity.OperatorPrefix, error)
+	FindKodeOperators(db *gorm.DB) ([]string, error)
 }
 
 type OperatorPrefixRepositoryImplementation struct {
@@ -27,3 +28,12 @@ func (repository *OperatorPrefixRepositoryImplementation) FindOperatorPrefixByPh
 	result := db.Find(operatorPrefix, "prefix_number = ?", phonePrefix)
 	return operatorPrefix, result.Error
 }
+
+func (repository *OperatorPrefixRepositoryImplementation) FindKodeOperators(db *gorm.DB) ([]string, error) {
+	kodeOperators := []string{}
+	result := db.
+		Model(&entity.OperatorPrefix{}).
+		Distinct("kode_operator").
+		Pluck("kode_operator", &kodeOperators)
+	return kodeOperators, result.Error
+}
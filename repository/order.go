@@ -14,12 +14,24 @@ type OrderRepositoryInterface interface {
 	FindOrderByNumberOrder(db *gorm.DB, numberOrder string) (*entity.Order, error)
 	FindOrderByUser(db *gorm.DB, idUser string, orderStatus int) ([]entity.Order, error)
 	FindOrderById(db *gorm.DB, idOrder string) (*entity.Order, error)
+	FindOrderByIdUser(db *gorm.DB, idOrder, idUser, idDesa string) (*entity.Order, error)
+	FindOrderSembakoByIdDesa(db *gorm.DB, idOrder, idDesa string) (*entity.Order, error)
+	FindOrdersSembakoByDesa(db *gorm.DB, idDesa string, orderStatus int) ([]entity.Order, error)
+	FindOrdersByCourier(db *gorm.DB, idCourier string) ([]entity.Order, error)
+	FindOrdersDeliveredBefore(db *gorm.DB, deliveredDate time.Time) ([]entity.Order, error)
+	UpdateOrderByIdOrderAndStatus(db *gorm.DB, idOrder string, orderStatus int, orderUpdate *entity.Order) (int64, error)
+	FindOrderByIdDesa(db *gorm.DB, idOrder, idDesa string) (*entity.Order, error)
+	FindOrdersTransferPendingByDesa(db *gorm.DB, idDesa string) ([]entity.Order, error)
+	FindOrdersTransferExpired(db *gorm.DB, paymentDueDate time.Time, limit int) ([]entity.Order, error)
+	RejectOrderBuktiBayar(db *gorm.DB, idOrder string, note string) error
 	FindOrderByRefId(db *gorm.DB, refId string) (*entity.Order, error)
 	UpdateOrderByIdOrder(db *gorm.DB, idOrder string, orderUpdate *entity.Order) error
+	UpdatePendingOrderPpobByIdOrder(db *gorm.DB, idOrder string, orderUpdate *entity.Order) (int64, error)
 	FindOrderPrepaidPulsaById(db *gorm.DB, idUser string, productType string) (*entity.Order, error)
 	FindOrderPrepaidPlnById(db *gorm.DB, idUser string) (*entity.Order, error)
 	FindOrderPayLaterById(db *gorm.DB, idUser string) ([]entity.Order, error)
 	FindOrderPaylaterUnpaidById(db *gorm.DB, idUser string) ([]entity.Order, error)
+	FindOrderPaylaterUnpaidByDueDate(db *gorm.DB, dueDateFrom time.Time, dueDateTo time.Time) ([]entity.Order, error)
 	FindOrderPaylaterAllPaidById(db *gorm.DB, idUser string) ([]entity.Order, error)
 	UpdateOrderPaylaterPaidStatus(db *gorm.DB, idUser string, orderUpdate *entity.Order) error
 	UpdateOrderPaylaterPaidStatusByIdOrder(db *gorm.DB, idOrder string, orderUpdate *entity.Order) error
@@ -28,6 +40,10 @@ type OrderRepositoryInterface interface {
 	FindUnPaidPaylater(db *gorm.DB, idUser string) ([]entity.Order, error)
 	FindOldestUnPaidPaylater(db *gorm.DB, idUser string) (entity.Order, error)
 	FindOrderTotalPaylaterByMonth(db *gorm.DB, idUser string, month int) ([]entity.Order, error)
+	PseudonymizeOrderByIdUser(db *gorm.DB, idUser string, pseudonym string) error
+	CountCompletedOrderByUser(db *gorm.DB, idUser string, excludeIdOrder string) (int64, error)
+	FindPpobOrdersPendingBefore(db *gorm.DB, paymentSuccessDate time.Time, afterId string, limit int) ([]entity.Order, error)
+	FindPpobOrdersByPaymentDate(db *gorm.DB, startDate time.Time, endDate time.Time) ([]entity.Order, error)
 }
 
 type OrderRepositoryImplementation struct {
@@ -162,6 +178,19 @@ func (repository *OrderRepositoryImplementation) FindOrderPaylaterUnpaidById(db
 	return orders, result.Error
 }
 
+func (repository *OrderRepositoryImplementation) FindOrderPaylaterUnpaidByDueDate(db *gorm.DB, dueDateFrom time.Time, dueDateTo time.Time) ([]entity.Order, error) {
+	orders := []entity.Order{}
+
+	result := db.
+		Where("payment_method = ?", "paylater").
+		Where("paylater_paid_status = ?", 0).
+		Where("order_status != ?", 9).
+		Where("payment_due_date BETWEEN ? AND ?", dueDateFrom, dueDateTo).
+		Find(&orders)
+
+	return orders, result.Error
+}
+
 func (repository *OrderRepositoryImplementation) FindOrderPaylaterAllPaidById(db *gorm.DB, idUser string) ([]entity.Order, error) {
 	orders := []entity.Order{}
 
@@ -213,6 +242,125 @@ func (repository *OrderRepositoryImplementation) FindOrderById(db *gorm.DB, idUs
 	return orders, result.Error
 }
 
+// FindOrderByIdUser order milik user di desa tersebut, order user lain dianggap tidak ada
+func (repository *OrderRepositoryImplementation) FindOrderByIdUser(db *gorm.DB, idOrder, idUser, idDesa string) (*entity.Order, error) {
+	orders := &entity.Order{}
+	result := db.
+		Where("id_user = ?", idUser).
+		Where("id_desa = ?", idDesa).
+		Find(orders, "id = ?", idOrder)
+	return orders, result.Error
+}
+
+// FindOrderSembakoByIdDesa order sembako milik desa admin/kurir yang login
+func (repository *OrderRepositoryImplementation) FindOrderSembakoByIdDesa(db *gorm.DB, idOrder, idDesa string) (*entity.Order, error) {
+	orders := &entity.Order{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Where("order_type = ?", 1).
+		Find(orders, "id = ?", idOrder)
+	return orders, result.Error
+}
+
+// FindOrdersSembakoByDesa antrian fulfilment desa, orderStatus -1 untuk semua status yang masih berjalan (1-4)
+func (repository *OrderRepositoryImplementation) FindOrdersSembakoByDesa(db *gorm.DB, idDesa string, orderStatus int) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	query := db.
+		Where("id_desa = ?", idDesa).
+		Where("order_type = ?", 1)
+	if orderStatus >= 0 {
+		query = query.Where("order_status = ?", orderStatus)
+	} else {
+		query = query.Where("order_status IN ?", []int{1, 2, 3, 4})
+	}
+	result := query.Order("order_date asc").Find(&orders)
+	return orders, result.Error
+}
+
+// FindOrdersByCourier order yang ditugaskan ke kurir dan belum sampai
+func (repository *OrderRepositoryImplementation) FindOrdersByCourier(db *gorm.DB, idCourier string) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	result := db.
+		Where("id_courier = ?", idCourier).
+		Where("order_status IN ?", []int{2, 3}).
+		Order("order_date asc").
+		Find(&orders)
+	return orders, result.Error
+}
+
+func (repository *OrderRepositoryImplementation) FindOrdersDeliveredBefore(db *gorm.DB, deliveredDate time.Time) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	result := db.
+		Where("order_type = ?", 1).
+		Where("order_status = ?", 4).
+		Where("delivered_date <= ?", deliveredDate).
+		Find(&orders)
+	return orders, result.Error
+}
+
+// UpdateOrderByIdOrderAndStatus update hanya jika status order masih sama, jumlah row 0 berarti
+// status sudah diubah proses lain
+func (repository *OrderRepositoryImplementation) UpdateOrderByIdOrderAndStatus(db *gorm.DB, idOrder string, orderStatus int, orderUpdate *entity.Order) (int64, error) {
+	order := &entity.Order{}
+	result := db.
+		Model(order).
+		Where("id = ?", idOrder).
+		Where("order_status = ?", orderStatus).
+		Updates(orderUpdate)
+	return result.RowsAffected, result.Error
+}
+
+// FindOrderByIdDesa order semua tipe milik desa admin yang login
+func (repository *OrderRepositoryImplementation) FindOrderByIdDesa(db *gorm.DB, idOrder, idDesa string) (*entity.Order, error) {
+	orders := &entity.Order{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Find(orders, "id = ?", idOrder)
+	return orders, result.Error
+}
+
+// FindOrdersTransferPendingByDesa antrian verifikasi transfer, order yang sudah upload bukti bayar didahulukan
+func (repository *OrderRepositoryImplementation) FindOrdersTransferPendingByDesa(db *gorm.DB, idDesa string) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Where("payment_method = ?", "trf").
+		Where("order_status = ?", 0).
+		Where("payment_status = ?", 0).
+		Order("bukti_bayar_date IS NULL, bukti_bayar_date asc, order_date asc").
+		Find(&orders)
+	return orders, result.Error
+}
+
+// FindOrdersTransferExpired order transfer lewat jatuh tempo tanpa bukti bayar yang menunggu verifikasi
+func (repository *OrderRepositoryImplementation) FindOrdersTransferExpired(db *gorm.DB, paymentDueDate time.Time, limit int) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	result := db.
+		Where("payment_method = ?", "trf").
+		Where("order_status = ?", 0).
+		Where("payment_status = ?", 0).
+		Where("payment_due_date < ?", paymentDueDate).
+		Where("bukti_bayar_date IS NULL").
+		Order("payment_due_date asc").
+		Limit(limit).
+		Find(&orders)
+	return orders, result.Error
+}
+
+// RejectOrderBuktiBayar hapus bukti bayar yang ditolak supaya customer upload ulang
+func (repository *OrderRepositoryImplementation) RejectOrderBuktiBayar(db *gorm.DB, idOrder string, note string) error {
+	order := make(map[string]interface{})
+	order["foto_bukti_bayar"] = ""
+	order["bukti_bayar_date"] = nil
+	order["bukti_bayar_note"] = note
+	result := db.
+		Model(&entity.Order{}).
+		Where("id = ?", idOrder).
+		Where("order_status = ?", 0).
+		Updates(order)
+	return result.Error
+}
+
 func (repository *OrderRepositoryImplementation) FindOrderPayLaterById(db *gorm.DB, idUser string) ([]entity.Order, error) {
 	orders := []entity.Order{}
 	// var month time.Month
@@ -264,3 +412,68 @@ func (repository *OrderRepositoryImplementation) UpdateOrderByIdOrder(db *gorm.D
 		Updates(orderUpdate)
 	return result.Error
 }
+
+// UpdatePendingOrderPpobByIdOrder hanya mengubah order yang masih diproses (status 1/2),
+// jumlah row 0 berarti order sudah final lebih dulu
+func (repository *OrderRepositoryImplementation) UpdatePendingOrderPpobByIdOrder(db *gorm.DB, idOrder string, orderUpdate *entity.Order) (int64, error) {
+	order := &entity.Order{}
+	result := db.
+		Model(order).
+		Where("id = ?", idOrder).
+		Where("order_status IN ?", []int{1, 2}).
+		Updates(orderUpdate)
+	return result.RowsAffected, result.Error
+}
+
+func (repository *OrderRepositoryImplementation) PseudonymizeOrderByIdUser(db *gorm.DB, idUser string, pseudonym string) error {
+	order := make(map[string]interface{})
+	order["nama_lengkap"] = pseudonym
+	order["email"] = ""
+	order["phone"] = ""
+	order["alamat_pengiriman"] = ""
+	order["catatan"] = ""
+	order["latitude"] = 0
+	order["longitude"] = 0
+	result := db.
+		Model(entity.Order{}).
+		Where("id_user = ?", idUser).
+		Updates(&order)
+	return result.Error
+}
+
+func (repository *OrderRepositoryImplementation) CountCompletedOrderByUser(db *gorm.DB, idUser string, excludeIdOrder string) (int64, error) {
+	var count int64
+	result := db.
+		Model(entity.Order{}).
+		Where("id_user = ? AND order_status = ? AND id <> ?", idUser, 5, excludeIdOrder).
+		Count(&count)
+	return count, result.Error
+}
+
+// FindPpobOrdersPendingBefore order ppob yang sudah dibayar tetapi belum ada status akhir dari provider,
+// dibaca per batch urut id setelah afterId
+func (repository *OrderRepositoryImplementation) FindPpobOrdersPendingBefore(db *gorm.DB, paymentSuccessDate time.Time, afterId string, limit int) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	result := db.
+		Where("order_type = ?", 2).
+		Where("payment_status = ?", 1).
+		Where("order_status IN ?", []int{1, 2}).
+		Where("payment_success_date <= ?", paymentSuccessDate).
+		Where("id > ?", afterId).
+		Order("id asc").
+		Limit(limit).
+		Find(&orders)
+	return orders, result.Error
+}
+
+func (repository *OrderRepositoryImplementation) FindPpobOrdersByPaymentDate(db *gorm.DB, startDate time.Time, endDate time.Time) ([]entity.Order, error) {
+	orders := []entity.Order{}
+	result := db.
+		Where("order_type = ?", 2).
+		Where("payment_status = ?", 1).
+		Where("payment_success_date >= ?", startDate).
+		Where("payment_success_date < ?", endDate).
+		Order("payment_success_date asc").
+		Find(&orders)
+	return orders, result.Error
+}
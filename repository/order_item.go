@@ -9,6 +9,7 @@ import (
 type OrderItemRepositoryInterface interface {
 	CreateOrderItem(db *gorm.DB, orderItem []entity.OrderItem) error
 	FindOrderItemsByIdOrder(db *gorm.DB, idOrder string) ([]entity.OrderItem, error)
+	FindOrderItemsByIdOrders(db *gorm.DB, idOrders []string) ([]entity.OrderItem, error)
 }
 
 type OrderItemRepositoryImplementation struct {
@@ -34,3 +35,10 @@ func (repository *OrderItemRepositoryImplementation) FindOrderItemsByIdOrder(db
 		Find(&orderItems, "id_order = ?", idOrder)
 	return orderItems, result.Error
 }
+
+func (repository *OrderItemRepositoryImplementation) FindOrderItemsByIdOrders(db *gorm.DB, idOrders []string) ([]entity.OrderItem, error) {
+	orderItems := []entity.OrderItem{}
+	result := db.
+		Find(&orderItems, "id_order IN ?", idOrders)
+	return orderItems, result.Error
+}
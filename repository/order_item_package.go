@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type OrderItemPackageRepositoryInterface interface {
+	CreateOrderItemPackage(db *gorm.DB, orderItemPackages []entity.OrderItemPackage) error
+	FindOrderItemPackageByIdOrder(db *gorm.DB, idOrder string) ([]entity.OrderItemPackage, error)
+}
+
+type OrderItemPackageRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewOrderItemPackageRepository(
+	db *config.Database,
+) OrderItemPackageRepositoryInterface {
+	return &OrderItemPackageRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *OrderItemPackageRepositoryImplementation) CreateOrderItemPackage(db *gorm.DB, orderItemPackages []entity.OrderItemPackage) error {
+	result := db.Create(&orderItemPackages)
+	return result.Error
+}
+
+func (repository *OrderItemPackageRepositoryImplementation) FindOrderItemPackageByIdOrder(db *gorm.DB, idOrder string) ([]entity.OrderItemPackage, error) {
+	orderItemPackages := []entity.OrderItemPackage{}
+	result := db.
+		Find(&orderItemPackages, "id_order = ?", idOrder)
+	return orderItemPackages, result.Error
+}
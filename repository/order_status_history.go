@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistoryRepositoryInterface interface {
+	CreateOrderStatusHistory(db *gorm.DB, orderStatusHistory *entity.OrderStatusHistory) error
+	FindOrderStatusHistoryByIdOrder(db *gorm.DB, idOrder string) ([]entity.OrderStatusHistory, error)
+}
+
+type OrderStatusHistoryRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewOrderStatusHistoryRepository(
+	db *config.Database,
+) OrderStatusHistoryRepositoryInterface {
+	return &OrderStatusHistoryRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *OrderStatusHistoryRepositoryImplementation) CreateOrderStatusHistory(db *gorm.DB, orderStatusHistory *entity.OrderStatusHistory) error {
+	result := db.Create(orderStatusHistory)
+	return result.Error
+}
+
+func (repository *OrderStatusHistoryRepositoryImplementation) FindOrderStatusHistoryByIdOrder(db *gorm.DB, idOrder string) ([]entity.OrderStatusHistory, error) {
+	orderStatusHistories := []entity.OrderStatusHistory{}
+	result := db.
+		Where("id_order = ?", idOrder).
+		Order("created_at asc").
+		Find(&orderStatusHistories)
+	return orderStatusHistories, result.Error
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"gorm.io/gorm"
@@ -10,6 +12,8 @@ type OtpManagerRepositoryInterface interface {
 	CreateOtp(db *gorm.DB, otpManager *entity.OtpManager) error
 	UpdateOtp(db *gorm.DB, idOtpManager string, otpManager *entity.OtpManager) error
 	FindOtpByPhone(db *gorm.DB, phone string) (*entity.OtpManager, error)
+	FindOtpById(db *gorm.DB, idOtpManager string) (*entity.OtpManager, error)
+	ExpireOtp(db *gorm.DB, idOtpManager string) error
 }
 
 type OtpManagerRepositoryImplementation struct {
@@ -32,6 +36,7 @@ func (repository *OtpManagerRepositoryImplementation) CreateOtp(db *gorm.DB, otp
 func (repository *OtpManagerRepositoryImplementation) UpdateOtp(db *gorm.DB, idOtpManager string, otpManager *entity.OtpManager) error {
 	updateOtp := make(map[string]interface{})
 	updateOtp["otp_code"] = otpManager.OtpCode
+	updateOtp["type_otp"] = otpManager.TypeOtp
 	updateOtp["otp_experied_at"] = otpManager.OtpExperiedAt
 	updateOtp["freeze_due_date"] = otpManager.FreezeDueDate
 	updateOtp["phone_limit"] = otpManager.PhoneLimit
@@ -49,3 +54,22 @@ func (repository *OtpManagerRepositoryImplementation) FindOtpByPhone(db *gorm.DB
 		Find(otpManager)
 	return otpManager, result.Error
 }
+
+func (repository *OtpManagerRepositoryImplementation) FindOtpById(db *gorm.DB, idOtpManager string) (*entity.OtpManager, error) {
+	otpManager := &entity.OtpManager{}
+	result := db.
+		Where("id = ?", idOtpManager).
+		Find(otpManager)
+	return otpManager, result.Error
+}
+
+func (repository *OtpManagerRepositoryImplementation) ExpireOtp(db *gorm.DB, idOtpManager string) error {
+	updateOtp := make(map[string]interface{})
+	updateOtp["otp_code"] = ""
+	updateOtp["otp_experied_at"] = time.Now()
+	result := db.
+		Model(entity.OtpManager{}).
+		Where("id = ?", idOtpManager).
+		Updates(&updateOtp)
+	return result.Error
+}
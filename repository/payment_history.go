@@ -9,6 +9,7 @@ import (
 type PaymentHistoryRepositoryInterface interface {
 	CreatePaymentHistory(db *gorm.DB, paymentHistory *entity.PaymentHistory) error
 	FindPaymentHistoryById(db *gorm.DB, idUser, indexDate string) (*entity.PaymentHistory, error)
+	FindPaymentHistoryByIdUser(db *gorm.DB, idUser string) ([]entity.PaymentHistory, error)
 }
 
 type PaymentHistoryRepositoryImplementation struct {
@@ -33,3 +34,9 @@ func (service *PaymentHistoryRepositoryImplementation) FindPaymentHistoryById(db
 	results := db.Where("id_user = ? AND index_date = ?", idUser, indexDate).First(paymentHistory)
 	return paymentHistory, results.Error
 }
+
+func (service *PaymentHistoryRepositoryImplementation) FindPaymentHistoryByIdUser(db *gorm.DB, idUser string) ([]entity.PaymentHistory, error) {
+	paymentHistories := []entity.PaymentHistory{}
+	results := db.Where("id_user = ?", idUser).Order("created_at desc").Find(&paymentHistories)
+	return paymentHistories, results.Error
+}
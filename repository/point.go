@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"gorm.io/gorm"
@@ -9,6 +11,8 @@ import (
 type PointRepositoryInterface interface {
 	CreatePoint(db *gorm.DB, point *entity.Point) error
 	FindPointByUser(db *gorm.DB, idUser string) (*entity.Point, error)
+	IncreasePoint(db *gorm.DB, idUser string, amount float64) error
+	DecreasePoint(db *gorm.DB, idUser string, amount float64) (int64, error)
 }
 
 type PointRepositoryImplementation struct {
@@ -34,3 +38,26 @@ func (repository *PointRepositoryImplementation) FindPointByUser(db *gorm.DB, id
 		Find(point, "points.id_user = ?", idUser)
 	return point, result.Error
 }
+
+func (repository *PointRepositoryImplementation) IncreasePoint(db *gorm.DB, idUser string, amount float64) error {
+	result := db.
+		Model(entity.Point{}).
+		Where("id_user = ?", idUser).
+		Updates(map[string]interface{}{
+			"jml_point":  gorm.Expr("jml_point + ?", amount),
+			"updated_at": time.Now(),
+		})
+	return result.Error
+}
+
+// DecreasePoint rows affected 0 berarti saldo point tidak cukup
+func (repository *PointRepositoryImplementation) DecreasePoint(db *gorm.DB, idUser string, amount float64) (int64, error) {
+	result := db.
+		Model(entity.Point{}).
+		Where("id_user = ? AND jml_point >= ?", idUser, amount).
+		Updates(map[string]interface{}{
+			"jml_point":  gorm.Expr("jml_point - ?", amount),
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PointHistoryRepositoryInterface interface {
+	CreatePointHistory(db *gorm.DB, pointHistory *entity.PointHistory) error
+	FindPointHistoryByUser(db *gorm.DB, idUser string, limit int, offset int) ([]entity.PointHistory, int64, error)
+	FindPointHistoryByIdOrder(db *gorm.DB, idOrder string) ([]entity.PointHistory, error)
+	FindAvailablePointCredit(db *gorm.DB, idUser string, now time.Time) ([]entity.PointHistory, error)
+	FindExpiredPointCredit(db *gorm.DB, now time.Time, limit int) ([]entity.PointHistory, error)
+	UpdatePointHistoryRemaining(db *gorm.DB, idPointHistory string, remaining float64) error
+}
+
+type PointHistoryRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPointHistoryRepository(
+	db *config.Database,
+) PointHistoryRepositoryInterface {
+	return &PointHistoryRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *PointHistoryRepositoryImplementation) CreatePointHistory(db *gorm.DB, pointHistory *entity.PointHistory) error {
+	result := db.Create(pointHistory)
+	return result.Error
+}
+
+func (repository *PointHistoryRepositoryImplementation) FindPointHistoryByUser(db *gorm.DB, idUser string, limit int, offset int) ([]entity.PointHistory, int64, error) {
+	pointHistories := []entity.PointHistory{}
+	var total int64
+	result := db.
+		Model(entity.PointHistory{}).
+		Where("id_user = ?", idUser).
+		Count(&total)
+	if result.Error != nil {
+		return pointHistories, total, result.Error
+	}
+
+	result = db.
+		Where("id_user = ?", idUser).
+		Order("trans_date desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&pointHistories)
+	return pointHistories, total, result.Error
+}
+
+func (repository *PointHistoryRepositoryImplementation) FindPointHistoryByIdOrder(db *gorm.DB, idOrder string) ([]entity.PointHistory, error) {
+	pointHistories := []entity.PointHistory{}
+	result := db.
+		Where("id_order = ?", idOrder).
+		Order("trans_date asc").
+		Find(&pointHistories)
+	return pointHistories, result.Error
+}
+
+// FindAvailablePointCredit kredit yang masih bersisa, yang paling dulu hangus dipakai lebih dulu
+func (repository *PointHistoryRepositoryImplementation) FindAvailablePointCredit(db *gorm.DB, idUser string, now time.Time) ([]entity.PointHistory, error) {
+	pointHistories := []entity.PointHistory{}
+	result := db.
+		Where("id_user = ? AND remaining > 0 AND (expired_date IS NULL OR expired_date > ?)", idUser, now).
+		Order("expired_date IS NULL, expired_date asc, trans_date asc").
+		Find(&pointHistories)
+	return pointHistories, result.Error
+}
+
+func (repository *PointHistoryRepositoryImplementation) FindExpiredPointCredit(db *gorm.DB, now time.Time, limit int) ([]entity.PointHistory, error) {
+	pointHistories := []entity.PointHistory{}
+	result := db.
+		Where("remaining > 0 AND expired_date <= ?", now).
+		Order("expired_date asc").
+		Limit(limit).
+		Find(&pointHistories)
+	return pointHistories, result.Error
+}
+
+func (repository *PointHistoryRepositoryImplementation) UpdatePointHistoryRemaining(db *gorm.DB, idPointHistory string, remaining float64) error {
+	result := db.
+		Model(entity.PointHistory{}).
+		Where("id = ?", idPointHistory).
+		Updates(map[string]interface{}{
+			"remaining":  remaining,
+			"updated_at": time.Now(),
+		})
+	return result.Error
+}
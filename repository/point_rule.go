@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PointRuleRepositoryInterface interface {
+	FindPointRulesByDesa(db *gorm.DB, idDesa string) ([]entity.PointRule, error)
+	FindPointRuleById(db *gorm.DB, idPointRule string) (*entity.PointRule, error)
+	CreatePointRule(db *gorm.DB, pointRule *entity.PointRule) error
+	UpdatePointRule(db *gorm.DB, idPointRule string, pointRule *entity.PointRule) error
+}
+
+type PointRuleRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPointRuleRepository(
+	db *config.Database,
+) PointRuleRepositoryInterface {
+	return &PointRuleRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *PointRuleRepositoryImplementation) FindPointRulesByDesa(db *gorm.DB, idDesa string) ([]entity.PointRule, error) {
+	pointRules := []entity.PointRule{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Order("rule_type asc, created_at asc").
+		Find(&pointRules)
+	return pointRules, result.Error
+}
+
+func (repository *PointRuleRepositoryImplementation) FindPointRuleById(db *gorm.DB, idPointRule string) (*entity.PointRule, error) {
+	pointRule := &entity.PointRule{}
+	result := db.Where("id = ?", idPointRule).Find(pointRule)
+	return pointRule, result.Error
+}
+
+func (repository *PointRuleRepositoryImplementation) CreatePointRule(db *gorm.DB, pointRule *entity.PointRule) error {
+	result := db.Create(pointRule)
+	return result.Error
+}
+
+func (repository *PointRuleRepositoryImplementation) UpdatePointRule(db *gorm.DB, idPointRule string, pointRule *entity.PointRule) error {
+	updatePointRule := make(map[string]interface{})
+	updatePointRule["rule_type"] = pointRule.RuleType
+	updatePointRule["id_product_desa"] = pointRule.IdProductDesa
+	updatePointRule["min_amount"] = pointRule.MinAmount
+	updatePointRule["point"] = pointRule.Point
+	updatePointRule["description"] = pointRule.Description
+	updatePointRule["is_active"] = pointRule.IsActive
+	updatePointRule["updated_at"] = pointRule.UpdatedAt
+	result := db.
+		Model(entity.PointRule{}).
+		Where("id = ?", idPointRule).
+		Updates(updatePointRule)
+	return result.Error
+}
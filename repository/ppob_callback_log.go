@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PpobCallbackLogRepositoryInterface interface {
+	CreatePpobCallbackLog(db *gorm.DB, ppobCallbackLog *entity.PpobCallbackLog) error
+}
+
+type PpobCallbackLogRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPpobCallbackLogRepository(
+	db *config.Database,
+) PpobCallbackLogRepositoryInterface {
+	return &PpobCallbackLogRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *PpobCallbackLogRepositoryImplementation) CreatePpobCallbackLog(db *gorm.DB, ppobCallbackLog *entity.PpobCallbackLog) error {
+	result := db.Create(ppobCallbackLog)
+	return result.Error
+}
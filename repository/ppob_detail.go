@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"gorm.io/gorm"
@@ -16,10 +18,16 @@ type PpobDetailRepositoryInterface interface {
 	FindPpobDetailPrepaidPlnById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailPrepaidPln, error)
 	FindPpobDetailPostpaidPlnById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailPostpaidPln, error)
 	FindPpobDetailPostpaidPdamById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailPostpaidPdam, error)
+	FindPpobDetailPostpaidTelcoById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailPostpaidTelco, error)
 	UpdatePpobPrepaidPulsaById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePrepaidPulsa *entity.PpobDetailPrepaidPulsa) error
 	UpdatePpobPrepaidPlnById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePrepaidPln *entity.PpobDetailPrepaidPln) error
 	UpdatePpobPostpaidPlnById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePostpaidPln *entity.PpobDetailPostpaidPln) error
 	UpdatePpobPostpaidPdamById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePostpaidPdam *entity.PpobDetailPostpaidPdam) error
+	UpdatePpobPostpaidTelcoById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePostpaidTelco *entity.PpobDetailPostpaidTelco) error
+	CreateOrderPpobDetailGeneric(db *gorm.DB, ppobDetailGeneric *entity.PpobDetailGeneric) error
+	FindPpobDetailGenericById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailGeneric, error)
+	UpdatePpobGenericById(db *gorm.DB, idPpobDetailGeneric string, ppobDetailUpdateGeneric *entity.PpobDetailGeneric) error
+	UpdatePpobDetailStatusByIdOrderItemPpob(db *gorm.DB, productType string, idOrderItemPpob string, statusTopUp int, lastBalance float64, sn string) (int64, error)
 }
 
 type PpobDetailRepositoryImplementation struct {
@@ -87,6 +95,13 @@ func (repository *PpobDetailRepositoryImplementation) FindPpobDetailPostpaidPdam
 	return ppobDetailPostpaidPdam, result.Error
 }
 
+func (repository *PpobDetailRepositoryImplementation) FindPpobDetailPostpaidTelcoById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailPostpaidTelco, error) {
+	ppobDetailPostpaidTelco := &entity.PpobDetailPostpaidTelco{}
+	result := db.
+		Find(ppobDetailPostpaidTelco, "id_order_item_ppob = ?", idOrderItemsPpob)
+	return ppobDetailPostpaidTelco, result.Error
+}
+
 func (repository *PpobDetailRepositoryImplementation) UpdatePpobPrepaidPulsaById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePrepaidPulsa *entity.PpobDetailPrepaidPulsa) error {
 	updatePrepaidPulsa := make(map[string]interface{})
 	updatePrepaidPulsa["status_topup"] = ppobDetailUpdatePrepaidPulsa.StatusTopUp
@@ -135,3 +150,96 @@ func (repository *PpobDetailRepositoryImplementation) UpdatePpobPostpaidPdamById
 		Updates(ppobDetailUpdatePostpaidPdam)
 	return result.Error
 }
+
+func (repository *PpobDetailRepositoryImplementation) UpdatePpobPostpaidTelcoById(db *gorm.DB, idOrderItemPpob string, ppobDetailUpdatePostpaidTelco *entity.PpobDetailPostpaidTelco) error {
+	updatePostpaidTelco := make(map[string]interface{})
+	updatePostpaidTelco["status_topup"] = ppobDetailUpdatePostpaidTelco.StatusTopUp
+	updatePostpaidTelco["last_balance"] = ppobDetailUpdatePostpaidTelco.LastBalance
+	if ppobDetailUpdatePostpaidTelco.TopupSuccessDate.Valid {
+		updatePostpaidTelco["topup_success_date"] = ppobDetailUpdatePostpaidTelco.TopupSuccessDate
+	}
+	if ppobDetailUpdatePostpaidTelco.TopupFailedDate.Valid {
+		updatePostpaidTelco["topup_failed_date"] = ppobDetailUpdatePostpaidTelco.TopupFailedDate
+	}
+	result := db.
+		Model(entity.PpobDetailPostpaidTelco{}).
+		Where("id = ?", idOrderItemPpob).
+		Updates(updatePostpaidTelco)
+	return result.Error
+}
+
+func (repository *PpobDetailRepositoryImplementation) CreateOrderPpobDetailGeneric(db *gorm.DB, ppobDetailGeneric *entity.PpobDetailGeneric) error {
+	result := db.Create(ppobDetailGeneric)
+	return result.Error
+}
+
+func (repository *PpobDetailRepositoryImplementation) FindPpobDetailGenericById(db *gorm.DB, idOrderItemsPpob string) (*entity.PpobDetailGeneric, error) {
+	ppobDetailGeneric := &entity.PpobDetailGeneric{}
+	result := db.
+		Find(ppobDetailGeneric, "id_order_item_ppob = ?", idOrderItemsPpob)
+	return ppobDetailGeneric, result.Error
+}
+
+// UpdatePpobGenericById hanya kolom yang diisi (bukan zero value) yang ikut diupdate
+func (repository *PpobDetailRepositoryImplementation) UpdatePpobGenericById(db *gorm.DB, idPpobDetailGeneric string, ppobDetailUpdateGeneric *entity.PpobDetailGeneric) error {
+	updateGeneric := make(map[string]interface{})
+	updateGeneric["status_topup"] = ppobDetailUpdateGeneric.StatusTopUp
+	updateGeneric["last_balance"] = ppobDetailUpdateGeneric.LastBalance
+	if len(ppobDetailUpdateGeneric.Sn) != 0 {
+		updateGeneric["sn"] = ppobDetailUpdateGeneric.Sn
+	}
+	if ppobDetailUpdateGeneric.TopupProccesingDate.Valid {
+		updateGeneric["topup_proccesing_date"] = ppobDetailUpdateGeneric.TopupProccesingDate
+	}
+	if ppobDetailUpdateGeneric.TopupSuccessDate.Valid {
+		updateGeneric["topup_success_date"] = ppobDetailUpdateGeneric.TopupSuccessDate
+	}
+	if ppobDetailUpdateGeneric.TopupFailedDate.Valid {
+		updateGeneric["topup_failed_date"] = ppobDetailUpdateGeneric.TopupFailedDate
+	}
+	result := db.
+		Model(entity.PpobDetailGeneric{}).
+		Where("id = ?", idPpobDetailGeneric).
+		Updates(updateGeneric)
+	return result.Error
+}
+
+// UpdatePpobDetailStatusByIdOrderItemPpob status akhir topup (1 sukses, 2 gagal) untuk semua tipe detail ppob,
+// sn disimpan sebagai token untuk pln prabayar
+func (repository *PpobDetailRepositoryImplementation) UpdatePpobDetailStatusByIdOrderItemPpob(db *gorm.DB, productType string, idOrderItemPpob string, statusTopUp int, lastBalance float64, sn string) (int64, error) {
+	var model interface{}
+	snColumn := ""
+	switch productType {
+	case "prepaid_pulsa", "prepaid_data":
+		model = entity.PpobDetailPrepaidPulsa{}
+	case "prepaid_pln":
+		model = entity.PpobDetailPrepaidPln{}
+		snColumn = "no_token"
+	case "postpaid_pln":
+		model = entity.PpobDetailPostpaidPln{}
+	case "postpaid_pdam":
+		model = entity.PpobDetailPostpaidPdam{}
+	case "postpaid_telco":
+		model = entity.PpobDetailPostpaidTelco{}
+	default:
+		model = entity.PpobDetailGeneric{}
+		snColumn = "sn"
+	}
+
+	updateStatus := make(map[string]interface{})
+	updateStatus["status_topup"] = statusTopUp
+	updateStatus["last_balance"] = lastBalance
+	if statusTopUp == 1 {
+		updateStatus["topup_success_date"] = time.Now()
+		if len(snColumn) != 0 && len(sn) != 0 {
+			updateStatus[snColumn] = sn
+		}
+	} else {
+		updateStatus["topup_failed_date"] = time.Now()
+	}
+	result := db.
+		Model(model).
+		Where("id_order_item_ppob = ?", idOrderItemPpob).
+		Updates(updateStatus)
+	return result.RowsAffected, result.Error
+}
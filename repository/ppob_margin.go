@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PpobMarginRepositoryInterface interface {
+	FindPpobMarginsByDesa(db *gorm.DB, idDesa string) ([]entity.PpobMargin, error)
+	FindPpobMarginById(db *gorm.DB, idPpobMargin string) (*entity.PpobMargin, error)
+	CreatePpobMargin(db *gorm.DB, ppobMargin *entity.PpobMargin) error
+	UpdatePpobMargin(db *gorm.DB, idPpobMargin string, ppobMargin *entity.PpobMargin) error
+}
+
+type PpobMarginRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPpobMarginRepository(
+	db *config.Database,
+) PpobMarginRepositoryInterface {
+	return &PpobMarginRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *PpobMarginRepositoryImplementation) FindPpobMarginsByDesa(db *gorm.DB, idDesa string) ([]entity.PpobMargin, error) {
+	ppobMargins := []entity.PpobMargin{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Order("product_type asc, operator asc").
+		Find(&ppobMargins)
+	return ppobMargins, result.Error
+}
+
+func (repository *PpobMarginRepositoryImplementation) FindPpobMarginById(db *gorm.DB, idPpobMargin string) (*entity.PpobMargin, error) {
+	ppobMargin := &entity.PpobMargin{}
+	result := db.Where("id = ?", idPpobMargin).Find(ppobMargin)
+	return ppobMargin, result.Error
+}
+
+func (repository *PpobMarginRepositoryImplementation) CreatePpobMargin(db *gorm.DB, ppobMargin *entity.PpobMargin) error {
+	result := db.Create(ppobMargin)
+	return result.Error
+}
+
+func (repository *PpobMarginRepositoryImplementation) UpdatePpobMargin(db *gorm.DB, idPpobMargin string, ppobMargin *entity.PpobMargin) error {
+	updatePpobMargin := make(map[string]interface{})
+	updatePpobMargin["product_type"] = ppobMargin.ProductType
+	updatePpobMargin["operator"] = ppobMargin.Operator
+	updatePpobMargin["margin_type"] = ppobMargin.MarginType
+	updatePpobMargin["margin"] = ppobMargin.Margin
+	updatePpobMargin["is_active"] = ppobMargin.IsActive
+	updatePpobMargin["updated_at"] = ppobMargin.UpdatedAt
+	result := db.
+		Model(entity.PpobMargin{}).
+		Where("id = ?", idPpobMargin).
+		Updates(updatePpobMargin)
+	return result.Error
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PpobPriceListRepositoryInterface interface {
+	FindPpobPriceListByType(db *gorm.DB, productType string, operator string) ([]entity.PpobPriceList, error)
+	FindPpobPriceListByCode(db *gorm.DB, productCode string) (*entity.PpobPriceList, error)
+	CountPpobPriceListByType(db *gorm.DB, productType string, operator string) (int64, error)
+	CreatePpobPriceList(db *gorm.DB, ppobPriceList *entity.PpobPriceList) error
+	UpdatePpobPriceList(db *gorm.DB, idPpobPriceList string, ppobPriceList *entity.PpobPriceList) error
+	DeactivatePpobPriceListNotSynced(db *gorm.DB, productType string, operator string, syncedAt time.Time) error
+}
+
+type PpobPriceListRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPpobPriceListRepository(
+	db *config.Database,
+) PpobPriceListRepositoryInterface {
+	return &PpobPriceListRepositoryImplementation{
+		DB: db,
+	}
+}
+
+// FindPpobPriceListByType hanya mengembalikan produk yang aktif
+func (repository *PpobPriceListRepositoryImplementation) FindPpobPriceListByType(db *gorm.DB, productType string, operator string) ([]entity.PpobPriceList, error) {
+	ppobPriceLists := []entity.PpobPriceList{}
+	result := db.
+		Where("product_type = ?", productType).
+		Where("operator = ?", operator).
+		Where("is_active = ?", 1).
+		Order("product_price asc").
+		Find(&ppobPriceLists)
+	return ppobPriceLists, result.Error
+}
+
+func (repository *PpobPriceListRepositoryImplementation) FindPpobPriceListByCode(db *gorm.DB, productCode string) (*entity.PpobPriceList, error) {
+	ppobPriceList := &entity.PpobPriceList{}
+	result := db.Where("product_code = ?", productCode).Find(ppobPriceList)
+	return ppobPriceList, result.Error
+}
+
+func (repository *PpobPriceListRepositoryImplementation) CountPpobPriceListByType(db *gorm.DB, productType string, operator string) (int64, error) {
+	var total int64
+	result := db.
+		Model(&entity.PpobPriceList{}).
+		Where("product_type = ?", productType).
+		Where("operator = ?", operator).
+		Count(&total)
+	return total, result.Error
+}
+
+func (repository *PpobPriceListRepositoryImplementation) CreatePpobPriceList(db *gorm.DB, ppobPriceList *entity.PpobPriceList) error {
+	result := db.Create(ppobPriceList)
+	return result.Error
+}
+
+func (repository *PpobPriceListRepositoryImplementation) UpdatePpobPriceList(db *gorm.DB, idPpobPriceList string, ppobPriceList *entity.PpobPriceList) error {
+	updatePpobPriceList := make(map[string]interface{})
+	updatePpobPriceList["product_type"] = ppobPriceList.ProductType
+	updatePpobPriceList["operator"] = ppobPriceList.Operator
+	updatePpobPriceList["product_description"] = ppobPriceList.ProductDescription
+	updatePpobPriceList["product_nominal"] = ppobPriceList.ProductNominal
+	updatePpobPriceList["product_details"] = ppobPriceList.ProductDetails
+	updatePpobPriceList["product_price"] = ppobPriceList.ProductPrice
+	updatePpobPriceList["active_period"] = ppobPriceList.ActivePeriod
+	updatePpobPriceList["status"] = ppobPriceList.Status
+	updatePpobPriceList["icon_url"] = ppobPriceList.IconUrl
+	updatePpobPriceList["is_active"] = ppobPriceList.IsActive
+	updatePpobPriceList["synced_at"] = ppobPriceList.SyncedAt
+	updatePpobPriceList["updated_at"] = ppobPriceList.UpdatedAt
+	result := db.
+		Model(entity.PpobPriceList{}).
+		Where("id = ?", idPpobPriceList).
+		Updates(updatePpobPriceList)
+	return result.Error
+}
+
+// DeactivatePpobPriceListNotSynced menonaktifkan produk yang sudah tidak ada di pricelist provider
+func (repository *PpobPriceListRepositoryImplementation) DeactivatePpobPriceListNotSynced(db *gorm.DB, productType string, operator string, syncedAt time.Time) error {
+	result := db.
+		Model(entity.PpobPriceList{}).
+		Where("product_type = ?", productType).
+		Where("operator = ?", operator).
+		Where("synced_at < ?", syncedAt).
+		Updates(map[string]interface{}{
+			"is_active":  0,
+			"updated_at": time.Now(),
+		})
+	return result.Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PpobProductTypeRepositoryInterface interface {
+	FindPpobProductTypes(db *gorm.DB) ([]entity.PpobProductType, error)
+	FindPpobProductTypeByCode(db *gorm.DB, code string) (*entity.PpobProductType, error)
+}
+
+type PpobProductTypeRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPpobProductTypeRepository(
+	db *config.Database,
+) PpobProductTypeRepositoryInterface {
+	return &PpobProductTypeRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *PpobProductTypeRepositoryImplementation) FindPpobProductTypes(db *gorm.DB) ([]entity.PpobProductType, error) {
+	ppobProductTypes := []entity.PpobProductType{}
+	result := db.
+		Where("is_active = ?", 1).
+		Order("sort_order asc").
+		Find(&ppobProductTypes)
+	return ppobProductTypes, result.Error
+}
+
+func (repository *PpobProductTypeRepositoryImplementation) FindPpobProductTypeByCode(db *gorm.DB, code string) (*entity.PpobProductType, error) {
+	ppobProductType := &entity.PpobProductType{}
+	result := db.
+		Where("code = ?", code).
+		Where("is_active = ?", 1).
+		Find(ppobProductType)
+	return ppobProductType, result.Error
+}
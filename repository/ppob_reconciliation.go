@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type PpobReconciliationRepositoryInterface interface {
+	FindPpobReconciliations(db *gorm.DB, limit int) ([]entity.PpobReconciliation, error)
+	FindPpobReconciliationByDate(db *gorm.DB, reconcileDate time.Time) (*entity.PpobReconciliation, error)
+	CreatePpobReconciliation(db *gorm.DB, ppobReconciliation *entity.PpobReconciliation) error
+}
+
+type PpobReconciliationRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewPpobReconciliationRepository(
+	db *config.Database,
+) PpobReconciliationRepositoryInterface {
+	return &PpobReconciliationRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *PpobReconciliationRepositoryImplementation) FindPpobReconciliations(db *gorm.DB, limit int) ([]entity.PpobReconciliation, error) {
+	ppobReconciliations := []entity.PpobReconciliation{}
+	result := db.
+		Order("reconcile_date desc").
+		Limit(limit).
+		Find(&ppobReconciliations)
+	return ppobReconciliations, result.Error
+}
+
+func (repository *PpobReconciliationRepositoryImplementation) FindPpobReconciliationByDate(db *gorm.DB, reconcileDate time.Time) (*entity.PpobReconciliation, error) {
+	ppobReconciliation := &entity.PpobReconciliation{}
+	result := db.
+		Where("reconcile_date = ?", reconcileDate).
+		Find(ppobReconciliation)
+	return ppobReconciliation, result.Error
+}
+
+func (repository *PpobReconciliationRepositoryImplementation) CreatePpobReconciliation(db *gorm.DB, ppobReconciliation *entity.PpobReconciliation) error {
+	result := db.Create(ppobReconciliation)
+	return result.Error
+}
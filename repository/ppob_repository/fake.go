@@ -0,0 +1,354 @@
+package ppobrepository
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+)
+
+// FakePpobProviderImplementation provider PPOB lokal tanpa koneksi ke IAK, untuk development dan testing.
+// Rc dipakai sebagai response code semua transaksi (default "00")
+type FakePpobProviderImplementation struct {
+	mutex              sync.Mutex
+	Rc                 string
+	Balance            float64
+	PrepaidPriceLists  []ppob.PrepaidPriceList
+	PostpaidPriceLists []ppob.PostpaidPriceList
+	PostpaidBills      map[string]float64
+	Topups             []ppob.TopupPrepaidPulsaResponseData
+	lastTrxId          int
+	inquiries          map[string]fakePostpaidInquiry
+}
+
+type fakePostpaidInquiry struct {
+	TrxId      int
+	Code       string
+	CustomerId string
+	Nominal    float64
+}
+
+func NewFakePpobProvider() *FakePpobProviderImplementation {
+	return &FakePpobProviderImplementation{
+		Rc:      "00",
+		Balance: 1000000,
+		PrepaidPriceLists: []ppob.PrepaidPriceList{
+			{ProductCode: "htelkomsel10000", ProductDescription: "Telkomsel", ProductNominal: "10000", ProductPrice: 10500, ProductType: "pulsa", ActivePeriod: "30", Status: "active"},
+			{ProductCode: "hindosat10000", ProductDescription: "Indosat", ProductNominal: "10000", ProductPrice: 10600, ProductType: "pulsa", ActivePeriod: "30", Status: "active"},
+			{ProductCode: "hpln20000", ProductDescription: "PLN", ProductNominal: "20000", ProductPrice: 20500, ProductType: "pln", ActivePeriod: "0", Status: "active"},
+			{ProductCode: "hovo20000", ProductDescription: "OVO", ProductNominal: "20000", ProductPrice: 20700, ProductType: "etoll", ActivePeriod: "0", Status: "active"},
+			{ProductCode: "hmobilelegend86", ProductDescription: "Mobile Legends", ProductNominal: "86 Diamonds", ProductPrice: 22000, ProductType: "game", ActivePeriod: "0", Status: "active"},
+		},
+		PostpaidPriceLists: []ppob.PostpaidPriceList{
+			{Code: "PLNPOSTPAID", Name: "PLN Pascabayar", Status: 1, Fee: 2500, Type: "pln"},
+			{Code: "PDAMKOTA.DENPASAR", Name: "PDAM Kota Denpasar", Status: 1, Fee: 2500, Type: "pdam"},
+			{Code: "HPTSEL", Name: "Telkomsel Halo", Status: 1, Fee: 2500, Type: "hp"},
+			{Code: "BPJS", Name: "BPJS Kesehatan", Status: 1, Fee: 2500, Type: "bpjs"},
+			{Code: "FNADIRA", Name: "Adira Finance", Status: 1, Fee: 2500, Type: "finance"},
+		},
+		PostpaidBills: map[string]float64{},
+		inquiries:     map[string]fakePostpaidInquiry{},
+	}
+}
+
+func (provider *FakePpobProviderImplementation) PrepaidPriceList(productType string, operator string) (*ppob.PrepaidPriceListResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	priceList := &ppob.PrepaidPriceListResponse{}
+	priceList.Data.Rc = "00"
+	for _, item := range provider.PrepaidPriceLists {
+		if item.ProductType == productType {
+			priceList.Data.Data = append(priceList.Data.Data, item)
+		}
+	}
+	return priceList, nil
+}
+
+func (provider *FakePpobProviderImplementation) PostpaidPriceList(productType string, province string) (*ppob.PostpaidPriceListResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	priceList := &ppob.PostpaidPriceListResponse{}
+	for _, item := range provider.PostpaidPriceLists {
+		if item.Type == productType {
+			priceList.Data.Pasca = append(priceList.Data.Pasca, item)
+		}
+	}
+	return priceList, nil
+}
+
+func (provider *FakePpobProviderImplementation) InquiryPrepaidPln(customerId string) (*ppob.InquiryPrepaidPln, error) {
+	inquiry := &ppob.InquiryPrepaidPln{}
+	inquiry.Data.Rc = provider.Rc
+	inquiry.Data.Status = "1"
+	inquiry.Data.CustomerId = customerId
+	inquiry.Data.MeterNo = customerId
+	inquiry.Data.SubscriberId = customerId
+	inquiry.Data.Name = "PELANGGAN " + customerId
+	inquiry.Data.SegmentPower = "R1 /000001300"
+	return inquiry, exceptions.IakPrepaidRcError(provider.Rc, "fake inquiry")
+}
+
+func (provider *FakePpobProviderImplementation) InquiryPostpaidPln(customerId string, refId string) (*ppob.InquiryPostpaidPln, error) {
+	bill := provider.inquiryPostpaid("PLNPOSTPAID", customerId, refId)
+	inquiry := &ppob.InquiryPostpaidPln{}
+	inquiry.Data.TrxId = bill.TrxId
+	inquiry.Data.Code = bill.Code
+	inquiry.Data.Hp = customerId
+	inquiry.Data.TrxName = "PELANGGAN " + customerId
+	inquiry.Data.Nominal = bill.Nominal
+	inquiry.Data.Admin = 2500
+	inquiry.Data.Price = bill.Nominal + 2500
+	inquiry.Data.SellingPrice = bill.Nominal + 2500
+	inquiry.Data.RefId = refId
+	inquiry.Data.ResponseCode = provider.Rc
+	return inquiry, exceptions.IakPostpaidRcError(provider.Rc, "fake inquiry")
+}
+
+func (provider *FakePpobProviderImplementation) InquiryPostpaidPdam(productCode string, customerId string, refId string) (*ppob.InquiryPostpaidPdam, error) {
+	bill := provider.inquiryPostpaid(productCode, customerId, refId)
+	inquiry := &ppob.InquiryPostpaidPdam{}
+	inquiry.Data.TrxId = bill.TrxId
+	inquiry.Data.Code = bill.Code
+	inquiry.Data.Hp = customerId
+	inquiry.Data.TrxName = "PELANGGAN " + customerId
+	inquiry.Data.Nominal = bill.Nominal
+	inquiry.Data.Admin = 2500
+	inquiry.Data.Price = bill.Nominal + 2500
+	inquiry.Data.SellingPrice = bill.Nominal + 2500
+	inquiry.Data.RefId = refId
+	inquiry.Data.ResponseCode = provider.Rc
+	return inquiry, exceptions.IakPostpaidRcError(provider.Rc, "fake inquiry")
+}
+
+func (provider *FakePpobProviderImplementation) InquiryPostpaidTelco(productCode string, customerId string, refId string) (*ppob.InquiryPostpaidTelco, error) {
+	bill := provider.inquiryPostpaid(productCode, customerId, refId)
+	inquiry := &ppob.InquiryPostpaidTelco{}
+	inquiry.Data.TrxId = bill.TrxId
+	inquiry.Data.Code = bill.Code
+	inquiry.Data.Hp = customerId
+	inquiry.Data.TrxName = "PELANGGAN " + customerId
+	inquiry.Data.Nominal = bill.Nominal
+	inquiry.Data.Admin = 2500
+	inquiry.Data.Price = bill.Nominal + 2500
+	inquiry.Data.SellingPrice = bill.Nominal + 2500
+	inquiry.Data.RefId = refId
+	inquiry.Data.ResponseCode = provider.Rc
+	return inquiry, exceptions.IakPostpaidRcError(provider.Rc, "fake inquiry")
+}
+
+func (provider *FakePpobProviderImplementation) TopupPrepaid(refId string, customerId string, productCode string) (*ppob.TopupPrepaidPulsaResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	topup := &ppob.TopupPrepaidPulsaResponse{}
+	topup.Data.RefId = refId
+	topup.Data.CustomerId = customerId
+	topup.Data.ProductCode = productCode
+	topup.Data.Rc = provider.Rc
+	topup.Data.Status = 2
+	if provider.Rc == "00" || provider.Rc == "39" {
+		for _, item := range provider.PrepaidPriceLists {
+			if item.ProductCode == productCode {
+				topup.Data.Price = item.ProductPrice
+			}
+		}
+		provider.Balance = provider.Balance - topup.Data.Price
+		provider.lastTrxId++
+		topup.Data.TrId = provider.lastTrxId
+		topup.Data.Status = 0
+	}
+	topup.Data.Balance = provider.Balance
+	provider.Topups = append(provider.Topups, topup.Data)
+	return topup, prepaidStatusRcError(provider.Rc, "fake topup")
+}
+
+func (provider *FakePpobProviderImplementation) PaymentPostpaidPln(trxId int) (*ppob.TopupPostaidPlnResponse, error) {
+	payment := &ppob.TopupPostaidPlnResponse{}
+	payment.Data.TrxId = trxId
+	payment.Data.ResponseCode = provider.Rc
+	payment.Data.Balance = provider.payPostpaid(trxId)
+	return payment, postpaidStatusRcError(provider.Rc, "fake payment")
+}
+
+func (provider *FakePpobProviderImplementation) PaymentPostpaidPdam(trxId int) (*ppob.TopupPostaidPdamResponse, error) {
+	payment := &ppob.TopupPostaidPdamResponse{}
+	payment.Data.TrxId = trxId
+	payment.Data.ResponseCode = provider.Rc
+	payment.Data.Balance = provider.payPostpaid(trxId)
+	return payment, postpaidStatusRcError(provider.Rc, "fake payment")
+}
+
+func (provider *FakePpobProviderImplementation) CheckStatusPrepaid(refId string) (*ppob.PrepaidCheckStatusResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	checkStatus := &ppob.PrepaidCheckStatusResponse{}
+	checkStatus.Data.RefId = refId
+	checkStatus.Data.Rc = "06"
+	checkStatus.Data.Status = 2
+	for _, topup := range provider.Topups {
+		if topup.RefId == refId {
+			checkStatus.Data = topup
+		}
+	}
+	return checkStatus, prepaidStatusRcError(checkStatus.Data.Rc, "fake check status")
+}
+
+func (provider *FakePpobProviderImplementation) CheckStatusPostpaidPln(refId string) (*ppob.PostpaidCheckTransactionPln, error) {
+	bill := provider.findInquiry(refId)
+	checkStatus := &ppob.PostpaidCheckTransactionPln{}
+	checkStatus.Data.TrxId = bill.TrxId
+	checkStatus.Data.Code = bill.Code
+	checkStatus.Data.Hp = bill.CustomerId
+	checkStatus.Data.TrName = "PELANGGAN " + bill.CustomerId
+	checkStatus.Data.Nominal = bill.Nominal
+	checkStatus.Data.Admin = 2500
+	checkStatus.Data.Price = bill.Nominal + 2500
+	checkStatus.Data.SellingPrice = bill.Nominal + 2500
+	checkStatus.Data.ResponseCode = provider.Rc
+	return checkStatus, postpaidStatusRcError(provider.Rc, "fake check status")
+}
+
+func (provider *FakePpobProviderImplementation) CheckStatusPostpaidPdam(refId string) (*ppob.PostpaidCheckTransactionPdam, error) {
+	bill := provider.findInquiry(refId)
+	checkStatus := &ppob.PostpaidCheckTransactionPdam{}
+	checkStatus.Data.TrxId = bill.TrxId
+	checkStatus.Data.Code = bill.Code
+	checkStatus.Data.Hp = bill.CustomerId
+	checkStatus.Data.TrName = "PELANGGAN " + bill.CustomerId
+	checkStatus.Data.Nominal = bill.Nominal
+	checkStatus.Data.Admin = 2500
+	checkStatus.Data.Price = bill.Nominal + 2500
+	checkStatus.Data.SellingPrice = bill.Nominal + 2500
+	checkStatus.Data.ResponseCode = provider.Rc
+	return checkStatus, postpaidStatusRcError(provider.Rc, "fake check status")
+}
+
+func (provider *FakePpobProviderImplementation) CheckStatusPostpaidTelco(refId string) (*ppob.PostpaidCheckTransactionTelco, error) {
+	bill := provider.findInquiry(refId)
+	checkStatus := &ppob.PostpaidCheckTransactionTelco{}
+	checkStatus.Data.TrxId = bill.TrxId
+	checkStatus.Data.Code = bill.Code
+	checkStatus.Data.Hp = bill.CustomerId
+	checkStatus.Data.TrName = "PELANGGAN " + bill.CustomerId
+	checkStatus.Data.Nominal = bill.Nominal
+	checkStatus.Data.Admin = 2500
+	checkStatus.Data.Price = bill.Nominal + 2500
+	checkStatus.Data.SellingPrice = bill.Nominal + 2500
+	checkStatus.Data.ResponseCode = provider.Rc
+	return checkStatus, postpaidStatusRcError(provider.Rc, "fake check status")
+}
+
+func (provider *FakePpobProviderImplementation) InquiryPostpaid(productCode string, customerId string, refId string, params map[string]string) (*ppob.PostpaidTransaction, error) {
+	bill := provider.inquiryPostpaid(productCode, customerId, refId)
+	inquiry := provider.postpaidTransaction(bill)
+	inquiry.Data.RefId = refId
+	return inquiry, exceptions.IakPostpaidRcError(provider.Rc, "fake inquiry")
+}
+
+func (provider *FakePpobProviderImplementation) PaymentPostpaid(trxId int) (*ppob.PostpaidTransaction, error) {
+	payment := &ppob.PostpaidTransaction{}
+	payment.Data.TrxId = trxId
+	payment.Data.ResponseCode = provider.Rc
+	payment.Data.Balance = provider.payPostpaid(trxId)
+	return payment, postpaidStatusRcError(provider.Rc, "fake payment")
+}
+
+func (provider *FakePpobProviderImplementation) CheckStatusPostpaid(refId string) (*ppob.PostpaidTransaction, error) {
+	checkStatus := provider.postpaidTransaction(provider.findInquiry(refId))
+	checkStatus.Data.RefId = refId
+	if provider.Rc == "00" {
+		checkStatus.Data.Status = 1
+	}
+	return checkStatus, postpaidStatusRcError(provider.Rc, "fake check status")
+}
+
+func (provider *FakePpobProviderImplementation) postpaidTransaction(bill fakePostpaidInquiry) *ppob.PostpaidTransaction {
+	transaction := &ppob.PostpaidTransaction{}
+	transaction.Data.TrxId = bill.TrxId
+	transaction.Data.Code = bill.Code
+	transaction.Data.Hp = bill.CustomerId
+	transaction.Data.TrName = "PELANGGAN " + bill.CustomerId
+	transaction.Data.Nominal = bill.Nominal
+	transaction.Data.Admin = 2500
+	transaction.Data.Price = bill.Nominal + 2500
+	transaction.Data.SellingPrice = bill.Nominal + 2500
+	transaction.Data.ResponseCode = provider.Rc
+	return transaction
+}
+
+func (provider *FakePpobProviderImplementation) CheckBalance() (*ppob.CheckBalanceResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	balance := &ppob.CheckBalanceResponse{}
+	balance.Data.Balance = provider.Balance
+	balance.Data.Rc = "00"
+	return balance, nil
+}
+
+// TransactionList semua topup prabayar yang pernah dikirim ke fake, tanggal diabaikan
+func (provider *FakePpobProviderImplementation) TransactionList(date time.Time) (*ppob.TransactionListResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	transactionList := &ppob.TransactionListResponse{}
+	transactionList.Data.Rc = "00"
+	for _, topup := range provider.Topups {
+		transactionList.Data.Transactions = append(transactionList.Data.Transactions, ppob.ProviderTransaction{
+			RefId:       topup.RefId,
+			CustomerId:  topup.CustomerId,
+			ProductCode: topup.ProductCode,
+			Price:       topup.Price,
+			Status:      topup.Status,
+			Sn:          topup.Sn,
+			Date:        date.Format("2006-01-02"),
+		})
+	}
+	return transactionList, nil
+}
+
+func (provider *FakePpobProviderImplementation) CallbackSign(refId string) string {
+	sign := md5.Sum([]byte("fake" + refId))
+	return hex.EncodeToString(sign[:])
+}
+
+// inquiryPostpaid tagihan default 100000 kecuali diatur lewat PostpaidBills
+func (provider *FakePpobProviderImplementation) inquiryPostpaid(productCode string, customerId string, refId string) fakePostpaidInquiry {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	nominal, ok := provider.PostpaidBills[customerId]
+	if !ok {
+		nominal = 100000
+	}
+	provider.lastTrxId++
+	bill := fakePostpaidInquiry{TrxId: provider.lastTrxId, Code: productCode, CustomerId: customerId, Nominal: nominal}
+	provider.inquiries[refId] = bill
+	return bill
+}
+
+func (provider *FakePpobProviderImplementation) findInquiry(refId string) fakePostpaidInquiry {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	return provider.inquiries[refId]
+}
+
+func (provider *FakePpobProviderImplementation) payPostpaid(trxId int) float64 {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if provider.Rc == "00" || provider.Rc == "39" {
+		for _, bill := range provider.inquiries {
+			if bill.TrxId == trxId {
+				provider.Balance = provider.Balance - bill.Nominal
+			}
+		}
+	}
+	return provider.Balance
+}
@@ -0,0 +1,404 @@
+package ppobrepository
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+)
+
+type IakProviderImplementation struct {
+	ConfigPpob config.Ppob
+	Logger     *logrus.Logger
+	Client     *http.Client
+}
+
+func NewIakProvider(configPpob config.Ppob, logger *logrus.Logger) PpobProviderInterface {
+	timeout := configPpob.Timeout
+	if timeout == 0 {
+		timeout = 30
+	}
+	return &IakProviderImplementation{
+		ConfigPpob: configPpob,
+		Logger:     logger,
+		Client:     &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}
+}
+
+// response minimal IAK untuk membaca rc saat body tidak sesuai struct hasil
+type iakRcEnvelope struct {
+	Data struct {
+		Rc           string `json:"rc"`
+		ResponseCode string `json:"response_code"`
+		Message      string `json:"message"`
+	} `json:"data"`
+}
+
+func (provider *IakProviderImplementation) PrepaidPriceList(productType string, operator string) (*ppob.PrepaidPriceListResponse, error) {
+	// operator kosong berarti semua operator dalam tipe produk
+	urlString := provider.ConfigPpob.PrepaidHost + "/pricelist/" + productType
+	if len(operator) != 0 {
+		urlString = urlString + "/" + operator
+	}
+
+	priceList := &ppob.PrepaidPriceListResponse{}
+	err := provider.post(urlString, map[string]interface{}{
+		"status":   "all",
+		"username": provider.ConfigPpob.Username,
+		"sign":     provider.sign("pl"),
+	}, priceList, exceptions.IakPrepaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return priceList, exceptions.IakPrepaidRcError(priceList.Data.Rc, priceList.Data.Message)
+}
+
+func (provider *IakProviderImplementation) PostpaidPriceList(productType string, province string) (*ppob.PostpaidPriceListResponse, error) {
+	body := map[string]interface{}{
+		"commands": "pricelist-pasca",
+		"username": provider.ConfigPpob.Username,
+		"sign":     provider.sign("pl"),
+		"status":   "all",
+	}
+	if len(province) != 0 {
+		body["province"] = province
+	}
+
+	priceList := &ppob.PostpaidPriceListResponse{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl+"/"+productType, body, priceList, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return priceList, nil
+}
+
+func (provider *IakProviderImplementation) InquiryPrepaidPln(customerId string) (*ppob.InquiryPrepaidPln, error) {
+	inquiry := &ppob.InquiryPrepaidPln{}
+	err := provider.post(provider.ConfigPpob.PrepaidHost+"/inquiry-pln", map[string]interface{}{
+		"username":    provider.ConfigPpob.Username,
+		"customer_id": customerId,
+		"sign":        provider.sign(customerId),
+	}, inquiry, exceptions.IakPrepaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return inquiry, exceptions.IakPrepaidRcError(inquiry.Data.Rc, inquiry.Data.Message)
+}
+
+func (provider *IakProviderImplementation) InquiryPostpaidPln(customerId string, refId string) (*ppob.InquiryPostpaidPln, error) {
+	inquiry := &ppob.InquiryPostpaidPln{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.inquiryPostpaidBody("PLNPOSTPAID", customerId, refId), inquiry, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return inquiry, exceptions.IakPostpaidRcError(inquiry.Data.ResponseCode, inquiry.Data.Message)
+}
+
+func (provider *IakProviderImplementation) InquiryPostpaidPdam(productCode string, customerId string, refId string) (*ppob.InquiryPostpaidPdam, error) {
+	inquiry := &ppob.InquiryPostpaidPdam{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.inquiryPostpaidBody(productCode, customerId, refId), inquiry, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return inquiry, exceptions.IakPostpaidRcError(inquiry.Data.ResponseCode, inquiry.Data.Message)
+}
+
+func (provider *IakProviderImplementation) InquiryPostpaidTelco(productCode string, customerId string, refId string) (*ppob.InquiryPostpaidTelco, error) {
+	inquiry := &ppob.InquiryPostpaidTelco{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.inquiryPostpaidBody(productCode, customerId, refId), inquiry, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return inquiry, exceptions.IakPostpaidRcError(inquiry.Data.ResponseCode, inquiry.Data.Message)
+}
+
+// TopupPrepaid tetap mengembalikan response walaupun rc gagal supaya status topup bisa disimpan
+func (provider *IakProviderImplementation) TopupPrepaid(refId string, customerId string, productCode string) (*ppob.TopupPrepaidPulsaResponse, error) {
+	topup := &ppob.TopupPrepaidPulsaResponse{}
+	err := provider.post(provider.ConfigPpob.PrepaidHost+"/top-up", map[string]interface{}{
+		"username":     provider.ConfigPpob.Username,
+		"ref_id":       refId,
+		"customer_id":  customerId,
+		"product_code": productCode,
+		"sign":         provider.sign(refId),
+	}, topup, exceptions.IakPrepaidRcError)
+	if err != nil {
+		return topup, err
+	}
+	return topup, prepaidStatusRcError(topup.Data.Rc, topup.Data.Message)
+}
+
+func (provider *IakProviderImplementation) PaymentPostpaidPln(trxId int) (*ppob.TopupPostaidPlnResponse, error) {
+	payment := &ppob.TopupPostaidPlnResponse{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.paymentPostpaidBody(trxId), payment, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return payment, err
+	}
+	return payment, postpaidStatusRcError(payment.Data.ResponseCode, payment.Data.Message)
+}
+
+func (provider *IakProviderImplementation) PaymentPostpaidPdam(trxId int) (*ppob.TopupPostaidPdamResponse, error) {
+	payment := &ppob.TopupPostaidPdamResponse{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.paymentPostpaidBody(trxId), payment, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return payment, err
+	}
+	return payment, postpaidStatusRcError(payment.Data.ResponseCode, payment.Data.Message)
+}
+
+func (provider *IakProviderImplementation) CheckStatusPrepaid(refId string) (*ppob.PrepaidCheckStatusResponse, error) {
+	checkStatus := &ppob.PrepaidCheckStatusResponse{}
+	err := provider.post(provider.ConfigPpob.PrepaidHost+"/check-status", map[string]interface{}{
+		"username": provider.ConfigPpob.Username,
+		"ref_id":   refId,
+		"sign":     provider.sign(refId),
+	}, checkStatus, exceptions.IakPrepaidRcError)
+	if err != nil {
+		return checkStatus, err
+	}
+	return checkStatus, prepaidStatusRcError(checkStatus.Data.Rc, checkStatus.Data.Message)
+}
+
+func (provider *IakProviderImplementation) CheckStatusPostpaidPln(refId string) (*ppob.PostpaidCheckTransactionPln, error) {
+	checkStatus := &ppob.PostpaidCheckTransactionPln{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.checkStatusPostpaidBody(refId), checkStatus, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return checkStatus, err
+	}
+	return checkStatus, postpaidStatusRcError(checkStatus.Data.ResponseCode, checkStatus.Data.Message)
+}
+
+func (provider *IakProviderImplementation) CheckStatusPostpaidPdam(refId string) (*ppob.PostpaidCheckTransactionPdam, error) {
+	checkStatus := &ppob.PostpaidCheckTransactionPdam{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.checkStatusPostpaidBody(refId), checkStatus, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return checkStatus, err
+	}
+	return checkStatus, postpaidStatusRcError(checkStatus.Data.ResponseCode, checkStatus.Data.Message)
+}
+
+func (provider *IakProviderImplementation) CheckStatusPostpaidTelco(refId string) (*ppob.PostpaidCheckTransactionTelco, error) {
+	checkStatus := &ppob.PostpaidCheckTransactionTelco{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.checkStatusPostpaidBody(refId), checkStatus, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return checkStatus, err
+	}
+	return checkStatus, postpaidStatusRcError(checkStatus.Data.ResponseCode, checkStatus.Data.Message)
+}
+
+// InquiryPostpaid inquiry pascabayar generic, params tambahan (mis. month untuk bpjs) ikut dikirim ke IAK
+func (provider *IakProviderImplementation) InquiryPostpaid(productCode string, customerId string, refId string, params map[string]string) (*ppob.PostpaidTransaction, error) {
+	body := provider.inquiryPostpaidBody(productCode, customerId, refId)
+	for key, value := range params {
+		body[key] = value
+	}
+
+	inquiry := &ppob.PostpaidTransaction{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, body, inquiry, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return inquiry, exceptions.IakPostpaidRcError(inquiry.Data.ResponseCode, inquiry.Data.Message)
+}
+
+func (provider *IakProviderImplementation) PaymentPostpaid(trxId int) (*ppob.PostpaidTransaction, error) {
+	payment := &ppob.PostpaidTransaction{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.paymentPostpaidBody(trxId), payment, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return payment, err
+	}
+	return payment, postpaidStatusRcError(payment.Data.ResponseCode, payment.Data.Message)
+}
+
+func (provider *IakProviderImplementation) CheckStatusPostpaid(refId string) (*ppob.PostpaidTransaction, error) {
+	checkStatus := &ppob.PostpaidTransaction{}
+	err := provider.post(provider.ConfigPpob.PostpaidUrl, provider.checkStatusPostpaidBody(refId), checkStatus, exceptions.IakPostpaidRcError)
+	if err != nil {
+		return checkStatus, err
+	}
+	return checkStatus, postpaidStatusRcError(checkStatus.Data.ResponseCode, checkStatus.Data.Message)
+}
+
+// CheckBalance saldo deposit IAK, dipakai untuk laporan rekonsiliasi
+func (provider *IakProviderImplementation) CheckBalance() (*ppob.CheckBalanceResponse, error) {
+	balance := &ppob.CheckBalanceResponse{}
+	err := provider.post(provider.ConfigPpob.PrepaidHost+"/check-balance", map[string]interface{}{
+		"username": provider.ConfigPpob.Username,
+		"sign":     provider.sign("bl"),
+	}, balance, exceptions.IakPrepaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return balance, exceptions.IakPrepaidRcError(balance.Data.Rc, balance.Data.Message)
+}
+
+// TransactionList daftar transaksi IAK pada satu tanggal, dipakai laporan rekonsiliasi
+func (provider *IakProviderImplementation) TransactionList(date time.Time) (*ppob.TransactionListResponse, error) {
+	if len(provider.ConfigPpob.TransactionListUrl) == 0 {
+		return nil, errors.New("ppob transaction list url not configured")
+	}
+
+	transactionDate := date.Format("2006-01-02")
+	transactionList := &ppob.TransactionListResponse{}
+	err := provider.post(provider.ConfigPpob.TransactionListUrl, map[string]interface{}{
+		"username": provider.ConfigPpob.Username,
+		"date":     transactionDate,
+		"sign":     provider.sign(transactionDate),
+	}, transactionList, exceptions.IakPrepaidRcError)
+	if err != nil {
+		return nil, err
+	}
+	return transactionList, exceptions.IakPrepaidRcError(transactionList.Data.Rc, transactionList.Data.Message)
+}
+
+func (provider *IakProviderImplementation) CallbackSign(refId string) string {
+	return provider.sign(refId)
+}
+
+func (provider *IakProviderImplementation) inquiryPostpaidBody(productCode string, customerId string, refId string) map[string]interface{} {
+	return map[string]interface{}{
+		"commands": "inq-pasca",
+		"username": provider.ConfigPpob.Username,
+		"code":     productCode,
+		"hp":       customerId,
+		"ref_id":   refId,
+		"sign":     provider.sign(refId),
+	}
+}
+
+func (provider *IakProviderImplementation) paymentPostpaidBody(trxId int) map[string]interface{} {
+	return map[string]interface{}{
+		"commands": "pay-pasca",
+		"username": provider.ConfigPpob.Username,
+		"tr_id":    trxId,
+		"sign":     provider.sign(strconv.Itoa(trxId)),
+	}
+}
+
+func (provider *IakProviderImplementation) checkStatusPostpaidBody(refId string) map[string]interface{} {
+	return map[string]interface{}{
+		"commands": "checkstatus",
+		"ref_id":   refId,
+		"username": provider.ConfigPpob.Username,
+		"sign":     provider.sign("cs"),
+	}
+}
+
+// sign md5(username + key + suffix) sesuai dokumentasi IAK
+func (provider *IakProviderImplementation) sign(suffix string) string {
+	sign := md5.Sum([]byte(provider.ConfigPpob.Username + provider.ConfigPpob.PpobKey + suffix))
+	return hex.EncodeToString(sign[:])
+}
+
+func (provider *IakProviderImplementation) post(urlString string, body map[string]interface{}, result interface{}, rcError func(rc string, message string) error) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, urlString, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	provider.Logger.WithFields(logrus.Fields{"url": urlString, "body": redactPpobBody(reqBody)}).Info("ppob request")
+
+	resp, err := provider.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	provider.Logger.WithFields(logrus.Fields{"url": urlString, "status": resp.StatusCode, "body": redactPpobBody(data)}).Info("ppob response")
+
+	if err = json.Unmarshal(data, result); err != nil {
+		// body error IAK kadang beda tipe dengan response sukses
+		envelope := &iakRcEnvelope{}
+		if json.Unmarshal(data, envelope) == nil {
+			rc := envelope.Data.Rc
+			if len(rc) == 0 {
+				rc = envelope.Data.ResponseCode
+			}
+			if len(rc) != 0 && rc != "00" {
+				return rcError(rc, envelope.Data.Message)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// prepaidStatusRcError rc pending bukan error untuk transaksi yang sedang diproses
+func prepaidStatusRcError(rc string, message string) error {
+	if rc == "39" {
+		return nil
+	}
+	return exceptions.IakPrepaidRcError(rc, message)
+}
+
+func postpaidStatusRcError(rc string, message string) error {
+	if rc == "39" {
+		return nil
+	}
+	return exceptions.IakPostpaidRcError(rc, message)
+}
+
+var ppobSecretKeys = []string{"username", "sign", "ppobkey", "key", "password", "token"}
+
+// redactPpobBody menyamarkan kredensial sebelum request/response ditulis ke log
+func redactPpobBody(data []byte) string {
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return string(data)
+	}
+	redactPpobValue(body)
+	redacted, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	return string(redacted)
+}
+
+func redactPpobValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isPpobSecretKey(key) {
+				v[key] = "***"
+				continue
+			}
+			redactPpobValue(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactPpobValue(item)
+		}
+	}
+}
+
+func isPpobSecretKey(key string) bool {
+	for _, secretKey := range ppobSecretKeys {
+		if strings.EqualFold(key, secretKey) {
+			return true
+		}
+	}
+	return false
+}
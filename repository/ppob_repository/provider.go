@@ -0,0 +1,40 @@
+package ppobrepository
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+)
+
+// PpobProviderInterface kontrak provider PPOB, error response code provider
+// dikembalikan sebagai exceptions.PpobRcError
+type PpobProviderInterface interface {
+	PrepaidPriceList(productType string, operator string) (*ppob.PrepaidPriceListResponse, error)
+	PostpaidPriceList(productType string, province string) (*ppob.PostpaidPriceListResponse, error)
+	InquiryPrepaidPln(customerId string) (*ppob.InquiryPrepaidPln, error)
+	InquiryPostpaidPln(customerId string, refId string) (*ppob.InquiryPostpaidPln, error)
+	InquiryPostpaidPdam(productCode string, customerId string, refId string) (*ppob.InquiryPostpaidPdam, error)
+	InquiryPostpaidTelco(productCode string, customerId string, refId string) (*ppob.InquiryPostpaidTelco, error)
+	TopupPrepaid(refId string, customerId string, productCode string) (*ppob.TopupPrepaidPulsaResponse, error)
+	PaymentPostpaidPln(trxId int) (*ppob.TopupPostaidPlnResponse, error)
+	PaymentPostpaidPdam(trxId int) (*ppob.TopupPostaidPdamResponse, error)
+	CheckStatusPrepaid(refId string) (*ppob.PrepaidCheckStatusResponse, error)
+	CheckStatusPostpaidPln(refId string) (*ppob.PostpaidCheckTransactionPln, error)
+	CheckStatusPostpaidPdam(refId string) (*ppob.PostpaidCheckTransactionPdam, error)
+	CheckStatusPostpaidTelco(refId string) (*ppob.PostpaidCheckTransactionTelco, error)
+	InquiryPostpaid(productCode string, customerId string, refId string, params map[string]string) (*ppob.PostpaidTransaction, error)
+	PaymentPostpaid(trxId int) (*ppob.PostpaidTransaction, error)
+	CheckStatusPostpaid(refId string) (*ppob.PostpaidTransaction, error)
+	CheckBalance() (*ppob.CheckBalanceResponse, error)
+	TransactionList(date time.Time) (*ppob.TransactionListResponse, error)
+	CallbackSign(refId string) string
+}
+
+func NewPpobProvider(configPpob config.Ppob, logger *logrus.Logger) PpobProviderInterface {
+	if configPpob.Provider == "fake" {
+		return NewFakePpobProvider()
+	}
+	return NewIakProvider(configPpob, logger)
+}
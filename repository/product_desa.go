@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"gorm.io/gorm"
@@ -14,6 +16,27 @@ type ProductDesaRepositoryInterface interface {
 	FindProductDesaById(db *gorm.DB, IdProductDesa string) (*entity.ProductsDesa, error)
 	UpdateProductStock(db *gorm.DB, idProductDesa string, productDesa *entity.ProductsDesa) error
 	FindListPackageByIdProductDesa(db *gorm.DB, idProductDesa string) ([]entity.ProductsPackageItems, error)
+	FindProductDesaByIdProduct(db *gorm.DB, IdDesa string, IdProduct string) (*entity.ProductsDesa, error)
+	CreateProductDesa(db *gorm.DB, productDesa *entity.ProductsDesa) error
+	UpdateProductDesa(db *gorm.DB, idProductDesa string, productDesa *entity.ProductsDesa) error
+	AdjustProductStock(db *gorm.DB, idProductDesa string, qty int) (int64, error)
+	SearchProductsDesa(db *gorm.DB, IdDesa string, params ProductDesaSearchParams) ([]entity.ProductsDesa, int64, error)
+}
+
+// ProductDesaSearchParams filter, urutan dan posisi cursor untuk SearchProductsDesa.
+// CursorValue berisi nilai SortColumn dari item terakhir halaman sebelumnya.
+type ProductDesaSearchParams struct {
+	Keyword       string
+	IdCategory    int
+	IdSubCategory int
+	IdBrand       int
+	IsPromo       bool
+	InStock       bool
+	SortColumn    string
+	SortDesc      bool
+	CursorValue   interface{}
+	CursorId      string
+	Limit         int
 }
 
 type ProductDesaRepositoryImplementation struct {
@@ -31,9 +54,9 @@ func NewProductDesaRepository(
 func (repository *ProductDesaRepositoryImplementation) FindListPackageByIdProductDesa(db *gorm.DB, IdProductDesa string) ([]entity.ProductsPackageItems, error) {
 	productsDesa := []entity.ProductsPackageItems{}
 	result := db.
-		Joins("ProductsMaster").
 		Joins("ProductsDesa").
-		Find(&productsDesa, "id_product_primary = ?", IdProductDesa)
+		Preload("ProductsDesa.ProductsMaster").
+		Find(&productsDesa, "products_package_items.id_product_primary = ?", IdProductDesa)
 	return productsDesa, result.Error
 }
 
@@ -89,3 +112,110 @@ func (repository *ProductDesaRepositoryImplementation) UpdateProductStock(db *go
 		Updates(&updateProduct)
 	return result.Error
 }
+
+func (repository *ProductDesaRepositoryImplementation) FindProductDesaByIdProduct(db *gorm.DB, IdDesa string, IdProduct string) (*entity.ProductsDesa, error) {
+	productsDesa := &entity.ProductsDesa{}
+	result := db.
+		Joins("ProductsMaster").
+		Find(productsDesa, "products_desa.id_desa = ? AND products_desa.id_product = ?", IdDesa, IdProduct)
+	return productsDesa, result.Error
+}
+
+func (repository *ProductDesaRepositoryImplementation) CreateProductDesa(db *gorm.DB, productDesa *entity.ProductsDesa) error {
+	result := db.Omit("ProductsMaster").Create(&productDesa)
+	return result.Error
+}
+
+func (repository *ProductDesaRepositoryImplementation) UpdateProductDesa(db *gorm.DB, idProductDesa string, productDesa *entity.ProductsDesa) error {
+	updateProduct := make(map[string]interface{})
+	updateProduct["price"] = productDesa.Price
+	updateProduct["price_grosir"] = productDesa.PriceGrosir
+	updateProduct["price_promo"] = productDesa.PricePromo
+	updateProduct["percentage_promo"] = productDesa.PercentagePromo
+	updateProduct["is_promo"] = productDesa.IsPromo
+	updateProduct["id_promo"] = productDesa.IdPromo
+	updateProduct["picture_url"] = productDesa.PictureUrl
+	updateProduct["thumbnail"] = productDesa.Thumbnail
+	updateProduct["description"] = productDesa.Description
+	result := db.
+		Model(entity.ProductsDesa{}).
+		Where("id = ?", idProductDesa).
+		Updates(&updateProduct)
+	return result.Error
+}
+
+// AdjustProductStock menambah / mengurangi stok secara atomik, stok tidak boleh minus
+func (repository *ProductDesaRepositoryImplementation) AdjustProductStock(db *gorm.DB, idProductDesa string, qty int) (int64, error) {
+	result := db.
+		Model(entity.ProductsDesa{}).
+		Where("id = ? AND stock_opname + ? >= 0", idProductDesa, qty).
+		Update("stock_opname", gorm.Expr("stock_opname + ?", qty))
+	return result.RowsAffected, result.Error
+}
+
+func (repository *ProductDesaRepositoryImplementation) SearchProductsDesa(db *gorm.DB, IdDesa string, params ProductDesaSearchParams) ([]entity.ProductsDesa, int64, error) {
+	productsDesa := []entity.ProductsDesa{}
+	query := db.
+		Model(&entity.ProductsDesa{}).
+		Joins("ProductsMaster").
+		Where("products_desa.id_desa = ?", IdDesa)
+
+	if keyword := productSearchBooleanQuery(params.Keyword); len(keyword) != 0 {
+		query = query.Where("MATCH(ProductsMaster.product_name, ProductsMaster.no_sku, ProductsMaster.description) AGAINST(? IN BOOLEAN MODE)", keyword)
+	}
+	if params.IdCategory != 0 {
+		query = query.Where("ProductsMaster.id_category = ?", params.IdCategory)
+	}
+	if params.IdSubCategory != 0 {
+		query = query.Where("ProductsMaster.id_sub_category = ?", params.IdSubCategory)
+	}
+	if params.IdBrand != 0 {
+		query = query.Where("ProductsMaster.id_brand = ?", params.IdBrand)
+	}
+	if params.IsPromo {
+		query = query.Where("products_desa.is_promo = ?", 1)
+	}
+	if params.InStock {
+		query = query.Where("products_desa.stock_opname > ?", 0)
+	}
+
+	var total int64
+	result := query.Session(&gorm.Session{}).Count(&total)
+	if result.Error != nil {
+		return productsDesa, total, result.Error
+	}
+
+	direction, operator := "asc", ">"
+	if params.SortDesc {
+		direction, operator = "desc", "<"
+	}
+	if len(params.CursorId) != 0 {
+		query = query.Where("("+params.SortColumn+" "+operator+" ? OR ("+params.SortColumn+" = ? AND products_desa.id "+operator+" ?))", params.CursorValue, params.CursorValue, params.CursorId)
+	}
+	result = query.
+		Order(params.SortColumn + " " + direction).
+		Order("products_desa.id " + direction).
+		Limit(params.Limit).
+		Find(&productsDesa)
+	return productsDesa, total, result.Error
+}
+
+// productSearchBooleanQuery setiap kata wajib ada sebagai awalan kata, operator boolean dari user dibuang
+func productSearchBooleanQuery(keyword string) string {
+	operatorRemover := strings.NewReplacer("+", " ", "-", " ", "<", " ", ">", " ", "(", " ", ")", " ", "~", " ", "*", " ", "\"", " ", "@", " ")
+	var terms []string
+	for _, term := range strings.Fields(operatorRemover.Replace(keyword)) {
+		terms = append(terms, "+"+term+"*")
+	}
+	return strings.Join(terms, " ")
+}
+
+// EnsureProductSearchIndex membuat index FULLTEXT pencarian produk master jika belum ada
+func EnsureProductSearchIndex(db *gorm.DB) {
+	if db.Migrator().HasIndex(&entity.ProductsMaster{}, "idx_products_master_search") {
+		return
+	}
+	if err := db.Migrator().CreateIndex(&entity.ProductsMaster{}, "idx_products_master_search"); err != nil {
+		panic("Cannot create product search index: " + err.Error())
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/cache"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type CachedProductDesaRepositoryImplementation struct {
+	ProductDesaRepositoryInterface ProductDesaRepositoryInterface
+	Cache                          cache.Cache
+	ConfigCache                    config.Cache
+}
+
+func NewCachedProductDesaRepository(
+	productDesaRepositoryInterface ProductDesaRepositoryInterface,
+	c cache.Cache,
+	configCache config.Cache,
+) ProductDesaRepositoryInterface {
+	return &CachedProductDesaRepositoryImplementation{
+		ProductDesaRepositoryInterface: productDesaRepositoryInterface,
+		Cache:                          c,
+		ConfigCache:                    configCache,
+	}
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) readThrough(db *gorm.DB, dest interface{}, load func() error, parts ...string) error {
+	key := cache.Key(repository.Cache, cache.NamespaceProduct, parts...)
+	return cacheReadThrough(repository.Cache, db, key, cache.Ttl(repository.ConfigCache, cache.NamespaceProduct), dest, load)
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindProductsDesa(db *gorm.DB, IdDesa string) (productsDesa []entity.ProductsDesa, err error) {
+	err = repository.readThrough(db, &productsDesa, func() (err error) {
+		productsDesa, err = repository.ProductDesaRepositoryInterface.FindProductsDesa(db, IdDesa)
+		return err
+	}, "list", IdDesa)
+	return productsDesa, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindProductsDesaByCategory(db *gorm.DB, IdDesa string, IdCategory int) (productsDesa []entity.ProductsDesa, err error) {
+	err = repository.readThrough(db, &productsDesa, func() (err error) {
+		productsDesa, err = repository.ProductDesaRepositoryInterface.FindProductsDesaByCategory(db, IdDesa, IdCategory)
+		return err
+	}, "category", IdDesa, strconv.Itoa(IdCategory))
+	return productsDesa, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindProductsDesaBySubCategory(db *gorm.DB, IdDesa string, IdSubCategory int) (productsDesa []entity.ProductsDesa, err error) {
+	err = repository.readThrough(db, &productsDesa, func() (err error) {
+		productsDesa, err = repository.ProductDesaRepositoryInterface.FindProductsDesaBySubCategory(db, IdDesa, IdSubCategory)
+		return err
+	}, "sub_category", IdDesa, strconv.Itoa(IdSubCategory))
+	return productsDesa, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindProductsDesaByPromo(db *gorm.DB, IdDesa string, IdPromo string) (productsDesa []entity.ProductsDesa, err error) {
+	err = repository.readThrough(db, &productsDesa, func() (err error) {
+		productsDesa, err = repository.ProductDesaRepositoryInterface.FindProductsDesaByPromo(db, IdDesa, IdPromo)
+		return err
+	}, "promo", IdDesa, IdPromo)
+	return productsDesa, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindProductDesaById(db *gorm.DB, IdProductDesa string) (*entity.ProductsDesa, error) {
+	productDesa := &entity.ProductsDesa{}
+	err := repository.readThrough(db, productDesa, func() error {
+		result, err := repository.ProductDesaRepositoryInterface.FindProductDesaById(db, IdProductDesa)
+		if result != nil {
+			*productDesa = *result
+		}
+		return err
+	}, "id", IdProductDesa)
+	return productDesa, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindListPackageByIdProductDesa(db *gorm.DB, idProductDesa string) (packageItems []entity.ProductsPackageItems, err error) {
+	err = repository.readThrough(db, &packageItems, func() (err error) {
+		packageItems, err = repository.ProductDesaRepositoryInterface.FindListPackageByIdProductDesa(db, idProductDesa)
+		return err
+	}, "package", idProductDesa)
+	return packageItems, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) FindProductDesaByIdProduct(db *gorm.DB, IdDesa string, IdProduct string) (*entity.ProductsDesa, error) {
+	return repository.ProductDesaRepositoryInterface.FindProductDesaByIdProduct(db, IdDesa, IdProduct)
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) SearchProductsDesa(db *gorm.DB, IdDesa string, params ProductDesaSearchParams) ([]entity.ProductsDesa, int64, error) {
+	searchResult := struct {
+		ProductsDesa []entity.ProductsDesa
+		Total        int64
+	}{}
+	paramsKey, err := json.Marshal(params)
+	if err != nil {
+		return repository.ProductDesaRepositoryInterface.SearchProductsDesa(db, IdDesa, params)
+	}
+	err = repository.readThrough(db, &searchResult, func() (err error) {
+		searchResult.ProductsDesa, searchResult.Total, err = repository.ProductDesaRepositoryInterface.SearchProductsDesa(db, IdDesa, params)
+		return err
+	}, "search", IdDesa, string(paramsKey))
+	return searchResult.ProductsDesa, searchResult.Total, err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) UpdateProductStock(db *gorm.DB, idProductDesa string, productDesa *entity.ProductsDesa) error {
+	err := repository.ProductDesaRepositoryInterface.UpdateProductStock(db, idProductDesa, productDesa)
+	cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+	return err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) CreateProductDesa(db *gorm.DB, productDesa *entity.ProductsDesa) error {
+	err := repository.ProductDesaRepositoryInterface.CreateProductDesa(db, productDesa)
+	cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+	return err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) UpdateProductDesa(db *gorm.DB, idProductDesa string, productDesa *entity.ProductsDesa) error {
+	err := repository.ProductDesaRepositoryInterface.UpdateProductDesa(db, idProductDesa, productDesa)
+	cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+	return err
+}
+
+func (repository *CachedProductDesaRepositoryImplementation) AdjustProductStock(db *gorm.DB, idProductDesa string, qty int) (int64, error) {
+	rowsAffected, err := repository.ProductDesaRepositoryInterface.AdjustProductStock(db, idProductDesa, qty)
+	cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+	return rowsAffected, err
+}
+
+// CachedProductMasterRepositoryImplementation hanya menginvalidasi cache produk desa,
+// data master dibaca langsung karena hanya dipakai admin
+type CachedProductMasterRepositoryImplementation struct {
+	ProductMasterRepositoryInterface
+	Cache cache.Cache
+}
+
+func NewCachedProductMasterRepository(
+	productMasterRepositoryInterface ProductMasterRepositoryInterface,
+	c cache.Cache,
+) ProductMasterRepositoryInterface {
+	return &CachedProductMasterRepositoryImplementation{
+		ProductMasterRepositoryInterface: productMasterRepositoryInterface,
+		Cache:                            c,
+	}
+}
+
+func (repository *CachedProductMasterRepositoryImplementation) CreateProductMaster(db *gorm.DB, productMaster *entity.ProductsMaster) error {
+	err := repository.ProductMasterRepositoryInterface.CreateProductMaster(db, productMaster)
+	cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+	return err
+}
+
+func (repository *CachedProductMasterRepositoryImplementation) UpdateProductMaster(db *gorm.DB, idProductMaster string, productMaster *entity.ProductsMaster) error {
+	err := repository.ProductMasterRepositoryInterface.UpdateProductMaster(db, idProductMaster, productMaster)
+	cacheInvalidate(repository.Cache, db, cache.NamespaceProduct)
+	return err
+}
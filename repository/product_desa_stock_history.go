@@ -8,6 +8,7 @@ import (
 
 type ProductDesaStockHistoryRepositoryInterface interface {
 	CreateProductDesaStockHistory(db *gorm.DB, productDesaStockHistory *entity.ProductDesaStockHistory) error
+	FindProductDesaStockHistoryByIdProductDesa(db *gorm.DB, idProductDesa string) ([]entity.ProductDesaStockHistory, error)
 }
 
 type ProductDesaStockHistoryRepositoryImplementation struct {
@@ -26,3 +27,12 @@ func (repository *ProductDesaStockHistoryRepositoryImplementation) CreateProduct
 	result := db.Create(&productDesaStockHistory)
 	return result.Error
 }
+
+func (repository *ProductDesaStockHistoryRepositoryImplementation) FindProductDesaStockHistoryByIdProductDesa(db *gorm.DB, idProductDesa string) ([]entity.ProductDesaStockHistory, error) {
+	productDesaStockHistories := []entity.ProductDesaStockHistory{}
+	result := db.
+		Where("id_product_desa = ?", idProductDesa).
+		Order("created_at desc").
+		Find(&productDesaStockHistories)
+	return productDesaStockHistories, result.Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type ProductMasterRepositoryInterface interface {
+	CreateProductMaster(db *gorm.DB, productMaster *entity.ProductsMaster) error
+	UpdateProductMaster(db *gorm.DB, idProductMaster string, productMaster *entity.ProductsMaster) error
+	FindProductMasterById(db *gorm.DB, idProductMaster string) (*entity.ProductsMaster, error)
+	FindProductMasterBySku(db *gorm.DB, noSku string) (*entity.ProductsMaster, error)
+}
+
+type ProductMasterRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewProductMasterRepository(
+	db *config.Database,
+) ProductMasterRepositoryInterface {
+	return &ProductMasterRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *ProductMasterRepositoryImplementation) CreateProductMaster(db *gorm.DB, productMaster *entity.ProductsMaster) error {
+	result := db.Create(&productMaster)
+	return result.Error
+}
+
+func (repository *ProductMasterRepositoryImplementation) UpdateProductMaster(db *gorm.DB, idProductMaster string, productMaster *entity.ProductsMaster) error {
+	productMasterEntity := make(map[string]interface{})
+	productMasterEntity["id_brand"] = productMaster.IdBrand
+	productMasterEntity["id_category"] = productMaster.IdCategory
+	productMasterEntity["id_sub_category"] = productMaster.IdSubCategory
+	productMasterEntity["id_unit"] = productMaster.IdUnit
+	productMasterEntity["unit_value"] = productMaster.UnitValue
+	productMasterEntity["no_sku"] = productMaster.NoSku
+	productMasterEntity["product_name"] = productMaster.ProductName
+	productMasterEntity["price"] = productMaster.Price
+	productMasterEntity["price_grosir"] = productMaster.PriceGrosir
+	productMasterEntity["description"] = productMaster.Description
+	productMasterEntity["picture_url"] = productMaster.PictureUrl
+	productMasterEntity["thumbnail"] = productMaster.Thumbnail
+	result := db.
+		Model(entity.ProductsMaster{}).
+		Where("id = ?", idProductMaster).
+		Updates(productMasterEntity)
+	return result.Error
+}
+
+func (repository *ProductMasterRepositoryImplementation) FindProductMasterById(db *gorm.DB, idProductMaster string) (*entity.ProductsMaster, error) {
+	productMaster := &entity.ProductsMaster{}
+	result := db.Find(productMaster, "id = ?", idProductMaster)
+	return productMaster, result.Error
+}
+
+func (repository *ProductMasterRepositoryImplementation) FindProductMasterBySku(db *gorm.DB, noSku string) (*entity.ProductsMaster, error) {
+	productMaster := &entity.ProductsMaster{}
+	result := db.Find(productMaster, "no_sku = ?", noSku)
+	return productMaster, result.Error
+}
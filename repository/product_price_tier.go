@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type ProductPriceTierRepositoryInterface interface {
+	FindPriceTierByIdProductDesa(db *gorm.DB, idProductDesa string) ([]entity.ProductsDesaPriceTier, error)
+	FindPriceTierByIdProductDesas(db *gorm.DB, idProductDesas []string) ([]entity.ProductsDesaPriceTier, error)
+	DeletePriceTierByIdProductDesa(db *gorm.DB, idProductDesa string) error
+	CreatePriceTier(db *gorm.DB, priceTiers []entity.ProductsDesaPriceTier) error
+}
+
+type ProductPriceTierRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewProductPriceTierRepository(
+	db *config.Database,
+) ProductPriceTierRepositoryInterface {
+	return &ProductPriceTierRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *ProductPriceTierRepositoryImplementation) FindPriceTierByIdProductDesa(db *gorm.DB, idProductDesa string) ([]entity.ProductsDesaPriceTier, error) {
+	priceTiers := []entity.ProductsDesaPriceTier{}
+	result := db.
+		Where("id_product_desa = ?", idProductDesa).
+		Order("account_type asc, min_qty asc").
+		Find(&priceTiers)
+	return priceTiers, result.Error
+}
+
+func (repository *ProductPriceTierRepositoryImplementation) FindPriceTierByIdProductDesas(db *gorm.DB, idProductDesas []string) ([]entity.ProductsDesaPriceTier, error) {
+	priceTiers := []entity.ProductsDesaPriceTier{}
+	if len(idProductDesas) == 0 {
+		return priceTiers, nil
+	}
+	result := db.
+		Where("id_product_desa IN ?", idProductDesas).
+		Order("min_qty asc").
+		Find(&priceTiers)
+	return priceTiers, result.Error
+}
+
+func (repository *ProductPriceTierRepositoryImplementation) DeletePriceTierByIdProductDesa(db *gorm.DB, idProductDesa string) error {
+	result := db.
+		Where("id_product_desa = ?", idProductDesa).
+		Delete(&entity.ProductsDesaPriceTier{})
+	return result.Error
+}
+
+func (repository *ProductPriceTierRepositoryImplementation) CreatePriceTier(db *gorm.DB, priceTiers []entity.ProductsDesaPriceTier) error {
+	result := db.Create(&priceTiers)
+	return result.Error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type ProductUnitRepositoryInterface interface {
+	FindProductUnits(db *gorm.DB) ([]entity.ProductsUnit, error)
+}
+
+type ProductUnitRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewProductUnitRepository(
+	db *config.Database,
+) ProductUnitRepositoryInterface {
+	return &ProductUnitRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *ProductUnitRepositoryImplementation) FindProductUnits(db *gorm.DB) ([]entity.ProductsUnit, error) {
+	productUnits := []entity.ProductsUnit{}
+	result := db.Find(&productUnits)
+	return productUnits, result.Error
+}
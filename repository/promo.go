@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"gorm.io/gorm"
@@ -8,6 +10,8 @@ import (
 
 type PromoRepositoryInterface interface {
 	FindPromo(db *gorm.DB, IdDesa string) ([]entity.Promo, error)
+	FindPromoById(db *gorm.DB, IdPromo string) (*entity.Promo, error)
+	SyncProductPromoSchedule(db *gorm.DB, now time.Time) (int64, error)
 }
 
 type PromoRepositoryImplementation struct {
@@ -25,7 +29,36 @@ func NewPromoRepository(
 func (service *PromoRepositoryImplementation) FindPromo(db *gorm.DB, IdDesa string) ([]entity.Promo, error) {
 	promos := []entity.Promo{}
 	results := db.
-		Where("id_desa = ?", IdDesa).
+		Where("id_desa = ? AND end_date >= ?", IdDesa, time.Now()).
 		Find(&promos)
 	return promos, results.Error
 }
+
+func (service *PromoRepositoryImplementation) FindPromoById(db *gorm.DB, IdPromo string) (*entity.Promo, error) {
+	promo := &entity.Promo{}
+	results := db.
+		Where("id = ?", IdPromo).
+		Find(promo)
+	return promo, results.Error
+}
+
+// SyncProductPromoSchedule mengaktifkan is_promo produk desa yang promonya sedang berjalan
+// dan menonaktifkan yang belum mulai atau sudah berakhir. Produk tanpa id_promo tidak diubah.
+func (service *PromoRepositoryImplementation) SyncProductPromoSchedule(db *gorm.DB, now time.Time) (int64, error) {
+	activePromo := db.Model(entity.Promo{}).Select("id").Where("start_date <= ? AND end_date >= ?", now, now)
+	inactivePromo := db.Model(entity.Promo{}).Select("id").Where("start_date > ? OR end_date < ?", now, now)
+
+	activated := db.
+		Model(entity.ProductsDesa{}).
+		Where("is_promo = ? AND price_promo > ? AND id_promo IN (?)", 0, 0, activePromo).
+		Update("is_promo", 1)
+	if activated.Error != nil {
+		return 0, activated.Error
+	}
+
+	deactivated := db.
+		Model(entity.ProductsDesa{}).
+		Where("is_promo = ? AND id_promo IN (?)", 1, inactivePromo).
+		Update("is_promo", 0)
+	return activated.RowsAffected + deactivated.RowsAffected, deactivated.Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type ReferralRepositoryInterface interface {
+	CreateReferral(db *gorm.DB, referral *entity.Referral) error
+	FindReferralByIdReferee(db *gorm.DB, idReferee string) (*entity.Referral, error)
+	FindReferralsByReferrer(db *gorm.DB, idReferrer string) ([]entity.Referral, error)
+	CountReferralByNoIdentitas(db *gorm.DB, noIdentitas string) (int64, error)
+	CountReferralByDeviceId(db *gorm.DB, deviceId string) (int64, error)
+	UpdateReferralRewarded(db *gorm.DB, idReferral string, referrerPoint float64, refereePoint float64) (int64, error)
+}
+
+type ReferralRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewReferralRepository(
+	db *config.Database,
+) ReferralRepositoryInterface {
+	return &ReferralRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *ReferralRepositoryImplementation) CreateReferral(db *gorm.DB, referral *entity.Referral) error {
+	result := db.Create(referral)
+	return result.Error
+}
+
+func (repository *ReferralRepositoryImplementation) FindReferralByIdReferee(db *gorm.DB, idReferee string) (*entity.Referral, error) {
+	referral := &entity.Referral{}
+	result := db.Where("id_referee = ?", idReferee).Find(referral)
+	return referral, result.Error
+}
+
+func (repository *ReferralRepositoryImplementation) FindReferralsByReferrer(db *gorm.DB, idReferrer string) ([]entity.Referral, error) {
+	referrals := []entity.Referral{}
+	result := db.
+		Where("id_referrer = ?", idReferrer).
+		Order("created_at desc").
+		Find(&referrals)
+	return referrals, result.Error
+}
+
+func (repository *ReferralRepositoryImplementation) CountReferralByNoIdentitas(db *gorm.DB, noIdentitas string) (int64, error) {
+	var total int64
+	result := db.
+		Model(&entity.Referral{}).
+		Where("no_identitas = ?", noIdentitas).
+		Count(&total)
+	return total, result.Error
+}
+
+func (repository *ReferralRepositoryImplementation) CountReferralByDeviceId(db *gorm.DB, deviceId string) (int64, error) {
+	var total int64
+	result := db.
+		Model(&entity.Referral{}).
+		Where("device_id = ?", deviceId).
+		Count(&total)
+	return total, result.Error
+}
+
+// UpdateReferralRewarded hanya mengubah referral yang masih pending supaya reward tidak dobel
+func (repository *ReferralRepositoryImplementation) UpdateReferralRewarded(db *gorm.DB, idReferral string, referrerPoint float64, refereePoint float64) (int64, error) {
+	result := db.
+		Model(&entity.Referral{}).
+		Where("id = ?", idReferral).
+		Where("status = ?", 1).
+		Updates(map[string]interface{}{
+			"status":         2,
+			"referrer_point": referrerPoint,
+			"referee_point":  refereePoint,
+			"rewarded_at":    time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
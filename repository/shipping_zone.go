@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type ShippingZoneRepositoryInterface interface {
+	FindShippingZoneByIdDesa(db *gorm.DB, idDesa string) (*entity.ShippingZone, error)
+	SaveShippingZone(db *gorm.DB, shippingZone *entity.ShippingZone) error
+}
+
+type ShippingZoneRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewShippingZoneRepository(
+	db *config.Database,
+) ShippingZoneRepositoryInterface {
+	return &ShippingZoneRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *ShippingZoneRepositoryImplementation) FindShippingZoneByIdDesa(db *gorm.DB, idDesa string) (*entity.ShippingZone, error) {
+	shippingZone := &entity.ShippingZone{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Find(shippingZone)
+	return shippingZone, result.Error
+}
+
+// SaveShippingZone insert atau update berdasarkan id desa
+func (repository *ShippingZoneRepositoryImplementation) SaveShippingZone(db *gorm.DB, shippingZone *entity.ShippingZone) error {
+	result := db.Save(shippingZone)
+	return result.Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type TransferUniqueAmountRepositoryInterface interface {
+	CreateTransferUniqueAmount(db *gorm.DB, transferUniqueAmount *entity.TransferUniqueAmount) error
+	FindTransferUniqueAmount(db *gorm.DB, idDesa string, amount float64) (*entity.TransferUniqueAmount, error)
+	FindTransferUniqueAmountsByRange(db *gorm.DB, idDesa string, minAmount float64, maxAmount float64) ([]entity.TransferUniqueAmount, error)
+	DeleteTransferUniqueAmountByIdOrder(db *gorm.DB, idOrder string) error
+}
+
+type TransferUniqueAmountRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewTransferUniqueAmountRepository(
+	db *config.Database,
+) TransferUniqueAmountRepositoryInterface {
+	return &TransferUniqueAmountRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *TransferUniqueAmountRepositoryImplementation) CreateTransferUniqueAmount(db *gorm.DB, transferUniqueAmount *entity.TransferUniqueAmount) error {
+	result := db.Create(transferUniqueAmount)
+	return result.Error
+}
+
+func (repository *TransferUniqueAmountRepositoryImplementation) FindTransferUniqueAmount(db *gorm.DB, idDesa string, amount float64) (*entity.TransferUniqueAmount, error) {
+	transferUniqueAmount := &entity.TransferUniqueAmount{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Where("amount = ?", amount).
+		Find(transferUniqueAmount)
+	return transferUniqueAmount, result.Error
+}
+
+func (repository *TransferUniqueAmountRepositoryImplementation) FindTransferUniqueAmountsByRange(db *gorm.DB, idDesa string, minAmount float64, maxAmount float64) ([]entity.TransferUniqueAmount, error) {
+	transferUniqueAmounts := []entity.TransferUniqueAmount{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Where("amount BETWEEN ? AND ?", minAmount, maxAmount).
+		Find(&transferUniqueAmounts)
+	return transferUniqueAmounts, result.Error
+}
+
+func (repository *TransferUniqueAmountRepositoryImplementation) DeleteTransferUniqueAmountByIdOrder(db *gorm.DB, idOrder string) error {
+	result := db.
+		Where("id_order = ?", idOrder).
+		Delete(&entity.TransferUniqueAmount{})
+	return result.Error
+}
@@ -27,6 +27,15 @@ type UserRepositoryInterface interface {
 	UpdateUserPayLaterFlag(db *gorm.DB, idUser string, userPayLaterFlag *entity.UsersPaylaterFlag) error
 	GetUserPaylaterList(db *gorm.DB, nik string) (*entity.UserGetPaylater, error)
 	UpdateUserForIsPaylater(db *gorm.DB, idUser string, userUpdate *entity.User) error
+	RevokeUserSession(db *gorm.DB, idUser string) error
+	FindUserByMerchantCode(db *gorm.DB, merchantCode string) (*entity.User, error)
+	FindUserByReferralCode(db *gorm.DB, referralCode string) (*entity.User, error)
+	UpdateUserReferralCode(db *gorm.DB, idUser string, referralCode string) error
+	FindUserDeletedBefore(db *gorm.DB, deleteDate time.Time) ([]entity.User, error)
+	FindUsersByRoleAndDesa(db *gorm.DB, idRole, idDesa string) ([]entity.UserProfile, error)
+	AnonymizeUser(db *gorm.DB, idUser string) error
+	FindUserPendingDeleteByPhone(db *gorm.DB, phone string) (*entity.User, error)
+	RestoreUserPendingDelete(db *gorm.DB, idUser string) (int64, error)
 }
 
 type UserRepositoryImplementation struct {
@@ -188,3 +197,104 @@ func (repository *UserRepositoryImplementation) SaveUserRefreshToken(db *gorm.DB
 		})
 	return result.RowsAffected, result.Error
 }
+
+func (repository *UserRepositoryImplementation) RevokeUserSession(db *gorm.DB, idUser string) error {
+	user := make(map[string]interface{})
+	user["refresh_token"] = ""
+	user["token_device"] = ""
+	result := db.
+		Model(entity.User{}).
+		Where("id = ?", idUser).
+		Updates(&user)
+	return result.Error
+}
+
+func (repository *UserRepositoryImplementation) FindUserDeletedBefore(db *gorm.DB, deleteDate time.Time) ([]entity.User, error) {
+	users := []entity.User{}
+	result := db.
+		Where("is_delete = ?", 1).
+		Where("is_delete_date <= ?", deleteDate).
+		Where("anonymized_at IS NULL").
+		Find(&users)
+	return users, result.Error
+}
+
+func (repository *UserRepositoryImplementation) FindUsersByRoleAndDesa(db *gorm.DB, idRole, idDesa string) ([]entity.UserProfile, error) {
+	userProfiles := []entity.UserProfile{}
+	result := db.
+		Joins("User").
+		Where("User.id_role = ?", idRole).
+		Where("User.id_desa = ?", idDesa).
+		Where("User.is_active = ?", 1).
+		Where("User.is_delete = ?", 0).
+		Find(&userProfiles)
+	return userProfiles, result.Error
+}
+
+// FindUserPendingDeleteByPhone user yang sudah minta dihapus tapi masih dalam masa tenggang
+func (repository *UserRepositoryImplementation) FindUserPendingDeleteByPhone(db *gorm.DB, phone string) (*entity.User, error) {
+	user := &entity.User{}
+	result := db.
+		Where("phone = ?", phone).
+		Where("is_delete = ?", 1).
+		Where("anonymized_at IS NULL").
+		Find(user)
+	return user, result.Error
+}
+
+// RestoreUserPendingDelete membatalkan penghapusan akun, rows affected 0 berarti akun sudah dianonimkan
+func (repository *UserRepositoryImplementation) RestoreUserPendingDelete(db *gorm.DB, idUser string) (int64, error) {
+	user := make(map[string]interface{})
+	user["is_delete"] = 0
+	user["is_delete_date"] = nil
+	result := db.
+		Model(entity.User{}).
+		Where("id = ?", idUser).
+		Where("is_delete = ?", 1).
+		Where("anonymized_at IS NULL").
+		Updates(&user)
+	return result.RowsAffected, result.Error
+}
+
+func (repository *UserRepositoryImplementation) AnonymizeUser(db *gorm.DB, idUser string) error {
+	user := make(map[string]interface{})
+	// phone tetap unik per user supaya tidak bentrok dengan user lain yang sudah dianonimkan
+	user["phone"] = "deleted:" + idUser
+	user["password"] = ""
+	user["refresh_token"] = ""
+	user["token_device"] = ""
+	user["inveli_access_token"] = ""
+	user["inveli_password"] = ""
+	user["is_active"] = 0
+	user["anonymized_at"] = time.Now()
+	result := db.
+		Model(entity.User{}).
+		Where("id = ?", idUser).
+		Updates(&user)
+	return result.Error
+}
+
+func (repository *UserRepositoryImplementation) FindUserByReferralCode(db *gorm.DB, referralCode string) (*entity.User, error) {
+	user := &entity.User{}
+	result := db.
+		Where("referral_code = ?", referralCode).
+		Where("is_delete = ?", 0).
+		Find(user)
+	return user, result.Error
+}
+
+func (repository *UserRepositoryImplementation) UpdateUserReferralCode(db *gorm.DB, idUser string, referralCode string) error {
+	result := db.
+		Model(entity.User{}).
+		Where("id = ?", idUser).
+		Update("referral_code", referralCode)
+	return result.Error
+}
+
+func (repository *UserRepositoryImplementation) FindUserByMerchantCode(db *gorm.DB, merchantCode string) (*entity.User, error) {
+	user := &entity.User{}
+	result := db.
+		Where("merchant_code = ?", merchantCode).
+		Find(user)
+	return user, result.Error
+}
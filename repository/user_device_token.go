@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+)
+
+type UserDeviceTokenRepositoryInterface interface {
+	FindUserDeviceTokenByToken(db *gorm.DB, token string) (*entity.UserDeviceToken, error)
+	FindUserDeviceTokenByIdUser(db *gorm.DB, idUser string) ([]entity.UserDeviceToken, error)
+	CreateUserDeviceToken(db *gorm.DB, userDeviceToken *entity.UserDeviceToken) error
+	UpdateUserDeviceToken(db *gorm.DB, idUserDeviceToken string, userDeviceToken *entity.UserDeviceToken) error
+	DeleteUserDeviceToken(db *gorm.DB, idUser string, token string) error
+	DeleteUserDeviceTokenByTokens(db *gorm.DB, tokens []string) error
+	DeleteUserDeviceTokenByIdUser(db *gorm.DB, idUser string) error
+}
+
+type UserDeviceTokenRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewUserDeviceTokenRepository(
+	db *config.Database,
+) UserDeviceTokenRepositoryInterface {
+	return &UserDeviceTokenRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) FindUserDeviceTokenByToken(db *gorm.DB, token string) (*entity.UserDeviceToken, error) {
+	userDeviceToken := &entity.UserDeviceToken{}
+	result := db.Where("token = ?", token).Find(userDeviceToken)
+	return userDeviceToken, result.Error
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) FindUserDeviceTokenByIdUser(db *gorm.DB, idUser string) ([]entity.UserDeviceToken, error) {
+	userDeviceTokens := []entity.UserDeviceToken{}
+	result := db.Where("id_user = ?", idUser).Find(&userDeviceTokens)
+	return userDeviceTokens, result.Error
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) CreateUserDeviceToken(db *gorm.DB, userDeviceToken *entity.UserDeviceToken) error {
+	result := db.Create(userDeviceToken)
+	return result.Error
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) UpdateUserDeviceToken(db *gorm.DB, idUserDeviceToken string, userDeviceToken *entity.UserDeviceToken) error {
+	userDeviceTokenUpdate := make(map[string]interface{})
+	userDeviceTokenUpdate["id_user"] = userDeviceToken.IdUser
+	userDeviceTokenUpdate["platform"] = userDeviceToken.Platform
+	userDeviceTokenUpdate["device_id"] = userDeviceToken.DeviceId
+	userDeviceTokenUpdate["updated_at"] = time.Now()
+	result := db.
+		Model(entity.UserDeviceToken{}).
+		Where("id = ?", idUserDeviceToken).
+		Updates(&userDeviceTokenUpdate)
+	return result.Error
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) DeleteUserDeviceToken(db *gorm.DB, idUser string, token string) error {
+	result := db.Where("id_user = ?", idUser).Where("token = ?", token).Delete(&entity.UserDeviceToken{})
+	return result.Error
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) DeleteUserDeviceTokenByTokens(db *gorm.DB, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	result := db.Where("token IN ?", tokens).Delete(&entity.UserDeviceToken{})
+	return result.Error
+}
+
+func (repository *UserDeviceTokenRepositoryImplementation) DeleteUserDeviceTokenByIdUser(db *gorm.DB, idUser string) error {
+	result := db.Where("id_user = ?", idUser).Delete(&entity.UserDeviceToken{})
+	return result.Error
+}
@@ -12,6 +12,7 @@ type UserProfileRepositoryInterface interface {
 	FindUserByNoIdentitas(db *gorm.DB, NoIdentitas string) (*entity.UserProfile, error)
 	UpdateUserProfile(db *gorm.DB, idUser string, userProfileUpdate *entity.UserProfile) error
 	FindUserProfileByIdUser(db *gorm.DB, idUser string) (*entity.UserProfile, error)
+	AnonymizeUserProfile(db *gorm.DB, idUser string, pseudonym string) error
 }
 
 type UserProfileRepositoryImplementation struct {
@@ -63,3 +64,16 @@ func (repository *UserProfileRepositoryImplementation) FindUserByNoIdentitas(db
 		Find(userProfile, "users_profile.no_identitas = ?", NoIdentitas)
 	return userProfile, result.Error
 }
+
+func (repository *UserProfileRepositoryImplementation) AnonymizeUserProfile(db *gorm.DB, idUser string, pseudonym string) error {
+	userProfile := make(map[string]interface{})
+	userProfile["no_identitas"] = pseudonym
+	userProfile["nama_lengkap"] = "Pengguna Terhapus"
+	userProfile["email"] = ""
+	userProfile["alamat_sesuai_identitas"] = ""
+	result := db.
+		Model(entity.UserProfile{}).
+		Where("id_user = ?", idUser).
+		Updates(&userProfile)
+	return result.Error
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"gorm.io/gorm"
@@ -10,8 +12,12 @@ type UserShippingAddressRepositoryInterface interface {
 	CreateUserShippingAddress(DB *gorm.DB, userShippingAddress *entity.UserShippingAddress) (*entity.UserShippingAddress, error)
 	FindUserShippingAddressByIdUser(DB *gorm.DB, idUser string) ([]entity.UserShippingAddress, error)
 	FindUserShippingAddressById(DB *gorm.DB, idUserShippingAddress string) (*entity.UserShippingAddress, error)
+	FindUserShippingAddressByIdAndIdUser(DB *gorm.DB, idUserShippingAddress, idUser string) (*entity.UserShippingAddress, error)
+	FindPrimaryUserShippingAddressByIdUser(DB *gorm.DB, idUser string) (*entity.UserShippingAddress, error)
+	UpdateUserShippingAddress(DB *gorm.DB, idUserShippingAddress string, userShippingAddress *entity.UserShippingAddress) error
+	UpdateUserShippingAddressPrimary(DB *gorm.DB, idUser, idUserShippingAddress string) error
 	DeleteUserShippingAddress(DB *gorm.DB, idUserShippingAddress string) error
-	FindUserShippingAddressByAddress(DB *gorm.DB, address string) (*entity.UserShippingAddress, error)
+	AnonymizeUserShippingAddressByIdUser(DB *gorm.DB, idUser string) error
 }
 
 type UserShippingAddressRepositoryImplementation struct {
@@ -24,30 +30,86 @@ func NewUserShippingAddressRepository(db *config.Database) UserShippingAddressRe
 	}
 }
 
-func (repository *UserShippingAddressRepositoryImplementation) FindUserShippingAddressByAddress(DB *gorm.DB, address string) (*entity.UserShippingAddress, error) {
-	userShippingAddresss := &entity.UserShippingAddress{}
-	results := DB.Where("alamat_pengiriman = ?", address).Find(userShippingAddresss)
-	return userShippingAddresss, results.Error
-}
-
 func (repository *UserShippingAddressRepositoryImplementation) CreateUserShippingAddress(DB *gorm.DB, userAddress *entity.UserShippingAddress) (*entity.UserShippingAddress, error) {
 	results := DB.Create(userAddress)
 	return userAddress, results.Error
 }
 
+func (repository *UserShippingAddressRepositoryImplementation) UpdateUserShippingAddress(DB *gorm.DB, idUserShippingAddress string, userShippingAddress *entity.UserShippingAddress) error {
+	userShippingAddressUpdate := make(map[string]interface{})
+	userShippingAddressUpdate["alamat_pengiriman"] = userShippingAddress.AlamatPengiriman
+	userShippingAddressUpdate["latitude"] = userShippingAddress.Latitude
+	userShippingAddressUpdate["longitude"] = userShippingAddress.Longitude
+	userShippingAddressUpdate["radius"] = userShippingAddress.Radius
+	userShippingAddressUpdate["catatan"] = userShippingAddress.Catatan
+	result := DB.
+		Model(entity.UserShippingAddress{}).
+		Where("id = ?", idUserShippingAddress).
+		Updates(&userShippingAddressUpdate)
+	return result.Error
+}
+
+// UpdateUserShippingAddressPrimary menjadikan satu alamat sebagai utama dan menurunkan alamat lain milik user
+func (repository *UserShippingAddressRepositoryImplementation) UpdateUserShippingAddressPrimary(DB *gorm.DB, idUser, idUserShippingAddress string) error {
+	result := DB.
+		Model(entity.UserShippingAddress{}).
+		Where("id_user = ?", idUser).
+		Where("is_delete = ?", 0).
+		Update("is_primary", gorm.Expr("CASE WHEN id = ? THEN 1 ELSE 0 END", idUserShippingAddress))
+	return result.Error
+}
+
+// DeleteUserShippingAddress soft delete, alamat tetap tersimpan untuk riwayat order
 func (repository *UserShippingAddressRepositoryImplementation) DeleteUserShippingAddress(DB *gorm.DB, idUserShippingAddress string) error {
-	result := DB.Where("id = ?", idUserShippingAddress).Delete(&entity.UserShippingAddress{})
+	userShippingAddress := make(map[string]interface{})
+	userShippingAddress["is_delete"] = 1
+	userShippingAddress["is_delete_date"] = time.Now()
+	userShippingAddress["is_primary"] = 0
+	result := DB.
+		Model(entity.UserShippingAddress{}).
+		Where("id = ?", idUserShippingAddress).
+		Updates(&userShippingAddress)
 	return result.Error
 }
 
 func (repository *UserShippingAddressRepositoryImplementation) FindUserShippingAddressByIdUser(DB *gorm.DB, idUser string) ([]entity.UserShippingAddress, error) {
 	userShippingAddresss := []entity.UserShippingAddress{}
-	results := DB.Where("id_user = ?", idUser).Find(&userShippingAddresss)
+	results := DB.
+		Where("id_user = ?", idUser).
+		Where("is_delete = ?", 0).
+		Order("is_primary desc").
+		Find(&userShippingAddresss)
 	return userShippingAddresss, results.Error
 }
 
 func (repository *UserShippingAddressRepositoryImplementation) FindUserShippingAddressById(DB *gorm.DB, idUserShippingAddress string) (*entity.UserShippingAddress, error) {
 	userShippingAddresss := &entity.UserShippingAddress{}
-	results := DB.Where("id = ?", idUserShippingAddress).Find(userShippingAddresss)
+	results := DB.Where("id = ? AND is_delete = ?", idUserShippingAddress, 0).Find(userShippingAddresss)
 	return userShippingAddresss, results.Error
 }
+
+func (repository *UserShippingAddressRepositoryImplementation) FindUserShippingAddressByIdAndIdUser(DB *gorm.DB, idUserShippingAddress, idUser string) (*entity.UserShippingAddress, error) {
+	userShippingAddresss := &entity.UserShippingAddress{}
+	results := DB.Where("id = ? AND id_user = ? AND is_delete = ?", idUserShippingAddress, idUser, 0).Find(userShippingAddresss)
+	return userShippingAddresss, results.Error
+}
+
+func (repository *UserShippingAddressRepositoryImplementation) FindPrimaryUserShippingAddressByIdUser(DB *gorm.DB, idUser string) (*entity.UserShippingAddress, error) {
+	userShippingAddresss := &entity.UserShippingAddress{}
+	results := DB.Where("id_user = ? AND is_primary = ? AND is_delete = ?", idUser, 1, 0).Find(userShippingAddresss)
+	return userShippingAddresss, results.Error
+}
+
+func (repository *UserShippingAddressRepositoryImplementation) AnonymizeUserShippingAddressByIdUser(DB *gorm.DB, idUser string) error {
+	userShippingAddress := make(map[string]interface{})
+	userShippingAddress["alamat_pengiriman"] = ""
+	userShippingAddress["catatan"] = ""
+	userShippingAddress["latitude"] = 0
+	userShippingAddress["longitude"] = 0
+	userShippingAddress["radius"] = 0
+	result := DB.
+		Model(entity.UserShippingAddress{}).
+		Where("id_user = ?", idUser).
+		Updates(&userShippingAddress)
+	return result.Error
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoucherRepositoryInterface interface {
+	FindVoucherByCode(db *gorm.DB, code string) (*entity.Voucher, error)
+	FindVoucherById(db *gorm.DB, idVoucher string) (*entity.Voucher, error)
+	FindVoucherByIdForUpdate(db *gorm.DB, idVoucher string) (*entity.Voucher, error)
+	FindVouchersByDesa(db *gorm.DB, idDesa string) ([]entity.Voucher, error)
+	CreateVoucher(db *gorm.DB, voucher *entity.Voucher) error
+	UpdateVoucher(db *gorm.DB, idVoucher string, voucher *entity.Voucher) error
+	IncreaseVoucherUsedCount(db *gorm.DB, idVoucher string) (int64, error)
+	DecreaseVoucherUsedCount(db *gorm.DB, idVoucher string) error
+	CountVoucherUsageByUser(db *gorm.DB, idVoucher string, idUser string) (int64, error)
+	FindVoucherUsageByIdOrder(db *gorm.DB, idOrder string) (*entity.VoucherUsage, error)
+	CreateVoucherUsage(db *gorm.DB, voucherUsage *entity.VoucherUsage) error
+	DeleteVoucherUsage(db *gorm.DB, idVoucherUsage string) error
+}
+
+type VoucherRepositoryImplementation struct {
+	DB *config.Database
+}
+
+func NewVoucherRepository(
+	db *config.Database,
+) VoucherRepositoryInterface {
+	return &VoucherRepositoryImplementation{
+		DB: db,
+	}
+}
+
+func (repository *VoucherRepositoryImplementation) FindVoucherByCode(db *gorm.DB, code string) (*entity.Voucher, error) {
+	voucher := &entity.Voucher{}
+	result := db.Where("code = ?", code).Find(voucher)
+	return voucher, result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) FindVoucherById(db *gorm.DB, idVoucher string) (*entity.Voucher, error) {
+	voucher := &entity.Voucher{}
+	result := db.Where("id = ?", idVoucher).Find(voucher)
+	return voucher, result.Error
+}
+
+// FindVoucherByIdForUpdate mengunci baris voucher sampai transaksi selesai, pemakaian voucher yang sama antri
+func (repository *VoucherRepositoryImplementation) FindVoucherByIdForUpdate(db *gorm.DB, idVoucher string) (*entity.Voucher, error) {
+	voucher := &entity.Voucher{}
+	result := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", idVoucher).Find(voucher)
+	return voucher, result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) FindVouchersByDesa(db *gorm.DB, idDesa string) ([]entity.Voucher, error) {
+	vouchers := []entity.Voucher{}
+	result := db.
+		Where("id_desa = ?", idDesa).
+		Order("created_at desc").
+		Find(&vouchers)
+	return vouchers, result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) CreateVoucher(db *gorm.DB, voucher *entity.Voucher) error {
+	result := db.Create(voucher)
+	return result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) UpdateVoucher(db *gorm.DB, idVoucher string, voucher *entity.Voucher) error {
+	updateVoucher := make(map[string]interface{})
+	updateVoucher["title"] = voucher.Title
+	updateVoucher["description"] = voucher.Description
+	updateVoucher["discount_type"] = voucher.DiscountType
+	updateVoucher["discount_value"] = voucher.DiscountValue
+	updateVoucher["max_discount"] = voucher.MaxDiscount
+	updateVoucher["min_spend"] = voucher.MinSpend
+	updateVoucher["quota_total"] = voucher.QuotaTotal
+	updateVoucher["quota_per_user"] = voucher.QuotaPerUser
+	updateVoucher["start_date"] = voucher.StartDate
+	updateVoucher["end_date"] = voucher.EndDate
+	updateVoucher["is_active"] = voucher.IsActive
+	updateVoucher["updated_at"] = voucher.UpdatedAt
+	result := db.
+		Model(entity.Voucher{}).
+		Where("id = ?", idVoucher).
+		Updates(updateVoucher)
+	return result.Error
+}
+
+// IncreaseVoucherUsedCount rows affected 0 berarti quota voucher sudah habis
+func (repository *VoucherRepositoryImplementation) IncreaseVoucherUsedCount(db *gorm.DB, idVoucher string) (int64, error) {
+	result := db.
+		Model(entity.Voucher{}).
+		Where("id = ? AND (quota_total = 0 OR used_count < quota_total)", idVoucher).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	return result.RowsAffected, result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) DecreaseVoucherUsedCount(db *gorm.DB, idVoucher string) error {
+	result := db.
+		Model(entity.Voucher{}).
+		Where("id = ? AND used_count > 0", idVoucher).
+		Update("used_count", gorm.Expr("used_count - 1"))
+	return result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) CountVoucherUsageByUser(db *gorm.DB, idVoucher string, idUser string) (int64, error) {
+	var count int64
+	result := db.
+		Model(entity.VoucherUsage{}).
+		Where("id_voucher = ? AND id_user = ?", idVoucher, idUser).
+		Count(&count)
+	return count, result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) FindVoucherUsageByIdOrder(db *gorm.DB, idOrder string) (*entity.VoucherUsage, error) {
+	voucherUsage := &entity.VoucherUsage{}
+	result := db.Where("id_order = ?", idOrder).Find(voucherUsage)
+	return voucherUsage, result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) CreateVoucherUsage(db *gorm.DB, voucherUsage *entity.VoucherUsage) error {
+	result := db.Create(voucherUsage)
+	return result.Error
+}
+
+func (repository *VoucherRepositoryImplementation) DeleteVoucherUsage(db *gorm.DB, idVoucherUsage string) error {
+	result := db.Where("id = ?", idVoucherUsage).Delete(&entity.VoucherUsage{})
+	return result.Error
+}
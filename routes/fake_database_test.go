@@ -0,0 +1,201 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDatabase database/sql driver in-memory untuk route test. Query select difilter dari kondisi
+// `kolom = ?` dan `kolom = angka` di query, sehingga filter id_user dari repository ikut diuji.
+// Statement selain select hanya dicatat.
+type fakeDatabase struct {
+	mutex  sync.Mutex
+	tables map[string][]map[string]driver.Value
+	execs  []string
+}
+
+var (
+	fakeDatabaseTable     = regexp.MustCompile("(?i)(?:FROM|UPDATE|INTO)\\s+`?(\\w+)`?")
+	fakeDatabaseCondition = regexp.MustCompile("`?(\\w+)`?\\s*=\\s*(\\?|-?\\d+\\b)|\\?")
+)
+
+func newFakeDatabase() *fakeDatabase {
+	return &fakeDatabase{tables: map[string][]map[string]driver.Value{}}
+}
+
+func (database *fakeDatabase) Insert(table string, row map[string]driver.Value) {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	database.tables[table] = append(database.tables[table], row)
+}
+
+func (database *fakeDatabase) Execs() []string {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	return append([]string{}, database.execs...)
+}
+
+func (database *fakeDatabase) Gorm(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(database),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func (database *fakeDatabase) query(query string, args []driver.Value) *fakeRows {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	table := ""
+	if match := fakeDatabaseTable.FindStringSubmatch(query); match != nil {
+		table = match[1]
+	}
+	where := query
+	if i := strings.Index(strings.ToUpper(query), " WHERE "); i >= 0 {
+		where = query[i:]
+	} else {
+		where = ""
+	}
+
+	// Argumen sebelum WHERE (mis. kolom select) tidak dipakai sebagai kondisi
+	skip := strings.Count(query[:len(query)-len(where)], "?")
+	conditions := map[string]string{}
+	for _, match := range fakeDatabaseCondition.FindAllStringSubmatch(where, -1) {
+		if match[2] == "?" || len(match[1]) == 0 {
+			if skip < len(args) && len(match[1]) != 0 {
+				conditions[match[1]] = fmt.Sprint(args[skip])
+			}
+			skip++
+			continue
+		}
+		conditions[match[1]] = match[2]
+	}
+
+	rows := &fakeRows{}
+	for _, row := range database.tables[table] {
+		if fakeDatabaseMatch(row, conditions) {
+			rows.values = append(rows.values, row)
+		}
+	}
+	if len(database.tables[table]) != 0 {
+		for column := range database.tables[table][0] {
+			rows.columns = append(rows.columns, column)
+		}
+	} else {
+		rows.columns = []string{"id"}
+	}
+	sort.Strings(rows.columns)
+	return rows
+}
+
+func fakeDatabaseMatch(row map[string]driver.Value, conditions map[string]string) bool {
+	for column, value := range conditions {
+		rowValue, ok := row[column]
+		if ok && fmt.Sprint(rowValue) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (database *fakeDatabase) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{database: database}, nil
+}
+
+func (database *fakeDatabase) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, fmt.Errorf("fake database hanya lewat connector")
+}
+
+type fakeConn struct {
+	database *fakeDatabase
+}
+
+func (conn *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: conn, query: query}, nil
+}
+
+func (conn *fakeConn) Close() error {
+	return nil
+}
+
+func (conn *fakeConn) Begin() (driver.Tx, error) {
+	return conn, nil
+}
+
+func (conn *fakeConn) Commit() error {
+	return nil
+}
+
+func (conn *fakeConn) Rollback() error {
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (stmt *fakeStmt) Close() error {
+	return nil
+}
+
+func (stmt *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	stmt.conn.database.mutex.Lock()
+	defer stmt.conn.database.mutex.Unlock()
+	stmt.conn.database.execs = append(stmt.conn.database.execs, stmt.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (stmt *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.conn.database.query(stmt.query, args), nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  []map[string]driver.Value
+}
+
+func (rows *fakeRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *fakeRows) Close() error {
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+	for i, column := range rows.columns {
+		dest[i] = rows.values[0][column]
+	}
+	rows.values = rows.values[1:]
+	return nil
+}
@@ -25,17 +25,17 @@ func OtpManagerRoute(e *echo.Echo, otpManagerControllerInterface controller.OtpM
 
 func KecamatanRoute(e *echo.Echo, kecamatanControllerInterface controller.KecamatanControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/kecamatan", kecamatanControllerInterface.FindKecamatan, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/kecamatan", kecamatanControllerInterface.FindKecamatan, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func KelurahanRoute(e *echo.Echo, kelurahanControllerInterface controller.KelurahanControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/kelurahan", kelurahanControllerInterface.FindKelurahanByIdKeca, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/kelurahan", kelurahanControllerInterface.FindKelurahanByIdKeca, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func DesaRoute(e *echo.Echo, desaControllerInterface controller.DesaControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/desa", desaControllerInterface.FindDesaByIdKelu, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/desa", desaControllerInterface.FindDesaByIdKelu, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func ListPinjamanRoute(e *echo.Echo, jwt config.Jwt, listPinjamanControllerInterface controller.ListPinjamanControllerInterface) {
@@ -56,6 +56,7 @@ func UserRoute(e *echo.Echo, jwt config.Jwt, userControllerInterface controller.
 	group.POST("/user/bigis", userControllerInterface.FindUserFromBigis, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.GET("/user", userControllerInterface.FindUserById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.DELETE("/user/delete", userControllerInterface.DeleteUserById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/user/delete/cancel", userControllerInterface.CancelDeleteUser, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.PUT("/user/update/password", userControllerInterface.UpdateUserPassword, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.PUT("/user/update/forgotpassword", userControllerInterface.UpdateUserForgotPassword, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.PUT("/user/update/profile", userControllerInterface.UpdateUserProfile, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
@@ -69,6 +70,11 @@ func UserRoute(e *echo.Echo, jwt config.Jwt, userControllerInterface controller.
 	group.GET("/user/no-rekening", userControllerInterface.GetNoRekening, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
+func PrivacyRoute(e *echo.Echo, jwt config.Jwt, privacyControllerInterface controller.PrivacyControllerInterface) {
+	group := e.Group("api/v1")
+	group.GET("/user/export", privacyControllerInterface.ExportUserData, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
 func InveliTestRoutes(e *echo.Echo, inveliTestControllerInterface controller.InveliTestingController) {
 	group := e.Group("api/v1")
 	group.POST("/inveli/test", inveliTestControllerInterface.GetAccountInfo, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
@@ -80,19 +86,22 @@ func InveliTestRoutes(e *echo.Echo, inveliTestControllerInterface controller.Inv
 
 func ProductDesaRoute(e *echo.Echo, jwt config.Jwt, productDesaControllerInterface controller.ProductDesaControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/products", productDesaControllerInterface.FindProductsDesa, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/product", productDesaControllerInterface.FindProductDesaById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/products/category", productDesaControllerInterface.FindProductsDesaByCategory, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/products/sub_category", productDesaControllerInterface.FindProductsDesaBySubCategory, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/products/promo", productDesaControllerInterface.FindProductsDesaByPromo, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/products/notoken", productDesaControllerInterface.FindProductsDesaNotoken, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/products/category/notoken", productDesaControllerInterface.FindProductsDesaByCategoryNotoken, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/products/sub_category/notoken", productDesaControllerInterface.FindProductsDesaBySubCategoryNotoken, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/products", productDesaControllerInterface.FindProductsDesa, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/product", productDesaControllerInterface.FindProductDesaById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/product/quote", productDesaControllerInterface.QuoteProductPrice, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/products/category", productDesaControllerInterface.FindProductsDesaByCategory, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/sub_category", productDesaControllerInterface.FindProductsDesaBySubCategory, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/search", productDesaControllerInterface.SearchProductsDesa, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/search/notoken", productDesaControllerInterface.SearchProductsDesaNotoken, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/promo", productDesaControllerInterface.FindProductsDesaByPromo, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/notoken", productDesaControllerInterface.FindProductsDesaNotoken, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/category/notoken", productDesaControllerInterface.FindProductsDesaByCategoryNotoken, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/products/sub_category/notoken", productDesaControllerInterface.FindProductsDesaBySubCategoryNotoken, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func PromoRoute(e *echo.Echo, jwt config.Jwt, promoDesaControllerInterface controller.PromoControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/promo", promoDesaControllerInterface.FindPromo, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/promo", promoDesaControllerInterface.FindPromo, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func CartRoute(e *echo.Echo, jwt config.Jwt, cartControllerInterface controller.CartControllerInterface) {
@@ -102,29 +111,129 @@ func CartRoute(e *echo.Echo, jwt config.Jwt, cartControllerInterface controller.
 	group.GET("/cart/user", cartControllerInterface.FindCartByUser, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
-func MerchantRoute(e *echo.Echo, jwt config.Jwt, merchantControllerInterface controller.MerchantControllerInterface) {
+func ProductAdminRoute(e *echo.Echo, jwt config.Jwt, role config.Role, productAdminControllerInterface controller.ProductAdminControllerInterface) {
+	group := e.Group("api/v1")
+	group.POST("/admin/product/master", productAdminControllerInterface.CreateProductMaster, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.SuperAdmin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/product/master", productAdminControllerInterface.UpdateProductMaster, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.SuperAdmin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/products", productAdminControllerInterface.FindProductsDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/product", productAdminControllerInterface.CreateProductDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/product", productAdminControllerInterface.UpdateProductDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/product/stock", productAdminControllerInterface.AdjustProductStock, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/product/stock_history", productAdminControllerInterface.FindProductDesaStockHistory, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/product/price_tier", productAdminControllerInterface.FindProductPriceTier, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/product/price_tier", productAdminControllerInterface.UpdateProductPriceTier, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/products/export", productAdminControllerInterface.ExportProductsDesaCsv, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/products/import", productAdminControllerInterface.ImportProductsDesaCsv, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func MerchantRoute(e *echo.Echo, jwt config.Jwt, role config.Role, merchantControllerInterface controller.MerchantControllerInterface) {
 	group := e.Group("api/v1")
 	group.POST("/merchant/request_approve", merchantControllerInterface.CreateMerchantApproveList, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.GET("/merchant/status_approve", merchantControllerInterface.FindMerchantStatusApproveByUserResponse, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/merchant/approve_list", merchantControllerInterface.FindMerchantApproveListByDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/merchant/approve", merchantControllerInterface.ApproveMerchant, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/merchant/reject", merchantControllerInterface.RejectMerchant, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func VoucherRoute(e *echo.Echo, jwt config.Jwt, role config.Role, voucherControllerInterface controller.VoucherControllerInterface) {
+	group := e.Group("api/v1")
+	group.POST("/voucher/check", voucherControllerInterface.CheckVoucher, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/vouchers", voucherControllerInterface.FindVouchersByDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/voucher", voucherControllerInterface.CreateVoucher, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/voucher", voucherControllerInterface.UpdateVoucher, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
-func PointRoute(e *echo.Echo, jwt config.Jwt, pointControllerInterface controller.PointControllerInterface) {
+func PointRoute(e *echo.Echo, jwt config.Jwt, role config.Role, pointControllerInterface controller.PointControllerInterface) {
 	group := e.Group("api/v1")
 	group.GET("/point", pointControllerInterface.FindPointByUser, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/point/history", pointControllerInterface.FindPointHistoryByUser, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/point/rules", pointControllerInterface.FindPointRulesByDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/point/rule", pointControllerInterface.CreatePointRule, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/point/rule", pointControllerInterface.UpdatePointRule, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
-func OrderRoute(e *echo.Echo, jwt config.Jwt, orderControllerInterface controller.OrderControllerInterface) {
+func PpobMarginRoute(e *echo.Echo, jwt config.Jwt, role config.Role, ppobMarginControllerInterface controller.PpobMarginControllerInterface) {
+	group := e.Group("api/v1")
+	group.GET("/admin/ppob/margins", ppobMarginControllerInterface.FindPpobMarginsByDesa, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/ppob/margin", ppobMarginControllerInterface.CreatePpobMargin, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/ppob/margin", ppobMarginControllerInterface.UpdatePpobMargin, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func PpobReconciliationRoute(e *echo.Echo, jwt config.Jwt, role config.Role, ppobReconciliationControllerInterface controller.PpobReconciliationControllerInterface) {
+	group := e.Group("api/v1")
+	group.GET("/admin/ppob/reconciliations", ppobReconciliationControllerInterface.FindPpobReconciliations, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func ReferralRoute(e *echo.Echo, jwt config.Jwt, referralControllerInterface controller.ReferralControllerInterface) {
+	group := e.Group("api/v1")
+	group.GET("/referral/stats", referralControllerInterface.FindReferralStats, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func OrderRoute(e *echo.Echo, jwt config.Jwt, ppob config.Ppob, orderControllerInterface controller.OrderControllerInterface) {
 	group := e.Group("api/v1")
 	group.POST("/order/create", orderControllerInterface.CreateOrder, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.GET("/orders/user", orderControllerInterface.FindOrderByUser, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.GET("/order", orderControllerInterface.FindOrderById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/order/receipt", orderControllerInterface.FindOrderReceipt, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.PUT("/order/cancel", orderControllerInterface.CancelOrderById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.PUT("/order/complete", orderControllerInterface.CompleteOrderById, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.POST("/order/update/payment", orderControllerInterface.UpdateOrderPaymentStatus, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.POST("/order/callback/ppob", orderControllerInterface.CallbackPpobTransaction, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/order/callback/ppob", orderControllerInterface.CallbackPpobTransaction, authMiddlerware.IpAllowlist(ppob.CallbackAllowedIps), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.GET("/order/paylater", orderControllerInterface.FindOrderPayLater, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
+func FulfilmentRoute(e *echo.Echo, jwt config.Jwt, role config.Role, fulfilmentControllerInterface controller.FulfilmentControllerInterface) {
+	group := e.Group("api/v1")
+	group.GET("/admin/fulfilment/orders", fulfilmentControllerInterface.FindFulfilmentOrders, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/fulfilment/pack", fulfilmentControllerInterface.PackOrder, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/couriers", fulfilmentControllerInterface.FindCouriers, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/fulfilment/assign", fulfilmentControllerInterface.AssignCourier, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/courier/orders", fulfilmentControllerInterface.FindCourierOrders, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Courier), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/courier/order/ship", fulfilmentControllerInterface.ShipOrder, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Courier), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/courier/order/deliver", fulfilmentControllerInterface.DeliverOrder, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Courier), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/order/tracking", fulfilmentControllerInterface.FindOrderTracking, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func UploadRoute(e *echo.Echo, jwt config.Jwt, role config.Role, configStorage config.Storage, uploadControllerInterface controller.UploadControllerInterface) {
+	// File publik driver local disajikan langsung dari disk
+	if configStorage.Driver != "s3" {
+		localDir := configStorage.LocalDir
+		if len(localDir) == 0 {
+			localDir = "./uploads"
+		}
+		e.Static("/files/public", localDir+"/public")
+	}
+	group := e.Group("api/v1")
+	group.POST("/admin/upload", uploadControllerInterface.UploadAdminFile, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/upload/bukti_bayar", uploadControllerInterface.UploadBuktiBayar, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/courier/upload/barang_sampai", uploadControllerInterface.UploadBarangSampai, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Courier), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/file/private", uploadControllerInterface.FindPrivateFile, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func TransferVerificationRoute(e *echo.Echo, jwt config.Jwt, role config.Role, transferVerificationControllerInterface controller.TransferVerificationControllerInterface) {
+	group := e.Group("api/v1")
+	group.PUT("/order/bukti_bayar", transferVerificationControllerInterface.SubmitBuktiBayar, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/transfer/verifications", transferVerificationControllerInterface.FindTransferVerifications, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/transfer/verify", transferVerificationControllerInterface.VerifyTransfer, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/transfer/mutations/csv", transferVerificationControllerInterface.ImportBankMutationCsv, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/admin/transfer/mutations/inveli", transferVerificationControllerInterface.ImportInveliMutation, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func ShippingRoute(e *echo.Echo, jwt config.Jwt, role config.Role, shippingControllerInterface controller.ShippingControllerInterface) {
+	group := e.Group("api/v1")
+	group.GET("/shipping/quote", shippingControllerInterface.FindShippingQuote, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/admin/shipping/zone", shippingControllerInterface.FindShippingZone, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/admin/shipping/zone", shippingControllerInterface.UpdateShippingZone, authMiddlerware.Authentication(jwt), authMiddlerware.Authorization(role.Admin), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
+func NotificationRoute(e *echo.Echo, jwt config.Jwt, notificationControllerInterface controller.NotificationControllerInterface) {
+	group := e.Group("api/v1")
+	group.POST("/notification/device_token", notificationControllerInterface.RegisterDeviceToken, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/notification/device_token/delete", notificationControllerInterface.UnregisterDeviceToken, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/notifications", notificationControllerInterface.FindNotifications, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/notifications/read", notificationControllerInterface.ReadNotifications, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+}
+
 func PaymentChannelRoute(e *echo.Echo, jwt config.Jwt, paymentChannelControllerInterface controller.PaymentChannelControllerInterface) {
 	group := e.Group("api/v1")
 	group.POST("/payment_channel", paymentChannelControllerInterface.FindPaymentChannel, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
@@ -132,20 +241,22 @@ func PaymentChannelRoute(e *echo.Echo, jwt config.Jwt, paymentChannelControllerI
 
 func SettingRoute(e *echo.Echo, jwt config.Jwt, settingControllerInterface controller.SettingControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/setting/shippingcost", settingControllerInterface.FindSettingShippingCost, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/version", settingControllerInterface.FindNewVersion, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/setting/shippingcost", settingControllerInterface.FindSettingShippingCost, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/version", settingControllerInterface.FindNewVersion, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func BannerRoute(e *echo.Echo, jwt config.Jwt, bannerControllerInterface controller.BannerControllerInterface) {
 	group := e.Group("api/v1")
-	group.GET("/banner", bannerControllerInterface.FindBannerByDesa, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
-	group.GET("/banner/no_token", bannerControllerInterface.FindBannerAll, authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/banner", bannerControllerInterface.FindBannerByDesa, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
+	group.GET("/banner/no_token", bannerControllerInterface.FindBannerAll, authMiddlerware.RateLimit(), authMiddlerware.ETag(), authMiddlerware.Timeout())
 }
 
 func UserShippingAddressRoute(e *echo.Echo, jwt config.Jwt, userShippingAddress controller.UserShippingAddressControllerInterface) {
 	group := e.Group("api/v1")
 	group.POST("/shipping_address/create", userShippingAddress.CreateUserShippingAddress, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.GET("/shipping_address", userShippingAddress.FindUserShippingAddress, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/shipping_address/update", userShippingAddress.UpdateUserShippingAddress, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.PUT("/shipping_address/primary", userShippingAddress.SetPrimaryUserShippingAddress, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.POST("/shipping_address/delete", userShippingAddress.DeleteUserShippingAddress, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
@@ -160,6 +271,9 @@ func PpobRoute(e *echo.Echo, jwt config.Jwt, ppob controller.PpobControllerInter
 	group.GET("/postpaid/list/telco", ppob.GetPostpaidTelcoProduct, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.POST("/postpaid/inquiry/pdam", ppob.InquiryPostpaidPdam, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 	group.POST("/postpaid/inquiry/telco", ppob.InquiryPostpaidTelco, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/ppob/product-types", ppob.FindPpobProductTypes, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.GET("/ppob/products", ppob.FindPpobProducts, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
+	group.POST("/ppob/inquiry", ppob.InquiryPpob, authMiddlerware.Authentication(jwt), authMiddlerware.RateLimit(), authMiddlerware.Timeout())
 }
 
 func PaylaterRoute(e *echo.Echo, jwt config.Jwt, paylaterControllerInterface controller.PaylaterControllerInterface) {
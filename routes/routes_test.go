@@ -0,0 +1,173 @@
+package routes
+
+import (
+	"database/sql/driver"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/controller"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	modelService "github.com/tensuqiuwulu/be-service-bupda-bali/model/service"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/service"
+)
+
+const (
+	testJwtKey = "route-test-key"
+	testUserA  = "user-a"
+	testUserB  = "user-b"
+	testIdDesa = "desa-1"
+)
+
+// newTestServer route order, cart dan alamat pengiriman dengan service dan repository asli di atas fake database
+func newTestServer(t *testing.T, database *fakeDatabase) *echo.Echo {
+	db := database.Gorm(t)
+	validate := validator.New()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	jwtConfig := config.Jwt{Key: testJwtKey}
+
+	e := echo.New()
+	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
+		DisableStackAll:   true,
+		DisablePrintStack: true,
+	}))
+	e.HTTPErrorHandler = exceptions.ErrorHandler
+	e.Use(middleware.RequestID())
+
+	orderService := &service.OrderServiceImplementation{
+		DB:                                    db,
+		Validate:                              validate,
+		Logger:                                logger,
+		OrderRepositoryInterface:              repository.NewOrderRepository(nil),
+		OrderStatusHistoryRepositoryInterface: repository.NewOrderStatusHistoryRepository(nil),
+	}
+	cartService := &service.CartServiceImplementation{
+		DB:                      db,
+		Validate:                validate,
+		Logger:                  logger,
+		CartRepositoryInterface: repository.NewCartRepository(nil),
+	}
+	userShippingAddressService := service.NewUserShippingAddressService(db, validate, logger, repository.NewUserShippingAddressRepository(nil))
+
+	OrderRoute(e, jwtConfig, config.Ppob{}, controller.NewOrderController(logger, orderService))
+	CartRoute(e, jwtConfig, controller.NewCartController(logger, cartService))
+	UserShippingAddressRoute(e, jwtConfig, controller.NewUserShippingAddressController(logger, userShippingAddressService))
+	return e
+}
+
+func newTestDatabase() *fakeDatabase {
+	database := newFakeDatabase()
+	database.Insert("orders_transaction", map[string]driver.Value{
+		"id":           "order-a",
+		"id_user":      testUserA,
+		"id_desa":      testIdDesa,
+		"number_order": "ORD-A",
+		"order_status": int64(4),
+	})
+	database.Insert("cart", map[string]driver.Value{
+		"id":              "cart-a",
+		"id_user":         testUserA,
+		"id_product_desa": "product-1",
+		"qty":             int64(1),
+	})
+	database.Insert("users_shipping_address", map[string]driver.Value{
+		"id":                "address-a",
+		"id_user":           testUserA,
+		"alamat_pengiriman": "Jl. Raya Ubud",
+	})
+	return database
+}
+
+func testToken(t *testing.T, idUser string) string {
+	claims := modelService.TokenClaims{
+		Id:     idUser,
+		IdDesa: testIdDesa,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJwtKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func doRequest(e *echo.Echo, token, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCrossUserAccessIsNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"find order", http.MethodGet, "/api/v1/order?id_order=order-a&product_type=sembako", ""},
+		{"cancel order", http.MethodPut, "/api/v1/order/cancel", `{"id_order":"order-a"}`},
+		{"complete order", http.MethodPut, "/api/v1/order/complete", `{"id_order":"order-a"}`},
+		{"update cart", http.MethodPut, "/api/v1/cart/update", `{"id_cart":"cart-a","qty":5}`},
+		{"delete shipping address", http.MethodPost, "/api/v1/shipping_address/delete", `{"id_user_shipping_address":"address-a"}`},
+		{"update shipping address", http.MethodPut, "/api/v1/shipping_address/update", `{"id_user_shipping_address":"address-a","alamat_pengiriman":"Jl. Lain","latitude":-8.5,"longitude":115.2,"catatan":"rumah"}`},
+		{"set primary shipping address", http.MethodPut, "/api/v1/shipping_address/primary", `{"id_user_shipping_address":"address-a"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			database := newTestDatabase()
+			e := newTestServer(t, database)
+
+			rec := doRequest(e, testToken(t, testUserB), test.method, test.target, test.body)
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d, body %s", rec.Code, http.StatusNotFound, rec.Body.String())
+			}
+			if execs := database.Execs(); len(execs) != 0 {
+				t.Fatalf("data user lain ikut diubah: %v", execs)
+			}
+		})
+	}
+}
+
+func TestOwnerAccessIsAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"update cart", http.MethodPut, "/api/v1/cart/update", `{"id_cart":"cart-a","qty":5}`},
+		{"set primary shipping address", http.MethodPut, "/api/v1/shipping_address/primary", `{"id_user_shipping_address":"address-a"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			database := newTestDatabase()
+			e := newTestServer(t, database)
+
+			rec := doRequest(e, testToken(t, testUserA), test.method, test.target, test.body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d, body %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if execs := database.Execs(); len(execs) == 0 {
+				t.Fatal("data pemilik tidak diubah")
+			}
+		})
+	}
+}
@@ -87,6 +87,7 @@ func (service *AuthServiceImplementation) Login(requestId string, loginRequest *
 
 		userModelService.Id = user.Id
 		userModelService.IdDesa = user.IdDesa
+		userModelService.IdRole = user.IdRole
 		userModelService.AccountType = user.AccountType
 
 		token, err := service.GenerateToken(userModelService)
@@ -308,6 +309,7 @@ func (service *AuthServiceImplementation) NewToken(requestId string, refreshToke
 		var userModelService modelService.User
 		userModelService.Id = user.Id
 		userModelService.IdDesa = user.IdDesa
+		userModelService.IdRole = user.IdRole
 		userModelService.AccountType = user.AccountType
 		token, err := service.GenerateRefreshToken(userModelService)
 		exceptions.PanicIfError(err, requestId, service.Logger)
@@ -324,6 +326,7 @@ func (service *AuthServiceImplementation) GenerateToken(user modelService.User)
 	claims := modelService.TokenClaims{
 		Id:          user.Id,
 		IdDesa:      user.IdDesa,
+		IdRole:      user.IdRole,
 		AccountType: user.AccountType,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(time.Minute * time.Duration(service.ConfigJwt.Tokenexpiredtime)).Unix(),
@@ -344,6 +347,7 @@ func (service *AuthServiceImplementation) GenerateRefreshToken(user modelService
 	claims := modelService.TokenClaims{
 		Id:          user.Id,
 		IdDesa:      user.IdDesa,
+		IdRole:      user.IdRole,
 		AccountType: user.AccountType,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().AddDate(0, 0, int(service.ConfigJwt.Refreshtokenexpiredtime)).Unix(),
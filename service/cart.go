@@ -18,18 +18,20 @@ import (
 
 type CartServiceInterface interface {
 	CreateCart(requestId string, idUser string, createCartRequest *request.CreateCartRequest) string
-	UpdateCart(requestId string, updateCartRequest *request.UpdateCartRequest) string
-	FindCartByUser(requestId string, idUser string, accountType int, idDesa string) (cartResponses response.FindCartByIdUserResponse)
+	UpdateCart(requestId string, idUser string, updateCartRequest *request.UpdateCartRequest) string
+	FindCartByUser(requestId string, idUser string, accountType int, idDesa string, idAddress string) (cartResponses response.FindCartByIdUserResponse)
 }
 
 type CartServiceImplementation struct {
-	DB                          *gorm.DB
-	Validate                    *validator.Validate
-	Logger                      *logrus.Logger
-	CartRepositoryInterface     repository.CartRepositoryInterface
-	ProductDesaServiceInterface repository.ProductDesaRepositoryInterface
-	SettingRepositoryInterface  repository.SettingRepositoryInterface
-	DesaRepositoryInterface     repository.DesaRepositoryInterface
+	DB                                  *gorm.DB
+	Validate                            *validator.Validate
+	Logger                              *logrus.Logger
+	CartRepositoryInterface             repository.CartRepositoryInterface
+	ProductDesaServiceInterface         repository.ProductDesaRepositoryInterface
+	SettingRepositoryInterface          repository.SettingRepositoryInterface
+	DesaRepositoryInterface             repository.DesaRepositoryInterface
+	ProductPriceTierRepositoryInterface repository.ProductPriceTierRepositoryInterface
+	ShippingServiceInterface            ShippingServiceInterface
 }
 
 func NewCartService(
@@ -40,15 +42,19 @@ func NewCartService(
 	productDesaRepositoryInterface repository.ProductDesaRepositoryInterface,
 	settingRepositoryInterface repository.SettingRepositoryInterface,
 	desaRepositoryInterface repository.DesaRepositoryInterface,
+	productPriceTierRepositoryInterface repository.ProductPriceTierRepositoryInterface,
+	shippingServiceInterface ShippingServiceInterface,
 ) CartServiceInterface {
 	return &CartServiceImplementation{
-		DB:                          db,
-		Validate:                    validate,
-		Logger:                      logger,
-		CartRepositoryInterface:     cartRepositoryInterface,
-		ProductDesaServiceInterface: productDesaRepositoryInterface,
-		SettingRepositoryInterface:  settingRepositoryInterface,
-		DesaRepositoryInterface:     desaRepositoryInterface,
+		DB:                                  db,
+		Validate:                            validate,
+		Logger:                              logger,
+		CartRepositoryInterface:             cartRepositoryInterface,
+		ProductDesaServiceInterface:         productDesaRepositoryInterface,
+		SettingRepositoryInterface:          settingRepositoryInterface,
+		DesaRepositoryInterface:             desaRepositoryInterface,
+		ProductPriceTierRepositoryInterface: productPriceTierRepositoryInterface,
+		ShippingServiceInterface:            shippingServiceInterface,
 	}
 }
 
@@ -98,13 +104,13 @@ func (service *CartServiceImplementation) CreateCart(requestId string, idUser st
 	}
 }
 
-func (service *CartServiceImplementation) UpdateCart(requestId string, updateCartRequest *request.UpdateCartRequest) string {
+func (service *CartServiceImplementation) UpdateCart(requestId string, idUser string, updateCartRequest *request.UpdateCartRequest) string {
 	var err error
 
 	request.ValidateRequest(service.Validate, updateCartRequest, requestId, service.Logger)
 
 	// Check product if exist in cart
-	cartResult, err := service.CartRepositoryInterface.FindCartById(service.DB, updateCartRequest.IdCart)
+	cartResult, err := service.CartRepositoryInterface.FindCartByIdUser(service.DB, updateCartRequest.IdCart, idUser)
 	exceptions.PanicIfError(err, requestId, service.Logger)
 	if len(cartResult.Id) == 0 {
 		exceptions.PanicIfRecordNotFound(errors.New("cart not found"), requestId, []string{"cart not found"}, service.Logger)
@@ -125,19 +131,40 @@ func (service *CartServiceImplementation) UpdateCart(requestId string, updateCar
 	return cartResult.Id
 }
 
-func (service *CartServiceImplementation) FindCartByUser(requestid string, idUser string, accountType int, idDesa string) (cartResponses response.FindCartByIdUserResponse) {
+func (service *CartServiceImplementation) FindCartByUser(requestid string, idUser string, accountType int, idDesa string, idAddress string) (cartResponses response.FindCartByIdUserResponse) {
 	carts, err := service.CartRepositoryInterface.FindCartByUser(service.DB, idUser)
 	exceptions.PanicIfError(err, requestid, service.Logger)
 	if len(carts) == 0 {
 		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestid, []string{"data not found"}, service.Logger)
 	}
 
-	desa, err := service.DesaRepositoryInterface.FindDesaById(service.DB, idDesa)
+	// Ongkir dihitung dari jarak depot ke alamat, keranjang tetap tampil walau alamat di luar area
+	userShippingAddress := service.ShippingServiceInterface.FindUserShippingAddress(requestid, idUser, idAddress)
+	shippingQuote, shippingErr := service.ShippingServiceInterface.QuoteShippingCost(requestid, idDesa, userShippingAddress)
+
+	// Harga keranjang dihitung sama dengan saat order dibuat
+	var idProductDesas []string
+	for _, cart := range carts {
+		idProductDesas = append(idProductDesas, cart.IdProductDesa)
+	}
+	priceTiers, err := service.ProductPriceTierRepositoryInterface.FindPriceTierByIdProductDesas(service.DB, idProductDesas)
 	exceptions.PanicIfError(err, requestid, service.Logger)
-	// if desa.Ongkir == 0 {
-	// 	exceptions.PanicIfRecordNotFound(errors.New("shipping cost not found"), requestid, []string{"shipping cost not found"}, service.Logger)
-	// }
 
-	cartResponses = response.ToFindCartByUserResponse(carts, desa.Ongkir, accountType)
+	quotes := make(map[string]response.PriceQuote)
+	for i := range carts {
+		var packageItems []entity.ProductsPackageItems
+		if carts[i].ProductsDesa.IdType == 2 {
+			packageItems, err = service.ProductDesaServiceInterface.FindListPackageByIdProductDesa(service.DB, carts[i].IdProductDesa)
+			exceptions.PanicIfError(err, requestid, service.Logger)
+		}
+		quotes[carts[i].Id] = QuotePrice(&carts[i].ProductsDesa, accountType, carts[i].Qty, priceTiers, packageItems)
+	}
+
+	cartResponses = response.ToFindCartByUserResponse(carts, quotes, shippingQuote.ShippingCost)
+	cartResponses.IdAddress = shippingQuote.IdAddress
+	cartResponses.DistanceKm = shippingQuote.DistanceKm
+	if shippingErr != nil {
+		cartResponses.ShippingMessage = shippingErr.Error()
+	}
 	return cartResponses
 }
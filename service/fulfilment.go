@@ -0,0 +1,354 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/storage"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+// Status order sembako: 0 menunggu pembayaran, 1 diproses, 2 dikemas, 3 dikirim,
+// 4 sampai, 5 selesai, 9 dibatalkan
+type FulfilmentServiceInterface interface {
+	FindFulfilmentOrders(requestId, idDesa string, orderStatus int) (fulfilmentOrderResponses []response.FindFulfilmentOrderResponse)
+	FindCouriers(requestId, idDesa string) (courierResponses []response.FindCourierResponse)
+	PackOrder(requestId, idDesa, idUser string, orderRequest *request.OrderIdRequest)
+	AssignCourier(requestId, idDesa, idUser string, assignCourierRequest *request.AssignCourierRequest)
+	FindCourierOrders(requestId, idDesa, idCourier string) (fulfilmentOrderResponses []response.FindFulfilmentOrderResponse)
+	ShipOrder(requestId, idDesa, idCourier string, orderRequest *request.OrderIdRequest)
+	DeliverOrder(requestId, idDesa, idCourier string, deliverOrderRequest *request.DeliverOrderRequest)
+	FindOrderTracking(requestId, idUser, idDesa, idOrder string) (orderTrackingResponse response.FindOrderTrackingResponse)
+	AutoCompleteDeliveredOrders()
+}
+
+type FulfilmentServiceImplementation struct {
+	DB                                    *gorm.DB
+	Validate                              *validator.Validate
+	Logger                                *logrus.Logger
+	ConfigRole                            config.Role
+	ConfigFulfilment                      config.Fulfilment
+	OrderRepositoryInterface              repository.OrderRepositoryInterface
+	OrderItemRepositoryInterface          repository.OrderItemRepositoryInterface
+	OrderStatusHistoryRepositoryInterface repository.OrderStatusHistoryRepositoryInterface
+	UserRepositoryInterface               repository.UserRepositoryInterface
+	OrderServiceInterface                 OrderServiceInterface
+	ConfigStorage                         config.Storage
+	Storage                               storage.Storage
+	NotificationServiceInterface          NotificationServiceInterface
+}
+
+func NewFulfilmentService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	configRole config.Role,
+	configFulfilment config.Fulfilment,
+	orderRepositoryInterface repository.OrderRepositoryInterface,
+	orderItemRepositoryInterface repository.OrderItemRepositoryInterface,
+	orderStatusHistoryRepositoryInterface repository.OrderStatusHistoryRepositoryInterface,
+	userRepositoryInterface repository.UserRepositoryInterface,
+	orderServiceInterface OrderServiceInterface,
+	configStorage config.Storage,
+	fileStorage storage.Storage,
+	notificationServiceInterface NotificationServiceInterface,
+) FulfilmentServiceInterface {
+	return &FulfilmentServiceImplementation{
+		DB:                                    db,
+		Validate:                              validate,
+		Logger:                                logger,
+		ConfigRole:                            configRole,
+		ConfigFulfilment:                      configFulfilment,
+		OrderRepositoryInterface:              orderRepositoryInterface,
+		OrderItemRepositoryInterface:          orderItemRepositoryInterface,
+		OrderStatusHistoryRepositoryInterface: orderStatusHistoryRepositoryInterface,
+		UserRepositoryInterface:               userRepositoryInterface,
+		OrderServiceInterface:                 orderServiceInterface,
+		ConfigStorage:                         configStorage,
+		Storage:                               fileStorage,
+		NotificationServiceInterface:          notificationServiceInterface,
+	}
+}
+
+// OrderStatusName label status order untuk tracking dan struk
+func OrderStatusName(orderStatus int) string {
+	switch orderStatus {
+	case 0:
+		return "Menunggu Pembayaran"
+	case 1:
+		return "Diproses"
+	case 2:
+		return "Dikemas"
+	case 3:
+		return "Dikirim"
+	case 4:
+		return "Sampai"
+	case 5:
+		return "Selesai"
+	case 9:
+		return "Dibatalkan"
+	default:
+		return "Diproses"
+	}
+}
+
+func (service *FulfilmentServiceImplementation) autoCompleteDays() uint {
+	if service.ConfigFulfilment.AutoCompleteDays == 0 {
+		return 3
+	}
+	return service.ConfigFulfilment.AutoCompleteDays
+}
+
+func (service *FulfilmentServiceImplementation) FindFulfilmentOrders(requestId, idDesa string, orderStatus int) (fulfilmentOrderResponses []response.FindFulfilmentOrderResponse) {
+	orders, err := service.OrderRepositoryInterface.FindOrdersSembakoByDesa(service.DB, idDesa, orderStatus)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	return service.toFulfilmentOrderResponses(requestId, orders)
+}
+
+func (service *FulfilmentServiceImplementation) FindCouriers(requestId, idDesa string) (courierResponses []response.FindCourierResponse) {
+	couriers, err := service.UserRepositoryInterface.FindUsersByRoleAndDesa(service.DB, service.ConfigRole.Courier, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	return response.ToFindCourierResponses(couriers)
+}
+
+func (service *FulfilmentServiceImplementation) PackOrder(requestId, idDesa, idUser string, orderRequest *request.OrderIdRequest) {
+	request.ValidateRequest(service.Validate, orderRequest, requestId, service.Logger)
+	order := service.findOrderByDesa(requestId, idDesa, orderRequest.IdOrder)
+
+	if order.OrderStatus != 1 {
+		exceptions.PanicIfBadRequest(errors.New("order not ready to pack"), requestId, []string{"order belum dibayar atau sudah dikemas"}, service.Logger)
+	}
+
+	service.updateOrderStatus(requestId, idUser, order, 1, &entity.Order{OrderStatus: 2}, "Pesanan dikemas")
+}
+
+// AssignCourier tugaskan atau ganti kurir selama order belum dikirim
+func (service *FulfilmentServiceImplementation) AssignCourier(requestId, idDesa, idUser string, assignCourierRequest *request.AssignCourierRequest) {
+	request.ValidateRequest(service.Validate, assignCourierRequest, requestId, service.Logger)
+	order := service.findOrderByDesa(requestId, idDesa, assignCourierRequest.IdOrder)
+
+	if order.OrderStatus != 2 {
+		exceptions.PanicIfBadRequest(errors.New("order not packed"), requestId, []string{"order harus dikemas sebelum ditugaskan ke kurir"}, service.Logger)
+	}
+
+	courier, err := service.UserRepositoryInterface.FindUserById(service.DB, assignCourierRequest.IdCourier)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(courier.User.Id) == 0 || courier.User.IdRole != service.ConfigRole.Courier || courier.User.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("courier not found"), requestId, []string{"kurir tidak ditemukan"}, service.Logger)
+	}
+
+	service.updateOrderStatus(requestId, idUser, order, 2, &entity.Order{IdCourier: courier.User.Id}, "Kurir "+courier.NamaLengkap+" ditugaskan")
+}
+
+func (service *FulfilmentServiceImplementation) FindCourierOrders(requestId, idDesa, idCourier string) (fulfilmentOrderResponses []response.FindFulfilmentOrderResponse) {
+	orders, err := service.OrderRepositoryInterface.FindOrdersByCourier(service.DB, idCourier)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	return service.toFulfilmentOrderResponses(requestId, orders)
+}
+
+func (service *FulfilmentServiceImplementation) ShipOrder(requestId, idDesa, idCourier string, orderRequest *request.OrderIdRequest) {
+	request.ValidateRequest(service.Validate, orderRequest, requestId, service.Logger)
+	order := service.findCourierOrder(requestId, idDesa, idCourier, orderRequest.IdOrder)
+
+	if order.OrderStatus != 2 {
+		exceptions.PanicIfBadRequest(errors.New("order not packed"), requestId, []string{"order belum dikemas atau sudah dikirim"}, service.Logger)
+	}
+
+	service.updateOrderStatus(requestId, idCourier, order, 2, &entity.Order{OrderStatus: 3}, "Pesanan dikirim")
+}
+
+func (service *FulfilmentServiceImplementation) DeliverOrder(requestId, idDesa, idCourier string, deliverOrderRequest *request.DeliverOrderRequest) {
+	request.ValidateRequest(service.Validate, deliverOrderRequest, requestId, service.Logger)
+	order := service.findCourierOrder(requestId, idDesa, idCourier, deliverOrderRequest.IdOrder)
+
+	if order.OrderStatus != 3 {
+		exceptions.PanicIfBadRequest(errors.New("order not shipped"), requestId, []string{"order belum dikirim"}, service.Logger)
+	}
+
+	// Key private hanya boleh dari upload foto barang sampai
+	if storage.IsPrivate(deliverOrderRequest.FotoBarangSampai) && !strings.HasPrefix(deliverOrderRequest.FotoBarangSampai, storage.PrefixPrivate+UploadCategoryBarangSampai+"/") {
+		exceptions.PanicIfBadRequest(errors.New("invalid foto barang sampai"), requestId, []string{"foto barang sampai tidak valid"}, service.Logger)
+	}
+
+	orderUpdate := &entity.Order{
+		OrderStatus:      4,
+		FotoBarangSampai: deliverOrderRequest.FotoBarangSampai,
+		DeliveredDate:    null.NewTime(time.Now(), true),
+	}
+	note := "Pesanan sampai"
+
+	// Uang cod diterima kurir saat barang sampai
+	if order.PaymentMethod == "cod" {
+		if deliverOrderRequest.CodCollected < order.TotalBill {
+			exceptions.PanicIfBadRequest(errors.New("cod collected less than total bill"), requestId, []string{"uang cod yang diterima kurang dari total tagihan"}, service.Logger)
+		}
+		orderUpdate.CodCollected = deliverOrderRequest.CodCollected
+		orderUpdate.PaymentStatus = 1
+		orderUpdate.PaymentSuccessDate = null.NewTime(time.Now(), true)
+		note = note + ", COD diterima " + utilities.FormatRupiah(deliverOrderRequest.CodCollected)
+	}
+
+	if len(deliverOrderRequest.Note) != 0 {
+		note = note + ". " + deliverOrderRequest.Note
+	}
+
+	service.updateOrderStatus(requestId, idCourier, order, 3, orderUpdate, note)
+}
+
+func (service *FulfilmentServiceImplementation) FindOrderTracking(requestId, idUser, idDesa, idOrder string) (orderTrackingResponse response.FindOrderTrackingResponse) {
+	order, err := service.OrderRepositoryInterface.FindOrderByIdUser(service.DB, idOrder, idUser, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(order.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
+	}
+
+	orderStatusHistories, err := service.OrderStatusHistoryRepositoryInterface.FindOrderStatusHistoryByIdOrder(service.DB, order.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	orderTrackingResponse.IdOrder = order.Id
+	orderTrackingResponse.NumberOrder = order.NumberOrder
+	orderTrackingResponse.OrderStatus = order.OrderStatus
+	orderTrackingResponse.FotoBarangSampai = storage.ResolveUrl(service.Storage, order.FotoBarangSampai, storage.SignedUrlExpiry(service.ConfigStorage))
+	orderTrackingResponse.DeliveredDate = order.DeliveredDate
+	if order.OrderStatus == 4 && order.DeliveredDate.Valid {
+		orderTrackingResponse.AutoCompleteDate = null.NewTime(order.DeliveredDate.Time.AddDate(0, 0, int(service.autoCompleteDays())), true)
+	}
+
+	if len(order.IdCourier) != 0 {
+		courier, err := service.UserRepositoryInterface.FindUserById(service.DB, order.IdCourier)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		orderTrackingResponse.NamaCourier = courier.NamaLengkap
+		orderTrackingResponse.PhoneCourier = courier.User.Phone
+	}
+
+	// Order dibuat dan dibayar belum tercatat di riwayat
+	orderTrackingResponse.Histories = []response.FindOrderStatusHistoryResponse{{
+		OrderStatus: 0,
+		Status:      OrderStatusName(0),
+		Note:        "Pesanan dibuat",
+		CreatedAt:   order.OrderedDate,
+	}}
+	if order.PaymentSuccessDate.Valid && order.PaymentMethod != "cod" {
+		orderTrackingResponse.Histories = append(orderTrackingResponse.Histories, response.FindOrderStatusHistoryResponse{
+			OrderStatus: 1,
+			Status:      OrderStatusName(1),
+			Note:        "Pembayaran diterima",
+			CreatedAt:   order.PaymentSuccessDate.Time,
+		})
+	}
+	for _, orderStatusHistory := range orderStatusHistories {
+		orderTrackingResponse.Histories = append(orderTrackingResponse.Histories, response.FindOrderStatusHistoryResponse{
+			OrderStatus: orderStatusHistory.OrderStatus,
+			Status:      OrderStatusName(orderStatusHistory.OrderStatus),
+			Note:        orderStatusHistory.Note,
+			CreatedAt:   orderStatusHistory.CreatedAt,
+		})
+	}
+	return orderTrackingResponse
+}
+
+// AutoCompleteDeliveredOrders selesaikan order yang sudah sampai tapi tidak dikonfirmasi customer
+func (service *FulfilmentServiceImplementation) AutoCompleteDeliveredOrders() {
+	deliveredDate := time.Now().AddDate(0, 0, -int(service.autoCompleteDays()))
+	orders, err := service.OrderRepositoryInterface.FindOrdersDeliveredBefore(service.DB, deliveredDate)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("auto complete order")
+		return
+	}
+
+	for i := range orders {
+		if err := service.autoCompleteOrder(&orders[i]); err != nil {
+			service.Logger.WithFields(logrus.Fields{"number_order": orders[i].NumberOrder, "error": err.Error()}).Error("auto complete order")
+		}
+	}
+}
+
+func (service *FulfilmentServiceImplementation) autoCompleteOrder(order *entity.Order) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	service.OrderServiceInterface.CompleteDeliveredOrder("auto-complete-"+order.NumberOrder, "", order, "Selesai otomatis")
+	go service.NotificationServiceInterface.NotifyOrderStatus(order, 5)
+	return nil
+}
+
+func (service *FulfilmentServiceImplementation) findOrderByDesa(requestId, idDesa, idOrder string) *entity.Order {
+	order, err := service.OrderRepositoryInterface.FindOrderSembakoByIdDesa(service.DB, idOrder, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(order.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
+	}
+	return order
+}
+
+// findCourierOrder order yang bukan tugas kurir tersebut dibalas 404
+func (service *FulfilmentServiceImplementation) findCourierOrder(requestId, idDesa, idCourier, idOrder string) *entity.Order {
+	order := service.findOrderByDesa(requestId, idDesa, idOrder)
+	if order.IdCourier != idCourier {
+		exceptions.PanicIfRecordNotFound(errors.New("order not assigned to courier"), requestId, []string{"order not found"}, service.Logger)
+	}
+	return order
+}
+
+// updateOrderStatus update order dan catat riwayat dalam satu transaksi, gagal jika status sudah diubah proses lain
+func (service *FulfilmentServiceImplementation) updateOrderStatus(requestId, idUser string, order *entity.Order, fromStatus int, orderUpdate *entity.Order, note string) {
+	orderStatus := orderUpdate.OrderStatus
+	if orderStatus == 0 {
+		orderStatus = fromStatus
+	}
+
+	tx := service.DB.Begin()
+	rowsAffected, err := service.OrderRepositoryInterface.UpdateOrderByIdOrderAndStatus(tx, order.Id, fromStatus, orderUpdate)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update order"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("order status changed"), requestId, []string{"status order sudah berubah"}, service.Logger)
+	}
+
+	err = service.OrderStatusHistoryRepositoryInterface.CreateOrderStatusHistory(tx, &entity.OrderStatusHistory{
+		Id:          utilities.RandomUUID(),
+		IdOrder:     order.Id,
+		IdUser:      idUser,
+		OrderStatus: orderStatus,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order status history"}, service.Logger, tx)
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+
+	if orderUpdate.OrderStatus != 0 {
+		go service.NotificationServiceInterface.NotifyOrderStatus(order, orderUpdate.OrderStatus)
+	}
+}
+
+func (service *FulfilmentServiceImplementation) toFulfilmentOrderResponses(requestId string, orders []entity.Order) []response.FindFulfilmentOrderResponse {
+	idOrders := []string{}
+	for _, order := range orders {
+		idOrders = append(idOrders, order.Id)
+	}
+
+	orderItems := []entity.OrderItem{}
+	if len(idOrders) != 0 {
+		var err error
+		orderItems, err = service.OrderItemRepositoryInterface.FindOrderItemsByIdOrders(service.DB, idOrders)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+	}
+	return response.ToFindFulfilmentOrderResponses(orders, orderItems)
+}
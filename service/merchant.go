@@ -12,12 +12,16 @@ import (
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
 )
 
 type MerchantServiceInterface interface {
 	CreateMerchantApproveList(requestId, idUser, idDesa string, createcMerchantApproveListRequest *request.CreateMerchantApproveListRequest) string
 	FindMerchantStatusApproveByUser(requestId, idUser string) (merchantResponse *response.FindMerchantStatusApproveByUserResponse)
+	FindMerchantApproveListByDesa(requestId, idDesa string, approveStatus int) (merchantResponses []response.FindMerchantApproveListResponse)
+	ApproveMerchant(requestId, idAdmin, idDesa string, approveMerchantRequest *request.ApproveMerchantRequest)
+	RejectMerchant(requestId, idAdmin, idDesa string, rejectMerchantRequest *request.RejectMerchantRequest)
 }
 
 type MerchantServiceImplementation struct {
@@ -26,6 +30,8 @@ type MerchantServiceImplementation struct {
 	Logger                         *logrus.Logger
 	UserProfileRepositoryInterface repository.UserProfileRepositoryInterface
 	MerchantRepositoryInterface    repository.MerchantRepositoryInterface
+	UserRepositoryInterface        repository.UserRepositoryInterface
+	DesaRepositoryInterface        repository.DesaRepositoryInterface
 }
 
 func NewMerchantService(
@@ -34,6 +40,8 @@ func NewMerchantService(
 	logger *logrus.Logger,
 	userProfileRepositoryInterface repository.UserProfileRepositoryInterface,
 	merchantRepositoryInterface repository.MerchantRepositoryInterface,
+	userRepositoryInterface repository.UserRepositoryInterface,
+	desaRepositoryInterface repository.DesaRepositoryInterface,
 ) MerchantServiceInterface {
 	return &MerchantServiceImplementation{
 		DB:                             db,
@@ -41,6 +49,8 @@ func NewMerchantService(
 		Logger:                         logger,
 		UserProfileRepositoryInterface: userProfileRepositoryInterface,
 		MerchantRepositoryInterface:    merchantRepositoryInterface,
+		UserRepositoryInterface:        userRepositoryInterface,
+		DesaRepositoryInterface:        desaRepositoryInterface,
 	}
 }
 
@@ -67,6 +77,20 @@ func (service *MerchantServiceImplementation) CreateMerchantApproveList(requestI
 		exceptions.PanicIfRecordNotFound(errors.New("user profile not found"), requestId, []string{"user profile not found"}, service.Logger)
 	}
 
+	// User sudah merchant
+	user, err := service.UserRepositoryInterface.FindUserById2(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if user.AccountType == 2 {
+		exceptions.PanicIfBadRequest(errors.New("user already merchant"), requestId, []string{"user sudah terdaftar sebagai merchant"}, service.Logger)
+	}
+
+	// Cek pengajuan yang masih menunggu review
+	merchantApproveStatus, err := service.MerchantRepositoryInterface.FindMerchantStatusApproveByUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(merchantApproveStatus.Id) != 0 && merchantApproveStatus.ApproveStatus == 1 {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("merchant request already pending"), requestId, []string{"pengajuan merchant masih dalam proses review"}, service.Logger)
+	}
+
 	// add product to cart
 	merchantApproveListEntity := &entity.MerchantApproveList{
 		Id:            utilities.RandomUUID(),
@@ -83,3 +107,107 @@ func (service *MerchantServiceImplementation) CreateMerchantApproveList(requestI
 
 	return ""
 }
+
+func (service *MerchantServiceImplementation) FindMerchantApproveListByDesa(requestId, idDesa string, approveStatus int) (merchantResponses []response.FindMerchantApproveListResponse) {
+	merchantApproveLists, err := service.MerchantRepositoryInterface.FindMerchantApproveListByDesa(service.DB, idDesa, approveStatus)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(merchantApproveLists) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("merchant approve list not found"), requestId, []string{"merchant approve list not found"}, service.Logger)
+	}
+
+	merchantResponses = response.ToFindMerchantApproveListResponses(merchantApproveLists)
+	return merchantResponses
+}
+
+func (service *MerchantServiceImplementation) ApproveMerchant(requestId, idAdmin, idDesa string, approveMerchantRequest *request.ApproveMerchantRequest) {
+	request.ValidateRequest(service.Validate, approveMerchantRequest, requestId, service.Logger)
+
+	merchantApproveList := service.findPendingMerchantApproveList(requestId, idDesa, approveMerchantRequest.IdMerchantApproveList)
+
+	desa, err := service.DesaRepositoryInterface.FindDesaById(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	// Generate merchant code yang belum dipakai
+	var merchantCode string
+	for i := 0; i < 5; i++ {
+		merchantCode = utilities.GenerateMerchantCode(desa.KodeTrx)
+		userMerchantCode, err := service.UserRepositoryInterface.FindUserByMerchantCode(service.DB, merchantCode)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(userMerchantCode.Id) == 0 {
+			break
+		}
+		merchantCode = ""
+	}
+
+	if len(merchantCode) == 0 {
+		exceptions.PanicIfError(errors.New("failed generate merchant code"), requestId, service.Logger)
+	}
+
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	err = service.MerchantRepositoryInterface.UpdateMerchantApproveList(tx, merchantApproveList.Id, &entity.MerchantApproveList{
+		ApproveStatus: 2,
+		ReviewedBy:    idAdmin,
+		ReviewedAt:    null.NewTime(time.Now(), true),
+		UpdatedAt:     null.NewTime(time.Now(), true),
+	})
+	if err == gorm.ErrRecordNotFound {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(err, requestId, []string{"merchant request already reviewed"}, service.Logger)
+	}
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update merchant approve list"}, service.Logger, tx)
+
+	err = service.UserRepositoryInterface.UpdateUser(tx, merchantApproveList.IdUser, &entity.User{
+		AccountType:  2,
+		MerchantCode: merchantCode,
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update user"}, service.Logger, tx)
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+
+	service.notifyMerchantDecision(merchantApproveList.IdUser, "Selamat, pengajuan merchant "+merchantApproveList.MerchantName+" telah disetujui BUPDA. Kode merchant Anda: "+merchantCode)
+}
+
+func (service *MerchantServiceImplementation) RejectMerchant(requestId, idAdmin, idDesa string, rejectMerchantRequest *request.RejectMerchantRequest) {
+	request.ValidateRequest(service.Validate, rejectMerchantRequest, requestId, service.Logger)
+
+	merchantApproveList := service.findPendingMerchantApproveList(requestId, idDesa, rejectMerchantRequest.IdMerchantApproveList)
+
+	err := service.MerchantRepositoryInterface.UpdateMerchantApproveList(service.DB, merchantApproveList.Id, &entity.MerchantApproveList{
+		ApproveStatus: 3,
+		RejectReason:  rejectMerchantRequest.RejectReason,
+		ReviewedBy:    idAdmin,
+		ReviewedAt:    null.NewTime(time.Now(), true),
+		UpdatedAt:     null.NewTime(time.Now(), true),
+	})
+	if err == gorm.ErrRecordNotFound {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"merchant request already reviewed"}, service.Logger)
+	}
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	service.notifyMerchantDecision(merchantApproveList.IdUser, "Mohon maaf, pengajuan merchant "+merchantApproveList.MerchantName+" ditolak BUPDA. Alasan: "+rejectMerchantRequest.RejectReason)
+}
+
+func (service *MerchantServiceImplementation) findPendingMerchantApproveList(requestId, idDesa, idMerchantApproveList string) *entity.MerchantApproveList {
+	merchantApproveList, err := service.MerchantRepositoryInterface.FindMerchantApproveListById(service.DB, idMerchantApproveList)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(merchantApproveList.Id) == 0 || merchantApproveList.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("merchant approve list not found"), requestId, []string{"merchant approve list not found"}, service.Logger)
+	}
+
+	if merchantApproveList.ApproveStatus != 1 {
+		exceptions.PanicIfBadRequest(errors.New("merchant request already reviewed"), requestId, []string{"merchant request already reviewed"}, service.Logger)
+	}
+
+	return merchantApproveList
+}
+
+func (service *MerchantServiceImplementation) notifyMerchantDecision(idUser string, message string) {
+	user, err := service.UserRepositoryInterface.FindUserById2(service.DB, idUser)
+	if err != nil || len(user.Phone) == 0 {
+		return
+	}
+	go SendSms(service.Logger, user.Phone, message)
+}
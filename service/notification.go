@@ -0,0 +1,250 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	fcmrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/fcm_repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gorm.io/gorm"
+)
+
+const (
+	NotificationTypeOrderPaid   = "order_paid"
+	NotificationTypeOrderStatus = "order_status"
+	NotificationTypePpobToken   = "ppob_token"
+	NotificationTypePaylaterDue = "paylater_due"
+)
+
+type NotificationServiceInterface interface {
+	RegisterDeviceToken(requestId string, idUser string, registerDeviceTokenRequest *request.RegisterDeviceTokenRequest)
+	UnregisterDeviceToken(requestId string, idUser string, unregisterDeviceTokenRequest *request.UnregisterDeviceTokenRequest)
+	FindNotifications(requestId string, idUser string, page int, limit int) (notificationResponse response.FindNotificationResponse)
+	ReadNotifications(requestId string, idUser string, readNotificationRequest *request.ReadNotificationRequest)
+	// Notify simpan ke inbox lalu kirim push, error hanya dicatat supaya tidak menggagalkan proses order
+	Notify(idUser, notificationType, title, body, idReference string)
+	NotifyOrderPaid(order *entity.Order)
+	NotifyOrderStatus(order *entity.Order, orderStatus int)
+	NotifyPpobToken(order *entity.Order, token string)
+	RemindPaylaterDue()
+}
+
+type NotificationServiceImplementation struct {
+	DB                                 *gorm.DB
+	Validate                           *validator.Validate
+	Logger                             *logrus.Logger
+	ConfigFcm                          config.Fcm
+	FcmProviderInterface               fcmrepository.FcmProviderInterface
+	NotificationRepositoryInterface    repository.NotificationRepositoryInterface
+	UserDeviceTokenRepositoryInterface repository.UserDeviceTokenRepositoryInterface
+	OrderRepositoryInterface           repository.OrderRepositoryInterface
+}
+
+func NewNotificationService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	configFcm config.Fcm,
+	fcmProviderInterface fcmrepository.FcmProviderInterface,
+	notificationRepositoryInterface repository.NotificationRepositoryInterface,
+	userDeviceTokenRepositoryInterface repository.UserDeviceTokenRepositoryInterface,
+	orderRepositoryInterface repository.OrderRepositoryInterface,
+) NotificationServiceInterface {
+	return &NotificationServiceImplementation{
+		DB:                                 db,
+		Validate:                           validate,
+		Logger:                             logger,
+		ConfigFcm:                          configFcm,
+		FcmProviderInterface:               fcmProviderInterface,
+		NotificationRepositoryInterface:    notificationRepositoryInterface,
+		UserDeviceTokenRepositoryInterface: userDeviceTokenRepositoryInterface,
+		OrderRepositoryInterface:           orderRepositoryInterface,
+	}
+}
+
+// RegisterDeviceToken dipanggil setiap login, token yang sama dipindah ke user yang sedang login
+func (service *NotificationServiceImplementation) RegisterDeviceToken(requestId string, idUser string, registerDeviceTokenRequest *request.RegisterDeviceTokenRequest) {
+	request.ValidateRequest(service.Validate, registerDeviceTokenRequest, requestId, service.Logger)
+
+	userDeviceToken, err := service.UserDeviceTokenRepositoryInterface.FindUserDeviceTokenByToken(service.DB, registerDeviceTokenRequest.Token)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	if len(userDeviceToken.Id) != 0 {
+		err = service.UserDeviceTokenRepositoryInterface.UpdateUserDeviceToken(service.DB, userDeviceToken.Id, &entity.UserDeviceToken{
+			IdUser:   idUser,
+			Platform: registerDeviceTokenRequest.Platform,
+			DeviceId: registerDeviceTokenRequest.DeviceId,
+		})
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		return
+	}
+
+	err = service.UserDeviceTokenRepositoryInterface.CreateUserDeviceToken(service.DB, &entity.UserDeviceToken{
+		Id:        utilities.RandomUUID(),
+		IdUser:    idUser,
+		Token:     registerDeviceTokenRequest.Token,
+		Platform:  registerDeviceTokenRequest.Platform,
+		DeviceId:  registerDeviceTokenRequest.DeviceId,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+// UnregisterDeviceToken dipanggil saat logout supaya perangkat tidak menerima push user tersebut
+func (service *NotificationServiceImplementation) UnregisterDeviceToken(requestId string, idUser string, unregisterDeviceTokenRequest *request.UnregisterDeviceTokenRequest) {
+	request.ValidateRequest(service.Validate, unregisterDeviceTokenRequest, requestId, service.Logger)
+
+	err := service.UserDeviceTokenRepositoryInterface.DeleteUserDeviceToken(service.DB, idUser, unregisterDeviceTokenRequest.Token)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *NotificationServiceImplementation) FindNotifications(requestId string, idUser string, page int, limit int) (notificationResponse response.FindNotificationResponse) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	notifications, total, err := service.NotificationRepositoryInterface.FindNotificationByIdUser(service.DB, idUser, limit, (page-1)*limit)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	unread, err := service.NotificationRepositoryInterface.CountUnreadNotificationByIdUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	notificationResponse = response.ToFindNotificationResponse(notifications, unread, page, limit, total)
+	return notificationResponse
+}
+
+func (service *NotificationServiceImplementation) ReadNotifications(requestId string, idUser string, readNotificationRequest *request.ReadNotificationRequest) {
+	request.ValidateRequest(service.Validate, readNotificationRequest, requestId, service.Logger)
+
+	err := service.NotificationRepositoryInterface.UpdateNotificationRead(service.DB, idUser, readNotificationRequest.IdNotifications)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *NotificationServiceImplementation) Notify(idUser, notificationType, title, body, idReference string) {
+	logger := service.Logger.WithFields(logrus.Fields{"id_user": idUser, "type": notificationType, "id_reference": idReference})
+
+	err := service.NotificationRepositoryInterface.CreateNotification(service.DB, &entity.Notification{
+		Id:          utilities.RandomUUID(),
+		IdUser:      idUser,
+		Type:        notificationType,
+		Title:       title,
+		Body:        body,
+		IdReference: idReference,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		logger.Error("error create notification ", err)
+	}
+
+	userDeviceTokens, err := service.UserDeviceTokenRepositoryInterface.FindUserDeviceTokenByIdUser(service.DB, idUser)
+	if err != nil {
+		logger.Error("error find device token ", err)
+		return
+	}
+	if len(userDeviceTokens) == 0 {
+		return
+	}
+
+	var tokens []string
+	for _, userDeviceToken := range userDeviceTokens {
+		tokens = append(tokens, userDeviceToken.Token)
+	}
+
+	invalidTokens, err := service.FcmProviderInterface.Send(tokens, fcmrepository.Message{
+		Title: title,
+		Body:  body,
+		Data: map[string]string{
+			"type":         notificationType,
+			"id_reference": idReference,
+		},
+	})
+	if err != nil {
+		logger.Error("error send push notification ", err)
+	}
+
+	// Token yang sudah tidak terdaftar di firebase dihapus
+	if err := service.UserDeviceTokenRepositoryInterface.DeleteUserDeviceTokenByTokens(service.DB, invalidTokens); err != nil {
+		logger.Error("error delete invalid device token ", err)
+	}
+}
+
+func (service *NotificationServiceImplementation) NotifyOrderPaid(order *entity.Order) {
+	service.Notify(order.IdUser, NotificationTypeOrderPaid, "Pembayaran berhasil",
+		fmt.Sprintf("Pembayaran pesanan %s sebesar %s sudah kami terima.", order.NumberOrder, utilities.FormatRupiah(order.TotalBill)),
+		order.Id)
+}
+
+func (service *NotificationServiceImplementation) NotifyOrderStatus(order *entity.Order, orderStatus int) {
+	var body string
+	switch orderStatus {
+	case 2:
+		body = "Pesanan %s sedang dikemas."
+	case 3:
+		body = "Pesanan %s sedang dikirim kurir."
+	case 4:
+		body = "Pesanan %s sudah sampai di alamat tujuan."
+	case 5:
+		body = "Pesanan %s sudah selesai. Terima kasih sudah berbelanja."
+	case 9:
+		body = "Pesanan %s dibatalkan."
+	default:
+		body = "Status pesanan %s: " + OrderStatusName(orderStatus) + "."
+	}
+	service.Notify(order.IdUser, NotificationTypeOrderStatus, "Pesanan "+OrderStatusName(orderStatus), fmt.Sprintf(body, order.NumberOrder), order.Id)
+}
+
+func (service *NotificationServiceImplementation) NotifyPpobToken(order *entity.Order, token string) {
+	service.Notify(order.IdUser, NotificationTypePpobToken, "Token listrik terbit",
+		fmt.Sprintf("Token PLN pesanan %s: %s", order.NumberOrder, token),
+		order.Id)
+}
+
+// RemindPaylaterDue pengingat tagihan paylater yang mendekati jatuh tempo, paling banyak sekali sehari per order
+func (service *NotificationServiceImplementation) RemindPaylaterDue() {
+	now := time.Now()
+	orders, err := service.OrderRepositoryInterface.FindOrderPaylaterUnpaidByDueDate(service.DB, now, now.AddDate(0, 0, int(service.paylaterReminderDays())))
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("remind paylater due")
+		return
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := range orders {
+		order := &orders[i]
+		total, err := service.NotificationRepositoryInterface.CountNotificationByReference(service.DB, order.IdUser, NotificationTypePaylaterDue, order.Id, today)
+		if err != nil {
+			service.Logger.WithFields(logrus.Fields{"number_order": order.NumberOrder, "error": err.Error()}).Error("remind paylater due")
+			continue
+		}
+		if total > 0 {
+			continue
+		}
+
+		amount := order.PaymentCash
+		if amount == 0 {
+			amount = order.TotalBill
+		}
+		service.Notify(order.IdUser, NotificationTypePaylaterDue, "Tagihan paylater jatuh tempo",
+			fmt.Sprintf("Tagihan paylater pesanan %s sebesar %s jatuh tempo pada %s.", order.NumberOrder, utilities.FormatRupiah(amount), order.PaymentDueDate.Time.Format("02-01-2006")),
+			order.Id)
+	}
+}
+
+func (service *NotificationServiceImplementation) paylaterReminderDays() uint {
+	if service.ConfigFcm.PaylaterReminderDays == 0 {
+		return 3
+	}
+	return service.ConfigFcm.PaylaterReminderDays
+}
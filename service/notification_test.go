@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	fcmrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/fcm_repository"
+	"gorm.io/gorm"
+)
+
+type stubNotificationRepository struct {
+	repository.NotificationRepositoryInterface
+	notifications []entity.Notification
+}
+
+func (repository *stubNotificationRepository) CreateNotification(db *gorm.DB, notification *entity.Notification) error {
+	repository.notifications = append(repository.notifications, *notification)
+	return nil
+}
+
+type stubUserDeviceTokenRepository struct {
+	repository.UserDeviceTokenRepositoryInterface
+	userDeviceTokens []entity.UserDeviceToken
+	deletedTokens    []string
+}
+
+func (repository *stubUserDeviceTokenRepository) FindUserDeviceTokenByIdUser(db *gorm.DB, idUser string) ([]entity.UserDeviceToken, error) {
+	var userDeviceTokens []entity.UserDeviceToken
+	for _, userDeviceToken := range repository.userDeviceTokens {
+		if userDeviceToken.IdUser == idUser {
+			userDeviceTokens = append(userDeviceTokens, userDeviceToken)
+		}
+	}
+	return userDeviceTokens, nil
+}
+
+func (repository *stubUserDeviceTokenRepository) DeleteUserDeviceTokenByTokens(db *gorm.DB, tokens []string) error {
+	repository.deletedTokens = append(repository.deletedTokens, tokens...)
+	return nil
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func newTestNotificationService(fcmProvider fcmrepository.FcmProviderInterface) (*NotificationServiceImplementation, *stubNotificationRepository, *stubUserDeviceTokenRepository) {
+	notificationRepository := &stubNotificationRepository{}
+	userDeviceTokenRepository := &stubUserDeviceTokenRepository{
+		userDeviceTokens: []entity.UserDeviceToken{
+			{Id: "token-1", IdUser: "user-1", Token: "device-1"},
+			{Id: "token-2", IdUser: "user-1", Token: "invalid-device-2"},
+			{Id: "token-3", IdUser: "user-2", Token: "device-3"},
+		},
+	}
+	notificationService := &NotificationServiceImplementation{
+		Logger:                             newTestLogger(),
+		FcmProviderInterface:               fcmProvider,
+		NotificationRepositoryInterface:    notificationRepository,
+		UserDeviceTokenRepositoryInterface: userDeviceTokenRepository,
+	}
+	return notificationService, notificationRepository, userDeviceTokenRepository
+}
+
+func TestNotifyPpobTokenSendsPushAndDeletesInvalidToken(t *testing.T) {
+	fcmProvider := fcmrepository.NewFakeFcmProvider()
+	notificationService, notificationRepository, userDeviceTokenRepository := newTestNotificationService(fcmProvider)
+
+	notificationService.NotifyPpobToken(&entity.Order{Id: "order-1", IdUser: "user-1", NumberOrder: "ORD-1"}, "1234-5678")
+
+	if len(notificationRepository.notifications) != 1 {
+		t.Fatalf("notifications = %d, want 1", len(notificationRepository.notifications))
+	}
+	if notification := notificationRepository.notifications[0]; notification.Type != NotificationTypePpobToken || notification.IdReference != "order-1" {
+		t.Fatalf("notification = %+v", notification)
+	}
+
+	messages := fcmProvider.SentTo("device-1")
+	if len(messages) != 1 {
+		t.Fatalf("push device-1 = %d, want 1", len(messages))
+	}
+	if !strings.Contains(messages[0].Body, "1234-5678") || messages[0].Data["id_reference"] != "order-1" {
+		t.Fatalf("message = %+v", messages[0])
+	}
+	if len(fcmProvider.SentTo("device-3")) != 0 {
+		t.Fatal("push terkirim ke device user lain")
+	}
+	if len(userDeviceTokenRepository.deletedTokens) != 1 || userDeviceTokenRepository.deletedTokens[0] != "invalid-device-2" {
+		t.Fatalf("deleted tokens = %v, want [invalid-device-2]", userDeviceTokenRepository.deletedTokens)
+	}
+}
+
+func TestNotifyKeepsInboxWhenPushFails(t *testing.T) {
+	fcmProvider := fcmrepository.NewFakeFcmProvider()
+	fcmProvider.Err = errors.New("fcm unavailable")
+	notificationService, notificationRepository, userDeviceTokenRepository := newTestNotificationService(fcmProvider)
+
+	notificationService.NotifyOrderStatus(&entity.Order{Id: "order-1", IdUser: "user-1", NumberOrder: "ORD-1"}, 5)
+
+	if len(notificationRepository.notifications) != 1 {
+		t.Fatalf("notifications = %d, want 1", len(notificationRepository.notifications))
+	}
+	if len(fcmProvider.SentTo("device-1")) != 0 {
+		t.Fatal("push tetap terkirim saat provider error")
+	}
+	if len(userDeviceTokenRepository.deletedTokens) != 0 {
+		t.Fatalf("deleted tokens = %v, want none", userDeviceTokenRepository.deletedTokens)
+	}
+}
@@ -1,19 +1,15 @@
 package service
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"runtime"
 
 	"log"
-	"math"
 	"math/rand"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"strconv"
 	"strings"
@@ -21,7 +17,6 @@ import (
 
 	"github.com/go-playground/validator"
 	"github.com/sirupsen/logrus"
-	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/payment"
@@ -30,6 +25,7 @@ import (
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
 	invelirepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/inveli_repository"
+	ppobrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/ppob_repository"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
 	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
@@ -42,43 +38,61 @@ type OrderServiceInterface interface {
 	CreateOrderPostpaidPdam(requestId, idUser, idDesa, productType string, orderRequest *request.CreateOrderPostpaidRequest) (createOrderResponse response.CreateOrderResponse)
 	CreateOrderPostpaidTelco(requestId, idUser, idDesa, productType string, orderRequest *request.CreateOrderPostpaidRequest) (createOrderResponse response.CreateOrderResponse)
 	CreateOrderPostpaidPln(requestId, idUser, idDesa, productType string, orderRequest *request.CreateOrderPostpaidRequest) (createOrderResponse response.CreateOrderResponse)
+	CreateOrderPpob(requestId, idUser, idDesa, productType string, orderRequest *request.CreateOrderPpobRequest) (createOrderResponse response.CreateOrderResponse)
 	FindOrderByUser(requestId, idUser string, orderStatus int) (orderResponses []response.FindOrderByUserResponse)
-	FindOrderSembakoById(requestId, idOrder string) (orderResponse response.FindOrderSembakoByIdResponse)
-	FindOrderPrepaidPulsaById(requestId, idOrder string, productType string) (orderResponse response.FindOrderPrepaidPulsaByIdResponse)
-	FindOrderPrepaidPlnById(requestId, idOrder string) (orderResponse response.FindOrderPrepaidPlnByIdResponse)
-	FindOrderPostpaidPdamById(requestId, idOrder string) (orderResponse response.FindOrderPostpaidPdamByIdResponse)
-	FindOrderPostpaidPlnById(requestId, idOrder string) (orderResponse response.FindOrderPostpaidPlnByIdResponse)
-	CancelOrderById(requestId string, orderRequest *request.OrderIdRequest)
-	CompleteOrderById(requestId string, orderRequest *request.OrderIdRequest)
+	FindOrderSembakoById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderSembakoByIdResponse)
+	FindOrderPrepaidPulsaById(requestId, idUser, idDesa, idOrder string, productType string) (orderResponse response.FindOrderPrepaidPulsaByIdResponse)
+	FindOrderPrepaidPlnById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPrepaidPlnByIdResponse)
+	FindOrderPostpaidPdamById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPostpaidPdamByIdResponse)
+	FindOrderPostpaidPlnById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPostpaidPlnByIdResponse)
+	FindOrderPpobById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPpobByIdResponse)
+	FindOrderReceipt(requestId, idUser, idDesa, idOrder string) (orderReceiptResponse response.FindOrderReceiptResponse)
+	CancelOrderById(requestId, idUser, idDesa string, orderRequest *request.OrderIdRequest)
+	CancelExpiredTransferOrders()
+	CompleteOrderById(requestId, idUser, idDesa string, orderRequest *request.OrderIdRequest)
+	CompleteDeliveredOrder(requestId, idUser string, order *entity.Order, note string)
 	UpdatePaymentStatusOrder(requestId string, orderRequest *request.UpdatePaymentStatusOrderRequest)
+	ConfirmOrderPayment(requestId string, order *entity.Order, verifiedBy string)
 	GenerateNumberOrder(idDesa string) (numberOrder string)
 	PrepaidPulsaTopup(requestId string, customerId, refId, productCode string) *ppob.TopupPrepaidPulsaResponse
 	OrderInquiryPrepaidPln(requestId string, customerId string) (inquiryPrepaidPlnResponse response.InquiryPrepaidPlnResponse)
-	CallbackPpobTransaction(requestId string, ppobCallbackRequest *request.PpobCallbackRequest)
+	CallbackPpobTransaction(requestId string, ppobCallbackRequest *request.PpobCallbackRequest, rawPayload []byte, remoteIp string)
+	UpdatePpobTransactionStatus(requestId string, order *entity.Order, ppobTransaction *request.PpobCallbackRequestData) (updated bool)
 	FindOrderPayLaterByIdUser(requestId, idUser string) (orderResponse []response.FindOrderByUserResponse)
-	FindOrderPaymentById(requestId, idOrder string) (orderResponse response.OrderPayment)
+	FindOrderPaymentById(requestId, idUser, idDesa, idOrder string) (orderResponse response.OrderPayment)
 	SendMessageToTelegram(message, chatId, token string)
 }
 
 type OrderServiceImplementation struct {
-	DB                                     *gorm.DB
-	Validate                               *validator.Validate
-	Logger                                 *logrus.Logger
-	OrderRepositoryInterface               repository.OrderRepositoryInterface
-	UserRepositoryInterface                repository.UserRepositoryInterface
-	PaymentServiceInterface                PaymentServiceInterface
-	CartRepositoryInterface                repository.CartRepositoryInterface
-	OrderItemRepositoryInterface           repository.OrderItemRepositoryInterface
-	PaymentChannelRepositoryInterface      repository.PaymentChannelRepositoryInterface
-	ProductDesaRepositoryInterface         repository.ProductDesaRepositoryInterface
-	ProductDesaServiceInterface            ProductDesaServiceInterface
-	OperatorPrefixRepositoryInterface      repository.OperatorPrefixRepositoryInterface
-	OrderItemPpobRepositoryInterface       repository.OrderItemPpobRepositoryInterface
-	PpobDetailRepositoryInterface          repository.PpobDetailRepositoryInterface
-	DesaRepositoryInterface                repository.DesaRepositoryInterface
-	InveliAPIRepositoryInterface           invelirepository.InveliAPIRepositoryInterface
-	ListPinjamanRepositoryInterface        repository.ListPinjamanRepositoryInterface
-	UserShippingAddressRepositoryInterface repository.UserShippingAddressRepositoryInterface
+	DB                                      *gorm.DB
+	Validate                                *validator.Validate
+	Logger                                  *logrus.Logger
+	OrderRepositoryInterface                repository.OrderRepositoryInterface
+	UserRepositoryInterface                 repository.UserRepositoryInterface
+	PaymentServiceInterface                 PaymentServiceInterface
+	CartRepositoryInterface                 repository.CartRepositoryInterface
+	OrderItemRepositoryInterface            repository.OrderItemRepositoryInterface
+	PaymentChannelRepositoryInterface       repository.PaymentChannelRepositoryInterface
+	ProductDesaRepositoryInterface          repository.ProductDesaRepositoryInterface
+	ProductDesaServiceInterface             ProductDesaServiceInterface
+	OperatorPrefixRepositoryInterface       repository.OperatorPrefixRepositoryInterface
+	OrderItemPpobRepositoryInterface        repository.OrderItemPpobRepositoryInterface
+	PpobDetailRepositoryInterface           repository.PpobDetailRepositoryInterface
+	DesaRepositoryInterface                 repository.DesaRepositoryInterface
+	InveliAPIRepositoryInterface            invelirepository.InveliAPIRepositoryInterface
+	ListPinjamanRepositoryInterface         repository.ListPinjamanRepositoryInterface
+	UserShippingAddressRepositoryInterface  repository.UserShippingAddressRepositoryInterface
+	OrderItemPackageRepositoryInterface     repository.OrderItemPackageRepositoryInterface
+	VoucherServiceInterface                 VoucherServiceInterface
+	PointServiceInterface                   PointServiceInterface
+	PpobProviderInterface                   ppobrepository.PpobProviderInterface
+	PpobPriceServiceInterface               PpobPriceServiceInterface
+	PpobProductTypeRepositoryInterface      repository.PpobProductTypeRepositoryInterface
+	PpobCallbackLogRepositoryInterface      repository.PpobCallbackLogRepositoryInterface
+	OrderStatusHistoryRepositoryInterface   repository.OrderStatusHistoryRepositoryInterface
+	TransferUniqueAmountRepositoryInterface repository.TransferUniqueAmountRepositoryInterface
+	ShippingServiceInterface                ShippingServiceInterface
+	NotificationServiceInterface            NotificationServiceInterface
 }
 
 func NewOrderService(
@@ -100,26 +114,48 @@ func NewOrderService(
 	inveliAPIRepositoryInterface invelirepository.InveliAPIRepositoryInterface,
 	listPinjamanRepositoryInterface repository.ListPinjamanRepositoryInterface,
 	userShippingAddressRepositoryInterface repository.UserShippingAddressRepositoryInterface,
+	orderItemPackageRepositoryInterface repository.OrderItemPackageRepositoryInterface,
+	voucherServiceInterface VoucherServiceInterface,
+	pointServiceInterface PointServiceInterface,
+	ppobProviderInterface ppobrepository.PpobProviderInterface,
+	ppobPriceServiceInterface PpobPriceServiceInterface,
+	ppobProductTypeRepositoryInterface repository.PpobProductTypeRepositoryInterface,
+	ppobCallbackLogRepositoryInterface repository.PpobCallbackLogRepositoryInterface,
+	orderStatusHistoryRepositoryInterface repository.OrderStatusHistoryRepositoryInterface,
+	transferUniqueAmountRepositoryInterface repository.TransferUniqueAmountRepositoryInterface,
+	shippingServiceInterface ShippingServiceInterface,
+	notificationServiceInterface NotificationServiceInterface,
 ) OrderServiceInterface {
 	return &OrderServiceImplementation{
-		DB:                                     db,
-		Validate:                               validate,
-		Logger:                                 logger,
-		OrderRepositoryInterface:               orderRepositoryInterface,
-		UserRepositoryInterface:                userRepositoryInterface,
-		PaymentServiceInterface:                paymentServiceInterface,
-		CartRepositoryInterface:                cartRepositoryInterface,
-		OrderItemRepositoryInterface:           orderItemRepositoryInterface,
-		PaymentChannelRepositoryInterface:      paymentChannelRepositoryInterface,
-		ProductDesaRepositoryInterface:         productDesaRepositoryInterface,
-		ProductDesaServiceInterface:            productDesaServiceInterface,
-		OperatorPrefixRepositoryInterface:      operatorPrefixRepositoryInterface,
-		OrderItemPpobRepositoryInterface:       orderItemPpobRepositoryInterface,
-		PpobDetailRepositoryInterface:          ppobDetailRepositoryInterface,
-		DesaRepositoryInterface:                desaRepositoryInterface,
-		InveliAPIRepositoryInterface:           inveliAPIRepositoryInterface,
-		ListPinjamanRepositoryInterface:        listPinjamanRepositoryInterface,
-		UserShippingAddressRepositoryInterface: userShippingAddressRepositoryInterface,
+		DB:                                      db,
+		Validate:                                validate,
+		Logger:                                  logger,
+		OrderRepositoryInterface:                orderRepositoryInterface,
+		UserRepositoryInterface:                 userRepositoryInterface,
+		PaymentServiceInterface:                 paymentServiceInterface,
+		CartRepositoryInterface:                 cartRepositoryInterface,
+		OrderItemRepositoryInterface:            orderItemRepositoryInterface,
+		PaymentChannelRepositoryInterface:       paymentChannelRepositoryInterface,
+		ProductDesaRepositoryInterface:          productDesaRepositoryInterface,
+		ProductDesaServiceInterface:             productDesaServiceInterface,
+		OperatorPrefixRepositoryInterface:       operatorPrefixRepositoryInterface,
+		OrderItemPpobRepositoryInterface:        orderItemPpobRepositoryInterface,
+		PpobDetailRepositoryInterface:           ppobDetailRepositoryInterface,
+		DesaRepositoryInterface:                 desaRepositoryInterface,
+		InveliAPIRepositoryInterface:            inveliAPIRepositoryInterface,
+		ListPinjamanRepositoryInterface:         listPinjamanRepositoryInterface,
+		UserShippingAddressRepositoryInterface:  userShippingAddressRepositoryInterface,
+		OrderItemPackageRepositoryInterface:     orderItemPackageRepositoryInterface,
+		VoucherServiceInterface:                 voucherServiceInterface,
+		PointServiceInterface:                   pointServiceInterface,
+		PpobProviderInterface:                   ppobProviderInterface,
+		PpobPriceServiceInterface:               ppobPriceServiceInterface,
+		PpobProductTypeRepositoryInterface:      ppobProductTypeRepositoryInterface,
+		PpobCallbackLogRepositoryInterface:      ppobCallbackLogRepositoryInterface,
+		OrderStatusHistoryRepositoryInterface:   orderStatusHistoryRepositoryInterface,
+		TransferUniqueAmountRepositoryInterface: transferUniqueAmountRepositoryInterface,
+		ShippingServiceInterface:                shippingServiceInterface,
+		NotificationServiceInterface:            notificationServiceInterface,
 	}
 }
 
@@ -192,50 +228,9 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPln(requestId, idU
 	orderEntity.OrderType = 2
 
 	// Check status transaksi
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "cs"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "checkstatus",
-		"ref_id":   orderRequest.RefId,
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-	// fmt.Println("url = ", urlString)
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	//  cek request
-
-	// reqDump, _ := httputil.DumpRequestOut(req, true)
-	// fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	defer resp.Body.Close()
-
-	trxData := &ppob.PostpaidCheckTransactionPln{}
-	if err = json.Unmarshal([]byte(data), &trxData); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+	trxData, err := service.PpobProviderInterface.CheckStatusPostpaidPln(orderRequest.RefId)
+	if !exceptions.IsPpobTransactionError(err) {
+		exceptions.PanicIfPpobError(err, requestId, service.Logger)
 	}
 
 	detailTagihan, _ := json.Marshal(trxData.Data.Desc.Tagihan.Detail)
@@ -259,7 +254,8 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPln(requestId, idU
 	orderItemsPpob.Admin = trxData.Data.Admin
 	orderItemsPpob.SellingPrice = trxData.Data.SellingPrice
 	orderItemsPpob.CreatedAt = time.Now()
-	orderItemsPpob.BillDetail = fmt.Sprintf("%s\n", data)
+	billDetail, _ := json.Marshal(trxData)
+	orderItemsPpob.BillDetail = string(billDetail)
 
 	ppobDetailPln := &entity.PpobDetailPostpaidPln{}
 	ppobDetailPln.Id = utilities.RandomUUID()
@@ -310,29 +306,11 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPln(requestId, idU
 		orderEntity.PaymentName = "Point"
 		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
 	case "trf":
-		min := 111
-		max := 299
-		rand3Number := rand.Intn(max-min+1) + min
-
-		min2 := 11
-		max2 := 99
-		rand2Number := rand.Intn(max2-min2+1) + min
-
-		sisaPembagi := math.Mod(orderRequest.TotalBill, 1000)
-		var Total float64
-
-		if sisaPembagi < 100 {
-			Total = orderRequest.TotalBill + float64(rand3Number)
-		} else if sisaPembagi >= 100 {
-			Total = orderRequest.TotalBill + float64(rand2Number)
-		}
-
 		orderEntity.OrderStatus = 0
 		orderEntity.PaymentStatus = 0
 		orderEntity.PaymentNo = paymentChannel.NoAccountBank
 		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
 		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
-		orderEntity.PaymentCash = Total
 
 	case "va", "qris":
 		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
@@ -429,6 +407,11 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPln(requestId, idU
 
 	tx := service.DB.Begin()
 
+	// Nominal unik transfer dicadangkan di transaksi order, ikut batal jika order gagal dibuat
+	if orderRequest.PaymentMethod == "trf" {
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
+	}
+
 	// Create Order
 	err = service.OrderRepositoryInterface.CreateOrder(tx, orderEntity)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order"}, service.Logger, tx)
@@ -497,50 +480,9 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPdam(requestId, id
 	orderEntity.OrderType = 2
 
 	// Check status transaksi
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "cs"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "checkstatus",
-		"ref_id":   orderRequest.RefId,
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-	// fmt.Println("url = ", urlString)
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	//  cek request
-
-	// reqDump, _ := httputil.DumpRequestOut(req, true)
-	// fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	defer resp.Body.Close()
-
-	trxData := &ppob.PostpaidCheckTransactionPdam{}
-	if err = json.Unmarshal([]byte(data), &trxData); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+	trxData, err := service.PpobProviderInterface.CheckStatusPostpaidPdam(orderRequest.RefId)
+	if !exceptions.IsPpobTransactionError(err) {
+		exceptions.PanicIfPpobError(err, requestId, service.Logger)
 	}
 
 	detailTagihan, _ := json.Marshal(trxData.Data.Desc.Bill.Detail)
@@ -560,7 +502,8 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPdam(requestId, id
 	orderItemsPpob.TrId = trxData.Data.TrxId
 	orderItemsPpob.RefId = orderRequest.RefId
 	orderItemsPpob.CreatedAt = time.Now()
-	orderItemsPpob.BillDetail = fmt.Sprintf("%s\n", data)
+	billDetail, _ := json.Marshal(trxData)
+	orderItemsPpob.BillDetail = string(billDetail)
 
 	ppobDetailPdam := &entity.PpobDetailPostpaidPdam{}
 	ppobDetailPdam.Id = utilities.RandomUUID()
@@ -612,29 +555,12 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidPdam(requestId, id
 		orderEntity.PaymentName = "Point"
 		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
 	case "trf":
-		min := 111
-		max := 299
-		rand3Number := rand.Intn(max-min+1) + min
-
-		min2 := 11
-		max2 := 99
-		rand2Number := rand.Intn(max2-min2+1) + min
-
-		sisaPembagi := math.Mod(orderRequest.TotalBill, 1000)
-		var Total float64
-
-		if sisaPembagi < 100 {
-			Total = orderRequest.TotalBill + float64(rand3Number)
-		} else if sisaPembagi >= 100 {
-			Total = orderRequest.TotalBill + float64(rand2Number)
-		}
-
 		orderEntity.OrderStatus = 0
 		orderEntity.PaymentStatus = 0
 		orderEntity.PaymentNo = paymentChannel.NoAccountBank
 		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
 		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
-		orderEntity.PaymentCash = Total
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
 
 	case "va", "qris":
 		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
@@ -795,50 +721,9 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidTelco(requestId, i
 	orderEntity.OrderType = 2
 
 	// Check status transaksi
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "cs"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "checkstatus",
-		"ref_id":   orderRequest.RefId,
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-	// fmt.Println("url = ", urlString)
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	// cek request
-
-	// reqDump, _ := httputil.DumpRequestOut(req, true)
-	// fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	defer resp.Body.Close()
-
-	trxData := &ppob.PostpaidCheckTransactionTelco{}
-	if err = json.Unmarshal([]byte(data), &trxData); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+	trxData, err := service.PpobProviderInterface.CheckStatusPostpaidTelco(orderRequest.RefId)
+	if !exceptions.IsPpobTransactionError(err) {
+		exceptions.PanicIfPpobError(err, requestId, service.Logger)
 	}
 
 	detailTagihan, _ := json.Marshal(trxData.Data.Desc.Tagihan)
@@ -858,7 +743,8 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidTelco(requestId, i
 	orderItemsPpob.TrId = trxData.Data.TrxId
 	orderItemsPpob.RefId = orderRequest.RefId
 	orderItemsPpob.CreatedAt = time.Now()
-	orderItemsPpob.BillDetail = fmt.Sprintf("%s\n", data)
+	billDetail, _ := json.Marshal(trxData)
+	orderItemsPpob.BillDetail = string(billDetail)
 
 	ppobDetailTelco := &entity.PpobDetailPostpaidTelco{}
 	ppobDetailTelco.Id = utilities.RandomUUID()
@@ -907,29 +793,12 @@ func (service *OrderServiceImplementation) CreateOrderPostpaidTelco(requestId, i
 		orderEntity.PaymentName = "Point"
 		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
 	case "trf":
-		min := 111
-		max := 299
-		rand3Number := rand.Intn(max-min+1) + min
-
-		min2 := 11
-		max2 := 99
-		rand2Number := rand.Intn(max2-min2+1) + min
-
-		sisaPembagi := math.Mod(orderRequest.TotalBill, 1000)
-		var Total float64
-
-		if sisaPembagi < 100 {
-			Total = orderRequest.TotalBill + float64(rand3Number)
-		} else if sisaPembagi >= 100 {
-			Total = orderRequest.TotalBill + float64(rand2Number)
-		}
-
 		orderEntity.OrderStatus = 0
 		orderEntity.PaymentStatus = 0
 		orderEntity.PaymentNo = paymentChannel.NoAccountBank
 		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
 		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
-		orderEntity.PaymentCash = Total
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
 
 	case "va", "qris":
 		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
@@ -1167,15 +1036,6 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPulsa(requestId, id
 	operator = opereratorPrefixResult.KodeOperator
 
 	// Get Data from iak
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "pl"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"status":   "all",
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
 	var typePpob string
 	if productType == "prepaid_pulsa" {
 		typePpob = "pulsa"
@@ -1183,85 +1043,52 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPulsa(requestId, id
 		typePpob = "data"
 	}
 
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/pricelist/" + typePpob + "/" + operator
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	//  cek request
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	// fmt.Printf("body: %s\n", data)
-
-	defer resp.Body.Close()
-
-	priceLists := &ppob.PrepaidPriceListResponse{}
-	if err = json.Unmarshal([]byte(data), &priceLists); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+	// Harga dari pricelist lokal, totalHarga sudah termasuk margin desa
+	priceList, totalHarga := service.PpobPriceServiceInterface.FindPrepaidProduct(requestId, idDesa, orderRequest.ProductCode)
+	if priceList.ProductType != typePpob || priceList.Operator != operator {
+		exceptions.PanicIfBadRequest(errors.New("product not match"), requestId, []string{"produk tidak sesuai dengan nomor tujuan"}, service.Logger)
 	}
 
-	var totalHarga float64
 	var product []string
 	var qty []int
 	var price []float64
 	orderItemsPpob := &entity.OrderItemPpob{}
+	orderItemsPpob.Id = utilities.RandomUUID()
+	orderItemsPpob.IdOrder = orderEntity.Id
+	orderItemsPpob.IdUser = userProfile.IdUser
+	orderItemsPpob.RefId = orderEntity.RefId
+	orderItemsPpob.ProductCode = priceList.ProductCode
+	orderItemsPpob.ProductType = productType
+	orderItemsPpob.Nominal = priceList.ProductPrice
+	orderItemsPpob.TotalTagihan = priceList.ProductPrice
+	orderItemsPpob.SellingPrice = totalHarga
+	orderItemsPpob.IconUrl = priceList.IconUrl
+	orderItemsPpob.CreatedAt = time.Now()
+	billDetail, _ := json.Marshal(ToPrepaidPriceList(priceList))
+	orderItemsPpob.BillDetail = string(billDetail)
+
 	ppobDetailPrepaidPulsa := &entity.PpobDetailPrepaidPulsa{}
-	for _, priceList := range priceLists.Data.Data {
-		if priceList.ProductCode == orderRequest.ProductCode {
-			totalHarga = priceList.ProductPrice
-			orderItemsPpob.Id = utilities.RandomUUID()
-			orderItemsPpob.IdOrder = orderEntity.Id
-			orderItemsPpob.IdUser = userProfile.IdUser
-			orderItemsPpob.RefId = orderEntity.RefId
-			orderItemsPpob.ProductCode = priceList.ProductCode
-			orderItemsPpob.ProductType = productType
-			orderItemsPpob.TotalTagihan = priceList.ProductPrice
-			orderItemsPpob.IconUrl = priceList.IconUrl
-			orderItemsPpob.CreatedAt = time.Now()
-			orderItemsPpob.BillDetail = fmt.Sprintf("%s\n", data)
-
-			ppobDetailPrepaidPulsa.Id = utilities.RandomUUID()
-			ppobDetailPrepaidPulsa.IdOrderItemPpob = orderItemsPpob.Id
-			ppobDetailPrepaidPulsa.ProductCode = priceList.ProductCode
-			ppobDetailPrepaidPulsa.ProductName = priceList.ProductNominal
-			ppobDetailPrepaidPulsa.ProductDescription = priceList.ProductDescription
-			ppobDetailPrepaidPulsa.CustomerId = orderRequest.CustomerId
-			ppobDetailPrepaidPulsa.Operator = operator
-			ppobDetailPrepaidPulsa.ActivePeriod = priceList.ActivePeriod
-			ppobDetailPrepaidPulsa.IconUrl = priceList.IconUrl
-			ppobDetailPrepaidPulsa.StatusTopUp = -1
-
-			if orderRequest.PaymentMethod == "cc" {
-				product = append(product, orderItemsPpob.ProductCode)
-				qty = append(qty, 1)
-				price = append(price, orderItemsPpob.TotalTagihan)
-			}
-			break
-		}
+	ppobDetailPrepaidPulsa.Id = utilities.RandomUUID()
+	ppobDetailPrepaidPulsa.IdOrderItemPpob = orderItemsPpob.Id
+	ppobDetailPrepaidPulsa.ProductCode = priceList.ProductCode
+	ppobDetailPrepaidPulsa.ProductName = priceList.ProductNominal
+	ppobDetailPrepaidPulsa.ProductDescription = priceList.ProductDescription
+	ppobDetailPrepaidPulsa.CustomerId = orderRequest.CustomerId
+	ppobDetailPrepaidPulsa.Operator = operator
+	ppobDetailPrepaidPulsa.ActivePeriod = priceList.ActivePeriod
+	ppobDetailPrepaidPulsa.IconUrl = priceList.IconUrl
+	ppobDetailPrepaidPulsa.StatusTopUp = -1
+
+	if orderRequest.PaymentMethod == "cc" {
+		product = append(product, orderItemsPpob.ProductCode)
+		qty = append(qty, 1)
+		price = append(price, orderItemsPpob.SellingPrice)
 	}
 
 	fmt.Println("Total Harga = ", totalHarga)
 	fmt.Println("Total Harga request = ", orderRequest.TotalBill)
 
-	if ((totalHarga + 1500) + orderRequest.PaymentFee) != (orderRequest.TotalBill + orderRequest.PaymentFee + orderEntity.PaymentPoint) {
+	if (totalHarga + orderRequest.PaymentFee) != (orderRequest.TotalBill + orderRequest.PaymentFee + orderEntity.PaymentPoint) {
 		exceptions.PanicIfRecordNotFound(errors.New("harga tidak sama"), requestId, []string{"harga tidak sama"}, service.Logger)
 	}
 
@@ -1288,29 +1115,11 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPulsa(requestId, id
 		orderEntity.PaymentName = "Point"
 		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
 	case "trf":
-		min := 111
-		max := 299
-		rand3Number := rand.Intn(max-min+1) + min
-
-		min2 := 11
-		max2 := 99
-		rand2Number := rand.Intn(max2-min2+1) + min
-
-		sisaPembagi := math.Mod(orderRequest.TotalBill, 1000)
-		var Total float64
-
-		if sisaPembagi < 100 {
-			Total = orderRequest.TotalBill + float64(rand3Number)
-		} else if sisaPembagi >= 100 {
-			Total = orderRequest.TotalBill + float64(rand2Number)
-		}
-
 		orderEntity.OrderStatus = 0
 		orderEntity.PaymentStatus = 0
 		orderEntity.PaymentNo = paymentChannel.NoAccountBank
 		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
 		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
-		orderEntity.PaymentCash = Total
 
 	case "va", "qris":
 		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
@@ -1406,6 +1215,12 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPulsa(requestId, id
 	}
 
 	tx := service.DB.Begin()
+
+	// Nominal unik transfer dicadangkan di transaksi order, ikut batal jika order gagal dibuat
+	if orderRequest.PaymentMethod == "trf" {
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
+	}
+
 	// Create Order
 	err = service.OrderRepositoryInterface.CreateOrder(tx, orderEntity)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order"}, service.Logger, tx)
@@ -1472,95 +1287,52 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPln(requestId, idUs
 	// Create Request
 	inquiryPlnData := service.OrderInquiryPrepaidPln(requestId, orderRequest.CustomerId)
 	// Get Data from iak
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "pl"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"status":   "all",
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	typePpob := "pln"
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/pricelist/" + typePpob + "/" + typePpob
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	//  cek request
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	// fmt.Printf("body: %s\n", data)
-
-	defer resp.Body.Close()
-
-	priceLists := &ppob.PrepaidPriceListResponse{}
-	if err = json.Unmarshal([]byte(data), &priceLists); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+	// Harga dari pricelist lokal, totalHarga sudah termasuk margin desa
+	priceList, totalHarga := service.PpobPriceServiceInterface.FindPrepaidProduct(requestId, idDesa, orderRequest.ProductCode)
+	if priceList.ProductType != "pln" {
+		exceptions.PanicIfBadRequest(errors.New("product not match"), requestId, []string{"produk bukan token pln"}, service.Logger)
 	}
 
-	var totalHarga float64
 	var product []string
 	var qty []int
 	var price []float64
 	orderItemsPpob := &entity.OrderItemPpob{}
+	orderItemsPpob.Id = utilities.RandomUUID()
+	orderItemsPpob.IdOrder = orderEntity.Id
+	orderItemsPpob.IdUser = userProfile.IdUser
+	orderItemsPpob.RefId = orderEntity.RefId
+	orderItemsPpob.ProductCode = priceList.ProductCode
+	orderItemsPpob.ProductType = productType
+	orderItemsPpob.Nominal = priceList.ProductPrice
+	orderItemsPpob.TotalTagihan = priceList.ProductPrice
+	orderItemsPpob.SellingPrice = totalHarga
+	orderItemsPpob.IconUrl = priceList.IconUrl
+	orderItemsPpob.CreatedAt = time.Now()
+	billDetail, _ := json.Marshal(ToPrepaidPriceList(priceList))
+	orderItemsPpob.BillDetail = string(billDetail)
+
 	ppobDetailPrepaidPln := &entity.PpobDetailPrepaidPln{}
-	for _, priceList := range priceLists.Data.Data {
-		if priceList.ProductCode == orderRequest.ProductCode {
-			totalHarga = priceList.ProductPrice
-			orderItemsPpob.Id = utilities.RandomUUID()
-			orderItemsPpob.IdOrder = orderEntity.Id
-			orderItemsPpob.IdUser = userProfile.IdUser
-			orderItemsPpob.RefId = orderEntity.RefId
-			orderItemsPpob.ProductCode = priceList.ProductCode
-			orderItemsPpob.ProductType = productType
-			orderItemsPpob.TotalTagihan = priceList.ProductPrice
-			orderItemsPpob.IconUrl = priceList.IconUrl
-			orderItemsPpob.CreatedAt = time.Now()
-			orderItemsPpob.BillDetail = fmt.Sprintf("%s\n", data)
-
-			ppobDetailPrepaidPln.Id = utilities.RandomUUID()
-			ppobDetailPrepaidPln.IdOrderItemPpob = orderItemsPpob.Id
-			ppobDetailPrepaidPln.ProductCode = priceList.ProductCode
-			ppobDetailPrepaidPln.ProductName = priceList.ProductNominal
-			ppobDetailPrepaidPln.ProductDescription = priceList.ProductDescription
-			ppobDetailPrepaidPln.CustomerId = orderRequest.CustomerId
-			ppobDetailPrepaidPln.MeterNo = inquiryPlnData.MeterNo
-			ppobDetailPrepaidPln.SubscriberId = inquiryPlnData.SubscriberId
-			ppobDetailPrepaidPln.CustomerName = inquiryPlnData.Name
-			ppobDetailPrepaidPln.SegmentPower = inquiryPlnData.SegmentPower
-			ppobDetailPrepaidPln.StatusTopUp = -1
-			if orderRequest.PaymentMethod == "cc" {
-				product = append(product, orderItemsPpob.ProductCode)
-				qty = append(qty, 1)
-				price = append(price, orderItemsPpob.TotalTagihan)
-			}
-			break
-		}
+	ppobDetailPrepaidPln.Id = utilities.RandomUUID()
+	ppobDetailPrepaidPln.IdOrderItemPpob = orderItemsPpob.Id
+	ppobDetailPrepaidPln.ProductCode = priceList.ProductCode
+	ppobDetailPrepaidPln.ProductName = priceList.ProductNominal
+	ppobDetailPrepaidPln.ProductDescription = priceList.ProductDescription
+	ppobDetailPrepaidPln.CustomerId = orderRequest.CustomerId
+	ppobDetailPrepaidPln.MeterNo = inquiryPlnData.MeterNo
+	ppobDetailPrepaidPln.SubscriberId = inquiryPlnData.SubscriberId
+	ppobDetailPrepaidPln.CustomerName = inquiryPlnData.Name
+	ppobDetailPrepaidPln.SegmentPower = inquiryPlnData.SegmentPower
+	ppobDetailPrepaidPln.StatusTopUp = -1
+	if orderRequest.PaymentMethod == "cc" {
+		product = append(product, orderItemsPpob.ProductCode)
+		qty = append(qty, 1)
+		price = append(price, orderItemsPpob.SellingPrice)
 	}
 
 	fmt.Println("Total Harga = ", totalHarga)
 	fmt.Println("Total Harga request = ", orderRequest.TotalBill)
 
-	if ((totalHarga + 1500) + orderRequest.PaymentFee) != (orderRequest.TotalBill + orderRequest.PaymentFee + orderEntity.PaymentPoint) {
+	if (totalHarga + orderRequest.PaymentFee) != (orderRequest.TotalBill + orderRequest.PaymentFee + orderEntity.PaymentPoint) {
 		exceptions.PanicIfRecordNotFound(errors.New("harga tidak sama"), requestId, []string{"harga tidak sama"}, service.Logger)
 	}
 
@@ -1588,29 +1360,11 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPln(requestId, idUs
 		orderEntity.PaymentName = "Point"
 		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
 	case "trf":
-		min := 111
-		max := 299
-		rand3Number := rand.Intn(max-min+1) + min
-
-		min2 := 11
-		max2 := 99
-		rand2Number := rand.Intn(max2-min2+1) + min
-
-		sisaPembagi := math.Mod(orderRequest.TotalBill, 1000)
-		var Total float64
-
-		if sisaPembagi < 100 {
-			Total = orderRequest.TotalBill + float64(rand3Number)
-		} else if sisaPembagi >= 100 {
-			Total = orderRequest.TotalBill + float64(rand2Number)
-		}
-
 		orderEntity.OrderStatus = 0
 		orderEntity.PaymentStatus = 0
 		orderEntity.PaymentNo = paymentChannel.NoAccountBank
 		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
 		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
-		orderEntity.PaymentCash = Total
 
 	case "va", "qris":
 		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
@@ -1707,6 +1461,11 @@ func (service *OrderServiceImplementation) CreateOrderPrepaidPln(requestId, idUs
 
 	tx := service.DB.Begin()
 
+	// Nominal unik transfer dicadangkan di transaksi order, ikut batal jika order gagal dibuat
+	if orderRequest.PaymentMethod == "trf" {
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
+	}
+
 	// Create Order
 	err = service.OrderRepositoryInterface.CreateOrder(tx, orderEntity)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order"}, service.Logger, tx)
@@ -1749,7 +1508,15 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 	}
 
 	// Get data user shipping status
-	userShippingAddress, _ := service.UserShippingAddressRepositoryInterface.FindUserShippingAddressByAddress(service.DB, orderRequest.AlamatPengiriman)
+	// Alamat dirujuk dengan id dan harus milik user yang login
+	userShippingAddress, err := service.UserShippingAddressRepositoryInterface.FindUserShippingAddressByIdAndIdUser(service.DB, orderRequest.IdUserShippingAddress, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(userShippingAddress.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("shipping address not found"), requestId, []string{"alamat pengiriman tidak ditemukan"}, service.Logger)
+	}
+
+	// Ongkir dihitung ulang di server dari jarak depot ke alamat, nilai dari client diabaikan
+	shippingCost := service.ShippingServiceInterface.CalculateShippingCost(requestId, idDesa, userShippingAddress)
 
 	// Get data user cart
 	userCartItems, err := service.CartRepositoryInterface.FindCartByUser(service.DB, userProfile.User.Id)
@@ -1758,6 +1525,16 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 		exceptions.PanicIfRecordNotFound(errors.New("items in cart not found"), requestId, []string{"items in cart not found"}, service.Logger)
 	}
 
+	// Cek stok produk dan komponen paket
+	service.ProductDesaServiceInterface.CheckProductStock(requestId, userCartItems)
+	units := service.ProductDesaServiceInterface.FindProductUnits(requestId)
+
+	var idProductDesas []string
+	for _, item := range userCartItems {
+		idProductDesas = append(idProductDesas, item.IdProductDesa)
+	}
+	priceTiers := service.ProductDesaServiceInterface.FindPriceTiers(requestId, idProductDesas)
+
 	// make object
 	orderEntity := &entity.Order{}
 
@@ -1770,9 +1547,9 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 	orderEntity.NamaLengkap = userProfile.NamaLengkap
 	orderEntity.Email = userProfile.Email
 	orderEntity.Phone = userProfile.User.Phone
-	orderEntity.AlamatPengiriman = orderRequest.AlamatPengiriman
+	orderEntity.AlamatPengiriman = userShippingAddress.AlamatPengiriman
 	orderEntity.Catatan = orderRequest.CatatanKurir
-	orderEntity.ShippingCost = orderRequest.ShippingCost
+	orderEntity.ShippingCost = shippingCost
 	orderEntity.ProductType = "sembako"
 	orderEntity.PaymentPoint = orderRequest.PaymentPoint
 	orderEntity.OrderedDate = time.Now()
@@ -1787,6 +1564,7 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 
 	// create order items from cart
 	var orderItems []entity.OrderItem
+	var orderItemPackages []entity.OrderItemPackage
 	var product []string
 	var qty []int
 	var price []float64
@@ -1800,23 +1578,53 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 		orderItemsEntity.ProductName = item.ProductsDesa.ProductsMaster.ProductName
 		orderItemsEntity.PictureUrl = item.ProductsDesa.ProductsMaster.PictureUrl
 		orderItemsEntity.Thumbnail = item.ProductsDesa.ProductsMaster.Thumbnail
-		orderItemsEntity.FlagPromo = item.ProductsDesa.IsPromo
 		orderItemsEntity.Description = item.ProductsDesa.ProductsMaster.Description
 		orderItemsEntity.Qty = item.Qty
-		if accountType == 1 {
-			orderItemsEntity.Price = item.ProductsDesa.Price
-			if item.ProductsDesa.IsPromo == 1 {
-				orderItemsEntity.PriceAfterDiscount = item.ProductsDesa.PricePromo
-				orderItemsEntity.TotalPrice = float64(orderItemsEntity.Qty) * item.ProductsDesa.PricePromo
-			} else if item.ProductsDesa.IsPromo == 0 {
-				orderItemsEntity.PriceAfterDiscount = 0
-				orderItemsEntity.TotalPrice = float64(orderItemsEntity.Qty) * orderItemsEntity.Price
+		orderItemsEntity.CreatedAt = time.Now()
+
+		var packageItems []entity.ProductsPackageItems
+		if item.ProductsDesa.IdType == 2 {
+			packageItems = service.ProductDesaServiceInterface.FindPackageItems(requestId, item.IdProductDesa)
+		}
+
+		// Harga mengikuti tipe akun, promo dan tier grosir seperti yang tampil di keranjang
+		quote := QuotePrice(&item.ProductsDesa, accountType, item.Qty, priceTiers, packageItems)
+		orderItemsEntity.FlagPromo = quote.FlagPromo
+		orderItemsEntity.Price = quote.Price
+		orderItemsEntity.PriceAfterDiscount = quote.PricePromo
+		orderItemsEntity.TotalPrice = quote.TotalPrice
+		orderItemsEntity.IdPriceTier = quote.IdPriceTier
+		orderItemsEntity.TierMinQty = quote.TierMinQty
+
+		// Berat dan volume total untuk qty item, paket dihitung dari komponennya
+		if item.ProductsDesa.IdType == 2 {
+			var packageWeight, packageVolume float64
+			for _, packageItem := range packageItems {
+				weight, volume := ProductWeightVolume(units, &packageItem.ProductsDesa.ProductsMaster)
+				packageWeight = packageWeight + weight*float64(packageItem.Qty)
+				packageVolume = packageVolume + volume*float64(packageItem.Qty)
+				orderItemPackages = append(orderItemPackages, entity.OrderItemPackage{
+					Id:            utilities.RandomUUID(),
+					IdOrder:       orderEntity.Id,
+					IdOrderItem:   orderItemsEntity.Id,
+					IdProductDesa: packageItem.IdProductItem,
+					NoSku:         packageItem.NoSku,
+					ProductName:   packageItem.ProductName,
+					QtyPerPackage: packageItem.Qty,
+					Qty:           packageItem.Qty * orderItemsEntity.Qty,
+					Price:         packageItem.Price,
+					Weight:        weight * float64(packageItem.Qty*orderItemsEntity.Qty),
+					Volume:        volume * float64(packageItem.Qty*orderItemsEntity.Qty),
+					CreatedAt:     time.Now(),
+				})
 			}
-		} else if accountType == 2 {
-			orderItemsEntity.Price = item.ProductsDesa.ProductsMaster.PriceGrosir
-			orderItemsEntity.TotalPrice = float64(orderItemsEntity.Qty) * orderItemsEntity.Price
+			orderItemsEntity.Weight = packageWeight * float64(orderItemsEntity.Qty)
+			orderItemsEntity.Volume = packageVolume * float64(orderItemsEntity.Qty)
+		} else {
+			weight, volume := ProductWeightVolume(units, &item.ProductsDesa.ProductsMaster)
+			orderItemsEntity.Weight = weight * float64(orderItemsEntity.Qty)
+			orderItemsEntity.Volume = volume * float64(orderItemsEntity.Qty)
 		}
-		orderItemsEntity.CreatedAt = time.Now()
 
 		totalPrice = totalPrice + orderItemsEntity.TotalPrice
 		orderItems = append(orderItems, *orderItemsEntity)
@@ -1828,20 +1636,38 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 	}
 	orderEntity.SubTotal = totalPrice
 
+	// Voucher divalidasi ulang di server, potongan disimpan di order
+	var voucher *entity.Voucher
+	if len(orderRequest.VoucherCode) != 0 {
+		voucher, orderEntity.Discount, orderEntity.ShippingDiscount = service.VoucherServiceInterface.CalculateVoucher(requestId, idUser, idDesa, orderRequest.VoucherCode, totalPrice, shippingCost)
+		orderEntity.IdVoucher = voucher.Id
+		orderEntity.VoucherCode = voucher.Code
+		totalPrice = totalPrice - orderEntity.Discount - orderEntity.ShippingDiscount
+	}
+
 	// Checking total bill from FE
-	log.Println("Harga kalkulasi server 1 = ", totalPrice+orderRequest.ShippingCost)
+	log.Println("Harga kalkulasi server 1 = ", totalPrice+shippingCost)
 	log.Println("Harga dari client 1 = ", orderRequest.TotalBill+orderRequest.PaymentPoint)
-	if (totalPrice + orderRequest.ShippingCost) != (orderRequest.TotalBill + orderRequest.PaymentPoint) {
+	if (totalPrice + shippingCost) != (orderRequest.TotalBill + orderRequest.PaymentPoint) {
 		exceptions.PanicIfRecordNotFound(errors.New("harga tidak sama"), requestId, []string{"harga tidak sama"}, service.Logger)
 	}
 
-	log.Println("Harga kalkulasi server 2 = ", totalPrice+orderRequest.ShippingCost+orderRequest.PaymentFee)
+	log.Println("Harga kalkulasi server 2 = ", totalPrice+shippingCost+orderRequest.PaymentFee)
 	log.Println("Harga dari client 2 = ", (orderRequest.TotalBill+orderRequest.PaymentFee)+orderRequest.PaymentPoint)
 	// Checking total payment from FE
-	if (totalPrice + orderRequest.ShippingCost + orderRequest.PaymentFee) != ((orderRequest.TotalBill + orderRequest.PaymentFee) + orderRequest.PaymentPoint) {
+	if (totalPrice + shippingCost + orderRequest.PaymentFee) != ((orderRequest.TotalBill + orderRequest.PaymentFee) + orderRequest.PaymentPoint) {
 		exceptions.PanicIfRecordNotFound(errors.New("harga tidak sama dengan payment cash"), requestId, []string{"harga tidak sama dengan payment cash"}, service.Logger)
 	}
 
+	// Point yang dipakai, metode point berarti seluruh tagihan dibayar dengan point
+	redeemPoint := orderRequest.PaymentPoint
+	if orderRequest.PaymentMethod == "point" {
+		redeemPoint = redeemPoint + orderRequest.TotalBill
+	}
+	if redeemPoint > 0 {
+		service.PointServiceInterface.CheckPointBalance(requestId, idUser, redeemPoint)
+	}
+
 	// Get detail payment channel
 	paymentChannel, err := service.PaymentChannelRepositoryInterface.FindPaymentChannelByCode(service.DB, orderRequest.PaymentChannel)
 	if err != nil {
@@ -1858,6 +1684,15 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 		exceptions.PanicIfRecordNotFound(errors.New("desa account paylater not found"), requestId, []string{"desa account paylater not found"}, service.Logger)
 	}
 
+	// Voucher dan point dipotong sebelum pembayaran ke pihak luar, dikembalikan jika order gagal dibuat
+	orderCreated := false
+	service.reserveOrderBenefit(requestId, orderEntity, voucher, redeemPoint)
+	defer func() {
+		if !orderCreated {
+			service.releaseOrderBenefit(requestId, orderEntity, voucher, redeemPoint)
+		}
+	}()
+
 	switch orderRequest.PaymentMethod {
 	case "cod":
 		orderEntity.OrderStatus = 1
@@ -1869,29 +1704,11 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 		orderEntity.PaymentName = "Point"
 		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
 	case "trf":
-		min := 111
-		max := 299
-		rand3Number := rand.Intn(max-min+1) + min
-
-		min2 := 11
-		max2 := 99
-		rand2Number := rand.Intn(max2-min2+1) + min
-
-		sisaPembagi := math.Mod(orderRequest.TotalBill, 1000)
-		var Total float64
-
-		if sisaPembagi < 100 {
-			Total = orderRequest.TotalBill + float64(rand3Number)
-		} else if sisaPembagi >= 100 {
-			Total = orderRequest.TotalBill + float64(rand2Number)
-		}
-
 		orderEntity.OrderStatus = 0
 		orderEntity.PaymentStatus = 0
 		orderEntity.PaymentNo = paymentChannel.NoAccountBank
 		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
 		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
-		orderEntity.PaymentCash = Total
 
 	case "va", "qris":
 		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
@@ -1924,7 +1741,12 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 		// tambahkan ongkos kirim
 		product = append(product, "Shipping Cost", "Payment Fee")
 		qty = append(qty, 1, 1)
-		price = append(price, orderRequest.ShippingCost, orderRequest.PaymentFee)
+		price = append(price, shippingCost, orderRequest.PaymentFee)
+		if voucher != nil {
+			product = append(product, "Voucher "+voucher.Code)
+			qty = append(qty, 1)
+			price = append(price, -(orderEntity.Discount + orderEntity.ShippingDiscount))
+		}
 
 		res := service.PaymentServiceInterface.CreditCardPay(requestId,
 			&payment.IpaymuCreditCardRequest{
@@ -1988,6 +1810,12 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 
 	// Create Order
 	tx := service.DB.Begin()
+
+	// Nominal unik transfer dicadangkan di transaksi order, ikut batal jika order gagal dibuat
+	if orderRequest.PaymentMethod == "trf" {
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
+	}
+
 	err = service.OrderRepositoryInterface.CreateOrder(tx, orderEntity)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order"}, service.Logger, tx)
 
@@ -1995,6 +1823,12 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 	err = service.OrderItemRepositoryInterface.CreateOrderItem(tx, orderItems)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order items"}, service.Logger, tx)
 
+	// Create rincian komponen paket
+	if len(orderItemPackages) != 0 {
+		err = service.OrderItemPackageRepositoryInterface.CreateOrderItemPackage(tx, orderItemPackages)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order item packages"}, service.Logger, tx)
+	}
+
 	// Delete items in cart
 	err = service.CartRepositoryInterface.DeleteCartByUser(tx, idUser, userCartItems)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error delete items in cart"}, service.Logger, tx)
@@ -2004,8 +1838,9 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 		service.ProductDesaServiceInterface.UpdateProductStock(requestId, orderEntity.Id, tx)
 	}
 
-	commit := tx.Commit()
+	commit := repository.CommitTransaction(tx)
 	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+	orderCreated = true
 
 	runtime.GOMAXPROCS(1)
 	mssg := "Order Sembako Baru Dari " + userProfile.NamaLengkap + " ID Order " + orderEntity.NumberOrder + " VIA " + paymentChannel.Alias
@@ -2015,6 +1850,49 @@ func (service *OrderServiceImplementation) CreateOrderSembako(requestId, idUser,
 	return createOrderResponse
 }
 
+// reserveOrderBenefit memakai voucher dan point di transaksi sendiri sebelum pembayaran ke pihak luar,
+// sehingga quota atau saldo yang tidak cukup ditolak sebelum user terlanjur ditagih
+func (service *OrderServiceImplementation) reserveOrderBenefit(requestId string, order *entity.Order, voucher *entity.Voucher, redeemPoint float64) {
+	if voucher == nil && redeemPoint <= 0 {
+		return
+	}
+
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	if voucher != nil {
+		service.VoucherServiceInterface.UseVoucher(requestId, tx, voucher, order.IdUser, order.Id, order.Discount, order.ShippingDiscount)
+	}
+
+	if redeemPoint > 0 {
+		service.PointServiceInterface.RedeemPoint(requestId, tx, order.IdUser, order.Id, redeemPoint, "Pembayaran order "+order.NumberOrder)
+	}
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+}
+
+// releaseOrderBenefit mengembalikan voucher dan point jika order gagal dibuat setelah reserveOrderBenefit
+func (service *OrderServiceImplementation) releaseOrderBenefit(requestId string, order *entity.Order, voucher *entity.Voucher, redeemPoint float64) {
+	if voucher == nil && redeemPoint <= 0 {
+		return
+	}
+
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	if voucher != nil {
+		service.VoucherServiceInterface.ReleaseVoucher(requestId, tx, order.Id)
+	}
+
+	if redeemPoint > 0 {
+		service.PointServiceInterface.ReverseOrderPoint(requestId, tx, order.Id)
+	}
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+}
+
 func (service *OrderServiceImplementation) SendMessageToTelegram(message, chatId, token string) {
 	url, _ := url.Parse("https://api.telegram.org/bot" + token + "/sendMessage?chat_id=" + chatId + "&text=" + message)
 
@@ -2044,15 +1922,21 @@ func (service *OrderServiceImplementation) FindOrderByUser(requestId, idUser str
 	return orderResponses
 }
 
-func (service *OrderServiceImplementation) FindOrderSembakoById(requestId, idOrder string) (orderResponse response.FindOrderSembakoByIdResponse) {
-	var err error
-
-	// Get order by id order
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, idOrder)
+// findOrderByIdUser order milik user yang login, order milik user atau desa lain dibalas 404 yang sama
+func (service *OrderServiceImplementation) findOrderByIdUser(requestId, idUser, idDesa, idOrder string) *entity.Order {
+	order, err := service.OrderRepositoryInterface.FindOrderByIdUser(service.DB, idOrder, idUser, idDesa)
 	exceptions.PanicIfError(err, requestId, service.Logger)
 	if len(order.Id) == 0 {
 		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
 	}
+	return order
+}
+
+func (service *OrderServiceImplementation) FindOrderSembakoById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderSembakoByIdResponse) {
+	var err error
+
+	// Get order by id order
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
 
 	// Get order items by id oder
 	orderItems, err := service.OrderItemRepositoryInterface.FindOrderItemsByIdOrder(service.DB, idOrder)
@@ -2068,19 +1952,19 @@ func (service *OrderServiceImplementation) FindOrderSembakoById(requestId, idOrd
 		exceptions.PanicIfRecordNotFound(errors.New("payment not found"), requestId, []string{"order item not found"}, service.Logger)
 	}
 
-	orderResponse = response.ToFindOrderSembakoByIdResponse(order, orderItems, payment)
+	// Rincian komponen paket
+	orderItemPackages, err := service.OrderItemPackageRepositoryInterface.FindOrderItemPackageByIdOrder(service.DB, idOrder)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	orderResponse = response.ToFindOrderSembakoByIdResponse(order, orderItems, orderItemPackages, payment)
 	return orderResponse
 }
 
-func (service *OrderServiceImplementation) FindOrderPaymentById(requestId, idOrder string) (orderResponse response.OrderPayment) {
+func (service *OrderServiceImplementation) FindOrderPaymentById(requestId, idUser, idDesa, idOrder string) (orderResponse response.OrderPayment) {
 	var err error
 
 	// Get order by id order
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, idOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
-	}
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
 
 	// Payment
 	payment, err := service.PaymentChannelRepositoryInterface.FindPaymentChannelByCode(service.DB, order.PaymentChannel)
@@ -2093,13 +1977,12 @@ func (service *OrderServiceImplementation) FindOrderPaymentById(requestId, idOrd
 	return orderResponse
 }
 
-func (service *OrderServiceImplementation) FindOrderPrepaidPulsaById(requestId, idOrder string, productType string) (orderResponse response.FindOrderPrepaidPulsaByIdResponse) {
+func (service *OrderServiceImplementation) FindOrderPrepaidPulsaById(requestId, idUser, idDesa, idOrder string, productType string) (orderResponse response.FindOrderPrepaidPulsaByIdResponse) {
 	var err error
 
 	// Get order by id order
-	order, err := service.OrderRepositoryInterface.FindOrderPrepaidPulsaById(service.DB, idOrder, productType)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
+	if order.ProductType != productType {
 		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
 	}
 
@@ -2128,15 +2011,11 @@ func (service *OrderServiceImplementation) FindOrderPrepaidPulsaById(requestId,
 	return orderResponse
 }
 
-func (service *OrderServiceImplementation) FindOrderPrepaidPlnById(requestId, idOrder string) (orderResponse response.FindOrderPrepaidPlnByIdResponse) {
+func (service *OrderServiceImplementation) FindOrderPrepaidPlnById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPrepaidPlnByIdResponse) {
 	var err error
 
 	// Get order by id order
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, idOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
-	}
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
 
 	// order items ppob
 	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
@@ -2163,15 +2042,11 @@ func (service *OrderServiceImplementation) FindOrderPrepaidPlnById(requestId, id
 	return orderResponse
 }
 
-func (service *OrderServiceImplementation) FindOrderPostpaidPlnById(requestId, idOrder string) (orderResponse response.FindOrderPostpaidPlnByIdResponse) {
+func (service *OrderServiceImplementation) FindOrderPostpaidPlnById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPostpaidPlnByIdResponse) {
 	var err error
 
 	// Get order by id order
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, idOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
-	}
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
 
 	// order items ppob
 	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
@@ -2205,15 +2080,11 @@ func (service *OrderServiceImplementation) FindOrderPostpaidPlnById(requestId, i
 	return orderResponse
 }
 
-func (service *OrderServiceImplementation) FindOrderPostpaidPdamById(requestId, idOrder string) (orderResponse response.FindOrderPostpaidPdamByIdResponse) {
+func (service *OrderServiceImplementation) FindOrderPostpaidPdamById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPostpaidPdamByIdResponse) {
 	var err error
 
 	// Get order by id order
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, idOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
-	}
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
 
 	// order items ppob
 	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
@@ -2246,50 +2117,118 @@ func (service *OrderServiceImplementation) FindOrderPostpaidPdamById(requestId,
 	return orderResponse
 }
 
-func (service *OrderServiceImplementation) CancelOrderById(requestId string, orderRequest *request.OrderIdRequest) {
-	var err error
+func (service *OrderServiceImplementation) CancelOrderById(requestId, idUser, idDesa string, orderRequest *request.OrderIdRequest) {
 	//Get order detail
 	request.ValidateRequest(service.Validate, orderRequest, requestId, service.Logger)
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, orderRequest.IdOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, orderRequest.IdOrder)
+
+	if order.OrderStatus == 9 {
+		exceptions.PanicIfBadRequest(errors.New("order already canceled"), requestId, []string{"order sudah dibatalkan"}, service.Logger)
+	}
+
+	// Order yang sudah dikirim kurir tidak bisa dibatalkan
+	if order.OrderStatus >= 3 {
+		exceptions.PanicIfBadRequest(errors.New("order already shipped"), requestId, []string{"order sudah dikirim"}, service.Logger)
 	}
 
-	// Update status order
-	err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, orderRequest.IdOrder, &entity.Order{
+	// Order ppob sudah diteruskan ke provider, hasilnya ditentukan callback atau rekonsiliasi
+	if order.OrderType == 2 {
+		exceptions.PanicIfBadRequest(errors.New("ppob order cannot be canceled"), requestId, []string{"order ppob tidak bisa dibatalkan"}, service.Logger)
+	}
+
+	// Order yang sudah dibayar stoknya sudah dikurangi dan pembayarannya perlu refund
+	if order.PaymentStatus == 1 {
+		exceptions.PanicIfBadRequest(errors.New("order already paid"), requestId, []string{"order sudah dibayar"}, service.Logger)
+	}
+
+	service.cancelOrder(requestId, idUser, order, "Dibatalkan oleh customer")
+}
+
+// cancelOrder batalkan order dan kembalikan nominal unik transfer, voucher dan point dalam satu transaksi
+func (service *OrderServiceImplementation) cancelOrder(requestId, idUser string, order *entity.Order, note string) {
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	// Update status hanya jika status belum berubah sejak dibaca, pembatalan bersamaan hanya diproses sekali
+	rowsAffected, err := service.OrderRepositoryInterface.UpdateOrderByIdOrderAndStatus(tx, order.Id, order.OrderStatus, &entity.Order{
 		OrderStatus:       9,
 		PaymentStatus:     9,
 		OrderCanceledDate: null.NewTime(time.Now(), true),
 	})
-	exceptions.PanicIfError(err, requestId, service.Logger)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update order"}, service.Logger, tx)
+	if rowsAffected != 1 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("order status changed"), requestId, []string{"status order sudah berubah"}, service.Logger)
+	}
+
+	err = service.OrderStatusHistoryRepositoryInterface.CreateOrderStatusHistory(tx, &entity.OrderStatusHistory{
+		Id:          utilities.RandomUUID(),
+		IdOrder:     order.Id,
+		IdUser:      idUser,
+		OrderStatus: 9,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order status history"}, service.Logger, tx)
+
+	// Nominal unik transfer bisa dipakai order lain
+	service.releaseTransferUniqueAmount(requestId, tx, order)
+
+	// Kembalikan quota voucher
+	if len(order.IdVoucher) != 0 {
+		service.VoucherServiceInterface.ReleaseVoucher(requestId, tx, order.Id)
+	}
+
+	// Kembalikan point yang dipakai dan tarik bonus point
+	service.PointServiceInterface.ReverseOrderPoint(requestId, tx, order.Id)
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
 }
 
-func (service *OrderServiceImplementation) CompleteOrderById(requestId string, orderRequest *request.OrderIdRequest) {
-	var err error
+func (service *OrderServiceImplementation) CompleteOrderById(requestId, idUser, idDesa string, orderRequest *request.OrderIdRequest) {
 	//Get order detail
 	request.ValidateRequest(service.Validate, orderRequest, requestId, service.Logger)
-	order, err := service.OrderRepositoryInterface.FindOrderById(service.DB, orderRequest.IdOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
-	}
-
-	tx := service.DB.Begin()
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, orderRequest.IdOrder)
 
 	// Check apakah order sudah sampai ditujuan
 	if order.OrderStatus != 4 {
 		exceptions.PanicIfBadRequest(errors.New("orderan belum sampai di tujuan"), requestId, []string{"orderan blum sampai di tujuan"}, service.Logger)
 	}
 
+	service.CompleteDeliveredOrder(requestId, idUser, order, "Dikonfirmasi customer")
+}
+
+// CompleteDeliveredOrder selesaikan order yang sudah sampai, dipakai konfirmasi customer
+// dan auto complete setelah beberapa hari
+func (service *OrderServiceImplementation) CompleteDeliveredOrder(requestId, idUser string, order *entity.Order, note string) {
+	tx := service.DB.Begin()
+
 	// Update status order menjadi selesai
-	err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(tx, orderRequest.IdOrder, &entity.Order{
+	rowsAffected, err := service.OrderRepositoryInterface.UpdateOrderByIdOrderAndStatus(tx, order.Id, 4, &entity.Order{
 		OrderStatus:        5,
 		OrderCompletedDate: null.NewTime(time.Now(), true),
 	})
-	exceptions.PanicIfError(err, requestId, service.Logger)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update order"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("order already completed"), requestId, []string{"order sudah selesai"}, service.Logger)
+	}
+
+	err = service.OrderStatusHistoryRepositoryInterface.CreateOrderStatusHistory(tx, &entity.OrderStatusHistory{
+		Id:          utilities.RandomUUID(),
+		IdOrder:     order.Id,
+		IdUser:      idUser,
+		OrderStatus: 5,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order status history"}, service.Logger, tx)
 
-	// Script untuk bonus point
+	// Bonus point sesuai aturan desa
+	if order.OrderType == 1 {
+		service.PointServiceInterface.EarnOrderPoint(requestId, tx, order)
+	}
 
 	commit := tx.Commit()
 	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
@@ -2315,537 +2254,286 @@ func (service *OrderServiceImplementation) UpdatePaymentStatusOrder(requestId st
 
 	paymentStatus := service.PaymentServiceInterface.CheckPaymentStatus(requestId, updatePaymentStatusOrderRequest.TrxId)
 
-	if paymentStatus.Status == 1 || paymentStatus.Status == 6 {
-		if order.OrderType == 1 {
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:        1,
-				PaymentStatus:      1,
-				PaymentSuccessDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-			service.ProductDesaServiceInterface.UpdateProductStock(requestId, order.Id, service.DB)
-
-			// Update stock
-		} else if order.OrderType == 2 {
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:        2,
-				PaymentStatus:      1,
-				PaymentSuccessDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-			orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
-			exceptions.PanicIfError(err, requestId, service.Logger)
-			if len(orderItemsPpob.Id) == 0 {
-				exceptions.PanicIfBadRequest(errors.New("order items ppob not found"), requestId, []string{"order items ppob not found"}, service.Logger)
-			}
-
-			switch order.ProductType {
-			case "prepaid_pulsa", "prepaid_data":
-				ppobDetailPrepaidPulsa, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPulsaById(service.DB, orderItemsPpob.Id)
-				exceptions.PanicIfError(err, requestId, service.Logger)
-				if len(ppobDetailPrepaidPulsa.Id) == 0 {
-					exceptions.PanicIfBadRequest(errors.New("ppob detail prepaid pulsa not found"), requestId, []string{"ppob detail prepaid pulsa not found"}, service.Logger)
-				}
-
-				response := service.PrepaidPulsaTopup(requestId, ppobDetailPrepaidPulsa.CustomerId, order.RefId, orderItemsPpob.ProductCode)
-
-				err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPulsaById(service.DB, ppobDetailPrepaidPulsa.Id, &entity.PpobDetailPrepaidPulsa{
-					StatusTopUp:         response.Data.Status,
-					TopupProccesingDate: null.NewTime(time.Now(), true),
-					LastBalance:         response.Data.Balance,
-				})
-				exceptions.PanicIfError(err, requestId, service.Logger)
-			case "prepaid_pln":
-				ppobDetailPrepaidPulsa, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPlnById(service.DB, orderItemsPpob.Id)
-				exceptions.PanicIfError(err, requestId, service.Logger)
-				if len(ppobDetailPrepaidPulsa.Id) == 0 {
-					exceptions.PanicIfBadRequest(errors.New("ppob detail prepaid pulsa not found"), requestId, []string{"ppob detail prepaid pulsa not found"}, service.Logger)
-				}
-
-				response := service.PrepaidPulsaTopup(requestId, ppobDetailPrepaidPulsa.CustomerId, order.RefId, orderItemsPpob.ProductCode)
-
-				err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPlnById(service.DB, ppobDetailPrepaidPulsa.Id, &entity.PpobDetailPrepaidPln{
-					StatusTopUp:         response.Data.Status,
-					NoToken:             response.Data.Sn,
-					TopupProccesingDate: null.NewTime(time.Now(), true),
-					LastBalance:         response.Data.Balance,
-				})
-				exceptions.PanicIfError(err, requestId, service.Logger)
-			case "postpaid_pln":
-				ppobDetailPostpaidPln, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPlnById(service.DB, orderItemsPpob.Id)
-				exceptions.PanicIfError(err, requestId, service.Logger)
-				if len(ppobDetailPostpaidPln.Id) == 0 {
-					exceptions.PanicIfBadRequest(errors.New("ppob detail postpaid pln not found"), requestId, []string{"ppob detail postpaid pln not found"}, service.Logger)
-				}
-
-				response := service.PostpaidTopupPln(requestId, ppobDetailPostpaidPln.CustomerId, ppobDetailPostpaidPln.OrderItemPpob.TrId, orderItemsPpob.ProductCode)
-
-				err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPlnById(service.DB, ppobDetailPostpaidPln.Id, &entity.PpobDetailPostpaidPln{
-					StatusTopUp:         3,
-					TopupProccesingDate: null.NewTime(time.Now(), true),
-					LastBalance:         response.Balance,
-				})
-				exceptions.PanicIfError(err, requestId, service.Logger)
-
-			case "postpaid_pdam":
-				ppobDetailPostpaidPdam, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPdamById(service.DB, orderItemsPpob.Id)
-				exceptions.PanicIfError(err, requestId, service.Logger)
-				if len(ppobDetailPostpaidPdam.Id) == 0 {
-					exceptions.PanicIfBadRequest(errors.New("ppob detail postpaid pdam not found"), requestId, []string{"ppob detail postpaid pdam not found"}, service.Logger)
-				}
-
-				response := service.PostpaidTopupPdam(requestId, ppobDetailPostpaidPdam.CustomerId, ppobDetailPostpaidPdam.OrderItemPpob.TrId, orderItemsPpob.ProductCode)
-
-				err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPdamById(service.DB, ppobDetailPostpaidPdam.Id, &entity.PpobDetailPostpaidPdam{
-					StatusTopUp:         3,
-					TopupProccesingDate: null.NewTime(time.Now(), true),
-					LastBalance:         response.Balance,
-				})
-				exceptions.PanicIfError(err, requestId, service.Logger)
-			}
-
-		} else {
-			exceptions.PanicIfBadRequest(errors.New("order type not found"), requestId, []string{"order type not found"}, service.Logger)
-		}
-	} else {
+	if paymentStatus.Status != 1 && paymentStatus.Status != 6 {
 		exceptions.PanicIfBadRequest(errors.New("status pembayaran belum terbayar"), requestId, []string{"status pembayaran belum terbayar"}, service.Logger)
 	}
-}
 
-func (service *OrderServiceImplementation) PrepaidPulsaTopup(requestId string, customerId, refId, productCode string) *ppob.TopupPrepaidPulsaResponse {
-	var err error
-
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + refId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"username":     config.GetConfig().Ppob.Username,
-		"ref_id":       refId,
-		"customer_id":  customerId,
-		"product_code": productCode,
-		"sign":         hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/top-up"
-
-	// URL
-	url, _ := url.Parse(urlString)
+	service.ConfirmOrderPayment(requestId, order, "")
+}
 
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
+// ConfirmOrderPayment proses order yang sudah dibayar (stok sembako atau topup ppob), dipakai callback
+// ipaymu dan verifikasi transfer manual. Update hanya berlaku untuk order yang masih status 0.
+func (service *OrderServiceImplementation) ConfirmOrderPayment(requestId string, order *entity.Order, verifiedBy string) {
+	if order.OrderType != 1 && order.OrderType != 2 {
+		exceptions.PanicIfBadRequest(errors.New("order type not found"), requestId, []string{"order type not found"}, service.Logger)
 	}
 
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
+	// Status bayar dan stok produk sembako berubah dalam satu transaksi
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+	if order.OrderType == 1 {
+		service.markOrderPaid(requestId, tx, order, 1, verifiedBy)
+		service.ProductDesaServiceInterface.UpdateProductStock(requestId, order.Id, tx)
+	} else {
+		service.markOrderPaid(requestId, tx, order, 2, verifiedBy)
 	}
+	commit := repository.CommitTransaction(tx)
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
 
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	topupPrepaidPulsaResponse := &ppob.TopupPrepaidPulsaResponse{}
+	go service.NotificationServiceInterface.NotifyOrderPaid(order)
 
-	if err = json.Unmarshal([]byte(data), &topupPrepaidPulsaResponse); err != nil {
+	if order.OrderType == 2 {
+		orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
 		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	return topupPrepaidPulsaResponse
-}
-
-func (service *OrderServiceImplementation) PostpaidTopupPln(requestId string, customerId string, TrxId int, productCode string) *ppob.TopupPostaidPlnDataResponse {
-	var err error
+		if len(orderItemsPpob.Id) == 0 {
+			exceptions.PanicIfBadRequest(errors.New("order items ppob not found"), requestId, []string{"order items ppob not found"}, service.Logger)
+		}
 
-	trxIdBytes := []byte(strconv.Itoa(TrxId))
-	concatString := config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + string(trxIdBytes)
-	concatBytes := []byte(concatString)
-	hash := md5.Sum(concatBytes)
-	sign := hash[:]
-
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "pay-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"tr_id":    TrxId,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
+		switch order.ProductType {
+		case "prepaid_pulsa", "prepaid_data":
+			ppobDetailPrepaidPulsa, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPulsaById(service.DB, orderItemsPpob.Id)
+			exceptions.PanicIfError(err, requestId, service.Logger)
+			if len(ppobDetailPrepaidPulsa.Id) == 0 {
+				exceptions.PanicIfBadRequest(errors.New("ppob detail prepaid pulsa not found"), requestId, []string{"ppob detail prepaid pulsa not found"}, service.Logger)
+			}
 
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
+			response := service.PrepaidPulsaTopup(requestId, ppobDetailPrepaidPulsa.CustomerId, order.RefId, orderItemsPpob.ProductCode)
 
-	urlString := config.GetConfig().Ppob.PostpaidUrl
+			err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPulsaById(service.DB, ppobDetailPrepaidPulsa.Id, &entity.PpobDetailPrepaidPulsa{
+				StatusTopUp:         response.Data.Status,
+				TopupProccesingDate: null.NewTime(time.Now(), true),
+				LastBalance:         response.Data.Balance,
+			})
+			exceptions.PanicIfError(err, requestId, service.Logger)
+		case "prepaid_pln":
+			ppobDetailPrepaidPulsa, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPlnById(service.DB, orderItemsPpob.Id)
+			exceptions.PanicIfError(err, requestId, service.Logger)
+			if len(ppobDetailPrepaidPulsa.Id) == 0 {
+				exceptions.PanicIfBadRequest(errors.New("ppob detail prepaid pulsa not found"), requestId, []string{"ppob detail prepaid pulsa not found"}, service.Logger)
+			}
 
-	// URL
-	url, _ := url.Parse(urlString)
+			response := service.PrepaidPulsaTopup(requestId, ppobDetailPrepaidPulsa.CustomerId, order.RefId, orderItemsPpob.ProductCode)
 
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
+			err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPlnById(service.DB, ppobDetailPrepaidPulsa.Id, &entity.PpobDetailPrepaidPln{
+				StatusTopUp:         response.Data.Status,
+				NoToken:             response.Data.Sn,
+				TopupProccesingDate: null.NewTime(time.Now(), true),
+				LastBalance:         response.Data.Balance,
+			})
+			exceptions.PanicIfError(err, requestId, service.Logger)
+			// Token dikirim ke user saat order difinalkan callback atau rekonsiliasi, bukan di sini
+		case "postpaid_pln":
+			ppobDetailPostpaidPln, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPlnById(service.DB, orderItemsPpob.Id)
+			exceptions.PanicIfError(err, requestId, service.Logger)
+			if len(ppobDetailPostpaidPln.Id) == 0 {
+				exceptions.PanicIfBadRequest(errors.New("ppob detail postpaid pln not found"), requestId, []string{"ppob detail postpaid pln not found"}, service.Logger)
+			}
 
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
+			response := service.PostpaidTopupPln(requestId, ppobDetailPostpaidPln.CustomerId, ppobDetailPostpaidPln.OrderItemPpob.TrId, orderItemsPpob.ProductCode)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
+			err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPlnById(service.DB, ppobDetailPostpaidPln.Id, &entity.PpobDetailPostpaidPln{
+				StatusTopUp:         3,
+				TopupProccesingDate: null.NewTime(time.Now(), true),
+				LastBalance:         response.Balance,
+			})
+			exceptions.PanicIfError(err, requestId, service.Logger)
 
-	defer resp.Body.Close()
+		case "postpaid_pdam":
+			ppobDetailPostpaidPdam, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPdamById(service.DB, orderItemsPpob.Id)
+			exceptions.PanicIfError(err, requestId, service.Logger)
+			if len(ppobDetailPostpaidPdam.Id) == 0 {
+				exceptions.PanicIfBadRequest(errors.New("ppob detail postpaid pdam not found"), requestId, []string{"ppob detail postpaid pdam not found"}, service.Logger)
+			}
 
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
+			response := service.PostpaidTopupPdam(requestId, ppobDetailPostpaidPdam.CustomerId, ppobDetailPostpaidPdam.OrderItemPpob.TrId, orderItemsPpob.ProductCode)
 
-	topupPostpaidPlnResonse := &ppob.TopupPostaidPlnResponse{}
+			err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPdamById(service.DB, ppobDetailPostpaidPdam.Id, &entity.PpobDetailPostpaidPdam{
+				StatusTopUp:         3,
+				TopupProccesingDate: null.NewTime(time.Now(), true),
+				LastBalance:         response.Balance,
+			})
+			exceptions.PanicIfError(err, requestId, service.Logger)
 
-	if err = json.Unmarshal([]byte(data), &topupPostpaidPlnResonse); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+		default:
+			// ppob generic dari ppob_product_type
+			service.topupOrderPpobById(requestId, order, orderItemsPpob)
+		}
 	}
+}
+
+// PrepaidPulsaTopup rc gagal dari provider tidak di-panic karena status topup tetap disimpan dari response
+func (service *OrderServiceImplementation) PrepaidPulsaTopup(requestId string, customerId, refId, productCode string) *ppob.TopupPrepaidPulsaResponse {
+	topupPrepaidPulsaResponse, err := service.PpobProviderInterface.TopupPrepaid(refId, customerId, productCode)
+	service.logPpobTransactionError(requestId, refId, err)
+	return topupPrepaidPulsaResponse
+}
 
+func (service *OrderServiceImplementation) PostpaidTopupPln(requestId string, customerId string, TrxId int, productCode string) *ppob.TopupPostaidPlnDataResponse {
+	topupPostpaidPlnResonse, err := service.PpobProviderInterface.PaymentPostpaidPln(TrxId)
+	service.logPpobTransactionError(requestId, customerId, err)
 	return &topupPostpaidPlnResonse.Data
 }
 
 func (service *OrderServiceImplementation) PostpaidTopupPdam(requestId string, customerId string, TrxId int, productCode string) *ppob.TopupPostaidPdamDataResponse {
-	var err error
-
-	trxIdBytes := []byte(strconv.Itoa(TrxId))
-	concatString := config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + string(trxIdBytes)
-	concatBytes := []byte(concatString)
-	hash := md5.Sum(concatBytes)
-	sign := hash[:]
-
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "pay-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"tr_id":    TrxId,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	topupPostpaidPdamResonse := &ppob.TopupPostaidPdamResponse{}
+	topupPostpaidPdamResonse, err := service.PpobProviderInterface.PaymentPostpaidPdam(TrxId)
+	service.logPpobTransactionError(requestId, customerId, err)
+	return &topupPostpaidPdamResonse.Data
+}
 
-	if err = json.Unmarshal([]byte(data), &topupPostpaidPdamResonse); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
+func (service *OrderServiceImplementation) logPpobTransactionError(requestId string, reference string, err error) {
+	if exceptions.IsPpobTransactionError(err) {
+		service.Logger.WithFields(logrus.Fields{"request_id": requestId, "reference": reference}).Error(err.Error())
+		return
 	}
-
-	return &topupPostpaidPdamResonse.Data
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 }
 
 func (service *OrderServiceImplementation) OrderInquiryPrepaidPln(requestId string, customerId string) (inquiryPrepaidPlnResponse response.InquiryPrepaidPlnResponse) {
-	var err error
+	inquiryPrepaidPln, err := service.PpobProviderInterface.InquiryPrepaidPln(customerId)
+	PanicIfInquiryPrepaidPlnError(err, requestId, service.Logger)
 
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + customerId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"username":    config.GetConfig().Ppob.Username,
-		"customer_id": customerId,
-		"sign":        hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/inquiry-pln"
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
+	inquiryPrepaidPlnResponse = response.ToInquiryPrepaidPlnResponse(inquiryPrepaidPln)
 
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
+	return inquiryPrepaidPlnResponse
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
+func (service *OrderServiceImplementation) CallbackPpobTransaction(requestId string, ppobCallbackRequest *request.PpobCallbackRequest, rawPayload []byte, remoteIp string) {
+	ppobCallbackLog := &entity.PpobCallbackLog{
+		Id:        utilities.RandomUUID(),
+		RefId:     ppobCallbackRequest.Data.RefId,
+		Status:    ppobCallbackRequest.Data.Status,
+		RemoteIp:  remoteIp,
+		Payload:   string(rawPayload),
+		Outcome:   "rejected",
+		CreatedAt: time.Now(),
 	}
 
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	inquiryPrepaidPln := &ppob.InquiryPrepaidPln{}
-
-	if err = json.Unmarshal([]byte(data), &inquiryPrepaidPln); err != nil {
-		inquiryPrepaidPlnErrorHandle := &ppob.InquiryPrepaidPlnErrorHandle{}
-		if err = json.Unmarshal([]byte(data), &inquiryPrepaidPlnErrorHandle); err != nil {
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else {
-			if inquiryPrepaidPlnErrorHandle.Data.Rc == "208" {
-				exceptions.PanicIfBadRequest(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-			}
-			if inquiryPrepaidPlnErrorHandle.Data.Rc == "14" {
-				exceptions.PanicIfBadRequest(errors.New("costumer id not found"), requestId, []string{"Costumer Id Not Found"}, service.Logger)
-			}
-			exceptions.PanicIfError(err, requestId, service.Logger)
+	// setiap callback dicatat beserta hasilnya, termasuk yang ditolak
+	defer func() {
+		r := recover()
+		if r != nil {
+			ppobCallbackLog.Message = fmt.Sprint(r)
 		}
-	}
 
-	if inquiryPrepaidPln.Data.Rc != "00" {
-		fmt.Printf("body: %s\n", inquiryPrepaidPln.Data)
-		exceptions.PanicIfError(errors.New("error from IAK"), requestId, service.Logger)
-	}
-
-	inquiryPrepaidPlnResponse = response.ToInquiryPrepaidPlnResponse(inquiryPrepaidPln)
-
-	return inquiryPrepaidPlnResponse
-}
-
-func (service *OrderServiceImplementation) CallbackPpobTransaction(requestId string, ppobCallbackRequest *request.PpobCallbackRequest) {
-	var err error
+		err := service.PpobCallbackLogRepositoryInterface.CreatePpobCallbackLog(service.DB, ppobCallbackLog)
+		if err != nil {
+			service.Logger.WithFields(logrus.Fields{"request_id": requestId}).Error(err)
+		}
 
-	fmt.Println("request = ", ppobCallbackRequest)
+		if r != nil {
+			panic(r)
+		}
+	}()
 
 	order, err := service.OrderRepositoryInterface.FindOrderByRefId(service.DB, ppobCallbackRequest.Data.RefId)
 	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(order.RefId) == 0 {
+	if len(order.RefId) == 0 || order.OrderType != 2 {
 		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
 	}
 
-	signCheck := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + string(order.RefId)))
-	log.Println("sign check = ", hex.EncodeToString(signCheck[:]))
-	log.Println("sign = ", ppobCallbackRequest.Data.Sign)
-
 	// cek sign dari iak dengan signcheck
-	if hex.EncodeToString(signCheck[:]) != ppobCallbackRequest.Data.Sign {
+	sign := service.PpobProviderInterface.CallbackSign(order.RefId)
+	if subtle.ConstantTimeCompare([]byte(sign), []byte(ppobCallbackRequest.Data.Sign)) != 1 {
 		exceptions.PanicIfBadRequest(errors.New("sign not match"), requestId, []string{"sign not match"}, service.Logger)
 	}
 
-	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
-	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(orderItemsPpob.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("order item ppob not found"), requestId, []string{"order item ppob not found"}, service.Logger)
+	if service.UpdatePpobTransactionStatus(requestId, order, &ppobCallbackRequest.Data) {
+		ppobCallbackLog.Outcome = "processed"
+	} else {
+		ppobCallbackLog.Outcome = "duplicate"
 	}
+}
 
-	switch order.ProductType {
-	case "prepaid_pulsa":
-		detailPrepaidPulsa, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPulsaById(service.DB, orderItemsPpob.Id)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-		if len(orderItemsPpob.Id) == 0 {
-			exceptions.PanicIfRecordNotFound(errors.New("detail prepaid pulsa not found"), requestId, []string{"detail prepaid pulsa not found"}, service.Logger)
-		}
-
-		// transaksi sukses
-		if ppobCallbackRequest.Data.Status == "1" {
-			// update order
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:        5,
-				OrderCompletedDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
-
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPulsaById(service.DB, detailPrepaidPulsa.Id, &entity.PpobDetailPrepaidPulsa{
-				StatusTopUp:      1,
-				TopupSuccessDate: null.NewTime(time.Now(), true),
-				LastBalance:      balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else if ppobCallbackRequest.Data.Status == "2" {
-			// Transaksi failed
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:       9,
-				OrderCanceledDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
-
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPulsaById(service.DB, detailPrepaidPulsa.Id, &entity.PpobDetailPrepaidPulsa{
-				StatusTopUp:     2,
-				TopupFailedDate: null.NewTime(time.Now(), true),
-				LastBalance:     balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-		} else {
-			exceptions.PanicIfBadRequest(errors.New("status not found"), requestId, []string{"error string"}, service.Logger)
-		}
-
-	case "prepaid_pln":
-		detailPrepaidPln, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPlnById(service.DB, orderItemsPpob.Id)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-		if len(orderItemsPpob.Id) == 0 {
-			exceptions.PanicIfRecordNotFound(errors.New("detail prepaid pln not found"), requestId, []string{"detail prepaid pln not found"}, service.Logger)
-		}
-
-		if ppobCallbackRequest.Data.Status == "1" {
-			// update order
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:        5,
-				OrderCompletedDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
-
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPlnById(service.DB, detailPrepaidPln.Id, &entity.PpobDetailPrepaidPln{
-				StatusTopUp:      1,
-				TopupSuccessDate: null.NewTime(time.Now(), true),
-				LastBalance:      balance,
-				NoToken:          ppobCallbackRequest.Data.Sn,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else if ppobCallbackRequest.Data.Status == "2" {
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:       9,
-				OrderCanceledDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
+// UpdatePpobTransactionStatus transisi status order ppob dari callback maupun hasil check status provider,
+// status "1" sukses dan "2" gagal. Status yang sama dengan status final order diabaikan (updated false),
+// status yang berlawanan dengan status final ditolak
+func (service *OrderServiceImplementation) UpdatePpobTransactionStatus(requestId string, order *entity.Order, ppobTransaction *request.PpobCallbackRequestData) (updated bool) {
+	var err error
 
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
+	if ppobTransaction.Status != "1" && ppobTransaction.Status != "2" {
+		exceptions.PanicIfBadRequest(errors.New("status not found"), requestId, []string{"status not found"}, service.Logger)
+	}
 
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPrepaidPlnById(service.DB, detailPrepaidPln.Id, &entity.PpobDetailPrepaidPln{
-				StatusTopUp:     2,
-				TopupFailedDate: null.NewTime(time.Now(), true),
-				LastBalance:     balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else {
-			exceptions.PanicIfBadRequest(errors.New("status not found"), requestId, []string{"error string"}, service.Logger)
+	switch order.OrderStatus {
+	case 1, 2:
+	case 5:
+		if ppobTransaction.Status == "1" {
+			return false
 		}
-
-	case "postpaid_pln":
-		detailPostpaidPln, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPlnById(service.DB, orderItemsPpob.Id)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-		if len(orderItemsPpob.Id) == 0 {
-			exceptions.PanicIfRecordNotFound(errors.New("detail postpaid pln not found"), requestId, []string{"detail postpaid pln not found"}, service.Logger)
+		exceptions.PanicIfBadRequest(errors.New("order already success"), requestId, []string{"order sudah sukses"}, service.Logger)
+	case 9:
+		if ppobTransaction.Status == "2" {
+			return false
 		}
+		exceptions.PanicIfBadRequest(errors.New("order already failed"), requestId, []string{"order sudah gagal"}, service.Logger)
+	default:
+		exceptions.PanicIfBadRequest(errors.New("order not in process"), requestId, []string{"order belum diproses"}, service.Logger)
+	}
 
-		if ppobCallbackRequest.Data.Status == "1" {
-			// update order
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:        5,
-				OrderCompletedDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
+	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(orderItemsPpob.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order item ppob not found"), requestId, []string{"order item ppob not found"}, service.Logger)
+	}
 
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPlnById(service.DB, detailPostpaidPln.Id, &entity.PpobDetailPostpaidPln{
-				StatusTopUp:      1,
-				TopupSuccessDate: null.NewTime(time.Now(), true),
-				LastBalance:      balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else if ppobCallbackRequest.Data.Status == "2" {
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:       9,
-				OrderCanceledDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
+	balance, _ := strconv.ParseFloat(ppobTransaction.Balance, 64)
+	statusTopUp := 2
+	if ppobTransaction.Status == "1" {
+		statusTopUp = 1
+	}
 
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
+	// Status order dan detail ppob difinalkan bersama, callback yang kalah cepat tidak mengubah apa pun
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
 
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPlnById(service.DB, detailPostpaidPln.Id, &entity.PpobDetailPostpaidPln{
-				StatusTopUp:     2,
-				TopupFailedDate: null.NewTime(time.Now(), true),
-				LastBalance:     balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else {
-			exceptions.PanicIfBadRequest(errors.New("status not found"), requestId, []string{"error string"}, service.Logger)
-		}
+	if !service.updatePendingOrderPpob(requestId, tx, order, ppobTransaction.Status) {
+		tx.Rollback()
+		return false
+	}
 
-	case "postpaid_pdam":
-		detailPostpaidPdam, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPdamById(service.DB, orderItemsPpob.Id)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-		if len(orderItemsPpob.Id) == 0 {
-			exceptions.PanicIfRecordNotFound(errors.New("detail postpaid pln not found"), requestId, []string{"detail postpaid pln not found"}, service.Logger)
-		}
+	rowsAffected, err := service.PpobDetailRepositoryInterface.UpdatePpobDetailStatusByIdOrderItemPpob(tx, order.ProductType, orderItemsPpob.Id, statusTopUp, balance, ppobTransaction.Sn)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update ppob detail"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfRecordNotFound(errors.New("detail ppob not found"), requestId, []string{"detail ppob not found"}, service.Logger)
+	}
 
-		if ppobCallbackRequest.Data.Status == "1" {
-			// update order
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:        5,
-				OrderCompletedDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
+	// Transaksi gagal, point yang dipakai membayar dikembalikan
+	if statusTopUp == 2 {
+		service.PointServiceInterface.ReverseOrderPoint(requestId, tx, order.Id)
+	}
 
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
 
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPdamById(service.DB, detailPostpaidPdam.Id, &entity.PpobDetailPostpaidPdam{
-				StatusTopUp:      1,
-				TopupSuccessDate: null.NewTime(time.Now(), true),
-				LastBalance:      balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else if ppobCallbackRequest.Data.Status == "2" {
-			err = service.OrderRepositoryInterface.UpdateOrderByIdOrder(service.DB, order.Id, &entity.Order{
-				OrderStatus:       9,
-				OrderCanceledDate: null.NewTime(time.Now(), true),
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
+	// Notifikasi hanya dikirim sekali saat order berpindah dari diproses ke final.
+	// Token PLN langsung dikirim ke user, transaksi lain cukup status akhirnya
+	if order.ProductType == "prepaid_pln" && ppobTransaction.Status == "1" && len(ppobTransaction.Sn) != 0 {
+		go service.NotificationServiceInterface.NotifyPpobToken(order, ppobTransaction.Sn)
+	} else if ppobTransaction.Status == "1" {
+		go service.NotificationServiceInterface.NotifyOrderStatus(order, 5)
+	} else {
+		go service.NotificationServiceInterface.NotifyOrderStatus(order, 9)
+	}
 
-			balance, _ := strconv.ParseFloat(ppobCallbackRequest.Data.Balance, 32)
+	return true
+}
 
-			err = service.PpobDetailRepositoryInterface.UpdatePpobPostpaidPdamById(service.DB, detailPostpaidPdam.Id, &entity.PpobDetailPostpaidPdam{
-				StatusTopUp:     2,
-				TopupFailedDate: null.NewTime(time.Now(), true),
-				LastBalance:     balance,
-			})
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else {
-			exceptions.PanicIfBadRequest(errors.New("status not found"), requestId, []string{"error string"}, service.Logger)
-		}
+// updatePendingOrderPpob set order ke sukses (5) atau gagal (9) hanya jika order masih diproses,
+// false jika callback lain sudah lebih dulu memfinalkan order
+func (service *OrderServiceImplementation) updatePendingOrderPpob(requestId string, tx *gorm.DB, order *entity.Order, status string) bool {
+	orderUpdate := &entity.Order{}
+	if status == "1" {
+		orderUpdate.OrderStatus = 5
+		orderUpdate.OrderCompletedDate = null.NewTime(time.Now(), true)
+	} else {
+		orderUpdate.OrderStatus = 9
+		orderUpdate.OrderCanceledDate = null.NewTime(time.Now(), true)
 	}
+
+	rowsAffected, err := service.OrderRepositoryInterface.UpdatePendingOrderPpobByIdOrder(tx, order.Id, orderUpdate)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update order"}, service.Logger, tx)
+	return rowsAffected > 0
 }
 
 // func (service *OrderServiceImplementation) UpdateLoanIdToOrder(idOrder string, idUser string) {
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/payment"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
+)
+
+// CreateOrderPpob order ppob generic berdasarkan konfigurasi ppob_product_type
+func (service *OrderServiceImplementation) CreateOrderPpob(requestId, idUser, idDesa, productType string, orderRequest *request.CreateOrderPpobRequest) (createOrderResponse response.CreateOrderResponse) {
+	var err error
+
+	request.ValidateRequest(service.Validate, orderRequest, requestId, service.Logger)
+
+	ppobProductType := FindPpobProductType(service.DB, requestId, service.Logger, service.PpobProductTypeRepositoryInterface, productType)
+
+	// Get data user
+	userProfile, err := service.UserRepositoryInterface.FindUserById(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(userProfile.User.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("user not found"), requestId, []string{"user not found"}, service.Logger)
+	}
+
+	orderEntity := &entity.Order{}
+	numberOrder := service.GenerateNumberOrder(idDesa)
+	orderEntity.Id = utilities.RandomUUID()
+	orderEntity.IdUser = idUser
+	orderEntity.IdDesa = idDesa
+	orderEntity.NumberOrder = numberOrder
+	orderEntity.NamaLengkap = userProfile.NamaLengkap
+	orderEntity.Email = userProfile.Email
+	orderEntity.Phone = userProfile.User.Phone
+	orderEntity.ProductType = productType
+	orderEntity.PaymentPoint = orderRequest.PaymentPoint
+	orderEntity.OrderedDate = time.Now()
+	orderEntity.PaymentMethod = orderRequest.PaymentMethod
+	orderEntity.PaymentChannel = orderRequest.PaymentChannel
+	orderEntity.TotalBill = orderRequest.TotalBill
+	orderEntity.PaymentFee = orderRequest.PaymentFee
+	orderEntity.OrderType = 2
+
+	orderItemsPpob := &entity.OrderItemPpob{}
+	orderItemsPpob.Id = utilities.RandomUUID()
+	orderItemsPpob.IdOrder = orderEntity.Id
+	orderItemsPpob.IdUser = userProfile.IdUser
+	orderItemsPpob.ProductType = productType
+	orderItemsPpob.IconUrl = ppobProductType.IconUrl
+	orderItemsPpob.CreatedAt = time.Now()
+
+	ppobDetailGeneric := &entity.PpobDetailGeneric{}
+	ppobDetailGeneric.Id = utilities.RandomUUID()
+	ppobDetailGeneric.IdOrderItemPpob = orderItemsPpob.Id
+	ppobDetailGeneric.CustomerId = orderRequest.CustomerId
+	ppobDetailGeneric.StatusTopUp = -1
+
+	billDetail := ppob.PpobBillDetail{
+		ProductType: productType,
+		Category:    ppobProductType.Category,
+		CustomerId:  orderRequest.CustomerId,
+	}
+
+	var totalHarga float64
+	if ppobProductType.Category == "prepaid" {
+		// Harga dari pricelist lokal, totalHarga sudah termasuk margin desa
+		priceList, sellingPrice := service.PpobPriceServiceInterface.FindPrepaidProduct(requestId, idDesa, orderRequest.ProductCode)
+		if priceList.ProductType != ppobProductType.ProviderType || (len(ppobProductType.ProviderOperator) != 0 && priceList.Operator != ppobProductType.ProviderOperator) {
+			exceptions.PanicIfBadRequest(errors.New("product not match"), requestId, []string{"produk tidak sesuai dengan tipe produk"}, service.Logger)
+		}
+		totalHarga = sellingPrice
+
+		orderEntity.RefId = utilities.GenerateRefId()
+		orderItemsPpob.ProductCode = priceList.ProductCode
+		orderItemsPpob.Nominal = priceList.ProductPrice
+		orderItemsPpob.TotalTagihan = priceList.ProductPrice
+		if len(priceList.IconUrl) != 0 {
+			orderItemsPpob.IconUrl = priceList.IconUrl
+		}
+
+		billDetail.ProductCode = priceList.ProductCode
+		billDetail.ProductName = priceList.ProductDescription + " " + priceList.ProductNominal
+		billDetail.Nominal = priceList.ProductPrice
+		billDetail.Price = priceList.ProductPrice
+	} else {
+		if len(orderRequest.RefId) == 0 {
+			exceptions.PanicIfBadRequest(errors.New("ref id required"), requestId, []string{"ref_id required"}, service.Logger)
+		}
+
+		// Satu hasil inquiry hanya boleh dipakai satu order
+		orderRefId, err := service.OrderRepositoryInterface.FindOrderByRefId(service.DB, orderRequest.RefId)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(orderRefId.Id) != 0 {
+			exceptions.PanicIfRecordAlreadyExists(errors.New("ref id already used"), requestId, []string{"tagihan sudah pernah diorder"}, service.Logger)
+		}
+
+		// Data tagihan diambil ulang dari provider sesuai hasil inquiry
+		trxData, err := service.PpobProviderInterface.CheckStatusPostpaid(orderRequest.RefId)
+		exceptions.PanicIfPpobError(err, requestId, service.Logger)
+		if trxData.Data.Code != orderRequest.ProductCode || trxData.Data.Hp != orderRequest.CustomerId {
+			exceptions.PanicIfBadRequest(errors.New("inquiry not match"), requestId, []string{"data tagihan tidak sesuai"}, service.Logger)
+		}
+		totalHarga = trxData.Data.Price
+
+		orderEntity.RefId = orderRequest.RefId
+		orderItemsPpob.TrId = trxData.Data.TrxId
+		orderItemsPpob.ProductCode = trxData.Data.Code
+		orderItemsPpob.Nominal = trxData.Data.Nominal
+		orderItemsPpob.Admin = trxData.Data.Admin
+		orderItemsPpob.TotalTagihan = trxData.Data.Price
+		ppobDetailGeneric.CustomerName = trxData.Data.TrName
+		ppobDetailGeneric.Period = trxData.Data.Period
+
+		billDetail.ProductCode = trxData.Data.Code
+		billDetail.ProductName = ppobProductType.Name
+		billDetail.CustomerName = trxData.Data.TrName
+		billDetail.Period = trxData.Data.Period
+		billDetail.Nominal = trxData.Data.Nominal
+		billDetail.Admin = trxData.Data.Admin
+		billDetail.Price = trxData.Data.Price
+		billDetail.Desc = trxData.Data.Desc
+	}
+
+	orderItemsPpob.RefId = orderEntity.RefId
+	orderItemsPpob.SellingPrice = totalHarga
+	billDetail.SellingPrice = totalHarga
+	billDetailJson, _ := json.Marshal(billDetail)
+	orderItemsPpob.BillDetail = string(billDetailJson)
+
+	if (totalHarga + orderRequest.PaymentFee) != (orderRequest.TotalBill + orderRequest.PaymentFee + orderEntity.PaymentPoint) {
+		exceptions.PanicIfRecordNotFound(errors.New("harga tidak sama"), requestId, []string{"harga tidak sama"}, service.Logger)
+	}
+
+	// Point yang dipakai, metode point berarti seluruh tagihan dibayar dengan point
+	redeemPoint := orderRequest.PaymentPoint
+	if orderRequest.PaymentMethod == "point" {
+		redeemPoint = redeemPoint + orderRequest.TotalBill
+	}
+	if redeemPoint > 0 {
+		service.PointServiceInterface.CheckPointBalance(requestId, idUser, redeemPoint)
+	}
+
+	// Get detail payment channel
+	paymentChannel, err := service.PaymentChannelRepositoryInterface.FindPaymentChannelByCode(service.DB, orderRequest.PaymentChannel)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(paymentChannel.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("payment not found"), requestId, []string{"payment not found"}, service.Logger)
+	}
+
+	// Get Desa
+	desa, _ := service.DesaRepositoryInterface.FindDesaById(service.DB, userProfile.User.IdDesa)
+	if len(desa.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("desa account paylater not found"), requestId, []string{"desa account paylater not found"}, service.Logger)
+	}
+
+	// Point dipotong sebelum pembayaran ke pihak luar, dikembalikan jika order gagal dibuat
+	orderCreated := false
+	service.reserveOrderBenefit(requestId, orderEntity, nil, redeemPoint)
+	defer func() {
+		if !orderCreated {
+			service.releaseOrderBenefit(requestId, orderEntity, nil, redeemPoint)
+		}
+	}()
+
+	service.payOrderPpob(requestId, orderEntity, orderItemsPpob, userProfile, desa, paymentChannel, orderRequest)
+
+	tx := service.DB.Begin()
+
+	// Nominal unik transfer dicadangkan di transaksi order, ikut batal jika order gagal dibuat
+	if orderRequest.PaymentMethod == "trf" {
+		orderEntity.PaymentCash = service.reserveTransferUniqueAmount(requestId, tx, orderEntity.IdDesa, orderEntity.Id, orderRequest.TotalBill, orderEntity.PaymentDueDate.Time)
+	}
+
+	err = service.OrderRepositoryInterface.CreateOrder(tx, orderEntity)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order"}, service.Logger, tx)
+
+	err = service.OrderItemPpobRepositoryInterface.CreateOrderItemPpob(tx, orderItemsPpob)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order items"}, service.Logger, tx)
+
+	err = service.PpobDetailRepositoryInterface.CreateOrderPpobDetailGeneric(tx, ppobDetailGeneric)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create order items"}, service.Logger, tx)
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+	orderCreated = true
+
+	if orderRequest.PaymentMethod == "point" || orderRequest.PaymentMethod == "tabungan_bima" || orderRequest.PaymentMethod == "paylater" {
+		service.topupOrderPpob(requestId, ppobProductType, orderEntity, orderItemsPpob, ppobDetailGeneric)
+	}
+
+	mssg := "Order " + ppobProductType.Name + " Baru Dari " + userProfile.NamaLengkap + " ID Order " + orderEntity.NumberOrder + " VIA " + paymentChannel.Alias
+	go service.SendMessageToTelegram(mssg, desa.ChatIdTelegram, desa.TokenBot)
+
+	createOrderResponse = response.ToCreateOrderResponse(orderEntity, paymentChannel)
+	return createOrderResponse
+}
+
+// payOrderPpob set status dan data pembayaran order sesuai payment method
+func (service *OrderServiceImplementation) payOrderPpob(requestId string, orderEntity *entity.Order, orderItemsPpob *entity.OrderItemPpob, userProfile *entity.UserProfile, desa *entity.Desa, paymentChannel *entity.PaymentChannel, orderRequest *request.CreateOrderPpobRequest) {
+	switch orderRequest.PaymentMethod {
+	case "point":
+		orderEntity.OrderStatus = 1
+		orderEntity.PaymentStatus = 1
+		orderEntity.PaymentName = "Point"
+		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
+	case "trf":
+		orderEntity.OrderStatus = 0
+		orderEntity.PaymentStatus = 0
+		orderEntity.PaymentNo = paymentChannel.NoAccountBank
+		orderEntity.PaymentName = paymentChannel.NamaPemilikBank
+		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
+
+	case "va", "qris":
+		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
+		res := service.PaymentServiceInterface.VaQrisPay(requestId,
+			&payment.IpaymuQrisVaRequest{
+				Name:           userProfile.NamaLengkap,
+				Phone:          userProfile.User.Phone,
+				Email:          userProfile.Email,
+				Amount:         orderEntity.PaymentCash,
+				ReferenceId:    orderEntity.NumberOrder,
+				PaymentMethod:  orderRequest.PaymentMethod,
+				PaymentChannel: orderRequest.PaymentChannel,
+			},
+		)
+
+		if res.Status != 200 {
+			exceptions.PanicIfRecordNotFound(errors.New("error response ipaymu"), requestId, []string{"Error response ipaymu"}, service.Logger)
+		}
+		paymentDueDate, _ := time.Parse("2006-01-02 15:04:05", res.Data.Expired)
+		orderEntity.PaymentStatus = 0
+		orderEntity.TrxId = res.Data.TransactionId
+		orderEntity.PaymentNo = res.Data.PaymentNo
+		orderEntity.PaymentName = res.Data.PaymentName
+		orderEntity.PaymentDueDate = null.NewTime(paymentDueDate, true)
+		orderEntity.OrderStatus = 0
+
+	case "cc":
+		res := service.PaymentServiceInterface.CreditCardPay(requestId,
+			&payment.IpaymuCreditCardRequest{
+				Product:       []string{orderItemsPpob.ProductCode, "Payment Fee"},
+				Qty:           []int{1, 1},
+				Price:         []float64{orderItemsPpob.SellingPrice, orderRequest.PaymentFee},
+				ReferenceId:   orderEntity.NumberOrder,
+				BuyerName:     userProfile.NamaLengkap,
+				BuyerEmail:    userProfile.Email,
+				BuyerPhone:    userProfile.User.Phone,
+				PaymentMethod: orderRequest.PaymentMethod,
+			},
+		)
+
+		if res.Status != 200 {
+			exceptions.PanicIfRecordNotFound(errors.New("error response ipaymu"), requestId, []string{"Error response ipaymu"}, service.Logger)
+		}
+		orderEntity.PaymentStatus = 0
+		orderEntity.PaymentNo = res.Data.Url
+		orderEntity.PaymentName = "Credit Card"
+		orderEntity.PaymentDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
+		orderEntity.OrderStatus = 0
+		orderEntity.PaymentCash = orderRequest.TotalBill + orderEntity.PaymentFee
+
+	case "paylater":
+		orderPaylater := service.PaymentServiceInterface.PayWithPaylater(userProfile.User.InveliAccessToken, userProfile.User.InveliIDMember, desa.GroupIdBupda, desa.NoRekening, userProfile.User.Id, orderRequest.TotalBill, orderRequest.PaymentFee)
+
+		orderEntity.PaymentDueDate = orderPaylater.PaymentDueDate
+		orderEntity.OrderStatus = orderPaylater.OrderStatus
+		orderEntity.PaymentStatus = orderPaylater.PaymentStatus
+		orderEntity.PaymentName = orderPaylater.PaymentName
+		orderEntity.PaymentSuccessDate = orderPaylater.PaymentSuccessDate
+		orderEntity.PaymentCash = orderPaylater.PaymentCash
+
+	case "tabungan_bima":
+		accountUser, _ := service.UserRepositoryInterface.GetUserAccountBimaByID(service.DB, userProfile.User.Id)
+		if len(accountUser.Id) == 0 {
+			exceptions.PanicIfRecordNotFound(errors.New("user account paylater not found"), requestId, []string{"user account paylater not found"}, service.Logger)
+		}
+
+		orderEntity.OrderStatus = 1
+		orderEntity.PaymentStatus = 1
+		orderEntity.PaymentName = "Tabungan Bima"
+		orderEntity.PaymentSuccessDate = null.NewTime(time.Now(), true)
+		orderEntity.PaymentCash = orderRequest.TotalBill
+
+		err := service.InveliAPIRepositoryInterface.ApiPayment(desa.NoRekening, accountUser.Code, userProfile.User.InveliAccessToken, orderRequest.TotalBill, 0)
+		if err != nil {
+			exceptions.PanicIfRecordNotFound(err, requestId, []string{strings.TrimPrefix(err.Error(), "graphql: ")}, service.Logger)
+		}
+
+	default:
+		exceptions.PanicIfBadRequest(errors.New("payment method not found"), requestId, []string{"payment method not found"}, service.Logger)
+	}
+}
+
+// topupOrderPpob kirim transaksi ke provider setelah order terbayar
+func (service *OrderServiceImplementation) topupOrderPpob(requestId string, ppobProductType *entity.PpobProductType, order *entity.Order, orderItemsPpob *entity.OrderItemPpob, ppobDetailGeneric *entity.PpobDetailGeneric) {
+	ppobDetailUpdate := &entity.PpobDetailGeneric{
+		TopupProccesingDate: null.NewTime(time.Now(), true),
+	}
+
+	if ppobProductType.Category == "prepaid" {
+		topupResponse, err := service.PpobProviderInterface.TopupPrepaid(order.RefId, ppobDetailGeneric.CustomerId, orderItemsPpob.ProductCode)
+		service.logPpobTransactionError(requestId, order.RefId, err)
+		ppobDetailUpdate.StatusTopUp = topupResponse.Data.Status
+		ppobDetailUpdate.Sn = topupResponse.Data.Sn
+		ppobDetailUpdate.LastBalance = topupResponse.Data.Balance
+	} else {
+		paymentResponse, err := service.PpobProviderInterface.PaymentPostpaid(orderItemsPpob.TrId)
+		service.logPpobTransactionError(requestId, order.RefId, err)
+		ppobDetailUpdate.StatusTopUp = 3
+		ppobDetailUpdate.Sn = paymentResponse.Data.NoRef
+		ppobDetailUpdate.LastBalance = paymentResponse.Data.Balance
+	}
+
+	err := service.PpobDetailRepositoryInterface.UpdatePpobGenericById(service.DB, ppobDetailGeneric.Id, ppobDetailUpdate)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+// topupOrderPpobById dipanggil setelah pembayaran order ppob generic terkonfirmasi
+func (service *OrderServiceImplementation) topupOrderPpobById(requestId string, order *entity.Order, orderItemsPpob *entity.OrderItemPpob) {
+	ppobProductType := FindPpobProductType(service.DB, requestId, service.Logger, service.PpobProductTypeRepositoryInterface, order.ProductType)
+
+	ppobDetailGeneric, err := service.PpobDetailRepositoryInterface.FindPpobDetailGenericById(service.DB, orderItemsPpob.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(ppobDetailGeneric.Id) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("ppob detail not found"), requestId, []string{"ppob detail not found"}, service.Logger)
+	}
+
+	service.topupOrderPpob(requestId, ppobProductType, order, orderItemsPpob, ppobDetailGeneric)
+}
+
+func (service *OrderServiceImplementation) FindOrderPpobById(requestId, idUser, idDesa, idOrder string) (orderResponse response.FindOrderPpobByIdResponse) {
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
+
+	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(orderItemsPpob.Id) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("order items ppob not found"), requestId, []string{"order items ppob not found"}, service.Logger)
+	}
+
+	ppobDetailGeneric, err := service.PpobDetailRepositoryInterface.FindPpobDetailGenericById(service.DB, orderItemsPpob.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(ppobDetailGeneric.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order item ppob not found"), requestId, []string{"order item ppob not found"}, service.Logger)
+	}
+
+	payment, err := service.PaymentChannelRepositoryInterface.FindPaymentChannelByCode(service.DB, order.PaymentChannel)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(payment.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("payment not found"), requestId, []string{"payment not found"}, service.Logger)
+	}
+
+	billDetail := ppob.PpobBillDetail{}
+	_ = json.Unmarshal([]byte(orderItemsPpob.BillDetail), &billDetail)
+
+	orderResponse = response.ToFindOrderPpobByIdResponse(order, orderItemsPpob, ppobDetailGeneric, payment, billDetail)
+	return orderResponse
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+)
+
+// ReceiptWidth lebar struk printer thermal 58mm (karakter per baris)
+const ReceiptWidth = 32
+
+func (service *OrderServiceImplementation) FindOrderReceipt(requestId, idUser, idDesa, idOrder string) (orderReceiptResponse response.FindOrderReceiptResponse) {
+	order := service.findOrderByIdUser(requestId, idUser, idDesa, idOrder)
+
+	desa, err := service.DesaRepositoryInterface.FindDesaById(service.DB, order.IdDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	orderReceiptResponse = response.ToFindOrderReceiptResponse(order, desa)
+
+	if order.OrderType == 1 {
+		orderItems, err := service.OrderItemRepositoryInterface.FindOrderItemsByIdOrder(service.DB, order.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		for _, orderItem := range orderItems {
+			orderReceiptResponse.Items = append(orderReceiptResponse.Items, response.FindOrderReceiptItemResponse{
+				ProductName: orderItem.ProductName,
+				Qty:         orderItem.Qty,
+				Price:       orderItem.PriceAfterDiscount,
+				TotalPrice:  orderItem.TotalPrice,
+			})
+		}
+		orderReceiptResponse.Details = appendReceiptDetail(orderReceiptResponse.Details, "Alamat", order.AlamatPengiriman)
+		orderReceiptResponse.Details = appendReceiptDetail(orderReceiptResponse.Details, "Catatan", order.Catatan)
+		return orderReceiptResponse
+	}
+
+	orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(orderItemsPpob.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order item ppob not found"), requestId, []string{"order item ppob not found"}, service.Logger)
+	}
+
+	productName := orderItemsPpob.ProductCode
+	details := []response.FindOrderReceiptDetailResponse{}
+
+	switch order.ProductType {
+	case "prepaid_pulsa", "prepaid_data":
+		detail, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPulsaById(service.DB, orderItemsPpob.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(detail.ProductName) != 0 {
+			productName = detail.ProductName
+		}
+		details = appendReceiptDetail(details, "No HP", detail.CustomerId)
+		details = appendReceiptDetail(details, "Operator", detail.Operator)
+		details = appendReceiptDetail(details, "Masa Aktif", detail.ActivePeriod)
+		details = appendReceiptDetail(details, "Status", receiptTopupStatus(detail.StatusTopUp))
+
+	case "prepaid_pln":
+		detail, err := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPlnById(service.DB, orderItemsPpob.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(detail.ProductName) != 0 {
+			productName = detail.ProductName
+		}
+		details = appendReceiptDetail(details, "No Meter", detail.MeterNo)
+		details = appendReceiptDetail(details, "ID Pelanggan", detail.SubscriberId)
+		details = appendReceiptDetail(details, "Nama", detail.CustomerName)
+		details = appendReceiptDetail(details, "Tarif/Daya", detail.SegmentPower)
+		details = appendReceiptDetail(details, "Status", receiptTopupStatus(detail.StatusTopUp))
+		details = appendReceiptDetail(details, "Token", receiptPlnToken(detail.NoToken))
+
+	case "postpaid_pln":
+		detail, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPlnById(service.DB, orderItemsPpob.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		productName = "Tagihan PLN"
+		details = appendReceiptDetail(details, "ID Pelanggan", detail.CustomerId)
+		details = appendReceiptDetail(details, "Nama", detail.CustomerName)
+		details = appendReceiptDetail(details, "Tarif/Daya", detail.Tarif+"/"+strconv.Itoa(detail.Daya)+"VA")
+		details = appendReceiptDetail(details, "Periode", detail.Period)
+		details = appendReceiptDetail(details, "Lembar Tagihan", detail.LembarTagihan)
+		details = appendReceiptBill(details, orderItemsPpob)
+		details = appendReceiptDetail(details, "Status", receiptTopupStatus(detail.StatusTopUp))
+
+	case "postpaid_pdam":
+		detail, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPdamById(service.DB, orderItemsPpob.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		productName = "Tagihan " + detail.PdamName
+		details = appendReceiptDetail(details, "ID Pelanggan", detail.CustomerId)
+		details = appendReceiptDetail(details, "Nama", detail.CustomerName)
+		details = appendReceiptDetail(details, "Periode", detail.Period)
+		details = appendReceiptDetail(details, "Jatuh Tempo", detail.DueDate)
+		details = appendReceiptBill(details, orderItemsPpob)
+		details = appendReceiptDetail(details, "Materai", detail.StampDuty)
+		details = appendReceiptDetail(details, "Status", receiptTopupStatus(detail.StatusTopUp))
+
+	case "postpaid_telco":
+		detail, err := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidTelcoById(service.DB, orderItemsPpob.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		productName = "Tagihan Telkom"
+		details = appendReceiptDetail(details, "ID Pelanggan", detail.CustomerId)
+		details = appendReceiptDetail(details, "Nama", detail.CustomerName)
+		details = appendReceiptDetail(details, "Periode", detail.Period)
+		details = appendReceiptBill(details, orderItemsPpob)
+		details = appendReceiptDetail(details, "Status", receiptTopupStatus(detail.StatusTopUp))
+
+	default:
+		detail, err := service.PpobDetailRepositoryInterface.FindPpobDetailGenericById(service.DB, orderItemsPpob.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+
+		billDetail := ppob.PpobBillDetail{}
+		_ = json.Unmarshal([]byte(orderItemsPpob.BillDetail), &billDetail)
+		if len(billDetail.ProductName) != 0 {
+			productName = billDetail.ProductName
+		}
+
+		customerIdLabel := "ID Pelanggan"
+		ppobProductType, err := service.PpobProductTypeRepositoryInterface.FindPpobProductTypeByCode(service.DB, order.ProductType)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(ppobProductType.CustomerIdLabel) != 0 {
+			customerIdLabel = ppobProductType.CustomerIdLabel
+		}
+
+		details = appendReceiptDetail(details, customerIdLabel, detail.CustomerId)
+		details = appendReceiptDetail(details, "Nama", detail.CustomerName)
+		details = appendReceiptDetail(details, "Periode", detail.Period)
+		if billDetail.Admin > 0 {
+			details = appendReceiptBill(details, orderItemsPpob)
+		}
+		details = appendReceiptDetail(details, "Status", receiptTopupStatus(detail.StatusTopUp))
+		details = appendReceiptDetail(details, "SN", detail.Sn)
+	}
+
+	orderReceiptResponse.Items = append(orderReceiptResponse.Items, response.FindOrderReceiptItemResponse{
+		ProductName: productName,
+		Qty:         1,
+		Price:       orderItemsPpob.SellingPrice,
+		TotalPrice:  orderItemsPpob.SellingPrice,
+	})
+	orderReceiptResponse.Details = details
+	return orderReceiptResponse
+}
+
+func appendReceiptDetail(details []response.FindOrderReceiptDetailResponse, label, value string) []response.FindOrderReceiptDetailResponse {
+	if len(strings.TrimSpace(value)) == 0 {
+		return details
+	}
+	return append(details, response.FindOrderReceiptDetailResponse{Label: label, Value: value})
+}
+
+// appendReceiptBill rincian tagihan pascabayar: nominal tagihan dan biaya admin
+func appendReceiptBill(details []response.FindOrderReceiptDetailResponse, orderItemsPpob *entity.OrderItemPpob) []response.FindOrderReceiptDetailResponse {
+	if orderItemsPpob.Nominal > 0 {
+		details = appendReceiptDetail(details, "Tagihan", utilities.FormatRupiah(orderItemsPpob.Nominal))
+	}
+	if orderItemsPpob.Admin > 0 {
+		details = appendReceiptDetail(details, "Admin", utilities.FormatRupiah(orderItemsPpob.Admin))
+	}
+	return details
+}
+
+func receiptTopupStatus(statusTopUp int) string {
+	switch statusTopUp {
+	case 1:
+		return "Sukses"
+	case 2:
+		return "Gagal"
+	default:
+		return "Diproses"
+	}
+}
+
+// receiptPlnToken token pln dikelompokkan per 4 digit supaya mudah diinput ke meteran
+func receiptPlnToken(noToken string) string {
+	noToken = strings.ReplaceAll(noToken, " ", "")
+	noToken = strings.ReplaceAll(noToken, "-", "")
+	groups := []string{}
+	for len(noToken) > 4 {
+		groups = append(groups, noToken[:4])
+		noToken = noToken[4:]
+	}
+	if len(noToken) != 0 {
+		groups = append(groups, noToken)
+	}
+	return strings.Join(groups, "-")
+}
+
+func receiptOrderStatus(orderStatus int) string {
+	switch orderStatus {
+	case 0:
+		return "Menunggu Pembayaran"
+	case 5:
+		return "Selesai"
+	case 9:
+		return "Dibatalkan"
+	default:
+		return "Diproses"
+	}
+}
+
+// OrderReceiptLines susun baris struk dari data receipt, dipakai untuk pdf, text dan ESC/POS
+func OrderReceiptLines(orderReceipt response.FindOrderReceiptResponse) []utilities.ReceiptLine {
+	separator := utilities.ReceiptLine{Text: strings.Repeat("-", ReceiptWidth)}
+
+	lines := []utilities.ReceiptLine{
+		{Text: receiptCenter("BUPDA " + strings.ToUpper(orderReceipt.NamaDesa)), Bold: true},
+		{Text: receiptCenter("STRUK PEMBAYARAN"), Bold: true},
+		separator,
+	}
+	lines = append(lines, receiptRow("No Order", orderReceipt.NumberOrder)...)
+	lines = append(lines, receiptRow("Tanggal", orderReceipt.OrderDate.Format("02/01/2006 15:04"))...)
+	lines = append(lines, receiptRow("Pelanggan", orderReceipt.NamaLengkap)...)
+	lines = append(lines, receiptRow("Pembayaran", orderReceipt.PaymentName)...)
+	lines = append(lines, receiptRow("Status", receiptOrderStatus(orderReceipt.OrderStatus))...)
+	lines = append(lines, separator)
+
+	for _, item := range orderReceipt.Items {
+		for _, text := range receiptWrap(item.ProductName, ReceiptWidth) {
+			lines = append(lines, utilities.ReceiptLine{Text: text})
+		}
+		lines = append(lines, receiptRow("  "+strconv.Itoa(item.Qty)+" x "+utilities.FormatRupiah(item.Price), utilities.FormatRupiah(item.TotalPrice))...)
+	}
+
+	if len(orderReceipt.Details) != 0 {
+		lines = append(lines, separator)
+		for _, detail := range orderReceipt.Details {
+			lines = append(lines, receiptRow(detail.Label, detail.Value)...)
+		}
+	}
+
+	lines = append(lines, separator)
+	lines = append(lines, receiptRow("Subtotal", utilities.FormatRupiah(orderReceipt.SubTotal))...)
+	if orderReceipt.ShippingCost > 0 {
+		lines = append(lines, receiptRow("Ongkir", utilities.FormatRupiah(orderReceipt.ShippingCost))...)
+	}
+	if orderReceipt.Discount > 0 {
+		lines = append(lines, receiptRow("Diskon", utilities.FormatRupiah(-orderReceipt.Discount))...)
+	}
+	if orderReceipt.PaymentFee > 0 {
+		lines = append(lines, receiptRow("Biaya Layanan", utilities.FormatRupiah(orderReceipt.PaymentFee))...)
+	}
+	if orderReceipt.PaymentPoint > 0 {
+		lines = append(lines, receiptRow("Point", utilities.FormatRupiah(-orderReceipt.PaymentPoint))...)
+	}
+	total := receiptRow("TOTAL", utilities.FormatRupiah(orderReceipt.TotalBill))
+	for i := range total {
+		total[i].Bold = true
+	}
+	lines = append(lines, total...)
+	lines = append(lines, separator)
+	lines = append(lines, utilities.ReceiptLine{Text: receiptCenter("Terima kasih")})
+	lines = append(lines, utilities.ReceiptLine{Text: receiptCenter("Simpan struk ini sebagai")})
+	lines = append(lines, utilities.ReceiptLine{Text: receiptCenter("bukti pembayaran yang sah")})
+	return lines
+}
+
+func receiptCenter(text string) string {
+	length := len([]rune(text))
+	if length >= ReceiptWidth {
+		return text
+	}
+	return strings.Repeat(" ", (ReceiptWidth-length)/2) + text
+}
+
+// receiptRow label rata kiri dan value rata kanan, value yang terlalu panjang turun ke baris berikutnya
+func receiptRow(label, value string) []utilities.ReceiptLine {
+	labelLength := len([]rune(label))
+	valueLength := len([]rune(value))
+	if labelLength+valueLength+1 <= ReceiptWidth {
+		return []utilities.ReceiptLine{{Text: label + strings.Repeat(" ", ReceiptWidth-labelLength-valueLength) + value}}
+	}
+
+	lines := []utilities.ReceiptLine{}
+	for _, text := range receiptWrap(label, ReceiptWidth) {
+		lines = append(lines, utilities.ReceiptLine{Text: text})
+	}
+	for _, text := range receiptWrap(value, ReceiptWidth) {
+		lines = append(lines, utilities.ReceiptLine{Text: strings.Repeat(" ", ReceiptWidth-len([]rune(text))) + text})
+	}
+	return lines
+}
+
+func receiptWrap(text string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for len([]rune(word)) > width {
+			if len(line) != 0 {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, string([]rune(word)[:width]))
+			word = string([]rune(word)[width:])
+		}
+		if len(line) == 0 {
+			line = word
+		} else if len([]rune(line))+1+len([]rune(word)) <= width {
+			line += " " + word
+		} else {
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if len(line) != 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+// Insert reservasi yang gagal berturut-turut sebelum menyerah, menghindari loop panjang saat database bermasalah
+const transferReserveMaxFailure = 5
+
+// Jumlah order transfer kadaluarsa yang dibatalkan per putaran scheduler
+const transferExpiredBatch = 500
+
+// transferUniqueCodeRange kode unik 3 digit jika 3 digit terakhir total < 100, selain itu 2 digit
+func transferUniqueCodeRange(totalBill float64) (minCode int, maxCode int) {
+	if math.Mod(totalBill, 1000) < 100 {
+		return 111, 299
+	}
+	return 11, 99
+}
+
+// reserveTransferUniqueAmount pilih kode unik yang belum dipakai order terbuka lain di desa tersebut.
+// Keunikan dijamin primary key (id_desa, amount) sehingga request bersamaan tidak bisa dapat nominal yang sama.
+// Dipanggil di transaksi create order agar reservasi ikut batal jika order gagal dibuat.
+func (service *OrderServiceImplementation) reserveTransferUniqueAmount(requestId string, tx *gorm.DB, idDesa, idOrder string, totalBill float64, expiredAt time.Time) float64 {
+	minCode, maxCode := transferUniqueCodeRange(totalBill)
+	minAmount := totalBill + float64(minCode)
+	maxAmount := totalBill + float64(maxCode)
+
+	// Reservasi order yang lewat jatuh tempo baru dilepas setelah ordernya dibatalkan CancelExpiredTransferOrders
+	transferUniqueAmounts, err := service.TransferUniqueAmountRepositoryInterface.FindTransferUniqueAmountsByRange(tx, idDesa, minAmount, maxAmount)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find transfer unique amount"}, service.Logger, tx)
+	reserved := map[float64]bool{}
+	for _, transferUniqueAmount := range transferUniqueAmounts {
+		reserved[transferUniqueAmount.Amount] = true
+	}
+
+	failure := 0
+	for _, code := range rand.Perm(maxCode - minCode + 1) {
+		amount := totalBill + float64(minCode+code)
+		if reserved[amount] {
+			continue
+		}
+
+		// Gagal insert berarti nominal baru saja diambil request lain, coba kode berikutnya
+		err := service.TransferUniqueAmountRepositoryInterface.CreateTransferUniqueAmount(tx, &entity.TransferUniqueAmount{
+			IdDesa:    idDesa,
+			Amount:    amount,
+			IdOrder:   idOrder,
+			ExpiredAt: expiredAt,
+			CreatedAt: time.Now(),
+		})
+		if err == nil {
+			return amount
+		}
+
+		service.Logger.WithFields(logrus.Fields{"request_id": requestId, "amount": amount, "error": err.Error()}).Warn("reserve transfer unique amount")
+		failure++
+		if failure >= transferReserveMaxFailure {
+			break
+		}
+	}
+
+	tx.Rollback()
+	exceptions.PanicIfBadRequest(errors.New("transfer unique amount not available"), requestId, []string{"kode unik transfer sedang penuh, silakan coba lagi atau gunakan metode pembayaran lain"}, service.Logger)
+	return 0
+}
+
+func (service *OrderServiceImplementation) releaseTransferUniqueAmount(requestId string, db *gorm.DB, order *entity.Order) {
+	if order.PaymentMethod != "trf" {
+		return
+	}
+	err := service.TransferUniqueAmountRepositoryInterface.DeleteTransferUniqueAmountByIdOrder(db, order.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error release transfer unique amount"}, service.Logger, db)
+}
+
+// markOrderPaid update status bayar hanya jika order masih menunggu pembayaran, mencegah order diproses dua kali
+// oleh callback dan verifikasi transfer yang bersamaan
+func (service *OrderServiceImplementation) markOrderPaid(requestId string, tx *gorm.DB, order *entity.Order, orderStatus int, verifiedBy string) {
+	rowsAffected, err := service.OrderRepositoryInterface.UpdateOrderByIdOrderAndStatus(tx, order.Id, 0, &entity.Order{
+		OrderStatus:        orderStatus,
+		PaymentStatus:      1,
+		PaymentSuccessDate: null.NewTime(time.Now(), true),
+		VerifiedBy:         verifiedBy,
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update order"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("order tidak dalam status 0"), requestId, []string{"order tidak dalam status 0"}, service.Logger)
+	}
+
+	service.releaseTransferUniqueAmount(requestId, tx, order)
+}
+
+// CancelExpiredTransferOrders dijalankan scheduler, order transfer yang lewat jatuh tempo dibatalkan
+// sehingga nominal unik baru bisa dipakai order lain setelah order lamanya tidak bisa dibayar
+func (service *OrderServiceImplementation) CancelExpiredTransferOrders() {
+	orders, err := service.OrderRepositoryInterface.FindOrdersTransferExpired(service.DB, time.Now(), transferExpiredBatch)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("cancel expired transfer order")
+		return
+	}
+
+	for i := range orders {
+		if err := service.cancelExpiredTransferOrder(&orders[i]); err != nil {
+			service.Logger.WithFields(logrus.Fields{"number_order": orders[i].NumberOrder, "error": err.Error()}).Error("cancel expired transfer order")
+		}
+	}
+}
+
+func (service *OrderServiceImplementation) cancelExpiredTransferOrder(order *entity.Order) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	service.cancelOrder("cancel-expired-"+order.NumberOrder, "", order, "Dibatalkan otomatis, batas waktu pembayaran lewat")
+	return nil
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
-	"math/rand"
+	"math/big"
 	"net/http"
 	"strings"
 	"time"
@@ -32,31 +35,48 @@ type OtpManagerServiceInterface interface {
 	VerifyOtp(requestId string, verifyOtpRequest *request.VerifyOtpRequest) *response.VerifyOtpResponse
 }
 
+// Tujuan OTP, disimpan di otp_manager.type_otp
+const (
+	OtpTypeRegistration   = 1
+	OtpTypeForgotPassword = 2
+	OtpTypeChangePhone    = 3
+)
+
 type OtpManagerServiceImplementation struct {
 	DB                            *gorm.DB
 	ConfigJwt                     config.Jwt
+	ConfigOtp                     config.Otp
 	Validate                      *validator.Validate
 	Logger                        *logrus.Logger
 	OtpManagerRepositoryInterface repository.OtpManagerRepositoryInterface
 	AuthServiceInterface          AuthServiceInterface
 	UserRepositoryInterface       repository.UserRepositoryInterface
+	FormTokenRepositoryInterface  repository.FormTokenRepositoryInterface
 }
 
 func NewOtpManagerService(
 	db *gorm.DB,
 	configJwt config.Jwt,
+	configOtp config.Otp,
 	validate *validator.Validate,
 	logger *logrus.Logger,
 	otpManaOtpManagerServiceInterface repository.OtpManagerRepositoryInterface,
 	userRepositoryInterface repository.UserRepositoryInterface,
+	formTokenRepositoryInterface repository.FormTokenRepositoryInterface,
 ) OtpManagerServiceInterface {
+	// Tanpa pepper hash kode OTP 6 digit bisa di-brute force dari isi database
+	if len(configOtp.Pepper) == 0 {
+		panic("otp pepper not configured")
+	}
 	return &OtpManagerServiceImplementation{
 		DB:                            db,
 		ConfigJwt:                     configJwt,
+		ConfigOtp:                     configOtp,
 		Validate:                      validate,
 		Logger:                        logger,
 		OtpManagerRepositoryInterface: otpManaOtpManagerServiceInterface,
 		UserRepositoryInterface:       userRepositoryInterface,
+		FormTokenRepositoryInterface:  formTokenRepositoryInterface,
 	}
 }
 
@@ -66,14 +86,25 @@ func GenerateRandomOtpCode() string {
 	length := 6
 
 	for i := 0; i < length; i++ {
-		random := rand.Intn(len(charSet))
-		randomChar := charSet[random]
+		random, err := rand.Int(rand.Reader, big.NewInt(int64(len(charSet))))
+		if err != nil {
+			panic(err)
+		}
+		randomChar := charSet[random.Int64()]
 		output.WriteString(string(randomChar))
 	}
 
 	return output.String()
 }
 
+// HashOtpCode menghasilkan HMAC-SHA256 dari kode OTP dengan pepper server,
+// diikat ke nomor hp dan tujuan OTP
+func HashOtpCode(pepper string, phone string, typeOtp int, otpCode string) string {
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(fmt.Sprintf("%s:%d:%s", phone, typeOtp, otpCode)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, sendOtpBySmsRequest *request.SendOtpBySmsRequest) {
 	var err error
 
@@ -89,19 +120,27 @@ func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, s
 	exceptions.PanicIfError(err, requestId, service.Logger)
 
 	// Find user by phone
-	if sendOtpBySmsRequest.TypeOtp == 1 {
+	if sendOtpBySmsRequest.TypeOtp == OtpTypeRegistration || sendOtpBySmsRequest.TypeOtp == OtpTypeChangePhone {
 		user, _ := service.UserRepositoryInterface.FindUserByPhone(service.DB, sendOtpBySmsRequest.Phone)
 		if len(user.Id) != 0 {
 			exceptions.PanicIfBadRequest(errors.New("phone already user"), requestId, []string{"phone already user"}, service.Logger)
 		}
 	}
 
+	if sendOtpBySmsRequest.TypeOtp == OtpTypeForgotPassword {
+		user, _ := service.UserRepositoryInterface.FindUserByPhone(service.DB, sendOtpBySmsRequest.Phone)
+		if len(user.Id) == 0 {
+			exceptions.PanicIfRecordNotFound(errors.New("user not found"), requestId, []string{"user not found"}, service.Logger)
+		}
+	}
+
 	// validasi data
 	if len(resultOtp.Id) == 0 {
 		otpManagerEntity := &entity.OtpManager{}
+		otpCode := GenerateRandomOtpCode()
 		otpManagerEntity.Id = utilities.RandomUUID()
-		otpManagerEntity.OtpCode = GenerateRandomOtpCode()
-		// otpManagerEntity.OtpCode = "123456"
+		otpManagerEntity.OtpCode = HashOtpCode(service.ConfigOtp.Pepper, sendOtpBySmsRequest.Phone, sendOtpBySmsRequest.TypeOtp, otpCode)
+		otpManagerEntity.TypeOtp = sendOtpBySmsRequest.TypeOtp
 		otpManagerEntity.Phone = sendOtpBySmsRequest.Phone
 		otpManagerEntity.PhoneLimit = 5
 		otpManagerEntity.IpAddressLimit = 5
@@ -109,7 +148,7 @@ func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, s
 		otpManagerEntity.CreatedDate = time.Now()
 
 		// Send OTP
-		go SendOTP(requestId, sendOtpBySmsRequest.Phone, otpManagerEntity.OtpCode)
+		go SendOTP(requestId, service.Logger, sendOtpBySmsRequest.Phone, otpCode)
 
 		createOtpErr := service.OtpManagerRepositoryInterface.CreateOtp(service.DB, otpManagerEntity)
 		exceptions.PanicIfError(createOtpErr, requestId, service.Logger)
@@ -120,9 +159,10 @@ func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, s
 		if resultOtp.PhoneLimit <= 0 {
 			if resultOtp.FreezeDueDate.Time.Before(time.Now()) {
 				otpManagerEntity := &entity.OtpManager{}
+				otpCode := GenerateRandomOtpCode()
 				otpManagerEntity.Id = resultOtp.Id
-				otpManagerEntity.OtpCode = GenerateRandomOtpCode()
-				// otpManagerEntity.OtpCode = "123456"
+				otpManagerEntity.OtpCode = HashOtpCode(service.ConfigOtp.Pepper, resultOtp.Phone, sendOtpBySmsRequest.TypeOtp, otpCode)
+				otpManagerEntity.TypeOtp = sendOtpBySmsRequest.TypeOtp
 				otpManagerEntity.Phone = resultOtp.Phone
 				otpManagerEntity.PhoneLimit = 5
 				otpManagerEntity.IpAddressLimit = 5
@@ -130,7 +170,7 @@ func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, s
 				otpManagerEntity.CreatedDate = time.Now()
 
 				// Send OTP
-				go SendOTP(requestId, resultOtp.Phone, otpManagerEntity.OtpCode)
+				go SendOTP(requestId, service.Logger, resultOtp.Phone, otpCode)
 
 				updateOtpErr := service.OtpManagerRepositoryInterface.UpdateOtp(service.DB, resultOtp.Id, otpManagerEntity)
 				exceptions.PanicIfError(updateOtpErr, requestId, service.Logger)
@@ -140,9 +180,10 @@ func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, s
 			}
 		} else {
 			otpManagerEntity := &entity.OtpManager{}
+			otpCode := GenerateRandomOtpCode()
 			otpManagerEntity.Id = resultOtp.Id
-			otpManagerEntity.OtpCode = GenerateRandomOtpCode()
-			// otpManagerEntity.OtpCode = "123456"
+			otpManagerEntity.OtpCode = HashOtpCode(service.ConfigOtp.Pepper, resultOtp.Phone, sendOtpBySmsRequest.TypeOtp, otpCode)
+			otpManagerEntity.TypeOtp = sendOtpBySmsRequest.TypeOtp
 			otpManagerEntity.Phone = resultOtp.Phone
 			if resultOtp.PhoneLimit <= 1 {
 				otpManagerEntity.FreezeDueDate = null.NewTime(time.Now().Add(time.Hour*24), true)
@@ -153,7 +194,7 @@ func (service *OtpManagerServiceImplementation) SendOtpBySms(requestId string, s
 			otpManagerEntity.CreatedDate = time.Now()
 
 			// Send OTP
-			go SendOTP(requestId, resultOtp.Phone, otpManagerEntity.OtpCode)
+			go SendOTP(requestId, service.Logger, resultOtp.Phone, otpCode)
 
 			updateOtpErr := service.OtpManagerRepositoryInterface.UpdateOtp(service.DB, resultOtp.Id, otpManagerEntity)
 			exceptions.PanicIfError(updateOtpErr, requestId, service.Logger)
@@ -169,28 +210,52 @@ func (service *OtpManagerServiceImplementation) VerifyOtp(requestId string, veri
 		exceptions.PanicIfRecordNotFound(errors.New("otp not found"), requestId, []string{"otp not found"}, service.Logger)
 	}
 
-	if verifyOtpRequest.OtpCode != otp.OtpCode {
+	if otp.TypeOtp != verifyOtpRequest.TypeOtp {
+		exceptions.PanicIfBadRequest(errors.New("otp type not match"), requestId, []string{"otp not match"}, service.Logger)
+	}
+
+	otpCodeHash := HashOtpCode(service.ConfigOtp.Pepper, otp.Phone, otp.TypeOtp, verifyOtpRequest.OtpCode)
+	if !hmac.Equal([]byte(otpCodeHash), []byte(otp.OtpCode)) {
 		exceptions.PanicIfBadRequest(errors.New("otp not match"), requestId, []string{"otp not match"}, service.Logger)
 	}
 
 	if time.Now().After(otp.OtpExperiedAt) {
 		exceptions.PanicIfBadRequest(errors.New("otp code has expired"), requestId, []string{"otp code has expired"}, service.Logger)
 	}
-	var userModelService modelService.User
-	userModelService.Phone = otp.Phone
-	token, _ := service.GenerateFormToken(userModelService)
+
+	// OTP hanya bisa dipakai sekali
+	err := service.OtpManagerRepositoryInterface.ExpireOtp(service.DB, otp.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	token, err := service.GenerateFormToken(otp)
+	exceptions.PanicIfError(err, requestId, service.Logger)
 	verifyOtpResponse := response.ToVerifyOtpResponse(token)
 
 	return &verifyOtpResponse
 
 }
 
-func (service *OtpManagerServiceImplementation) GenerateFormToken(user modelService.User) (token string, err error) {
+// GenerateFormToken membuat form token sekali pakai yang terikat ke tujuan dan record OTP,
+// jti token dicatat di tabel form_token
+func (service *OtpManagerServiceImplementation) GenerateFormToken(otp *entity.OtpManager) (token string, err error) {
+	expiredAt := time.Now().Add(time.Minute * time.Duration(10))
+
+	formTokenEntity := &entity.FormToken{
+		Id:           utilities.RandomUUID(),
+		IdOtpManager: otp.Id,
+		Phone:        otp.Phone,
+		TypeOtp:      otp.TypeOtp,
+		ExpiredAt:    expiredAt,
+		CreatedDate:  time.Now(),
+	}
+
 	// Create the Claims
 	claims := modelService.TokenClaims{
-		Phone: user.Phone,
+		Phone:   otp.Phone,
+		TypeOtp: otp.TypeOtp,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Minute * time.Duration(10)).Unix(),
+			Id:        formTokenEntity.Id,
+			ExpiresAt: expiredAt.Unix(),
 			Issuer:    "cyrilia",
 		},
 	}
@@ -200,12 +265,31 @@ func (service *OtpManagerServiceImplementation) GenerateFormToken(user modelServ
 	if err != nil {
 		return "", err
 	}
+
+	err = service.FormTokenRepositoryInterface.CreateFormToken(service.DB, formTokenEntity)
+	if err != nil {
+		return "", err
+	}
 	return token, err
 }
 
-func SendOTP(requestId string, phone string, otpCode string) {
+// SendSms mengirim sms notifikasi, response provider dicatat tanpa nomor hp lengkap dan isi pesan
+func SendSms(logger *logrus.Logger, phone string, message string) {
+	sendZenziva(logger, "", "https://console.zenziva.net/masking/api/sendsms", phone, message)
+}
+
+func SendOTP(requestId string, logger *logrus.Logger, phone string, otpCode string) {
 	message := fmt.Sprintf("Kode Verifikasi Akun Bupda Bali Anda adalah: %s *JANGAN BERIKAN KODE INI KEPADA SIAPAPUN, TERMASUK PIHAK BUPDA BALI* Hubungi 085960144218 untuk bantuan.", otpCode)
+	sendZenziva(logger, requestId, "https://console.zenziva.net/masking/api/sendOTP", phone, message)
+}
+
+type zenzivaResponse struct {
+	MessageId interface{} `json:"messageId"`
+	Status    interface{} `json:"status"`
+	Text      string      `json:"text"`
+}
 
+func sendZenziva(logger *logrus.Logger, requestId string, url string, phone string, message string) {
 	postBody, _ := json.Marshal(map[string]string{
 		"userkey": config.GetConfig().Sms.UserKey,
 		"passkey": config.GetConfig().Sms.PassKey,
@@ -213,19 +297,36 @@ func SendOTP(requestId string, phone string, otpCode string) {
 		"message": message,
 	})
 
-	responseBody := bytes.NewBuffer(postBody)
-	//Leverage Go's HTTP Post function to make request
-	resp, err := http.Post("https://console.zenziva.net/masking/api/sendOTP", "application/json", responseBody)
-	//Handle Error
+	fields := logrus.Fields{"request_id": requestId, "phone": maskPhone(phone)}
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(postBody))
 	if err != nil {
-		log.Printf("An Error Occured %v", err)
+		logger.WithFields(fields).Error(err)
+		return
 	}
 	defer resp.Body.Close()
-	//Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Println(err)
+		logger.WithFields(fields).Error(err)
+		return
+	}
+
+	// Hanya status dari response yang dicatat, body bisa berisi nomor hp dan isi pesan
+	smsResponse := &zenzivaResponse{}
+	json.Unmarshal(body, smsResponse)
+	fields["http_status"] = resp.StatusCode
+	fields["status"] = smsResponse.Status
+	fields["text"] = smsResponse.Text
+	if resp.StatusCode != http.StatusOK || fmt.Sprint(smsResponse.Status) != "1" {
+		logger.WithFields(fields).Warn("sms not sent")
+		return
+	}
+	logger.WithFields(fields).Info("sms sent")
+}
+
+// maskPhone menyisakan 4 digit terakhir nomor hp untuk log
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return strings.Repeat("*", len(phone))
 	}
-	sb := string(body)
-	log.Println(sb)
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
 }
@@ -1,23 +1,63 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/go-playground/validator"
 	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
 )
 
+// Keterangan ledger pembalikan point, hanya untuk ditampilkan ke user
+const (
+	pointReverseDescription      = "Pengembalian point order dibatalkan"
+	pointBonusReverseDescription = "Pembatalan bonus point order"
+)
+
+// Jenis mutasi point di ledger, PointEntryReverse menandai order yang sudah dibalik
+const (
+	PointEntryRedeem  = 1
+	PointEntryEarn    = 2
+	PointEntryReverse = 3
+	PointEntryExpire  = 4
+)
+
 type PointServiceInterface interface {
 	FindPointByUser(requestId string, idUser string) (pointResponse response.FindPointByUserResponse)
+	FindPointHistoryByUser(requestId string, idUser string, page int, limit int) (pointHistoryResponse response.FindPointHistoryResponse)
+	FindPointRulesByDesa(requestId string, idDesa string) (pointRuleResponses []response.FindPointRuleResponse)
+	CreatePointRule(requestId string, idDesa string, createPointRuleRequest *request.CreatePointRuleRequest)
+	UpdatePointRule(requestId string, idDesa string, updatePointRuleRequest *request.UpdatePointRuleRequest)
+	CheckPointBalance(requestId string, idUser string, amount float64)
+	RedeemPoint(requestId string, tx *gorm.DB, idUser string, idOrder string, amount float64, description string)
+	EarnOrderPoint(requestId string, tx *gorm.DB, order *entity.Order)
+	ReverseOrderPoint(requestId string, tx *gorm.DB, idOrder string)
+	ExpirePoints()
 }
 
 type PointServiceImplementation struct {
-	DB                       *gorm.DB
-	Validate                 *validator.Validate
-	Logger                   *logrus.Logger
-	PointRepositoryInterface repository.PointRepositoryInterface
+	DB                              *gorm.DB
+	Validate                        *validator.Validate
+	Logger                          *logrus.Logger
+	PointRepositoryInterface        repository.PointRepositoryInterface
+	ConfigPoint                     config.Point
+	PointHistoryRepositoryInterface repository.PointHistoryRepositoryInterface
+	PointRuleRepositoryInterface    repository.PointRuleRepositoryInterface
+	OrderRepositoryInterface        repository.OrderRepositoryInterface
+	OrderItemRepositoryInterface    repository.OrderItemRepositoryInterface
+	ProductDesaRepositoryInterface  repository.ProductDesaRepositoryInterface
+	ReferralRepositoryInterface     repository.ReferralRepositoryInterface
 }
 
 func NewPointService(
@@ -25,12 +65,26 @@ func NewPointService(
 	validate *validator.Validate,
 	logger *logrus.Logger,
 	pointServiceInterface repository.PointRepositoryInterface,
+	configPoint config.Point,
+	pointHistoryRepositoryInterface repository.PointHistoryRepositoryInterface,
+	pointRuleRepositoryInterface repository.PointRuleRepositoryInterface,
+	orderRepositoryInterface repository.OrderRepositoryInterface,
+	orderItemRepositoryInterface repository.OrderItemRepositoryInterface,
+	productDesaRepositoryInterface repository.ProductDesaRepositoryInterface,
+	referralRepositoryInterface repository.ReferralRepositoryInterface,
 ) PointServiceInterface {
 	return &PointServiceImplementation{
-		DB:                       db,
-		Validate:                 validate,
-		Logger:                   logger,
-		PointRepositoryInterface: pointServiceInterface,
+		DB:                              db,
+		Validate:                        validate,
+		Logger:                          logger,
+		PointRepositoryInterface:        pointServiceInterface,
+		ConfigPoint:                     configPoint,
+		PointHistoryRepositoryInterface: pointHistoryRepositoryInterface,
+		PointRuleRepositoryInterface:    pointRuleRepositoryInterface,
+		OrderRepositoryInterface:        orderRepositoryInterface,
+		OrderItemRepositoryInterface:    orderItemRepositoryInterface,
+		ProductDesaRepositoryInterface:  productDesaRepositoryInterface,
+		ReferralRepositoryInterface:     referralRepositoryInterface,
 	}
 }
 
@@ -40,3 +94,343 @@ func (service *PointServiceImplementation) FindPointByUser(requestId string, idU
 	pointResponse = response.ToFindPointByUserResponse(point)
 	return pointResponse
 }
+
+func (service *PointServiceImplementation) FindPointHistoryByUser(requestId string, idUser string, page int, limit int) (pointHistoryResponse response.FindPointHistoryResponse) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	pointHistories, total, err := service.PointHistoryRepositoryInterface.FindPointHistoryByUser(service.DB, idUser, limit, (page-1)*limit)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	pointHistoryResponse = response.ToFindPointHistoryResponse(pointHistories, page, limit, total)
+	return pointHistoryResponse
+}
+
+func (service *PointServiceImplementation) FindPointRulesByDesa(requestId string, idDesa string) (pointRuleResponses []response.FindPointRuleResponse) {
+	pointRules, err := service.PointRuleRepositoryInterface.FindPointRulesByDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(pointRules) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("point rule not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+	pointRuleResponses = response.ToFindPointRuleResponses(pointRules)
+	return pointRuleResponses
+}
+
+func (service *PointServiceImplementation) CreatePointRule(requestId string, idDesa string, createPointRuleRequest *request.CreatePointRuleRequest) {
+	request.ValidateRequest(service.Validate, createPointRuleRequest, requestId, service.Logger)
+	service.validatePointRule(requestId, idDesa, createPointRuleRequest)
+
+	err := service.PointRuleRepositoryInterface.CreatePointRule(service.DB, &entity.PointRule{
+		Id:            utilities.RandomUUID(),
+		IdDesa:        idDesa,
+		RuleType:      createPointRuleRequest.RuleType,
+		IdProductDesa: createPointRuleRequest.IdProductDesa,
+		MinAmount:     createPointRuleRequest.MinAmount,
+		Point:         createPointRuleRequest.Point,
+		Description:   createPointRuleRequest.Description,
+		IsActive:      createPointRuleRequest.IsActive,
+		CreatedAt:     time.Now(),
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *PointServiceImplementation) UpdatePointRule(requestId string, idDesa string, updatePointRuleRequest *request.UpdatePointRuleRequest) {
+	request.ValidateRequest(service.Validate, updatePointRuleRequest, requestId, service.Logger)
+	service.validatePointRule(requestId, idDesa, &updatePointRuleRequest.CreatePointRuleRequest)
+
+	pointRule, err := service.PointRuleRepositoryInterface.FindPointRuleById(service.DB, updatePointRuleRequest.IdPointRule)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(pointRule.Id) == 0 || pointRule.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("point rule not found"), requestId, []string{"point rule not found"}, service.Logger)
+	}
+
+	err = service.PointRuleRepositoryInterface.UpdatePointRule(service.DB, pointRule.Id, &entity.PointRule{
+		RuleType:      updatePointRuleRequest.RuleType,
+		IdProductDesa: updatePointRuleRequest.IdProductDesa,
+		MinAmount:     updatePointRuleRequest.MinAmount,
+		Point:         updatePointRuleRequest.Point,
+		Description:   updatePointRuleRequest.Description,
+		IsActive:      updatePointRuleRequest.IsActive,
+		UpdatedAt:     null.NewTime(time.Now(), true),
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+// CheckPointBalance cek awal sebelum order dibuat, pengurangan sebenarnya di RedeemPoint
+func (service *PointServiceImplementation) CheckPointBalance(requestId string, idUser string, amount float64) {
+	point, err := service.PointRepositoryInterface.FindPointByUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(point.Id) == 0 || point.JmlPoint < amount {
+		exceptions.PanicIfBadRequest(errors.New("point not enough"), requestId, []string{"point tidak cukup"}, service.Logger)
+	}
+}
+
+// RedeemPoint mengurangi saldo secara atomic lalu memakai sisa kredit point yang paling dulu hangus
+func (service *PointServiceImplementation) RedeemPoint(requestId string, tx *gorm.DB, idUser string, idOrder string, amount float64, description string) {
+	point, err := service.PointRepositoryInterface.FindPointByUser(tx, idUser)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point"}, service.Logger, tx)
+
+	rowsAffected, err := service.PointRepositoryInterface.DecreasePoint(tx, idUser, amount)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update point"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("point not enough"), requestId, []string{"point tidak cukup"}, service.Logger)
+	}
+
+	now := time.Now()
+	pointCredits, err := service.PointHistoryRepositoryInterface.FindAvailablePointCredit(tx, idUser, now)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point history"}, service.Logger, tx)
+
+	// Saldo lama yang belum tercatat di ledger tidak punya kredit, cukup dipotong dari saldo
+	rest := amount
+	for _, pointCredit := range pointCredits {
+		if rest <= 0 {
+			break
+		}
+		used := math.Min(rest, pointCredit.Remaining)
+		err = service.PointHistoryRepositoryInterface.UpdatePointHistoryRemaining(tx, pointCredit.Id, pointCredit.Remaining-used)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update point history"}, service.Logger, tx)
+		rest = rest - used
+	}
+
+	err = service.PointHistoryRepositoryInterface.CreatePointHistory(tx, &entity.PointHistory{
+		Id:          utilities.RandomUUID(),
+		IdPoint:     point.Id,
+		IdUser:      idUser,
+		IdOrder:     idOrder,
+		Debit:       amount,
+		Description: description,
+		EntryType:   PointEntryRedeem,
+		TransDate:   now,
+		CreatedDate: now,
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create point history"}, service.Logger, tx)
+}
+
+// EarnOrderPoint menghitung bonus point dari aturan desa saat order sembako selesai
+func (service *PointServiceImplementation) EarnOrderPoint(requestId string, tx *gorm.DB, order *entity.Order) {
+	pointRules, err := service.PointRuleRepositoryInterface.FindPointRulesByDesa(tx, order.IdDesa)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point rule"}, service.Logger, tx)
+	if len(pointRules) == 0 {
+		return
+	}
+
+	orderItems, err := service.OrderItemRepositoryInterface.FindOrderItemsByIdOrder(tx, order.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find order items"}, service.Logger, tx)
+
+	var earned, referrerReward, refereeReward float64
+	for _, pointRule := range pointRules {
+		if pointRule.IsActive != 1 {
+			continue
+		}
+		switch pointRule.RuleType {
+		case 1:
+			if pointRule.MinAmount > 0 {
+				earned = earned + math.Floor((order.SubTotal-order.Discount)/pointRule.MinAmount)*pointRule.Point
+			}
+		case 2:
+			for _, orderItem := range orderItems {
+				if orderItem.IdProductDesa == pointRule.IdProductDesa {
+					earned = earned + pointRule.Point*float64(orderItem.Qty)
+				}
+			}
+		case 3:
+			completedOrder, err := service.OrderRepositoryInterface.CountCompletedOrderByUser(tx, order.IdUser, order.Id)
+			exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error count order"}, service.Logger, tx)
+			if completedOrder == 0 {
+				earned = earned + pointRule.Point
+			}
+		case 4:
+			referrerReward = referrerReward + pointRule.Point
+		case 5:
+			refereeReward = refereeReward + pointRule.Point
+		}
+	}
+
+	if referrerReward > 0 || refereeReward > 0 {
+		service.rewardReferral(requestId, tx, order, referrerReward, refereeReward)
+	}
+
+	if earned <= 0 {
+		return
+	}
+
+	point := service.findOrCreatePoint(requestId, tx, order.IdUser)
+	service.creditPoint(requestId, tx, point, order.Id, earned, "Bonus point order "+order.NumberOrder, PointEntryEarn)
+}
+
+// rewardReferral memberi point ke referrer dan referee saat referee menyelesaikan order pertama
+func (service *PointServiceImplementation) rewardReferral(requestId string, tx *gorm.DB, order *entity.Order, referrerReward float64, refereeReward float64) {
+	referral, err := service.ReferralRepositoryInterface.FindReferralByIdReferee(tx, order.IdUser)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find referral"}, service.Logger, tx)
+	if len(referral.Id) == 0 || referral.Status != 1 {
+		return
+	}
+
+	completedOrder, err := service.OrderRepositoryInterface.CountCompletedOrderByUser(tx, order.IdUser, order.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error count order"}, service.Logger, tx)
+	if completedOrder != 0 {
+		return
+	}
+
+	rowsAffected, err := service.ReferralRepositoryInterface.UpdateReferralRewarded(tx, referral.Id, referrerReward, refereeReward)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update referral"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		return
+	}
+
+	if referrerReward > 0 {
+		referrerPoint := service.findOrCreatePoint(requestId, tx, referral.IdReferrer)
+		service.creditPoint(requestId, tx, referrerPoint, order.Id, referrerReward, "Bonus referral "+referral.ReferralCode, PointEntryEarn)
+	}
+
+	if refereeReward > 0 {
+		refereePoint := service.findOrCreatePoint(requestId, tx, order.IdUser)
+		service.creditPoint(requestId, tx, refereePoint, order.Id, refereeReward, "Bonus referral "+referral.ReferralCode, PointEntryEarn)
+	}
+}
+
+func (service *PointServiceImplementation) findOrCreatePoint(requestId string, tx *gorm.DB, idUser string) *entity.Point {
+	point, err := service.PointRepositoryInterface.FindPointByUser(tx, idUser)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point"}, service.Logger, tx)
+	if len(point.Id) == 0 {
+		point = &entity.Point{
+			Id:          utilities.RandomUUID(),
+			IdUser:      idUser,
+			StatusPoint: 1,
+			CreatedDate: time.Now(),
+		}
+		err = service.PointRepositoryInterface.CreatePoint(tx, point)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create point"}, service.Logger, tx)
+	}
+	return point
+}
+
+// ReverseOrderPoint dipanggil di transaksi pembatalan order, point yang dipakai dikembalikan dan
+// point bonus yang belum terpakai ditarik kembali. Order yang sudah pernah dibalik dilewati.
+func (service *PointServiceImplementation) ReverseOrderPoint(requestId string, tx *gorm.DB, idOrder string) {
+	pointHistories, err := service.PointHistoryRepositoryInterface.FindPointHistoryByIdOrder(tx, idOrder)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point history"}, service.Logger, tx)
+	for _, pointHistory := range pointHistories {
+		if pointHistory.EntryType == PointEntryReverse {
+			return
+		}
+	}
+
+	for _, pointHistory := range pointHistories {
+		point, err := service.PointRepositoryInterface.FindPointByUser(tx, pointHistory.IdUser)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point"}, service.Logger, tx)
+
+		if pointHistory.Debit > 0 {
+			service.creditPoint(requestId, tx, point, idOrder, pointHistory.Debit, pointReverseDescription, PointEntryReverse)
+		} else if pointHistory.Kredit > 0 && pointHistory.Remaining > 0 {
+			service.debitPoint(requestId, tx, point, idOrder, pointHistory, math.Min(pointHistory.Remaining, point.JmlPoint), pointBonusReverseDescription, PointEntryReverse)
+		}
+	}
+}
+
+// ExpirePoints dijalankan scheduler, sisa kredit point yang melewati masa berlaku dihanguskan
+func (service *PointServiceImplementation) ExpirePoints() {
+	pointCredits, err := service.PointHistoryRepositoryInterface.FindExpiredPointCredit(service.DB, time.Now(), 500)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("expire points")
+		return
+	}
+
+	for _, pointCredit := range pointCredits {
+		if err := service.expirePointCredit(pointCredit); err != nil {
+			service.Logger.WithFields(logrus.Fields{"id_point_history": pointCredit.Id, "error": err.Error()}).Error("expire points")
+		}
+	}
+}
+
+func (service *PointServiceImplementation) expirePointCredit(pointCredit entity.PointHistory) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	requestId := "expire-point-" + pointCredit.Id
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	point, err := service.PointRepositoryInterface.FindPointByUser(tx, pointCredit.IdUser)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find point"}, service.Logger, tx)
+
+	service.debitPoint(requestId, tx, point, "", pointCredit, math.Min(pointCredit.Remaining, point.JmlPoint), "Point hangus", PointEntryExpire)
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+	return nil
+}
+
+func (service *PointServiceImplementation) creditPoint(requestId string, tx *gorm.DB, point *entity.Point, idOrder string, amount float64, description string, entryType int) {
+	expiryDays := service.ConfigPoint.ExpiryDays
+	if expiryDays == 0 {
+		expiryDays = 365
+	}
+
+	err := service.PointRepositoryInterface.IncreasePoint(tx, point.IdUser, amount)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update point"}, service.Logger, tx)
+
+	now := time.Now()
+	err = service.PointHistoryRepositoryInterface.CreatePointHistory(tx, &entity.PointHistory{
+		Id:          utilities.RandomUUID(),
+		IdPoint:     point.Id,
+		IdUser:      point.IdUser,
+		IdOrder:     idOrder,
+		Kredit:      amount,
+		Remaining:   amount,
+		Description: description,
+		EntryType:   entryType,
+		TransDate:   now,
+		ExpiredDate: null.NewTime(now.AddDate(0, 0, int(expiryDays)), true),
+		CreatedDate: now,
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create point history"}, service.Logger, tx)
+}
+
+// debitPoint menutup sisa kredit pointCredit dan mengurangi saldo sebesar amount
+func (service *PointServiceImplementation) debitPoint(requestId string, tx *gorm.DB, point *entity.Point, idOrder string, pointCredit entity.PointHistory, amount float64, description string, entryType int) {
+	err := service.PointHistoryRepositoryInterface.UpdatePointHistoryRemaining(tx, pointCredit.Id, 0)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update point history"}, service.Logger, tx)
+	if amount <= 0 {
+		return
+	}
+
+	_, err = service.PointRepositoryInterface.DecreasePoint(tx, point.IdUser, amount)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update point"}, service.Logger, tx)
+
+	now := time.Now()
+	err = service.PointHistoryRepositoryInterface.CreatePointHistory(tx, &entity.PointHistory{
+		Id:          utilities.RandomUUID(),
+		IdPoint:     point.Id,
+		IdUser:      point.IdUser,
+		IdOrder:     idOrder,
+		Debit:       amount,
+		Description: description,
+		EntryType:   entryType,
+		TransDate:   now,
+		CreatedDate: now,
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create point history"}, service.Logger, tx)
+}
+
+func (service *PointServiceImplementation) validatePointRule(requestId string, idDesa string, createPointRuleRequest *request.CreatePointRuleRequest) {
+	switch createPointRuleRequest.RuleType {
+	case 1:
+		if createPointRuleRequest.MinAmount <= 0 {
+			exceptions.PanicIfBadRequest(errors.New("invalid min amount"), requestId, []string{"min amount harus lebih dari 0"}, service.Logger)
+		}
+	case 2:
+		productDesa, err := service.ProductDesaRepositoryInterface.FindProductDesaById(service.DB, createPointRuleRequest.IdProductDesa)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(productDesa.Id) == 0 || productDesa.IdDesa != idDesa {
+			exceptions.PanicIfRecordNotFound(errors.New("product not found"), requestId, []string{"product not found"}, service.Logger)
+		}
+	}
+}
@@ -1,49 +1,46 @@
 package service
 
 import (
-	"crypto/md5"
-	"encoding/hex"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"strings"
 
 	"github.com/go-playground/validator"
 	"github.com/sirupsen/logrus"
-	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	ppobrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/ppob_repository"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
 	"gorm.io/gorm"
 )
 
 type PpobServiceInterface interface {
-	GetPrepaidPulsaPriceList(requestId string, numberPhone string) (priceListResponse []response.GetPrepaidPriceListResponse)
-	GetPrepaidDataPriceList(requestId string, numberPhone string) (priceListResponse []response.GetPrepaidPriceListResponse)
-	GetPrepaidPlnPriceList(requestId string, idPelanggan string) (priceListResponse []response.GetPrepaidPriceListResponse)
+	GetPrepaidPulsaPriceList(requestId string, idDesa string, numberPhone string) (priceListResponse []response.GetPrepaidPriceListResponse)
+	GetPrepaidDataPriceList(requestId string, idDesa string, numberPhone string) (priceListResponse []response.GetPrepaidPriceListResponse)
+	GetPrepaidPlnPriceList(requestId string, idDesa string, idPelanggan string) (priceListResponse []response.GetPrepaidPriceListResponse)
 	GetPostpaidPdamProduct(requestId string) (postPaidPadmProductResponse []response.GetPostpaidPdamProductResponse)
 	GetPostpaidTelcoProduct(requestId string) (postPaidTelcoProductResponse []response.GetPostpaidTelcoProductResponse)
 	InquiryPrepaidPln(requestId string, inquiryPrepaidPlnRequest *request.InquiryPrepaidPlnRequest) (inquiryPrepaidPlnResponse response.InquiryPrepaidPlnResponse)
 	InquiryPostpaidPln(requestId string, inquiryPostpaidPlnRequest *request.InquiryPostpaidPlnRequest) (inquiryPostpadPlnResponse response.InquiryPostpaidPlnResponse)
 	InquiryPostpaidPdam(requestId string, inquiryPostpaidPdamRequest *request.InquiryPostpaidPdamRequest) (inquiryPostpaidPdamResponse response.InquiryPostpaidPdamResponse)
 	InquiryPostpaidTelco(requestId string, inquiryPostpaidTelcoRequest *request.InquiryPostpaidTelcoRequest) (inquiryPostpaidTelcoResponse response.InquiryPostpaidTelcoResponse)
-	PrepaidCheckStatusTransaction(requestId, NumberOrder string)
+	FindPpobProductTypes(requestId string) (ppobProductTypeResponses []response.FindPpobProductTypeResponse)
+	FindPpobProducts(requestId string, idDesa string, productType string) (ppobProductResponses interface{})
+	InquiryPpob(requestId string, productType string, inquiryPpobRequest *request.InquiryPpobRequest) (inquiryPpobResponse response.InquiryPpobResponse)
 }
 
 type PpobServiceImplementation struct {
-	DB                                *gorm.DB
-	Validate                          *validator.Validate
-	Logger                            *logrus.Logger
-	OperatorPrefixRepositoryInterface repository.OperatorPrefixRepositoryInterface
-	OrderServiceInterface             OrderServiceInterface
+	DB                                 *gorm.DB
+	Validate                           *validator.Validate
+	Logger                             *logrus.Logger
+	OperatorPrefixRepositoryInterface  repository.OperatorPrefixRepositoryInterface
+	OrderServiceInterface              OrderServiceInterface
+	PpobProviderInterface              ppobrepository.PpobProviderInterface
+	PpobPriceServiceInterface          PpobPriceServiceInterface
+	PpobProductTypeRepositoryInterface repository.PpobProductTypeRepositoryInterface
 }
 
 func NewPpobService(
@@ -52,13 +49,19 @@ func NewPpobService(
 	logger *logrus.Logger,
 	operatorPrefixRepositoryInterface repository.OperatorPrefixRepositoryInterface,
 	orderServiceInterface OrderServiceInterface,
+	ppobProviderInterface ppobrepository.PpobProviderInterface,
+	ppobPriceServiceInterface PpobPriceServiceInterface,
+	ppobProductTypeRepositoryInterface repository.PpobProductTypeRepositoryInterface,
 ) PpobServiceInterface {
 	return &PpobServiceImplementation{
-		DB:                                db,
-		Validate:                          validate,
-		Logger:                            logger,
-		OperatorPrefixRepositoryInterface: operatorPrefixRepositoryInterface,
-		OrderServiceInterface:             orderServiceInterface,
+		DB:                                 db,
+		Validate:                           validate,
+		Logger:                             logger,
+		OperatorPrefixRepositoryInterface:  operatorPrefixRepositoryInterface,
+		OrderServiceInterface:              orderServiceInterface,
+		PpobProviderInterface:              ppobProviderInterface,
+		PpobPriceServiceInterface:          ppobPriceServiceInterface,
+		PpobProductTypeRepositoryInterface: ppobProductTypeRepositoryInterface,
 	}
 }
 
@@ -68,13 +71,13 @@ func PrefixNumber(phone string) string {
 	return phoneJoin
 }
 
-func (service *PpobServiceImplementation) GetPrepaidPlnPriceList(requestId string, idPelanggan string) (priceListResponses []response.GetPrepaidPriceListResponse) {
-	prepaidPlnPriceList := service.GetPrepaidPriceList(requestId, idPelanggan, "pln", "pln")
-	priceListResponses = response.ToGetPrepaidPriceListResponse(prepaidPlnPriceList.Data.Data)
+func (service *PpobServiceImplementation) GetPrepaidPlnPriceList(requestId string, idDesa string, idPelanggan string) (priceListResponses []response.GetPrepaidPriceListResponse) {
+	prepaidPlnPriceList := service.PpobPriceServiceInterface.FindPrepaidPriceList(requestId, idDesa, "pln", "pln")
+	priceListResponses = response.ToGetPrepaidPriceListResponse(prepaidPlnPriceList)
 	return priceListResponses
 }
 
-func (service *PpobServiceImplementation) GetPrepaidPulsaPriceList(requestId string, numberPhone string) (priceListResponses []response.GetPrepaidPriceListResponse) {
+func (service *PpobServiceImplementation) GetPrepaidPulsaPriceList(requestId string, idDesa string, numberPhone string) (priceListResponses []response.GetPrepaidPriceListResponse) {
 	phone := PrefixNumber(numberPhone)
 
 	opereratorPrefixResult, err := service.OperatorPrefixRepositoryInterface.FindOperatorPrefixByPhone(service.DB, phone)
@@ -83,12 +86,12 @@ func (service *PpobServiceImplementation) GetPrepaidPulsaPriceList(requestId str
 		exceptions.PanicIfRecordNotFound(errors.New("operator tidak ditemukan"), requestId, []string{"operator not found"}, service.Logger)
 	}
 
-	prepaidPulsaPriceList := service.GetPrepaidPriceList(requestId, numberPhone, "pulsa", opereratorPrefixResult.KodeOperator)
-	priceListResponses = response.ToGetPrepaidPriceListResponse(prepaidPulsaPriceList.Data.Data)
+	prepaidPulsaPriceList := service.PpobPriceServiceInterface.FindPrepaidPriceList(requestId, idDesa, "pulsa", opereratorPrefixResult.KodeOperator)
+	priceListResponses = response.ToGetPrepaidPriceListResponse(prepaidPulsaPriceList)
 	return priceListResponses
 }
 
-func (service *PpobServiceImplementation) GetPrepaidDataPriceList(requestId string, numberPhone string) (priceListResponses []response.GetPrepaidPriceListResponse) {
+func (service *PpobServiceImplementation) GetPrepaidDataPriceList(requestId string, idDesa string, numberPhone string) (priceListResponses []response.GetPrepaidPriceListResponse) {
 	phone := PrefixNumber(numberPhone)
 
 	opereratorPrefixResult, err := service.OperatorPrefixRepositoryInterface.FindOperatorPrefixByPhone(service.DB, phone)
@@ -97,202 +100,38 @@ func (service *PpobServiceImplementation) GetPrepaidDataPriceList(requestId stri
 		exceptions.PanicIfRecordNotFound(errors.New("operator tidak ditemukan"), requestId, []string{"operator not found"}, service.Logger)
 	}
 
-	prepaidDataPriceList := service.GetPrepaidPriceList(requestId, numberPhone, "data", opereratorPrefixResult.KodeOperator)
-	priceListResponses = response.ToGetPrepaidDataPriceListResponse(prepaidDataPriceList.Data.Data)
+	prepaidDataPriceList := service.PpobPriceServiceInterface.FindPrepaidPriceList(requestId, idDesa, "data", opereratorPrefixResult.KodeOperator)
+	priceListResponses = response.ToGetPrepaidDataPriceListResponse(prepaidDataPriceList)
 	return priceListResponses
 }
 
-func (service *PpobServiceImplementation) GetPrepaidPriceList(requestId string, id, tipe, operator string) *ppob.PrepaidPriceListResponse {
-
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "pl"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"status":   "all",
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/pricelist" + "/" + tipe + "/" + operator
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	// fmt.Printf("body: %s\n", data)
-
-	defer resp.Body.Close()
-
-	prepaidPriceList := &ppob.PrepaidPriceListResponse{}
-	// fmt.Printf("body: %s\n", prepaidPriceList)
-
-	if err = json.Unmarshal([]byte(data), &prepaidPriceList); err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	if prepaidPriceList.Data.Rc != "00" {
-		// fmt.Printf("body: %s\n", prepaidPriceList.Data)
-		exceptions.PanicIfError(errors.New("error from IAK"), requestId, service.Logger)
-	}
-
-	return prepaidPriceList
-}
-
 func (service *PpobServiceImplementation) InquiryPrepaidPln(requestId string, inquiryPrepaidPlnRequest *request.InquiryPrepaidPlnRequest) (inquiryPrepaidPlnResponse response.InquiryPrepaidPlnResponse) {
-	var err error
-
 	request.ValidateRequest(service.Validate, inquiryPrepaidPlnRequest, requestId, service.Logger)
 
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + inquiryPrepaidPlnRequest.CustomerId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"username":    config.GetConfig().Ppob.Username,
-		"customer_id": inquiryPrepaidPlnRequest.CustomerId,
-		"sign":        hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/inquiry-pln"
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	inquiryPrepaidPln := &ppob.InquiryPrepaidPln{}
-
-	if err = json.Unmarshal([]byte(data), &inquiryPrepaidPln); err != nil {
-		inquiryPrepaidPlnErrorHandle := &ppob.InquiryPrepaidPlnErrorHandle{}
-		if err = json.Unmarshal([]byte(data), &inquiryPrepaidPlnErrorHandle); err != nil {
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		} else {
-			if inquiryPrepaidPlnErrorHandle.Data.Rc == "208" {
-				exceptions.PanicIfBadRequest(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-			}
-			if inquiryPrepaidPlnErrorHandle.Data.Rc == "14" {
-				exceptions.PanicIfBadRequest(errors.New("costumer id not found"), requestId, []string{"Costumer Id Not Found"}, service.Logger)
-			}
-			exceptions.PanicIfError(err, requestId, service.Logger)
-		}
-	}
-
-	if inquiryPrepaidPln.Data.Rc != "00" {
-		fmt.Printf("body: %s\n", inquiryPrepaidPln.Data)
-		exceptions.PanicIfError(errors.New("error from IAK"), requestId, service.Logger)
-	}
+	inquiryPrepaidPln, err := service.PpobProviderInterface.InquiryPrepaidPln(inquiryPrepaidPlnRequest.CustomerId)
+	PanicIfInquiryPrepaidPlnError(err, requestId, service.Logger)
 
 	inquiryPrepaidPlnResponse = response.ToInquiryPrepaidPlnResponse(inquiryPrepaidPln)
 
 	return inquiryPrepaidPlnResponse
 }
 
-func (service *PpobServiceImplementation) InquiryPostpaidPln(requestId string, inquiryPostpaidPlnRequest *request.InquiryPostpaidPlnRequest) (inquiryPostpaidPlnResponse response.InquiryPostpaidPlnResponse) {
-	var err error
+// PanicIfInquiryPrepaidPlnError id pelanggan salah dikembalikan sebagai bad request
+func PanicIfInquiryPrepaidPlnError(err error, requestId string, logger *logrus.Logger) {
+	if errors.Is(err, exceptions.ErrPpobCustomerNotFound) {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"Costumer Id Not Found"}, logger)
+	}
+	exceptions.PanicIfPpobError(err, requestId, logger)
+}
 
+func (service *PpobServiceImplementation) InquiryPostpaidPln(requestId string, inquiryPostpaidPlnRequest *request.InquiryPostpaidPlnRequest) (inquiryPostpaidPlnResponse response.InquiryPostpaidPlnResponse) {
 	request.ValidateRequest(service.Validate, inquiryPostpaidPlnRequest, requestId, service.Logger)
 
 	// generate number order yg akan digunakan sebagai ref id
 	refId := utilities.GenerateRefId()
 
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + refId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "inq-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"code":     "PLNPOSTPAID",
-		"hp":       inquiryPostpaidPlnRequest.CustomerId,
-		"ref_id":   refId,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
-
-	inquiryPostpaidPln := &ppob.InquiryPostpaidPln{}
-	postpaidErrorResponse := &ppob.PostpaidErrorResponse{}
-
-	if err = json.Unmarshal([]byte(data), &inquiryPostpaidPln); err != nil {
-		if err = json.Unmarshal([]byte(data), &postpaidErrorResponse); err != nil {
-			exceptions.PanicIfRecordNotFound(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-		}
-	}
-
-	if inquiryPostpaidPln.Data.ResponseCode == "01" {
-		exceptions.PanicPPOBHandler(errors.New("error 001"), requestId, inquiryPostpaidPln.Data.Message, []string{"001"}, service.Logger)
-	}
-
-	if inquiryPostpaidPln.Data.ResponseCode == "14" {
-		exceptions.PanicPPOBHandler(errors.New("error 002"), requestId, inquiryPostpaidPln.Data.Message, []string{"002"}, service.Logger)
-	}
+	inquiryPostpaidPln, err := service.PpobProviderInterface.InquiryPostpaidPln(inquiryPostpaidPlnRequest.CustomerId, refId)
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 
 	inquiryPostpaidPlnResponse = response.ToInquiryPostpaidPlnResponse(inquiryPostpaidPln, inquiryPostpaidPln.Data.Desc.Tagihan.Detail, refId)
 
@@ -300,53 +139,8 @@ func (service *PpobServiceImplementation) InquiryPostpaidPln(requestId string, i
 }
 
 func (service *PpobServiceImplementation) GetPostpaidPdamProduct(requestId string) (postpaidPdamProductResponse []response.GetPostpaidPdamProductResponse) {
-	var err error
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "pl"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "pricelist-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-		"status":   "all",
-		"province": "bali",
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl + "/pdam"
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	// fmt.Printf("body: %s\n", data)
-
-	postpaidPriceList := &ppob.PostpaidPriceListResponse{}
-
-	if err = json.Unmarshal([]byte(data), &postpaidPriceList); err != nil {
-		exceptions.PanicIfBadRequest(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-	}
+	postpaidPriceList, err := service.PpobProviderInterface.PostpaidPriceList("pdam", "bali")
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 
 	postpaidPdamProductResponse = response.ToGetPostpaidPadmProductResponse(postpaidPriceList.Data.Pasca)
 
@@ -354,52 +148,8 @@ func (service *PpobServiceImplementation) GetPostpaidPdamProduct(requestId strin
 }
 
 func (service *PpobServiceImplementation) GetPostpaidTelcoProduct(requestId string) (postpaidTelcoProductResponse []response.GetPostpaidTelcoProductResponse) {
-	var err error
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + "pl"))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "pricelist-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"sign":     hex.EncodeToString(sign[:]),
-		"status":   "all",
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl + "/hp"
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	// fmt.Printf("body: %s\n", data)
-
-	postpaidTelcoPriceList := &ppob.InquiryProductPostpaidPPOB{}
-
-	if err = json.Unmarshal([]byte(data), &postpaidTelcoPriceList); err != nil {
-		exceptions.PanicIfBadRequest(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-	}
+	postpaidTelcoPriceList, err := service.PpobProviderInterface.PostpaidPriceList("hp", "")
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 
 	postpaidTelcoProductResponse = response.ToGetPostpaidTelcoProductResponse(postpaidTelcoPriceList)
 
@@ -407,69 +157,12 @@ func (service *PpobServiceImplementation) GetPostpaidTelcoProduct(requestId stri
 }
 
 func (service *PpobServiceImplementation) InquiryPostpaidPdam(requestId string, inquiryPostpaidPdamRequest *request.InquiryPostpaidPdamRequest) (inquiryPostpaidPdamResponse response.InquiryPostpaidPdamResponse) {
-
-	var err error
-
 	request.ValidateRequest(service.Validate, inquiryPostpaidPdamRequest, requestId, service.Logger)
 
 	refId := utilities.GenerateRefId()
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + refId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "inq-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"code":     inquiryPostpaidPdamRequest.Code,
-		"hp":       inquiryPostpaidPdamRequest.Hp,
-		"ref_id":   refId,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
-
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
 
-	inquiryPostpaidPdam := &ppob.InquiryPostpaidPdam{}
-	postpaidErrorResponse := &ppob.PostpaidErrorResponse{}
-	if err = json.Unmarshal([]byte(data), &inquiryPostpaidPdam); err != nil {
-		if err = json.Unmarshal([]byte(data), &postpaidErrorResponse); err != nil {
-			exceptions.PanicIfBadRequest(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-		}
-	}
-
-	if inquiryPostpaidPdam.Data.ResponseCode == "01" {
-		exceptions.PanicPPOBHandler(errors.New("error 001"), requestId, inquiryPostpaidPdam.Data.Message, []string{"001"}, service.Logger)
-	}
-
-	if inquiryPostpaidPdam.Data.ResponseCode == "14" {
-		exceptions.PanicPPOBHandler(errors.New("error 002"), requestId, inquiryPostpaidPdam.Data.Message, []string{"002"}, service.Logger)
-	}
+	inquiryPostpaidPdam, err := service.PpobProviderInterface.InquiryPostpaidPdam(inquiryPostpaidPdamRequest.Code, inquiryPostpaidPdamRequest.Hp, refId)
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 
 	inquiryPostpaidPdamResponse = response.ToInquiryPostpaidPdamResponse(inquiryPostpaidPdam, inquiryPostpaidPdam.Data.Desc.Bill.Detail, refId)
 
@@ -477,113 +170,85 @@ func (service *PpobServiceImplementation) InquiryPostpaidPdam(requestId string,
 }
 
 func (service *PpobServiceImplementation) InquiryPostpaidTelco(requestId string, inquiryPostpaidTelcoRequest *request.InquiryPostpaidTelcoRequest) (inquiryPostpaidPdamResponse response.InquiryPostpaidTelcoResponse) {
-
-	var err error
-
 	request.ValidateRequest(service.Validate, inquiryPostpaidTelcoRequest, requestId, service.Logger)
 
 	refId := utilities.GenerateRefId()
-	// Create Request
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + refId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"commands": "inq-pasca",
-		"username": config.GetConfig().Ppob.Username,
-		"code":     inquiryPostpaidTelcoRequest.Code,
-		"hp":       inquiryPostpaidTelcoRequest.Hp,
-		"ref_id":   refId,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
-
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
-
-	urlString := config.GetConfig().Ppob.PostpaidUrl
-
-	// URL
-	url, _ := url.Parse(urlString)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
-	}
 
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
+	inquiryPostpaidTelco, err := service.PpobProviderInterface.InquiryPostpaidTelco(inquiryPostpaidTelcoRequest.Code, inquiryPostpaidTelcoRequest.Hp, refId)
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
-
-	defer resp.Body.Close()
-
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
+	inquiryPostpaidPdamResponse = response.ToInquiryPostpaidTelcoResponse(inquiryPostpaidTelco, inquiryPostpaidTelco.Data.Desc.Tagihan.Tagihan, refId)
 
-	inquiryPostpaidTelco := &ppob.InquiryPostpaidTelco{}
-	postpaidErrorResponse := &ppob.PostpaidErrorResponse{}
-	if err = json.Unmarshal([]byte(data), &inquiryPostpaidTelco); err != nil {
-		if err = json.Unmarshal([]byte(data), &postpaidErrorResponse); err != nil {
-			exceptions.PanicIfBadRequest(errors.New("INVALID DATA"), requestId, []string{"INVALID DATA"}, service.Logger)
-		}
-	}
+	return inquiryPostpaidPdamResponse
+}
 
-	if inquiryPostpaidTelco.Data.ResponseCode == "01" {
-		exceptions.PanicPPOBHandler(errors.New("error 001"), requestId, inquiryPostpaidTelco.Data.Message, []string{"001"}, service.Logger)
+// FindPpobProductType tipe produk ppob generic yang tidak terdaftar / tidak aktif dikembalikan sebagai bad request
+func FindPpobProductType(db *gorm.DB, requestId string, logger *logrus.Logger, ppobProductTypeRepository repository.PpobProductTypeRepositoryInterface, productType string) *entity.PpobProductType {
+	ppobProductType, err := ppobProductTypeRepository.FindPpobProductTypeByCode(db, productType)
+	exceptions.PanicIfError(err, requestId, logger)
+	if len(ppobProductType.Id) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("product type not found"), requestId, []string{"tipe produk tidak ditemukan"}, logger)
 	}
+	return ppobProductType
+}
 
-	if inquiryPostpaidTelco.Data.ResponseCode == "14" {
-		exceptions.PanicPPOBHandler(errors.New("error 002"), requestId, inquiryPostpaidTelco.Data.Message, []string{"002"}, service.Logger)
+// FindPostpaidPpobProduct memastikan kode produk pascabayar terdaftar di pricelist provider
+func FindPostpaidPpobProduct(requestId string, logger *logrus.Logger, ppobProvider ppobrepository.PpobProviderInterface, ppobProductType *entity.PpobProductType, productCode string) ppob.PostpaidPriceList {
+	postpaidPriceList, err := ppobProvider.PostpaidPriceList(ppobProductType.ProviderType, "")
+	exceptions.PanicIfPpobError(err, requestId, logger)
+	for _, postpaidProduct := range postpaidPriceList.Data.Pasca {
+		if postpaidProduct.Code == productCode && postpaidProduct.Status == 1 {
+			return postpaidProduct
+		}
 	}
+	exceptions.PanicIfBadRequest(errors.New("product not found"), requestId, []string{"produk sedang tidak tersedia"}, logger)
+	return ppob.PostpaidPriceList{}
+}
 
-	inquiryPostpaidPdamResponse = response.ToInquiryPostpaidTelcoResponse(inquiryPostpaidTelco, inquiryPostpaidTelco.Data.Desc.Tagihan.Tagihan, refId)
-
-	return inquiryPostpaidPdamResponse
+func (service *PpobServiceImplementation) FindPpobProductTypes(requestId string) (ppobProductTypeResponses []response.FindPpobProductTypeResponse) {
+	ppobProductTypes, err := service.PpobProductTypeRepositoryInterface.FindPpobProductTypes(service.DB)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	ppobProductTypeResponses = response.ToFindPpobProductTypeResponses(ppobProductTypes)
+	return ppobProductTypeResponses
 }
 
-func (service *PpobServiceImplementation) PrepaidCheckStatusTransaction(requestId, refId string) {
-	var err error
+func (service *PpobServiceImplementation) FindPpobProducts(requestId string, idDesa string, productType string) (ppobProductResponses interface{}) {
+	ppobProductType := FindPpobProductType(service.DB, requestId, service.Logger, service.PpobProductTypeRepositoryInterface, productType)
 
-	sign := md5.Sum([]byte(config.GetConfig().Ppob.Username + config.GetConfig().Ppob.PpobKey + refId))
-	body, _ := json.Marshal(map[string]interface{}{
-		"username": config.GetConfig().Ppob.Username,
-		"ref_id":   refId,
-		"sign":     hex.EncodeToString(sign[:]),
-	})
+	if ppobProductType.Category == "prepaid" {
+		prepaidPriceList := service.PpobPriceServiceInterface.FindPrepaidPriceList(requestId, idDesa, ppobProductType.ProviderType, ppobProductType.ProviderOperator)
+		return response.ToGetPrepaidDataPriceListResponse(prepaidPriceList)
+	}
 
-	reqBody := io.NopCloser(strings.NewReader(string(body)))
+	postpaidPriceList, err := service.PpobProviderInterface.PostpaidPriceList(ppobProductType.ProviderType, "")
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
+	return response.ToGetPostpaidTelcoProductResponse(postpaidPriceList)
+}
 
-	urlString := config.GetConfig().Ppob.PrepaidHost + "/check-status"
+func (service *PpobServiceImplementation) InquiryPpob(requestId string, productType string, inquiryPpobRequest *request.InquiryPpobRequest) (inquiryPpobResponse response.InquiryPpobResponse) {
+	request.ValidateRequest(service.Validate, inquiryPpobRequest, requestId, service.Logger)
 
-	// URL
-	url, _ := url.Parse(urlString)
+	ppobProductType := FindPpobProductType(service.DB, requestId, service.Logger, service.PpobProductTypeRepositoryInterface, productType)
+	if ppobProductType.Category != "postpaid" {
+		exceptions.PanicIfBadRequest(errors.New("inquiry not supported"), requestId, []string{"produk prabayar tidak memerlukan inquiry"}, service.Logger)
+	}
 
-	req := &http.Request{
-		Method: "POST",
-		URL:    url,
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-		Body: reqBody,
+	// parameter tambahan wajib sesuai konfigurasi tipe produk
+	for _, param := range strings.Split(ppobProductType.InquiryParams, ",") {
+		param = strings.TrimSpace(param)
+		if len(param) != 0 && len(inquiryPpobRequest.Params[param]) == 0 {
+			exceptions.PanicIfBadRequest(errors.New("missing inquiry param"), requestId, []string{param + " required"}, service.Logger)
+		}
 	}
 
-	reqDump, _ := httputil.DumpRequestOut(req, true)
-	fmt.Printf("REQUEST:\n%s", string(reqDump))
+	FindPostpaidPpobProduct(requestId, service.Logger, service.PpobProviderInterface, ppobProductType, inquiryPpobRequest.ProductCode)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("An Error Occured %v", err)
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
+	refId := utilities.GenerateRefId()
+
+	inquiryPpob, err := service.PpobProviderInterface.InquiryPostpaid(inquiryPpobRequest.ProductCode, inquiryPpobRequest.CustomerId, refId, inquiryPpobRequest.Params)
+	exceptions.PanicIfPpobError(err, requestId, service.Logger)
 
-	defer resp.Body.Close()
+	inquiryPpobResponse = response.ToInquiryPpobResponse(ppobProductType.Code, inquiryPpob, refId)
 
-	// Read response body
-	data, _ := io.ReadAll(resp.Body)
-	fmt.Printf("body: %s\n", data)
+	return inquiryPpobResponse
 }
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	ppobrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/ppob_repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+// Margin bawaan untuk desa yang belum mengatur margin ppob
+const defaultPpobMargin float64 = 1500
+
+type PpobPriceServiceInterface interface {
+	FindPrepaidPriceList(requestId string, idDesa string, productType string, operator string) (priceLists []ppob.PrepaidPriceList)
+	FindPrepaidProduct(requestId string, idDesa string, productCode string) (priceList *entity.PpobPriceList, sellingPrice float64)
+	FindPpobMarginsByDesa(requestId string, idDesa string) (ppobMarginResponses []response.FindPpobMarginResponse)
+	CreatePpobMargin(requestId string, idDesa string, createPpobMarginRequest *request.CreatePpobMarginRequest)
+	UpdatePpobMargin(requestId string, idDesa string, updatePpobMarginRequest *request.UpdatePpobMarginRequest)
+	SyncPrepaidPriceList()
+}
+
+type PpobPriceServiceImplementation struct {
+	DB                                 *gorm.DB
+	Validate                           *validator.Validate
+	Logger                             *logrus.Logger
+	PpobPriceListRepositoryInterface   repository.PpobPriceListRepositoryInterface
+	PpobMarginRepositoryInterface      repository.PpobMarginRepositoryInterface
+	OperatorPrefixRepositoryInterface  repository.OperatorPrefixRepositoryInterface
+	PpobProviderInterface              ppobrepository.PpobProviderInterface
+	PpobProductTypeRepositoryInterface repository.PpobProductTypeRepositoryInterface
+}
+
+func NewPpobPriceService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	ppobPriceListRepositoryInterface repository.PpobPriceListRepositoryInterface,
+	ppobMarginRepositoryInterface repository.PpobMarginRepositoryInterface,
+	operatorPrefixRepositoryInterface repository.OperatorPrefixRepositoryInterface,
+	ppobProviderInterface ppobrepository.PpobProviderInterface,
+	ppobProductTypeRepositoryInterface repository.PpobProductTypeRepositoryInterface,
+) PpobPriceServiceInterface {
+	return &PpobPriceServiceImplementation{
+		DB:                                 db,
+		Validate:                           validate,
+		Logger:                             logger,
+		PpobPriceListRepositoryInterface:   ppobPriceListRepositoryInterface,
+		PpobMarginRepositoryInterface:      ppobMarginRepositoryInterface,
+		OperatorPrefixRepositoryInterface:  operatorPrefixRepositoryInterface,
+		PpobProviderInterface:              ppobProviderInterface,
+		PpobProductTypeRepositoryInterface: ppobProductTypeRepositoryInterface,
+	}
+}
+
+// FindPrepaidPriceList mengembalikan produk aktif dengan ProductPrice sudah berupa harga jual desa
+func (service *PpobPriceServiceImplementation) FindPrepaidPriceList(requestId string, idDesa string, productType string, operator string) (priceLists []ppob.PrepaidPriceList) {
+	ppobPriceLists, err := service.PpobPriceListRepositoryInterface.FindPpobPriceListByType(service.DB, productType, operator)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	// Pricelist lokal belum pernah diisi, ambil langsung dari provider sekali
+	if len(ppobPriceLists) == 0 {
+		total, err := service.PpobPriceListRepositoryInterface.CountPpobPriceListByType(service.DB, productType, operator)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if total == 0 {
+			err = service.syncPrepaidPriceList(productType, operator)
+			exceptions.PanicIfPpobError(err, requestId, service.Logger)
+			ppobPriceLists, err = service.PpobPriceListRepositoryInterface.FindPpobPriceListByType(service.DB, productType, operator)
+			exceptions.PanicIfError(err, requestId, service.Logger)
+		}
+	}
+
+	ppobMargins, err := service.PpobMarginRepositoryInterface.FindPpobMarginsByDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	for i := range ppobPriceLists {
+		priceList := ToPrepaidPriceList(&ppobPriceLists[i])
+		priceList.ProductPrice = SellingPricePpob(ppobMargins, &ppobPriceLists[i])
+		priceLists = append(priceLists, priceList)
+	}
+	return priceLists
+}
+
+func (service *PpobPriceServiceImplementation) FindPrepaidProduct(requestId string, idDesa string, productCode string) (priceList *entity.PpobPriceList, sellingPrice float64) {
+	priceList, err := service.PpobPriceListRepositoryInterface.FindPpobPriceListByCode(service.DB, productCode)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(priceList.Id) == 0 || priceList.IsActive == 0 {
+		exceptions.PanicIfBadRequest(errors.New("product not found"), requestId, []string{"produk sedang tidak tersedia"}, service.Logger)
+	}
+
+	ppobMargins, err := service.PpobMarginRepositoryInterface.FindPpobMarginsByDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	return priceList, SellingPricePpob(ppobMargins, priceList)
+}
+
+// SellingPricePpob memakai margin aktif yang paling spesifik: tipe + operator, lalu tipe, lalu semua produk
+func SellingPricePpob(ppobMargins []entity.PpobMargin, priceList *entity.PpobPriceList) float64 {
+	var selected *entity.PpobMargin
+	selectedScore := -1
+	for i, ppobMargin := range ppobMargins {
+		if ppobMargin.IsActive != 1 {
+			continue
+		}
+		if len(ppobMargin.ProductType) != 0 && ppobMargin.ProductType != priceList.ProductType {
+			continue
+		}
+		if len(ppobMargin.Operator) != 0 && ppobMargin.Operator != priceList.Operator {
+			continue
+		}
+		score := 0
+		if len(ppobMargin.ProductType) != 0 {
+			score += 2
+		}
+		if len(ppobMargin.Operator) != 0 {
+			score++
+		}
+		if score > selectedScore {
+			selected = &ppobMargins[i]
+			selectedScore = score
+		}
+	}
+
+	if selected == nil {
+		return priceList.ProductPrice + defaultPpobMargin
+	}
+	if selected.MarginType == 2 {
+		return priceList.ProductPrice + math.Ceil(priceList.ProductPrice*selected.Margin/100)
+	}
+	return priceList.ProductPrice + selected.Margin
+}
+
+func (service *PpobPriceServiceImplementation) FindPpobMarginsByDesa(requestId string, idDesa string) (ppobMarginResponses []response.FindPpobMarginResponse) {
+	ppobMargins, err := service.PpobMarginRepositoryInterface.FindPpobMarginsByDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(ppobMargins) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("ppob margin not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+	ppobMarginResponses = response.ToFindPpobMarginResponses(ppobMargins)
+	return ppobMarginResponses
+}
+
+func (service *PpobPriceServiceImplementation) CreatePpobMargin(requestId string, idDesa string, createPpobMarginRequest *request.CreatePpobMarginRequest) {
+	request.ValidateRequest(service.Validate, createPpobMarginRequest, requestId, service.Logger)
+	service.validatePpobMargin(requestId, createPpobMarginRequest)
+
+	err := service.PpobMarginRepositoryInterface.CreatePpobMargin(service.DB, &entity.PpobMargin{
+		Id:          utilities.RandomUUID(),
+		IdDesa:      idDesa,
+		ProductType: createPpobMarginRequest.ProductType,
+		Operator:    createPpobMarginRequest.Operator,
+		MarginType:  createPpobMarginRequest.MarginType,
+		Margin:      createPpobMarginRequest.Margin,
+		IsActive:    createPpobMarginRequest.IsActive,
+		CreatedAt:   time.Now(),
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *PpobPriceServiceImplementation) UpdatePpobMargin(requestId string, idDesa string, updatePpobMarginRequest *request.UpdatePpobMarginRequest) {
+	request.ValidateRequest(service.Validate, updatePpobMarginRequest, requestId, service.Logger)
+	service.validatePpobMargin(requestId, &updatePpobMarginRequest.CreatePpobMarginRequest)
+
+	ppobMargin, err := service.PpobMarginRepositoryInterface.FindPpobMarginById(service.DB, updatePpobMarginRequest.IdPpobMargin)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(ppobMargin.Id) == 0 || ppobMargin.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("ppob margin not found"), requestId, []string{"ppob margin not found"}, service.Logger)
+	}
+
+	err = service.PpobMarginRepositoryInterface.UpdatePpobMargin(service.DB, ppobMargin.Id, &entity.PpobMargin{
+		ProductType: updatePpobMarginRequest.ProductType,
+		Operator:    updatePpobMarginRequest.Operator,
+		MarginType:  updatePpobMarginRequest.MarginType,
+		Margin:      updatePpobMarginRequest.Margin,
+		IsActive:    updatePpobMarginRequest.IsActive,
+		UpdatedAt:   null.NewTime(time.Now(), true),
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *PpobPriceServiceImplementation) validatePpobMargin(requestId string, createPpobMarginRequest *request.CreatePpobMarginRequest) {
+	if createPpobMarginRequest.MarginType == 2 && createPpobMarginRequest.Margin > 100 {
+		exceptions.PanicIfBadRequest(errors.New("margin percentage too high"), requestId, []string{"margin persen maksimal 100"}, service.Logger)
+	}
+	if len(createPpobMarginRequest.Operator) != 0 && len(createPpobMarginRequest.ProductType) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("product type required"), requestId, []string{"product_type wajib diisi jika operator diisi"}, service.Logger)
+	}
+}
+
+// SyncPrepaidPriceList dijalankan scheduler untuk memperbarui pricelist lokal dari provider
+func (service *PpobPriceServiceImplementation) SyncPrepaidPriceList() {
+	defer func() {
+		if r := recover(); r != nil {
+			service.Logger.WithField("error", r).Error("sync ppob pricelist")
+		}
+	}()
+
+	kodeOperators, err := service.OperatorPrefixRepositoryInterface.FindKodeOperators(service.DB)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("sync ppob pricelist")
+		return
+	}
+
+	ppobProductTypes, err := service.PpobProductTypeRepositoryInterface.FindPpobProductTypes(service.DB)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("sync ppob pricelist")
+		return
+	}
+
+	syncTargets := [][2]string{{"pln", "pln"}}
+	for _, kodeOperator := range kodeOperators {
+		syncTargets = append(syncTargets, [2]string{"pulsa", kodeOperator}, [2]string{"data", kodeOperator})
+	}
+	for _, ppobProductType := range ppobProductTypes {
+		if ppobProductType.Category == "prepaid" {
+			syncTargets = append(syncTargets, [2]string{ppobProductType.ProviderType, ppobProductType.ProviderOperator})
+		}
+	}
+
+	for _, syncTarget := range syncTargets {
+		if err := service.syncPrepaidPriceList(syncTarget[0], syncTarget[1]); err != nil {
+			service.Logger.WithFields(logrus.Fields{
+				"product_type": syncTarget[0],
+				"operator":     syncTarget[1],
+				"error":        err.Error(),
+			}).Error("sync ppob pricelist")
+		}
+	}
+}
+
+func (service *PpobPriceServiceImplementation) syncPrepaidPriceList(productType string, operator string) error {
+	prepaidPriceList, err := service.PpobProviderInterface.PrepaidPriceList(productType, operator)
+	if err != nil {
+		return err
+	}
+
+	syncedAt := time.Now().Truncate(time.Second)
+	tx := service.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	for _, priceList := range prepaidPriceList.Data.Data {
+		isActive := 0
+		if priceList.Status == "active" {
+			isActive = 1
+		}
+
+		ppobPriceList := &entity.PpobPriceList{
+			ProductCode:        priceList.ProductCode,
+			ProductType:        productType,
+			Operator:           operator,
+			ProductDescription: priceList.ProductDescription,
+			ProductNominal:     priceList.ProductNominal,
+			ProductDetails:     priceList.ProductDetails,
+			ProductPrice:       priceList.ProductPrice,
+			ActivePeriod:       priceList.ActivePeriod,
+			Status:             priceList.Status,
+			IconUrl:            priceList.IconUrl,
+			IsActive:           isActive,
+			SyncedAt:           syncedAt,
+		}
+
+		existing, err := service.PpobPriceListRepositoryInterface.FindPpobPriceListByCode(tx, priceList.ProductCode)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if len(existing.Id) == 0 {
+			ppobPriceList.Id = utilities.RandomUUID()
+			ppobPriceList.CreatedAt = syncedAt
+			err = service.PpobPriceListRepositoryInterface.CreatePpobPriceList(tx, ppobPriceList)
+		} else {
+			ppobPriceList.UpdatedAt = null.NewTime(syncedAt, true)
+			err = service.PpobPriceListRepositoryInterface.UpdatePpobPriceList(tx, existing.Id, ppobPriceList)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := service.PpobPriceListRepositoryInterface.DeactivatePpobPriceListNotSynced(tx, productType, operator, syncedAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func ToPrepaidPriceList(priceList *entity.PpobPriceList) ppob.PrepaidPriceList {
+	return ppob.PrepaidPriceList{
+		ProductCode:        priceList.ProductCode,
+		ProductDescription: priceList.ProductDescription,
+		ProductNominal:     priceList.ProductNominal,
+		ProductDetails:     priceList.ProductDetails,
+		ProductPrice:       priceList.ProductPrice,
+		ProductType:        priceList.ProductType,
+		ActivePeriod:       priceList.ActivePeriod,
+		Status:             priceList.Status,
+		IconUrl:            priceList.IconUrl,
+	}
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/ppob"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	ppobrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/ppob_repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gorm.io/gorm"
+)
+
+type PpobReconcileServiceInterface interface {
+	ReconcilePendingPpobOrders()
+	CreateDailyPpobReconciliation()
+	FindPpobReconciliations(requestId string) (ppobReconciliationResponses []response.FindPpobReconciliationResponse)
+}
+
+type PpobReconcileServiceImplementation struct {
+	DB                                    *gorm.DB
+	ConfigPpob                            config.Ppob
+	Logger                                *logrus.Logger
+	OrderRepositoryInterface              repository.OrderRepositoryInterface
+	OrderItemPpobRepositoryInterface      repository.OrderItemPpobRepositoryInterface
+	PpobDetailRepositoryInterface         repository.PpobDetailRepositoryInterface
+	PpobProductTypeRepositoryInterface    repository.PpobProductTypeRepositoryInterface
+	PpobReconciliationRepositoryInterface repository.PpobReconciliationRepositoryInterface
+	PpobProviderInterface                 ppobrepository.PpobProviderInterface
+	OrderServiceInterface                 OrderServiceInterface
+}
+
+func NewPpobReconcileService(
+	db *gorm.DB,
+	configPpob config.Ppob,
+	logger *logrus.Logger,
+	orderRepositoryInterface repository.OrderRepositoryInterface,
+	orderItemPpobRepositoryInterface repository.OrderItemPpobRepositoryInterface,
+	ppobDetailRepositoryInterface repository.PpobDetailRepositoryInterface,
+	ppobProductTypeRepositoryInterface repository.PpobProductTypeRepositoryInterface,
+	ppobReconciliationRepositoryInterface repository.PpobReconciliationRepositoryInterface,
+	ppobProviderInterface ppobrepository.PpobProviderInterface,
+	orderServiceInterface OrderServiceInterface,
+) PpobReconcileServiceInterface {
+	return &PpobReconcileServiceImplementation{
+		DB:                                    db,
+		ConfigPpob:                            configPpob,
+		Logger:                                logger,
+		OrderRepositoryInterface:              orderRepositoryInterface,
+		OrderItemPpobRepositoryInterface:      orderItemPpobRepositoryInterface,
+		PpobDetailRepositoryInterface:         ppobDetailRepositoryInterface,
+		PpobProductTypeRepositoryInterface:    ppobProductTypeRepositoryInterface,
+		PpobReconciliationRepositoryInterface: ppobReconciliationRepositoryInterface,
+		PpobProviderInterface:                 ppobProviderInterface,
+		OrderServiceInterface:                 orderServiceInterface,
+	}
+}
+
+// ppobReconcileBatch jumlah order pending yang dicek ke provider per query
+const ppobReconcileBatch = 100
+
+// ppobProviderTransaction status transaksi di provider, Status 0 diproses, 1 sukses, 2 gagal
+type ppobProviderTransaction struct {
+	Status  int
+	Sn      string
+	Price   float64
+	Balance float64
+}
+
+// ReconcilePendingPpobOrders cek status ke provider untuk order ppob yang callbacknya tidak kunjung datang
+func (service *PpobReconcileServiceImplementation) ReconcilePendingPpobOrders() {
+	afterMinutes := service.ConfigPpob.ReconcileAfterMinutes
+	if afterMinutes == 0 {
+		afterMinutes = 15
+	}
+
+	pendingBefore := time.Now().Add(-time.Duration(afterMinutes) * time.Minute)
+	afterId := ""
+	for {
+		orders, err := service.OrderRepositoryInterface.FindPpobOrdersPendingBefore(service.DB, pendingBefore, afterId, ppobReconcileBatch)
+		if err != nil {
+			service.Logger.WithField("error", err.Error()).Error("reconcile ppob order")
+			return
+		}
+
+		for i := range orders {
+			if err := service.reconcilePpobOrder(&orders[i]); err != nil {
+				service.Logger.WithFields(logrus.Fields{"number_order": orders[i].NumberOrder, "ref_id": orders[i].RefId, "error": err.Error()}).Error("reconcile ppob order")
+			}
+		}
+
+		if len(orders) < ppobReconcileBatch {
+			return
+		}
+		afterId = orders[len(orders)-1].Id
+	}
+}
+
+func (service *PpobReconcileServiceImplementation) reconcilePpobOrder(order *entity.Order) (err error) {
+	// transisi status memakai panic exceptions, satu order gagal tidak menghentikan order lain
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	providerTransaction, err := service.checkPpobTransaction(order)
+	if err != nil {
+		return err
+	}
+
+	// masih diproses provider, dicek lagi di putaran berikutnya
+	if providerTransaction.Status != 1 && providerTransaction.Status != 2 {
+		return nil
+	}
+
+	service.OrderServiceInterface.UpdatePpobTransactionStatus("reconcile-"+order.NumberOrder, order, &request.PpobCallbackRequestData{
+		RefId:   order.RefId,
+		Status:  strconv.Itoa(providerTransaction.Status),
+		Sn:      providerTransaction.Sn,
+		Price:   strconv.FormatFloat(providerTransaction.Price, 'f', -1, 64),
+		Balance: strconv.FormatFloat(providerTransaction.Balance, 'f', -1, 64),
+	})
+
+	service.Logger.WithFields(logrus.Fields{"number_order": order.NumberOrder, "ref_id": order.RefId, "status": providerTransaction.Status}).Info("ppob order reconciled")
+	return nil
+}
+
+// checkPpobTransaction rc gagal dari provider dianggap transaksi gagal,
+// error koneksi / sistem provider dikembalikan supaya dicoba lagi
+func (service *PpobReconcileServiceImplementation) checkPpobTransaction(order *entity.Order) (*ppobProviderTransaction, error) {
+	category, err := service.ppobCategory(order.ProductType)
+	if err != nil {
+		return nil, err
+	}
+
+	providerTransaction := &ppobProviderTransaction{}
+	if category == "prepaid" {
+		checkStatus, err := service.PpobProviderInterface.CheckStatusPrepaid(order.RefId)
+		if err != nil && !exceptions.IsPpobTransactionError(err) {
+			return nil, err
+		}
+		providerTransaction.Status = checkStatus.Data.Status
+		providerTransaction.Sn = checkStatus.Data.Sn
+		providerTransaction.Price = checkStatus.Data.Price
+		providerTransaction.Balance = checkStatus.Data.Balance
+		if err != nil && !errors.Is(err, exceptions.ErrPpobPending) {
+			providerTransaction.Status = 2
+		}
+		return providerTransaction, nil
+	}
+
+	checkStatus, err := service.PpobProviderInterface.CheckStatusPostpaid(order.RefId)
+	if err != nil && !exceptions.IsPpobTransactionError(err) {
+		return nil, err
+	}
+	providerTransaction.Status = checkStatus.Data.Status
+	providerTransaction.Sn = checkStatus.Data.NoRef
+	providerTransaction.Price = checkStatus.Data.Price
+	providerTransaction.Balance = checkStatus.Data.Balance
+	if err != nil && !errors.Is(err, exceptions.ErrPpobPending) {
+		providerTransaction.Status = 2
+	}
+	return providerTransaction, nil
+}
+
+func (service *PpobReconcileServiceImplementation) ppobCategory(productType string) (string, error) {
+	if strings.HasPrefix(productType, "prepaid_") {
+		return "prepaid", nil
+	}
+	if strings.HasPrefix(productType, "postpaid_") {
+		return "postpaid", nil
+	}
+
+	ppobProductType, err := service.PpobProductTypeRepositoryInterface.FindPpobProductTypeByCode(service.DB, productType)
+	if err != nil {
+		return "", err
+	}
+	if len(ppobProductType.Id) == 0 {
+		return "", errors.New("ppob product type not found: " + productType)
+	}
+	return ppobProductType.Category, nil
+}
+
+// CreateDailyPpobReconciliation laporan order ppob kemarin dibandingkan dengan status transaksi dan saldo deposit di provider,
+// dipanggil tiap jam dan hanya dibuat sekali per tanggal
+func (service *PpobReconcileServiceImplementation) CreateDailyPpobReconciliation() {
+	defer func() {
+		if r := recover(); r != nil {
+			service.Logger.WithField("error", r).Error("ppob reconciliation")
+		}
+	}()
+
+	now := time.Now()
+	reconcileDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+
+	existingReconciliation, err := service.PpobReconciliationRepositoryInterface.FindPpobReconciliationByDate(service.DB, reconcileDate)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("ppob reconciliation")
+		return
+	}
+	if len(existingReconciliation.Id) != 0 {
+		return
+	}
+
+	orders, err := service.OrderRepositoryInterface.FindPpobOrdersByPaymentDate(service.DB, reconcileDate, reconcileDate.AddDate(0, 0, 1))
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("ppob reconciliation")
+		return
+	}
+
+	// Daftar transaksi provider dibandingkan dua arah, cek status per order hanya jika daftar tidak tersedia
+	providerTransactions, err := service.findPpobProviderTransactions(reconcileDate)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Warn("ppob reconciliation transaction list, fallback check status")
+	}
+
+	reconciliation := &entity.PpobReconciliation{
+		Id:            utilities.RandomUUID(),
+		ReconcileDate: reconcileDate,
+		CreatedAt:     time.Now(),
+	}
+	mismatches := []response.PpobReconciliationMismatch{}
+
+	var lastOrder *entity.Order
+	var lastOrderItemsPpob *entity.OrderItemPpob
+	for i := range orders {
+		order := &orders[i]
+		reconciliation.TotalOrder++
+
+		mismatch := response.PpobReconciliationMismatch{
+			NumberOrder: order.NumberOrder,
+			RefId:       order.RefId,
+			ProductType: order.ProductType,
+			Status:      ppobOrderStatus(order),
+		}
+
+		orderItemsPpob, err := service.OrderItemPpobRepositoryInterface.FindOrderItemsPpobByIdOrder(service.DB, order.Id)
+		if err != nil || len(orderItemsPpob.Id) == 0 {
+			mismatch.Note = "order item ppob tidak ditemukan"
+			reconciliation.TotalMismatch++
+			mismatches = append(mismatches, mismatch)
+			continue
+		}
+		mismatch.ProductCode = orderItemsPpob.ProductCode
+		mismatch.Amount = orderItemsPpob.TotalTagihan
+		if mismatch.Status == 1 {
+			reconciliation.TotalAmount += orderItemsPpob.TotalTagihan
+			lastOrder, lastOrderItemsPpob = order, orderItemsPpob
+		}
+
+		var providerTransaction *ppobProviderTransaction
+		if providerTransactions != nil {
+			transaction, ok := providerTransactions[order.RefId]
+			delete(providerTransactions, order.RefId)
+			if !ok {
+				// order gagal boleh tidak pernah sampai ke provider
+				if mismatch.Status == 2 {
+					reconciliation.TotalMatch++
+					continue
+				}
+				mismatch.Note = "tidak ada di daftar transaksi provider"
+				reconciliation.TotalMismatch++
+				mismatches = append(mismatches, mismatch)
+				continue
+			}
+			providerTransaction = &ppobProviderTransaction{Status: transaction.Status, Sn: transaction.Sn, Price: transaction.Price}
+		} else {
+			providerTransaction, err = service.checkPpobTransaction(order)
+			if err != nil {
+				mismatch.Note = "gagal cek status provider: " + err.Error()
+				reconciliation.TotalMismatch++
+				mismatches = append(mismatches, mismatch)
+				continue
+			}
+		}
+		mismatch.ProviderStatus = providerTransaction.Status
+		mismatch.ProviderAmount = providerTransaction.Price
+		if providerTransaction.Status == 1 {
+			reconciliation.ProviderAmount += providerTransaction.Price
+		}
+
+		switch {
+		case mismatch.Status != mismatch.ProviderStatus:
+			mismatch.Note = "status berbeda"
+		case mismatch.Status == 0:
+			reconciliation.TotalPending++
+			continue
+		case mismatch.Status == 1 && mismatch.Amount != mismatch.ProviderAmount:
+			mismatch.Note = "harga berbeda"
+		default:
+			reconciliation.TotalMatch++
+			continue
+		}
+		reconciliation.TotalMismatch++
+		mismatches = append(mismatches, mismatch)
+	}
+
+	// transaksi di provider yang tidak punya order di aplikasi
+	var refIds []string
+	for refId := range providerTransactions {
+		refIds = append(refIds, refId)
+	}
+	sort.Strings(refIds)
+	for _, refId := range refIds {
+		transaction := providerTransactions[refId]
+		if transaction.Status == 1 {
+			reconciliation.ProviderAmount += transaction.Price
+		}
+		reconciliation.TotalMismatch++
+		mismatches = append(mismatches, response.PpobReconciliationMismatch{
+			RefId:          refId,
+			ProductCode:    transaction.ProductCode,
+			ProviderStatus: transaction.Status,
+			ProviderAmount: transaction.Price,
+			Note:           "transaksi provider tanpa order",
+		})
+	}
+
+	// saldo provider saat laporan dibuat dibandingkan dengan last balance transaksi sukses terakhir
+	if lastOrder != nil {
+		reconciliation.LastBalance = service.findPpobLastBalance(lastOrder, lastOrderItemsPpob)
+	}
+	providerBalance, err := service.PpobProviderInterface.CheckBalance()
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("ppob reconciliation check balance")
+	} else {
+		reconciliation.ProviderBalance = providerBalance.Data.Balance
+		reconciliation.BalanceDifference = reconciliation.ProviderBalance - reconciliation.LastBalance
+	}
+
+	detailJson, _ := json.Marshal(mismatches)
+	reconciliation.DetailJson = string(detailJson)
+
+	err = service.PpobReconciliationRepositoryInterface.CreatePpobReconciliation(service.DB, reconciliation)
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("ppob reconciliation")
+		return
+	}
+
+	service.Logger.WithFields(logrus.Fields{
+		"reconcile_date": reconcileDate.Format("2006-01-02"),
+		"total_order":    reconciliation.TotalOrder,
+		"total_mismatch": reconciliation.TotalMismatch,
+		"total_pending":  reconciliation.TotalPending,
+	}).Info("ppob reconciliation created")
+}
+
+// findPpobProviderTransactions daftar transaksi provider pada tanggal rekonsiliasi per ref id
+func (service *PpobReconcileServiceImplementation) findPpobProviderTransactions(reconcileDate time.Time) (map[string]ppob.ProviderTransaction, error) {
+	transactionList, err := service.PpobProviderInterface.TransactionList(reconcileDate)
+	if err != nil {
+		return nil, err
+	}
+	providerTransactions := make(map[string]ppob.ProviderTransaction)
+	for _, transaction := range transactionList.Data.Transactions {
+		providerTransactions[transaction.RefId] = transaction
+	}
+	return providerTransactions, nil
+}
+
+// ppobOrderStatus status order dalam format status provider
+func ppobOrderStatus(order *entity.Order) int {
+	switch order.OrderStatus {
+	case 5:
+		return 1
+	case 9:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func (service *PpobReconcileServiceImplementation) findPpobLastBalance(order *entity.Order, orderItemsPpob *entity.OrderItemPpob) float64 {
+	switch order.ProductType {
+	case "prepaid_pulsa", "prepaid_data":
+		detail, _ := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPulsaById(service.DB, orderItemsPpob.Id)
+		return detail.LastBalance
+	case "prepaid_pln":
+		detail, _ := service.PpobDetailRepositoryInterface.FindPpobDetailPrepaidPlnById(service.DB, orderItemsPpob.Id)
+		return detail.LastBalance
+	case "postpaid_pln":
+		detail, _ := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPlnById(service.DB, orderItemsPpob.Id)
+		return detail.LastBalance
+	case "postpaid_pdam":
+		detail, _ := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidPdamById(service.DB, orderItemsPpob.Id)
+		return detail.LastBalance
+	case "postpaid_telco":
+		detail, _ := service.PpobDetailRepositoryInterface.FindPpobDetailPostpaidTelcoById(service.DB, orderItemsPpob.Id)
+		return detail.LastBalance
+	default:
+		detail, _ := service.PpobDetailRepositoryInterface.FindPpobDetailGenericById(service.DB, orderItemsPpob.Id)
+		return detail.LastBalance
+	}
+}
+
+func (service *PpobReconcileServiceImplementation) FindPpobReconciliations(requestId string) (ppobReconciliationResponses []response.FindPpobReconciliationResponse) {
+	ppobReconciliations, err := service.PpobReconciliationRepositoryInterface.FindPpobReconciliations(service.DB, 30)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	ppobReconciliationResponses = response.ToFindPpobReconciliationResponses(ppobReconciliations)
+	return ppobReconciliationResponses
+}
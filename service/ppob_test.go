@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	ppobrepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/ppob_repository"
+)
+
+// recoverPanic isi panic service (json ErrorStruct), kosong jika tidak panic
+func recoverPanic(fn func()) (panicValue string) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicValue = fmt.Sprint(r)
+		}
+	}()
+	fn()
+	return ""
+}
+
+func newTestPpobService(ppobProvider ppobrepository.PpobProviderInterface) *PpobServiceImplementation {
+	return &PpobServiceImplementation{
+		Validate:              validator.New(),
+		Logger:                newTestLogger(),
+		PpobProviderInterface: ppobProvider,
+	}
+}
+
+func TestInquiryPrepaidPlnWithFakeProvider(t *testing.T) {
+	ppobProvider := ppobrepository.NewFakePpobProvider()
+	ppobService := newTestPpobService(ppobProvider)
+
+	var inquiry interface{}
+	if panicValue := recoverPanic(func() {
+		inquiry = ppobService.InquiryPrepaidPln("test", &request.InquiryPrepaidPlnRequest{CustomerId: "530000000001"})
+	}); len(panicValue) != 0 {
+		t.Fatalf("inquiry panic: %s", panicValue)
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", inquiry), "530000000001") {
+		t.Fatalf("inquiry = %+v", inquiry)
+	}
+
+	// rc 14 id pelanggan tidak ditemukan dibalas bad request
+	ppobProvider.Rc = "14"
+	panicValue := recoverPanic(func() {
+		ppobService.InquiryPrepaidPln("test", &request.InquiryPrepaidPlnRequest{CustomerId: "530000000001"})
+	})
+	if !strings.Contains(panicValue, `"code":400`) || !strings.Contains(panicValue, "Costumer Id Not Found") {
+		t.Fatalf("panic = %s", panicValue)
+	}
+}
+
+func TestFindPostpaidPpobProductWithFakeProvider(t *testing.T) {
+	ppobProvider := ppobrepository.NewFakePpobProvider()
+	ppobProductType := &entity.PpobProductType{ProviderType: "pdam"}
+
+	product := FindPostpaidPpobProduct("test", newTestLogger(), ppobProvider, ppobProductType, "PDAMKOTA.DENPASAR")
+	if product.Code != "PDAMKOTA.DENPASAR" {
+		t.Fatalf("product = %+v", product)
+	}
+
+	// produk tipe lain tidak boleh dipakai lewat tipe pdam
+	panicValue := recoverPanic(func() {
+		FindPostpaidPpobProduct("test", newTestLogger(), ppobProvider, ppobProductType, "HPTSEL")
+	})
+	if !strings.Contains(panicValue, "produk sedang tidak tersedia") {
+		t.Fatalf("panic = %s", panicValue)
+	}
+}
+
+func TestPrepaidPulsaTopupWithFakeProvider(t *testing.T) {
+	ppobProvider := ppobrepository.NewFakePpobProvider()
+	orderService := &OrderServiceImplementation{
+		Logger:                newTestLogger(),
+		PpobProviderInterface: ppobProvider,
+	}
+
+	topup := orderService.PrepaidPulsaTopup("test", "081200000001", "REF-1", "htelkomsel10000")
+	if topup.Data.Status != 0 || topup.Data.Balance != 1000000-10500 {
+		t.Fatalf("topup = %+v", topup.Data)
+	}
+
+	// transaksi gagal dari provider tidak panic, status gagal disimpan dari response
+	ppobProvider.Rc = "06"
+	var failedStatus int
+	if panicValue := recoverPanic(func() {
+		failedStatus = orderService.PrepaidPulsaTopup("test", "081200000001", "REF-2", "htelkomsel10000").Data.Status
+	}); len(panicValue) != 0 {
+		t.Fatalf("topup gagal panic: %s", panicValue)
+	}
+	if failedStatus != 2 {
+		t.Fatalf("status = %d, want 2", failedStatus)
+	}
+
+	// deposit provider habis bukan kegagalan transaksi, request dihentikan
+	ppobProvider.Rc = "17"
+	if panicValue := recoverPanic(func() {
+		orderService.PrepaidPulsaTopup("test", "081200000001", "REF-3", "htelkomsel10000")
+	}); len(panicValue) == 0 {
+		t.Fatal("deposit habis tidak panic")
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strconv"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+)
+
+// QuotePrice menghitung harga satuan dan total untuk qty tertentu. Urutan harga dasar:
+// merchant memakai PriceGrosir products desa lalu products master, paket tanpa harga
+// dihitung dari komponennya, kemudian tier dengan MinQty terbesar dipakai jika lebih murah.
+func QuotePrice(productDesa *entity.ProductsDesa, AccountType int, qty int, priceTiers []entity.ProductsDesaPriceTier, packageItems []entity.ProductsPackageItems) (quote response.PriceQuote) {
+	quote.IdProductDesa = productDesa.Id
+	quote.Qty = qty
+
+	if AccountType == 2 {
+		quote.Price = productDesa.PriceGrosir
+		if quote.Price == 0 {
+			quote.Price = productDesa.ProductsMaster.PriceGrosir
+		}
+		quote.AccountType = "User Merchant"
+		quote.PriceInfo = "Krama Harga Grosir"
+	} else {
+		quote.Price = productDesa.Price
+		if productDesa.IsPromo == 1 && productDesa.PricePromo > 0 {
+			quote.PricePromo = productDesa.PricePromo
+			quote.FlagPromo = 1
+		}
+		quote.AccountType = "User Biasa"
+		quote.PriceInfo = "Krama Harga Normal"
+	}
+
+	if quote.Price == 0 && productDesa.IdType == 2 {
+		quote.Price = PackagePrice(packageItems, AccountType)
+	}
+
+	quote.UnitPrice = quote.Price
+	if quote.FlagPromo == 1 {
+		quote.UnitPrice = quote.PricePromo
+	}
+
+	var priceTier *entity.ProductsDesaPriceTier
+	for i := range priceTiers {
+		if priceTiers[i].IdProductDesa != productDesa.Id || priceTiers[i].AccountType != AccountType || priceTiers[i].MinQty > qty {
+			continue
+		}
+		if priceTier == nil || priceTiers[i].MinQty > priceTier.MinQty {
+			priceTier = &priceTiers[i]
+		}
+	}
+	if priceTier != nil && priceTier.Price < quote.UnitPrice {
+		quote.Price = priceTier.Price
+		quote.PricePromo = 0
+		quote.FlagPromo = 0
+		quote.UnitPrice = priceTier.Price
+		quote.IdPriceTier = priceTier.Id
+		quote.TierMinQty = priceTier.MinQty
+		quote.PriceInfo = "Harga Min. Beli " + strconv.Itoa(priceTier.MinQty)
+	}
+
+	quote.TotalPrice = quote.UnitPrice * float64(qty)
+	return quote
+}
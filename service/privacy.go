@@ -0,0 +1,192 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"gorm.io/gorm"
+)
+
+type PrivacyServiceInterface interface {
+	ExportUserData(requestId string, idUser string) (exportUserDataResponse response.ExportUserDataResponse)
+	ExportUserDataZip(requestId string, idUser string) []byte
+	AnonymizeDeletedUsers()
+}
+
+type PrivacyServiceImplementation struct {
+	DB                                     *gorm.DB
+	ConfigPrivacy                          config.Privacy
+	Logger                                 *logrus.Logger
+	UserRepositoryInterface                repository.UserRepositoryInterface
+	UserProfileRepositoryInterface         repository.UserProfileRepositoryInterface
+	UserShippingAddressRepositoryInterface repository.UserShippingAddressRepositoryInterface
+	CartRepositoryInterface                repository.CartRepositoryInterface
+	PointRepositoryInterface               repository.PointRepositoryInterface
+	OrderRepositoryInterface               repository.OrderRepositoryInterface
+	OrderItemRepositoryInterface           repository.OrderItemRepositoryInterface
+	ListPinjamanRepositoryInterface        repository.ListPinjamanRepositoryInterface
+	PaymentHistoryRepositoryInterface      repository.PaymentHistoryRepositoryInterface
+	UserDeviceTokenRepositoryInterface     repository.UserDeviceTokenRepositoryInterface
+	NotificationRepositoryInterface        repository.NotificationRepositoryInterface
+}
+
+func NewPrivacyService(
+	db *gorm.DB,
+	configPrivacy config.Privacy,
+	logger *logrus.Logger,
+	userRepositoryInterface repository.UserRepositoryInterface,
+	userProfileRepositoryInterface repository.UserProfileRepositoryInterface,
+	userShippingAddressRepositoryInterface repository.UserShippingAddressRepositoryInterface,
+	cartRepositoryInterface repository.CartRepositoryInterface,
+	pointRepositoryInterface repository.PointRepositoryInterface,
+	orderRepositoryInterface repository.OrderRepositoryInterface,
+	orderItemRepositoryInterface repository.OrderItemRepositoryInterface,
+	listPinjamanRepositoryInterface repository.ListPinjamanRepositoryInterface,
+	paymentHistoryRepositoryInterface repository.PaymentHistoryRepositoryInterface,
+	userDeviceTokenRepositoryInterface repository.UserDeviceTokenRepositoryInterface,
+	notificationRepositoryInterface repository.NotificationRepositoryInterface,
+) PrivacyServiceInterface {
+	return &PrivacyServiceImplementation{
+		DB:                                     db,
+		ConfigPrivacy:                          configPrivacy,
+		Logger:                                 logger,
+		UserRepositoryInterface:                userRepositoryInterface,
+		UserProfileRepositoryInterface:         userProfileRepositoryInterface,
+		UserShippingAddressRepositoryInterface: userShippingAddressRepositoryInterface,
+		CartRepositoryInterface:                cartRepositoryInterface,
+		PointRepositoryInterface:               pointRepositoryInterface,
+		OrderRepositoryInterface:               orderRepositoryInterface,
+		OrderItemRepositoryInterface:           orderItemRepositoryInterface,
+		ListPinjamanRepositoryInterface:        listPinjamanRepositoryInterface,
+		PaymentHistoryRepositoryInterface:      paymentHistoryRepositoryInterface,
+		UserDeviceTokenRepositoryInterface:     userDeviceTokenRepositoryInterface,
+		NotificationRepositoryInterface:        notificationRepositoryInterface,
+	}
+}
+
+func (service *PrivacyServiceImplementation) ExportUserData(requestId string, idUser string) (exportUserDataResponse response.ExportUserDataResponse) {
+	user, err := service.UserRepositoryInterface.FindUserById(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(user.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("user not found"), requestId, []string{"user tidak ditemukan"}, service.Logger)
+	}
+
+	userShippingAddresses, err := service.UserShippingAddressRepositoryInterface.FindUserShippingAddressByIdUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	point, err := service.PointRepositoryInterface.FindPointByUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	orders, err := service.OrderRepositoryInterface.FindOrderByUser(service.DB, idUser, -1)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	orderItems := make(map[string][]entity.OrderItem)
+	for _, order := range orders {
+		items, err := service.OrderItemRepositoryInterface.FindOrderItemsByIdOrder(service.DB, order.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		orderItems[order.Id] = items
+	}
+
+	listPinjamans, err := service.ListPinjamanRepositoryInterface.FindListPinjamanByIdUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	paymentHistories, err := service.PaymentHistoryRepositoryInterface.FindPaymentHistoryByIdUser(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	exportUserDataResponse = response.ToExportUserDataResponse(user, userShippingAddresses, point, orders, orderItems, listPinjamans, paymentHistories)
+	return exportUserDataResponse
+}
+
+func (service *PrivacyServiceImplementation) ExportUserDataZip(requestId string, idUser string) []byte {
+	exportUserDataResponse := service.ExportUserData(requestId, idUser)
+
+	data, err := json.MarshalIndent(exportUserDataResponse, "", "  ")
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	buffer := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buffer)
+	file, err := zipWriter.Create("data-pribadi.json")
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	_, err = file.Write(data)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	err = zipWriter.Close()
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	return buffer.Bytes()
+}
+
+// AnonymizeDeletedUsers menghapus data pribadi user yang sudah melewati masa tenggang penghapusan akun,
+// data transaksi tetap disimpan dalam bentuk pseudonim
+func (service *PrivacyServiceImplementation) AnonymizeDeletedUsers() {
+	graceDays := service.ConfigPrivacy.DeletionGraceDays
+	if graceDays == 0 {
+		graceDays = 30
+	}
+
+	users, err := service.UserRepositoryInterface.FindUserDeletedBefore(service.DB, time.Now().AddDate(0, 0, -int(graceDays)))
+	if err != nil {
+		service.Logger.Error("error find deleted user ", err)
+		return
+	}
+
+	for _, user := range users {
+		if err := service.anonymizeUser(user.Id); err != nil {
+			service.Logger.WithFields(logrus.Fields{"id_user": user.Id}).Error("error anonymize user ", err)
+		}
+	}
+}
+
+func (service *PrivacyServiceImplementation) anonymizeUser(idUser string) error {
+	pseudonym := UserPseudonym(idUser)
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		if err := service.UserProfileRepositoryInterface.AnonymizeUserProfile(tx, idUser, pseudonym); err != nil {
+			return err
+		}
+
+		if err := service.UserShippingAddressRepositoryInterface.AnonymizeUserShippingAddressByIdUser(tx, idUser); err != nil {
+			return err
+		}
+
+		if err := service.CartRepositoryInterface.DeleteCartByUser(tx, idUser, []entity.Cart{}); err != nil {
+			return err
+		}
+
+		if err := service.UserDeviceTokenRepositoryInterface.DeleteUserDeviceTokenByIdUser(tx, idUser); err != nil {
+			return err
+		}
+
+		if err := service.NotificationRepositoryInterface.DeleteNotificationByIdUser(tx, idUser); err != nil {
+			return err
+		}
+
+		if err := service.OrderRepositoryInterface.PseudonymizeOrderByIdUser(tx, idUser, pseudonym); err != nil {
+			return err
+		}
+
+		if err := service.ListPinjamanRepositoryInterface.PseudonymizeListPinjamanByIdUser(tx, idUser, pseudonym); err != nil {
+			return err
+		}
+
+		return service.UserRepositoryInterface.AnonymizeUser(tx, idUser)
+	})
+}
+
+// UserPseudonym menghasilkan pengenal pengganti yang tetap untuk user yang sudah dihapus
+func UserPseudonym(idUser string) string {
+	sum := sha256.Sum256([]byte(idUser))
+	return "DEL-" + hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,550 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gorm.io/gorm"
+)
+
+// Kolom csv untuk export / import produk desa
+var productDesaCsvHeader = []string{"id_product_desa", "no_sku", "product_name", "id_category", "id_sub_category", "id_unit", "price", "price_grosir", "price_promo", "is_promo", "stock_opname", "harga_beli"}
+
+type ProductAdminServiceInterface interface {
+	CreateProductMaster(requestId string, createProductMasterRequest *request.CreateProductMasterRequest)
+	UpdateProductMaster(requestId string, updateProductMasterRequest *request.UpdateProductMasterRequest)
+	FindProductsDesa(requestId string, idDesa string) (productsDesaResponses []response.FindProductsDesaAdminResponse)
+	CreateProductDesa(requestId string, idDesa string, createProductDesaRequest *request.CreateProductDesaRequest)
+	UpdateProductDesa(requestId string, idDesa string, updateProductDesaRequest *request.UpdateProductDesaRequest)
+	AdjustProductStock(requestId string, idDesa string, adjustProductStockRequest *request.AdjustProductStockRequest)
+	FindProductDesaStockHistory(requestId string, idDesa string, idProductDesa string) (stockHistoryResponses []response.FindProductDesaStockHistoryResponse)
+	ExportProductsDesaCsv(requestId string, idDesa string) []byte
+	ImportProductsDesaCsv(requestId string, idDesa string, file io.Reader) (importResponse response.ImportProductsDesaResponse)
+	FindProductPriceTier(requestId string, idDesa string, idProductDesa string) (priceTierResponses []response.FindProductPriceTierResponse)
+	UpdateProductPriceTier(requestId string, idDesa string, updateProductPriceTierRequest *request.UpdateProductPriceTierRequest)
+}
+
+type ProductAdminServiceImplementation struct {
+	DB                                         *gorm.DB
+	Validate                                   *validator.Validate
+	Logger                                     *logrus.Logger
+	ProductMasterRepositoryInterface           repository.ProductMasterRepositoryInterface
+	ProductDesaRepositoryInterface             repository.ProductDesaRepositoryInterface
+	ProductDesaStockHistoryRepositoryInterface repository.ProductDesaStockHistoryRepositoryInterface
+	ProductPriceTierRepositoryInterface        repository.ProductPriceTierRepositoryInterface
+	PromoRepositoryInterface                   repository.PromoRepositoryInterface
+}
+
+func NewProductAdminService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	productMasterRepositoryInterface repository.ProductMasterRepositoryInterface,
+	productDesaRepositoryInterface repository.ProductDesaRepositoryInterface,
+	productDesaStockHistoryRepositoryInterface repository.ProductDesaStockHistoryRepositoryInterface,
+	productPriceTierRepositoryInterface repository.ProductPriceTierRepositoryInterface,
+	promoRepositoryInterface repository.PromoRepositoryInterface,
+) ProductAdminServiceInterface {
+	return &ProductAdminServiceImplementation{
+		DB:                               db,
+		Validate:                         validate,
+		Logger:                           logger,
+		ProductMasterRepositoryInterface: productMasterRepositoryInterface,
+		ProductDesaRepositoryInterface:   productDesaRepositoryInterface,
+		ProductDesaStockHistoryRepositoryInterface: productDesaStockHistoryRepositoryInterface,
+		ProductPriceTierRepositoryInterface:        productPriceTierRepositoryInterface,
+		PromoRepositoryInterface:                   promoRepositoryInterface,
+	}
+}
+
+func (service *ProductAdminServiceImplementation) CreateProductMaster(requestId string, createProductMasterRequest *request.CreateProductMasterRequest) {
+	request.ValidateRequest(service.Validate, createProductMasterRequest, requestId, service.Logger)
+
+	productMaster, err := service.ProductMasterRepositoryInterface.FindProductMasterBySku(service.DB, createProductMasterRequest.NoSku)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productMaster.Id) != 0 {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("sku already exist"), requestId, []string{"sku already exist"}, service.Logger)
+	}
+
+	productMasterEntity := toProductMasterEntity(createProductMasterRequest)
+	productMasterEntity.Id = utilities.RandomUUID()
+	err = service.ProductMasterRepositoryInterface.CreateProductMaster(service.DB, productMasterEntity)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *ProductAdminServiceImplementation) UpdateProductMaster(requestId string, updateProductMasterRequest *request.UpdateProductMasterRequest) {
+	request.ValidateRequest(service.Validate, updateProductMasterRequest, requestId, service.Logger)
+
+	productMaster, err := service.ProductMasterRepositoryInterface.FindProductMasterById(service.DB, updateProductMasterRequest.IdProductMaster)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productMaster.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("product not found"), requestId, []string{"product not found"}, service.Logger)
+	}
+
+	productMasterBySku, err := service.ProductMasterRepositoryInterface.FindProductMasterBySku(service.DB, updateProductMasterRequest.NoSku)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productMasterBySku.Id) != 0 && productMasterBySku.Id != productMaster.Id {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("sku already exist"), requestId, []string{"sku already exist"}, service.Logger)
+	}
+
+	err = service.ProductMasterRepositoryInterface.UpdateProductMaster(service.DB, productMaster.Id, toProductMasterEntity(&updateProductMasterRequest.CreateProductMasterRequest))
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *ProductAdminServiceImplementation) FindProductsDesa(requestId string, idDesa string) (productsDesaResponses []response.FindProductsDesaAdminResponse) {
+	productsDesa, err := service.ProductDesaRepositoryInterface.FindProductsDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productsDesa) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+	productsDesaResponses = response.ToFindProductsDesaAdminResponse(productsDesa)
+	return productsDesaResponses
+}
+
+func (service *ProductAdminServiceImplementation) CreateProductDesa(requestId string, idDesa string, createProductDesaRequest *request.CreateProductDesaRequest) {
+	request.ValidateRequest(service.Validate, createProductDesaRequest, requestId, service.Logger)
+	isPromo := service.productDesaPromoStatus(requestId, idDesa, createProductDesaRequest.IdPromo, createProductDesaRequest.IsPromo)
+	validateProductDesaPromo(requestId, createProductDesaRequest.Price, createProductDesaRequest.PricePromo, promoFlag(createProductDesaRequest.IdPromo, isPromo), service.Logger)
+
+	productMaster, err := service.ProductMasterRepositoryInterface.FindProductMasterById(service.DB, createProductDesaRequest.IdProductMaster)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productMaster.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("product not found"), requestId, []string{"product not found"}, service.Logger)
+	}
+
+	productDesa, err := service.ProductDesaRepositoryInterface.FindProductDesaByIdProduct(service.DB, idDesa, productMaster.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productDesa.Id) != 0 {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("product already exist in desa"), requestId, []string{"product already exist in desa"}, service.Logger)
+	}
+
+	productDesaEntity := &entity.ProductsDesa{
+		Id:              utilities.RandomUUID(),
+		IdProduct:       productMaster.Id,
+		IdType:          1,
+		IdDesa:          idDesa,
+		Price:           createProductDesaRequest.Price,
+		PriceGrosir:     createProductDesaRequest.PriceGrosir,
+		PricePromo:      createProductDesaRequest.PricePromo,
+		PercentagePromo: promoPercentage(createProductDesaRequest.Price, createProductDesaRequest.PricePromo, promoFlag(createProductDesaRequest.IdPromo, isPromo)),
+		IsPromo:         isPromo,
+		IdPromo:         createProductDesaRequest.IdPromo,
+		PictureUrl:      createProductDesaRequest.PictureUrl,
+		Thumbnail:       createProductDesaRequest.Thumbnail,
+		Description:     createProductDesaRequest.Description,
+		CreatedDate:     time.Now(),
+	}
+	err = service.ProductDesaRepositoryInterface.CreateProductDesa(service.DB, productDesaEntity)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *ProductAdminServiceImplementation) UpdateProductDesa(requestId string, idDesa string, updateProductDesaRequest *request.UpdateProductDesaRequest) {
+	request.ValidateRequest(service.Validate, updateProductDesaRequest, requestId, service.Logger)
+	isPromo := service.productDesaPromoStatus(requestId, idDesa, updateProductDesaRequest.IdPromo, updateProductDesaRequest.IsPromo)
+	validateProductDesaPromo(requestId, updateProductDesaRequest.Price, updateProductDesaRequest.PricePromo, promoFlag(updateProductDesaRequest.IdPromo, isPromo), service.Logger)
+
+	productDesa := service.findProductDesaInDesa(requestId, idDesa, updateProductDesaRequest.IdProductDesa)
+
+	err := service.ProductDesaRepositoryInterface.UpdateProductDesa(service.DB, productDesa.Id, &entity.ProductsDesa{
+		Price:           updateProductDesaRequest.Price,
+		PriceGrosir:     updateProductDesaRequest.PriceGrosir,
+		PricePromo:      updateProductDesaRequest.PricePromo,
+		PercentagePromo: promoPercentage(updateProductDesaRequest.Price, updateProductDesaRequest.PricePromo, promoFlag(updateProductDesaRequest.IdPromo, isPromo)),
+		IsPromo:         isPromo,
+		IdPromo:         updateProductDesaRequest.IdPromo,
+		PictureUrl:      updateProductDesaRequest.PictureUrl,
+		Thumbnail:       updateProductDesaRequest.Thumbnail,
+		Description:     updateProductDesaRequest.Description,
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *ProductAdminServiceImplementation) AdjustProductStock(requestId string, idDesa string, adjustProductStockRequest *request.AdjustProductStockRequest) {
+	request.ValidateRequest(service.Validate, adjustProductStockRequest, requestId, service.Logger)
+
+	productDesa := service.findProductDesaInDesa(requestId, idDesa, adjustProductStockRequest.IdProductDesa)
+
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	service.adjustProductStock(tx, requestId, productDesa, adjustProductStockRequest.Qty, adjustProductStockRequest.HargaBeli, adjustProductStockRequest.Reason)
+
+	commit := repository.CommitTransaction(tx)
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+}
+
+func (service *ProductAdminServiceImplementation) FindProductDesaStockHistory(requestId string, idDesa string, idProductDesa string) (stockHistoryResponses []response.FindProductDesaStockHistoryResponse) {
+	productDesa := service.findProductDesaInDesa(requestId, idDesa, idProductDesa)
+
+	productDesaStockHistories, err := service.ProductDesaStockHistoryRepositoryInterface.FindProductDesaStockHistoryByIdProductDesa(service.DB, productDesa.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productDesaStockHistories) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+	stockHistoryResponses = response.ToFindProductDesaStockHistoryResponse(productDesaStockHistories)
+	return stockHistoryResponses
+}
+
+func (service *ProductAdminServiceImplementation) ExportProductsDesaCsv(requestId string, idDesa string) []byte {
+	productsDesa, err := service.ProductDesaRepositoryInterface.FindProductsDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	err = writer.Write(productDesaCsvHeader)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	for _, productDesa := range productsDesa {
+		err = writer.Write([]string{
+			productDesa.Id,
+			productDesa.ProductsMaster.NoSku,
+			productDesa.ProductsMaster.ProductName,
+			strconv.Itoa(productDesa.ProductsMaster.IdCategory),
+			strconv.Itoa(productDesa.ProductsMaster.IdSubCategory),
+			strconv.Itoa(productDesa.ProductsMaster.IdUnit),
+			strconv.FormatFloat(productDesa.Price, 'f', -1, 64),
+			strconv.FormatFloat(productDesa.PriceGrosir, 'f', -1, 64),
+			strconv.FormatFloat(productDesa.PricePromo, 'f', -1, 64),
+			strconv.Itoa(productDesa.IsPromo),
+			strconv.Itoa(productDesa.StockOpname),
+			"",
+		})
+		exceptions.PanicIfError(err, requestId, service.Logger)
+	}
+	writer.Flush()
+	exceptions.PanicIfError(writer.Error(), requestId, service.Logger)
+
+	return buffer.Bytes()
+}
+
+// ImportProductsDesaCsv membuat / mengupdate produk desa berdasarkan no_sku.
+// Baris yang tidak valid atau sku yang belum ada di produk master dilewati, error database membatalkan seluruh import.
+func (service *ProductAdminServiceImplementation) ImportProductsDesaCsv(requestId string, idDesa string, file io.Reader) (importResponse response.ImportProductsDesaResponse) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"invalid csv file"}, service.Logger)
+	}
+	if len(records) < 2 {
+		exceptions.PanicIfBadRequest(errors.New("empty csv file"), requestId, []string{"empty csv file"}, service.Logger)
+	}
+
+	columns := map[string]int{}
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["no_sku"]; !ok {
+		exceptions.PanicIfBadRequest(errors.New("column no_sku required"), requestId, []string{"column no_sku required"}, service.Logger)
+	}
+	if _, ok := columns["price"]; !ok {
+		exceptions.PanicIfBadRequest(errors.New("column price required"), requestId, []string{"column price required"}, service.Logger)
+	}
+
+	importResponse.Skipped = []string{}
+
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	for i, record := range records[1:] {
+		line := "baris " + strconv.Itoa(i+2) + ": "
+		row, errRow := parseProductDesaCsvRow(columns, record)
+		if errRow != nil {
+			importResponse.Skipped = append(importResponse.Skipped, line+errRow.Error())
+			continue
+		}
+
+		productMaster, err := service.ProductMasterRepositoryInterface.FindProductMasterBySku(tx, row.NoSku)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find product master"}, service.Logger, tx)
+		// Produk master hanya dibuat super admin
+		if len(productMaster.Id) == 0 {
+			importResponse.Skipped = append(importResponse.Skipped, line+"sku not found in product master")
+			continue
+		}
+
+		productDesa, err := service.ProductDesaRepositoryInterface.FindProductDesaByIdProduct(tx, idDesa, productMaster.Id)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find product desa"}, service.Logger, tx)
+		if len(productDesa.Id) == 0 {
+			productDesa = &entity.ProductsDesa{
+				Id:              utilities.RandomUUID(),
+				IdProduct:       productMaster.Id,
+				IdType:          1,
+				IdDesa:          idDesa,
+				Price:           row.Price,
+				PriceGrosir:     row.PriceGrosir,
+				PricePromo:      row.PricePromo,
+				PercentagePromo: promoPercentage(row.Price, row.PricePromo, row.IsPromo),
+				IsPromo:         row.IsPromo,
+				CreatedDate:     time.Now(),
+			}
+			err = service.ProductDesaRepositoryInterface.CreateProductDesa(tx, productDesa)
+			exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create product desa"}, service.Logger, tx)
+			importResponse.Created++
+		} else {
+			err = service.ProductDesaRepositoryInterface.UpdateProductDesa(tx, productDesa.Id, &entity.ProductsDesa{
+				Price:           row.Price,
+				PriceGrosir:     row.PriceGrosir,
+				PricePromo:      row.PricePromo,
+				PercentagePromo: promoPercentage(row.Price, row.PricePromo, row.IsPromo),
+				IsPromo:         row.IsPromo,
+				IdPromo:         productDesa.IdPromo,
+				PictureUrl:      productDesa.PictureUrl,
+				Thumbnail:       productDesa.Thumbnail,
+				Description:     productDesa.Description,
+			})
+			exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update product desa"}, service.Logger, tx)
+			importResponse.Updated++
+		}
+
+		if row.HasStock && row.StockOpname != productDesa.StockOpname {
+			service.adjustProductStock(tx, requestId, productDesa, row.StockOpname-productDesa.StockOpname, row.HargaBeli, "IMPORT CSV")
+		}
+	}
+
+	commit := repository.CommitTransaction(tx)
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+
+	return importResponse
+}
+
+func (service *ProductAdminServiceImplementation) FindProductPriceTier(requestId string, idDesa string, idProductDesa string) (priceTierResponses []response.FindProductPriceTierResponse) {
+	productDesa := service.findProductDesaInDesa(requestId, idDesa, idProductDesa)
+
+	priceTiers, err := service.ProductPriceTierRepositoryInterface.FindPriceTierByIdProductDesa(service.DB, productDesa.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(priceTiers) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+	priceTierResponses = response.ToFindProductPriceTierResponse(priceTiers)
+	return priceTierResponses
+}
+
+func (service *ProductAdminServiceImplementation) UpdateProductPriceTier(requestId string, idDesa string, updateProductPriceTierRequest *request.UpdateProductPriceTierRequest) {
+	request.ValidateRequest(service.Validate, updateProductPriceTierRequest, requestId, service.Logger)
+
+	productDesa := service.findProductDesaInDesa(requestId, idDesa, updateProductPriceTierRequest.IdProductDesa)
+
+	// Satu tier per tipe akun dan min qty, harga tidak boleh naik untuk qty yang lebih besar
+	var priceTiers []entity.ProductsDesaPriceTier
+	for _, priceTierRequest := range updateProductPriceTierRequest.PriceTiers {
+		for _, priceTier := range priceTiers {
+			if priceTier.AccountType != priceTierRequest.AccountType {
+				continue
+			}
+			if priceTier.MinQty == priceTierRequest.MinQty {
+				exceptions.PanicIfBadRequest(errors.New("duplicate price tier"), requestId, []string{"min qty " + strconv.Itoa(priceTierRequest.MinQty) + " duplicate"}, service.Logger)
+			}
+			if (priceTier.MinQty < priceTierRequest.MinQty && priceTier.Price < priceTierRequest.Price) || (priceTier.MinQty > priceTierRequest.MinQty && priceTier.Price > priceTierRequest.Price) {
+				exceptions.PanicIfBadRequest(errors.New("invalid price tier"), requestId, []string{"harga tier harus turun untuk min qty yang lebih besar"}, service.Logger)
+			}
+		}
+		priceTiers = append(priceTiers, entity.ProductsDesaPriceTier{
+			Id:            utilities.RandomUUID(),
+			IdProductDesa: productDesa.Id,
+			AccountType:   priceTierRequest.AccountType,
+			MinQty:        priceTierRequest.MinQty,
+			Price:         priceTierRequest.Price,
+			CreatedDate:   time.Now(),
+		})
+	}
+
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	err := service.ProductPriceTierRepositoryInterface.DeletePriceTierByIdProductDesa(tx, productDesa.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error delete price tier"}, service.Logger, tx)
+
+	if len(priceTiers) > 0 {
+		err = service.ProductPriceTierRepositoryInterface.CreatePriceTier(tx, priceTiers)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create price tier"}, service.Logger, tx)
+	}
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
+}
+
+func (service *ProductAdminServiceImplementation) findProductDesaInDesa(requestId string, idDesa string, idProductDesa string) *entity.ProductsDesa {
+	productDesa, err := service.ProductDesaRepositoryInterface.FindProductDesaById(service.DB, idProductDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productDesa.Id) == 0 || productDesa.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("product not found"), requestId, []string{"product not found"}, service.Logger)
+	}
+	return productDesa
+}
+
+// adjustProductStock mengubah stok dan mencatat history, dipanggil di dalam transaksi
+func (service *ProductAdminServiceImplementation) adjustProductStock(tx *gorm.DB, requestId string, productDesa *entity.ProductsDesa, qty int, hargaBeli float64, reason string) {
+	rowsAffected, err := service.ProductDesaRepositoryInterface.AdjustProductStock(tx, productDesa.Id, qty)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"update stock error"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("stock not enough"), requestId, []string{"stock not enough"}, service.Logger)
+	}
+
+	productDesaUpdated, err := service.ProductDesaRepositoryInterface.FindProductDesaById(tx, productDesa.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find product desa"}, service.Logger, tx)
+
+	productDesaStockHistory := &entity.ProductDesaStockHistory{}
+	productDesaStockHistory.Id = utilities.RandomUUID()
+	productDesaStockHistory.IdProductDesa = productDesa.Id
+	productDesaStockHistory.TransDate = time.Now()
+	if qty > 0 {
+		productDesaStockHistory.AddStockQty = qty
+	} else {
+		productDesaStockHistory.MinStockQty = -qty
+	}
+	productDesaStockHistory.StockOpname = productDesaUpdated.StockOpname - qty
+	productDesaStockHistory.StockFinal = productDesaUpdated.StockOpname
+	productDesaStockHistory.HargaBeli = hargaBeli
+	productDesaStockHistory.HargaJual = productDesaUpdated.Price
+	productDesaStockHistory.Description = reason
+	productDesaStockHistory.CreatedDate = time.Now()
+	err = service.ProductDesaStockHistoryRepositoryInterface.CreateProductDesaStockHistory(tx, productDesaStockHistory)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"add stock history error"}, service.Logger, tx)
+
+	productDesa.StockOpname = productDesaUpdated.StockOpname
+}
+
+func toProductMasterEntity(createProductMasterRequest *request.CreateProductMasterRequest) *entity.ProductsMaster {
+	return &entity.ProductsMaster{
+		IdBrand:       createProductMasterRequest.IdBrand,
+		IdCategory:    createProductMasterRequest.IdCategory,
+		IdSubCategory: createProductMasterRequest.IdSubCategory,
+		IdUnit:        createProductMasterRequest.IdUnit,
+		UnitValue:     createProductMasterRequest.UnitValue,
+		NoSku:         createProductMasterRequest.NoSku,
+		ProductName:   createProductMasterRequest.ProductName,
+		Price:         createProductMasterRequest.Price,
+		PriceGrosir:   createProductMasterRequest.PriceGrosir,
+		Description:   createProductMasterRequest.Description,
+		PictureUrl:    createProductMasterRequest.PictureUrl,
+		Thumbnail:     createProductMasterRequest.Thumbnail,
+	}
+}
+
+// productDesaPromoStatus produk yang terhubung ke promo hanya aktif selama jadwal promo berjalan
+func (service *ProductAdminServiceImplementation) productDesaPromoStatus(requestId string, idDesa string, idPromo string, isPromo int) int {
+	if len(idPromo) == 0 {
+		return isPromo
+	}
+
+	promo, err := service.PromoRepositoryInterface.FindPromoById(service.DB, idPromo)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(promo.Id) == 0 || promo.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("promo not found"), requestId, []string{"promo not found"}, service.Logger)
+	}
+
+	now := time.Now()
+	if now.Before(promo.StartDate) || now.After(promo.EndDate) {
+		return 0
+	}
+	return 1
+}
+
+// promoFlag harga promo produk terjadwal tetap divalidasi walaupun promo belum aktif
+func promoFlag(idPromo string, isPromo int) int {
+	if len(idPromo) != 0 {
+		return 1
+	}
+	return isPromo
+}
+
+func validateProductDesaPromo(requestId string, price float64, pricePromo float64, isPromo int, logger *logrus.Logger) {
+	if isPromo == 1 && (pricePromo <= 0 || pricePromo >= price) {
+		exceptions.PanicIfBadRequest(errors.New("invalid price promo"), requestId, []string{"price promo must be lower than price"}, logger)
+	}
+}
+
+func promoPercentage(price float64, pricePromo float64, isPromo int) float64 {
+	if isPromo != 1 || price <= 0 {
+		return 0
+	}
+	return math.Round((price-pricePromo)/price*10000) / 100
+}
+
+type productDesaCsvRow struct {
+	NoSku       string
+	Price       float64
+	PriceGrosir float64
+	PricePromo  float64
+	IsPromo     int
+	StockOpname int
+	HasStock    bool
+	HargaBeli   float64
+}
+
+func parseProductDesaCsvRow(columns map[string]int, record []string) (row productDesaCsvRow, err error) {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	parseInt := func(column string) int {
+		if err != nil || len(value(column)) == 0 {
+			return 0
+		}
+		var result int
+		result, err = strconv.Atoi(value(column))
+		if err != nil {
+			err = errors.New("invalid " + column)
+		}
+		return result
+	}
+	parseFloat := func(column string) float64 {
+		if err != nil || len(value(column)) == 0 {
+			return 0
+		}
+		var result float64
+		result, err = strconv.ParseFloat(value(column), 64)
+		if err != nil || result < 0 {
+			err = errors.New("invalid " + column)
+		}
+		return result
+	}
+
+	row.NoSku = value("no_sku")
+	if len(row.NoSku) == 0 {
+		return row, errors.New("no_sku required")
+	}
+	row.Price = parseFloat("price")
+	row.PriceGrosir = parseFloat("price_grosir")
+	row.PricePromo = parseFloat("price_promo")
+	row.IsPromo = parseInt("is_promo")
+	row.HargaBeli = parseFloat("harga_beli")
+	if len(value("stock_opname")) != 0 {
+		row.HasStock = true
+		row.StockOpname = parseInt("stock_opname")
+	}
+	if err != nil {
+		return row, err
+	}
+
+	if row.Price <= 0 {
+		return row, errors.New("price must be greater than 0")
+	}
+	if row.IsPromo != 0 && row.IsPromo != 1 {
+		return row, errors.New("is_promo must be 0 or 1")
+	}
+	if row.IsPromo == 1 && (row.PricePromo <= 0 || row.PricePromo >= row.Price) {
+		return row, errors.New("price promo must be lower than price")
+	}
+	if row.StockOpname < 0 {
+		return row, errors.New("stock_opname must not be negative")
+	}
+	return row, nil
+}
@@ -1,13 +1,17 @@
 package service
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/sirupsen/logrus"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
@@ -21,15 +25,28 @@ type ProductDesaServiceInterface interface {
 	FindProductsDesaByPromo(requestId string, IdDesa string, IdPromo string, AccountType int) (productsDesaResponses []response.FindProductsDesaResponse)
 	FindProductDesaById(requestId string, IdProductDesa string, AccountType int) (productDesaReponse response.FindProductDesaByIdResponse)
 	UpdateProductStock(requestId string, IdOrder string, db *gorm.DB)
+	SearchProductsDesa(requestId string, IdDesa string, AccountType int, searchRequest *request.SearchProductsDesaRequest) (searchResponse response.SearchProductsDesaResponse)
+	FindPackageItems(requestId string, IdProductDesa string) []entity.ProductsPackageItems
+	FindProductUnits(requestId string) map[int]entity.ProductsUnit
+	CheckProductStock(requestId string, cartItems []entity.Cart)
+	FindPriceTiers(requestId string, IdProductDesas []string) []entity.ProductsDesaPriceTier
+	QuoteProductPrice(requestId string, IdProductDesa string, AccountType int, qty int) (priceQuote response.PriceQuote)
+}
+
+type productDesaSearchCursor struct {
+	Value string `json:"v"`
+	Id    string `json:"id"`
 }
 
 type ProductDesaServiceImplementation struct {
-	DB                               *gorm.DB
-	Validate                         *validator.Validate
-	Logger                           *logrus.Logger
-	ProductDesaRepositoryInterface   repository.ProductDesaRepositoryInterface
-	OrderItemRepositoryInterface     repository.OrderItemRepositoryInterface
-	ProductDesaStockHistoryInterface repository.ProductDesaStockHistoryRepositoryInterface
+	DB                                  *gorm.DB
+	Validate                            *validator.Validate
+	Logger                              *logrus.Logger
+	ProductDesaRepositoryInterface      repository.ProductDesaRepositoryInterface
+	OrderItemRepositoryInterface        repository.OrderItemRepositoryInterface
+	ProductDesaStockHistoryInterface    repository.ProductDesaStockHistoryRepositoryInterface
+	ProductUnitRepositoryInterface      repository.ProductUnitRepositoryInterface
+	ProductPriceTierRepositoryInterface repository.ProductPriceTierRepositoryInterface
 }
 
 func NewProductDesaService(
@@ -39,14 +56,18 @@ func NewProductDesaService(
 	productDesaRepositoryInterface repository.ProductDesaRepositoryInterface,
 	orderItemRepositoryInterface repository.OrderItemRepositoryInterface,
 	productDesaStockHistoryInterface repository.ProductDesaStockHistoryRepositoryInterface,
+	productUnitRepositoryInterface repository.ProductUnitRepositoryInterface,
+	productPriceTierRepositoryInterface repository.ProductPriceTierRepositoryInterface,
 ) ProductDesaServiceInterface {
 	return &ProductDesaServiceImplementation{
-		DB:                               db,
-		Validate:                         validate,
-		Logger:                           logger,
-		ProductDesaRepositoryInterface:   productDesaRepositoryInterface,
-		OrderItemRepositoryInterface:     orderItemRepositoryInterface,
-		ProductDesaStockHistoryInterface: productDesaStockHistoryInterface,
+		DB:                                  db,
+		Validate:                            validate,
+		Logger:                              logger,
+		ProductDesaRepositoryInterface:      productDesaRepositoryInterface,
+		OrderItemRepositoryInterface:        orderItemRepositoryInterface,
+		ProductDesaStockHistoryInterface:    productDesaStockHistoryInterface,
+		ProductUnitRepositoryInterface:      productUnitRepositoryInterface,
+		ProductPriceTierRepositoryInterface: productPriceTierRepositoryInterface,
 	}
 }
 
@@ -107,13 +128,19 @@ func (service *ProductDesaServiceImplementation) FindProductDesaById(requestid s
 	}
 
 	productDesaResponse = response.ToFindProductDesaByIdResponse(productDesa, AccountType, ListItemsPackage)
+	if productDesa.IdType == 2 {
+		productDesaResponse.StockOpname = PackageStock(ListItemsPackage)
+		if productDesaResponse.Price == 0 {
+			productDesaResponse.Price = PackagePrice(ListItemsPackage, AccountType)
+		}
+	}
 	return productDesaResponse
 }
 
 func (service *ProductDesaServiceImplementation) UpdateProductStock(requestId string, IdOrder string, db *gorm.DB) {
 	var err error
 	orderItems, err := service.OrderItemRepositoryInterface.FindOrderItemsByIdOrder(db, IdOrder)
-	exceptions.PanicIfError(err, requestId, service.Logger)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find order items"}, service.Logger, db)
 	if len(orderItems) == 0 {
 		exceptions.PanicIfErrorWithRollback(errors.New("product not found"), requestId, []string{"product not found"}, service.Logger, db)
 	}
@@ -122,22 +149,266 @@ func (service *ProductDesaServiceImplementation) UpdateProductStock(requestId st
 		productDesa, errFindProduct := service.ProductDesaRepositoryInterface.FindProductDesaById(db, orderItem.IdProductDesa)
 		exceptions.PanicIfErrorWithRollback(errFindProduct, requestId, []string{"product not found"}, service.Logger, db)
 
-		productDesaEntityStockHistory := &entity.ProductDesaStockHistory{}
-		productDesaEntityStockHistory.Id = utilities.RandomUUID()
-		productDesaEntityStockHistory.IdProductDesa = orderItem.IdProductDesa
-		productDesaEntityStockHistory.TransDate = time.Now()
-		productDesaEntityStockHistory.MinStockQty = orderItem.Qty
-		productDesaEntityStockHistory.StockFinal = productDesa.StockOpname - orderItem.Qty
-		productDesaEntityStockHistory.Description = "PEMBELIAN " + orderItem.NumberOrder
-		productDesaEntityStockHistory.CreatedDate = time.Now()
-		productDesaEntityStockHistory.HargaBeli = 0
-		productDesaEntityStockHistory.HargaJual = orderItem.Price
-		err = service.ProductDesaStockHistoryInterface.CreateProductDesaStockHistory(db, productDesaEntityStockHistory)
-		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"add stock history error"}, service.Logger, db)
-
-		productDesaEntity := &entity.ProductsDesa{}
-		productDesaEntity.StockOpname = productDesa.StockOpname - orderItem.Qty
-		err = service.ProductDesaRepositoryInterface.UpdateProductStock(db, productDesa.Id, productDesaEntity)
-		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"update stock error"}, service.Logger, db)
+		// Paket mengurangi stok masing-masing komponen
+		if productDesa.IdType == 2 {
+			packageItems, err := service.ProductDesaRepositoryInterface.FindListPackageByIdProductDesa(db, productDesa.Id)
+			exceptions.PanicIfErrorWithRollback(err, requestId, []string{"package item not found"}, service.Logger, db)
+			for _, packageItem := range packageItems {
+				component, err := service.ProductDesaRepositoryInterface.FindProductDesaById(db, packageItem.IdProductItem)
+				exceptions.PanicIfErrorWithRollback(err, requestId, []string{"product not found"}, service.Logger, db)
+				service.decreaseProductStock(requestId, db, component, packageItem.Qty*orderItem.Qty, component.Price, "PEMBELIAN PAKET "+orderItem.NumberOrder)
+			}
+			continue
+		}
+
+		service.decreaseProductStock(requestId, db, productDesa, orderItem.Qty, orderItem.Price, "PEMBELIAN "+orderItem.NumberOrder)
+	}
+}
+
+// decreaseProductStock stok dikurangi atomik di database, pembelian bersamaan tidak bisa membuat stok minus
+func (service *ProductDesaServiceImplementation) decreaseProductStock(requestId string, db *gorm.DB, productDesa *entity.ProductsDesa, qty int, hargaJual float64, description string) {
+	rowsAffected, err := service.ProductDesaRepositoryInterface.AdjustProductStock(db, productDesa.Id, -qty)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"update stock error"}, service.Logger, db)
+	if rowsAffected == 0 {
+		db.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("stock not enough"), requestId, []string{"stock not enough"}, service.Logger)
+	}
+
+	productDesaUpdated, err := service.ProductDesaRepositoryInterface.FindProductDesaById(db, productDesa.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find product desa"}, service.Logger, db)
+
+	productDesaEntityStockHistory := &entity.ProductDesaStockHistory{}
+	productDesaEntityStockHistory.Id = utilities.RandomUUID()
+	productDesaEntityStockHistory.IdProductDesa = productDesa.Id
+	productDesaEntityStockHistory.TransDate = time.Now()
+	productDesaEntityStockHistory.MinStockQty = qty
+	productDesaEntityStockHistory.StockOpname = productDesaUpdated.StockOpname + qty
+	productDesaEntityStockHistory.StockFinal = productDesaUpdated.StockOpname
+	productDesaEntityStockHistory.Description = description
+	productDesaEntityStockHistory.CreatedDate = time.Now()
+	productDesaEntityStockHistory.HargaBeli = 0
+	productDesaEntityStockHistory.HargaJual = hargaJual
+	err = service.ProductDesaStockHistoryInterface.CreateProductDesaStockHistory(db, productDesaEntityStockHistory)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"add stock history error"}, service.Logger, db)
+}
+
+func (service *ProductDesaServiceImplementation) FindPackageItems(requestId string, IdProductDesa string) []entity.ProductsPackageItems {
+	packageItems, err := service.ProductDesaRepositoryInterface.FindListPackageByIdProductDesa(service.DB, IdProductDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(packageItems) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("package item not found"), requestId, []string{"package item not found"}, service.Logger)
+	}
+	return packageItems
+}
+
+func (service *ProductDesaServiceImplementation) FindProductUnits(requestId string) map[int]entity.ProductsUnit {
+	productUnits, err := service.ProductUnitRepositoryInterface.FindProductUnits(service.DB)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	units := make(map[int]entity.ProductsUnit)
+	for _, productUnit := range productUnits {
+		units[productUnit.Id] = productUnit
+	}
+	return units
+}
+
+func (service *ProductDesaServiceImplementation) FindPriceTiers(requestId string, IdProductDesas []string) []entity.ProductsDesaPriceTier {
+	priceTiers, err := service.ProductPriceTierRepositoryInterface.FindPriceTierByIdProductDesas(service.DB, IdProductDesas)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	return priceTiers
+}
+
+func (service *ProductDesaServiceImplementation) QuoteProductPrice(requestId string, IdProductDesa string, AccountType int, qty int) (priceQuote response.PriceQuote) {
+	if qty < 1 {
+		exceptions.PanicIfBadRequest(errors.New("invalid qty"), requestId, []string{"qty minimal 1"}, service.Logger)
+	}
+
+	productDesa, err := service.ProductDesaRepositoryInterface.FindProductDesaById(service.DB, IdProductDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(productDesa.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+
+	var packageItems []entity.ProductsPackageItems
+	if productDesa.IdType == 2 {
+		packageItems = service.FindPackageItems(requestId, IdProductDesa)
+	}
+
+	priceTiers := service.FindPriceTiers(requestId, []string{IdProductDesa})
+	return QuotePrice(productDesa, AccountType, qty, priceTiers, packageItems)
+}
+
+// CheckProductStock memastikan stok cukup untuk seluruh isi cart, paket dihitung dari stok komponennya
+func (service *ProductDesaServiceImplementation) CheckProductStock(requestId string, cartItems []entity.Cart) {
+	required := make(map[string]int)
+	stocks := make(map[string]int)
+	names := make(map[string]string)
+	for _, cartItem := range cartItems {
+		if cartItem.ProductsDesa.IdType == 2 {
+			for _, packageItem := range service.FindPackageItems(requestId, cartItem.IdProductDesa) {
+				required[packageItem.IdProductItem] += packageItem.Qty * cartItem.Qty
+				stocks[packageItem.IdProductItem] = packageItem.ProductsDesa.StockOpname
+				names[packageItem.IdProductItem] = packageItem.ProductName
+			}
+			continue
+		}
+		required[cartItem.IdProductDesa] += cartItem.Qty
+		stocks[cartItem.IdProductDesa] = cartItem.ProductsDesa.StockOpname
+		names[cartItem.IdProductDesa] = cartItem.ProductsDesa.ProductsMaster.ProductName
+	}
+
+	for idProductDesa, qty := range required {
+		if qty > stocks[idProductDesa] {
+			exceptions.PanicIfBadRequest(errors.New("stock not enough"), requestId, []string{"stok " + names[idProductDesa] + " tidak mencukupi"}, service.Logger)
+		}
+	}
+}
+
+// PackagePrice harga satu paket yang dihitung dari harga komponennya
+func PackagePrice(packageItems []entity.ProductsPackageItems, AccountType int) (price float64) {
+	for _, packageItem := range packageItems {
+		componentPrice := packageItem.Price
+		if AccountType == 2 && packageItem.ProductsDesa.PriceGrosir > 0 {
+			componentPrice = packageItem.ProductsDesa.PriceGrosir
+		} else if AccountType == 1 && packageItem.ProductsDesa.IsPromo == 1 && packageItem.ProductsDesa.PricePromo > 0 {
+			componentPrice = packageItem.ProductsDesa.PricePromo
+		} else if AccountType == 1 && packageItem.ProductsDesa.Price > 0 {
+			componentPrice = packageItem.ProductsDesa.Price
+		}
+		price = price + componentPrice*float64(packageItem.Qty)
+	}
+	return price
+}
+
+// PackageStock jumlah paket yang bisa dibentuk dari stok komponen
+func PackageStock(packageItems []entity.ProductsPackageItems) (stock int) {
+	for i, packageItem := range packageItems {
+		if packageItem.Qty <= 0 {
+			continue
+		}
+		componentStock := packageItem.ProductsDesa.StockOpname / packageItem.Qty
+		if i == 0 || componentStock < stock {
+			stock = componentStock
+		}
+	}
+	if stock < 0 {
+		stock = 0
+	}
+	return stock
+}
+
+// ProductWeightVolume berat (kg) dan volume (liter) untuk satu qty produk berdasarkan satuannya
+func ProductWeightVolume(units map[int]entity.ProductsUnit, productsMaster *entity.ProductsMaster) (weight float64, volume float64) {
+	unit, ok := units[productsMaster.IdUnit]
+	if !ok {
+		return 0, 0
+	}
+	switch unit.UnitType {
+	case 1:
+		weight = productsMaster.UnitValue * unit.Conversion
+	case 2:
+		volume = productsMaster.UnitValue * unit.Conversion
+	}
+	return weight, volume
+}
+
+func (service *ProductDesaServiceImplementation) SearchProductsDesa(requestId string, IdDesa string, AccountType int, searchRequest *request.SearchProductsDesaRequest) (searchResponse response.SearchProductsDesaResponse) {
+	request.ValidateRequest(service.Validate, searchRequest, requestId, service.Logger)
+
+	params := repository.ProductDesaSearchParams{
+		Keyword:       searchRequest.Keyword,
+		IdCategory:    searchRequest.IdCategory,
+		IdSubCategory: searchRequest.IdSubCategory,
+		IdBrand:       searchRequest.IdBrand,
+		IsPromo:       searchRequest.IsPromo,
+		InStock:       searchRequest.InStock,
+		Limit:         searchRequest.Limit,
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	// Harga yang diurutkan mengikuti harga yang tampil ke user, user biasa melihat harga promo jika produk promo
+	priceColumn := "(CASE WHEN products_desa.is_promo = 1 THEN products_desa.price_promo ELSE products_desa.price END)"
+	if AccountType == 2 {
+		priceColumn = "products_desa.price_grosir"
+	}
+	switch searchRequest.Sort {
+	case "price_asc":
+		params.SortColumn = priceColumn
+	case "price_desc":
+		params.SortColumn, params.SortDesc = priceColumn, true
+	case "name_desc":
+		params.SortColumn, params.SortDesc = "ProductsMaster.product_name", true
+	case "newest":
+		params.SortColumn, params.SortDesc = "products_desa.created_at", true
+	default:
+		params.SortColumn = "ProductsMaster.product_name"
+	}
+
+	if len(searchRequest.Cursor) != 0 {
+		cursor := productDesaSearchCursor{}
+		data, err := base64.RawURLEncoding.DecodeString(searchRequest.Cursor)
+		if err == nil {
+			err = json.Unmarshal(data, &cursor)
+		}
+		if err == nil {
+			params.CursorValue, err = parseProductDesaSearchCursorValue(searchRequest.Sort, cursor.Value)
+		}
+		if err != nil || len(cursor.Id) == 0 {
+			exceptions.PanicIfBadRequest(errors.New("invalid cursor"), requestId, []string{"invalid cursor"}, service.Logger)
+		}
+		params.CursorId = cursor.Id
+	}
+
+	// Ambil satu data lebih untuk mengetahui masih ada halaman berikutnya
+	limit := params.Limit
+	params.Limit = limit + 1
+	productsDesa, total, err := service.ProductDesaRepositoryInterface.SearchProductsDesa(service.DB, IdDesa, params)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	if len(productsDesa) > limit {
+		productsDesa = productsDesa[:limit]
+		last := productsDesa[len(productsDesa)-1]
+		cursor, err := json.Marshal(productDesaSearchCursor{
+			Value: productDesaSearchCursorValue(searchRequest.Sort, &last, AccountType),
+			Id:    last.Id,
+		})
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		searchResponse.NextCursor = base64.RawURLEncoding.EncodeToString(cursor)
+	}
+
+	searchResponse.Products = response.ToFindProductsDesaResponse(productsDesa, AccountType)
+	if searchResponse.Products == nil {
+		searchResponse.Products = []response.FindProductsDesaResponse{}
+	}
+	searchResponse.Total = total
+	return searchResponse
+}
+
+func productDesaSearchCursorValue(sort string, productDesa *entity.ProductsDesa, AccountType int) string {
+	switch sort {
+	case "price_asc", "price_desc":
+		if AccountType == 2 {
+			return strconv.FormatFloat(productDesa.PriceGrosir, 'f', -1, 64)
+		}
+		if productDesa.IsPromo == 1 {
+			return strconv.FormatFloat(productDesa.PricePromo, 'f', -1, 64)
+		}
+		return strconv.FormatFloat(productDesa.Price, 'f', -1, 64)
+	case "newest":
+		return productDesa.CreatedDate.Format(time.RFC3339Nano)
+	default:
+		return productDesa.ProductsMaster.ProductName
+	}
+}
+
+func parseProductDesaSearchCursorValue(sort string, value string) (interface{}, error) {
+	switch sort {
+	case "price_asc", "price_desc":
+		return strconv.ParseFloat(value, 64)
+	case "newest":
+		return time.Parse(time.RFC3339Nano, value)
+	default:
+		return value, nil
 	}
 }
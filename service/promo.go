@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/go-playground/validator"
 	"github.com/sirupsen/logrus"
 	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
@@ -11,6 +13,7 @@ import (
 
 type PromoServiceInterface interface {
 	FindPromo(requestId string, IdDesa string) (promosResponses []response.FindPromoResponse)
+	SyncPromoSchedule()
 }
 
 type PromoServiceImplementation struct {
@@ -40,3 +43,15 @@ func (service *PromoServiceImplementation) FindPromo(requestId string, IdDesa st
 	promosResponses = response.ToFindPromoResponse(promos)
 	return promosResponses
 }
+
+// SyncPromoSchedule dijalankan scheduler agar harga promo produk mengikuti start dan end date promo
+func (service *PromoServiceImplementation) SyncPromoSchedule() {
+	rowsAffected, err := service.PromoRepositoryInterface.SyncProductPromoSchedule(service.DB, time.Now())
+	if err != nil {
+		service.Logger.WithField("error", err.Error()).Error("sync promo schedule")
+		return
+	}
+	if rowsAffected > 0 {
+		service.Logger.WithField("products", rowsAffected).Info("sync promo schedule")
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gorm.io/gorm"
+)
+
+type ReferralServiceInterface interface {
+	FindReferralStats(requestId string, idUser string) (referralStatsResponse response.FindReferralStatsResponse)
+	GenerateReferralCode(requestId string) string
+	FindReferrer(requestId string, referralCode string, phone string, noIdentitas string) *entity.User
+	CreateReferral(requestId string, tx *gorm.DB, referrer *entity.User, idReferee string, noIdentitas string, deviceId string)
+}
+
+type ReferralServiceImplementation struct {
+	DB                                 *gorm.DB
+	ConfigPrivacy                      config.Privacy
+	Validate                           *validator.Validate
+	Logger                             *logrus.Logger
+	ReferralRepositoryInterface        repository.ReferralRepositoryInterface
+	UserRepositoryInterface            repository.UserRepositoryInterface
+	UserProfileRepositoryInterface     repository.UserProfileRepositoryInterface
+	UserDeviceTokenRepositoryInterface repository.UserDeviceTokenRepositoryInterface
+}
+
+func NewReferralService(
+	db *gorm.DB,
+	configPrivacy config.Privacy,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	referralRepositoryInterface repository.ReferralRepositoryInterface,
+	userRepositoryInterface repository.UserRepositoryInterface,
+	userProfileRepositoryInterface repository.UserProfileRepositoryInterface,
+	userDeviceTokenRepositoryInterface repository.UserDeviceTokenRepositoryInterface,
+) ReferralServiceInterface {
+	// Tanpa pepper hash no identitas bisa ditebak dari daftar NIK
+	if len(configPrivacy.IdentityPepper) == 0 {
+		panic("privacy identity pepper not configured")
+	}
+	return &ReferralServiceImplementation{
+		DB:                                 db,
+		ConfigPrivacy:                      configPrivacy,
+		Validate:                           validate,
+		Logger:                             logger,
+		ReferralRepositoryInterface:        referralRepositoryInterface,
+		UserRepositoryInterface:            userRepositoryInterface,
+		UserProfileRepositoryInterface:     userProfileRepositoryInterface,
+		UserDeviceTokenRepositoryInterface: userDeviceTokenRepositoryInterface,
+	}
+}
+
+func (service *ReferralServiceImplementation) FindReferralStats(requestId string, idUser string) (referralStatsResponse response.FindReferralStatsResponse) {
+	user, err := service.UserRepositoryInterface.FindUserById2(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(user.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("user not found"), requestId, []string{"user not found"}, service.Logger)
+	}
+
+	// User lama belum punya kode referral
+	if len(user.ReferralCode) == 0 {
+		user.ReferralCode = service.GenerateReferralCode(requestId)
+		err = service.UserRepositoryInterface.UpdateUserReferralCode(service.DB, user.Id, user.ReferralCode)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+	}
+
+	referrals, err := service.ReferralRepositoryInterface.FindReferralsByReferrer(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	referralStatsResponse = response.ToFindReferralStatsResponse(user.ReferralCode, referrals)
+	return referralStatsResponse
+}
+
+func (service *ReferralServiceImplementation) GenerateReferralCode(requestId string) string {
+	for i := 0; i < 5; i++ {
+		referralCode := utilities.GenerateReferralCode()
+		user, err := service.UserRepositoryInterface.FindUserByReferralCode(service.DB, referralCode)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(user.Id) == 0 {
+			return referralCode
+		}
+	}
+	exceptions.PanicIfError(errors.New("failed generate referral code"), requestId, service.Logger)
+	return ""
+}
+
+// FindReferrer mengembalikan nil jika kode referral tidak diisi
+func (service *ReferralServiceImplementation) FindReferrer(requestId string, referralCode string, phone string, noIdentitas string) *entity.User {
+	referralCode = strings.ToUpper(strings.TrimSpace(referralCode))
+	if len(referralCode) == 0 {
+		return nil
+	}
+
+	referrer, err := service.UserRepositoryInterface.FindUserByReferralCode(service.DB, referralCode)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(referrer.Id) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("referral code not found"), requestId, []string{"kode referral tidak valid"}, service.Logger)
+	}
+
+	referrerProfile, err := service.UserProfileRepositoryInterface.FindUserProfileByIdUser(service.DB, referrer.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if referrer.Phone == phone || referrerProfile.NoIdentitas == noIdentitas {
+		exceptions.PanicIfBadRequest(errors.New("self referral"), requestId, []string{"tidak bisa memakai kode referral sendiri"}, service.Logger)
+	}
+
+	return referrer
+}
+
+// CreateReferral mencatat referral di dalam transaksi registrasi, referral yang terindikasi
+// curang tetap dicatat dengan status ditolak supaya tidak mendapat reward
+func (service *ReferralServiceImplementation) CreateReferral(requestId string, tx *gorm.DB, referrer *entity.User, idReferee string, noIdentitas string, deviceId string) {
+	var rejectReason string
+
+	// No identitas hanya disimpan dalam bentuk hash, cukup untuk mendeteksi referral berulang
+	noIdentitas = HashNoIdentitas(service.ConfigPrivacy.IdentityPepper, noIdentitas)
+
+	totalNoIdentitas, err := service.ReferralRepositoryInterface.CountReferralByNoIdentitas(tx, noIdentitas)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find referral"}, service.Logger, tx)
+	if totalNoIdentitas > 0 {
+		rejectReason = "no identitas sudah pernah direferensikan"
+	}
+
+	if len(rejectReason) == 0 && len(deviceId) != 0 {
+		totalDevice, err := service.ReferralRepositoryInterface.CountReferralByDeviceId(tx, deviceId)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find referral"}, service.Logger, tx)
+		if totalDevice > 0 {
+			rejectReason = "device sudah pernah digunakan"
+		}
+	}
+
+	// Device referee sama dengan device yang pernah dipakai login referrer
+	if len(rejectReason) == 0 && len(deviceId) != 0 {
+		referrerDeviceTokens, err := service.UserDeviceTokenRepositoryInterface.FindUserDeviceTokenByIdUser(tx, referrer.Id)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find user device token"}, service.Logger, tx)
+		for _, referrerDeviceToken := range referrerDeviceTokens {
+			if referrerDeviceToken.DeviceId == deviceId {
+				rejectReason = "device sudah pernah digunakan"
+				break
+			}
+		}
+	}
+
+	status := 1
+	if len(rejectReason) != 0 {
+		status = 3
+	}
+
+	err = service.ReferralRepositoryInterface.CreateReferral(tx, &entity.Referral{
+		Id:           utilities.RandomUUID(),
+		IdReferrer:   referrer.Id,
+		IdReferee:    idReferee,
+		ReferralCode: referrer.ReferralCode,
+		NoIdentitas:  noIdentitas,
+		DeviceId:     deviceId,
+		Status:       status,
+		RejectReason: rejectReason,
+		CreatedAt:    time.Now(),
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create referral"}, service.Logger, tx)
+}
+
+// HashNoIdentitas menghasilkan HMAC-SHA256 no identitas dengan pepper server
+func HashNoIdentitas(pepper string, noIdentitas string) string {
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(strings.TrimSpace(noIdentitas)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gorm.io/gorm"
+)
+
+var (
+	errShippingAddressNotFound = errors.New("alamat pengiriman tidak ditemukan")
+	errShippingOutOfArea       = errors.New("alamat pengiriman di luar area layanan")
+)
+
+type ShippingServiceInterface interface {
+	// QuoteShippingCost hitung ongkir ke alamat, error jika alamat tidak ada atau di luar area layanan
+	QuoteShippingCost(requestId, idDesa string, userShippingAddress *entity.UserShippingAddress) (shippingQuoteResponse response.FindShippingQuoteResponse, err error)
+	CalculateShippingCost(requestId, idDesa string, userShippingAddress *entity.UserShippingAddress) float64
+	FindUserShippingAddress(requestId, idUser, idAddress string) (userShippingAddress *entity.UserShippingAddress)
+	FindShippingQuote(requestId, idUser, idDesa, idAddress string) (shippingQuoteResponse response.FindShippingQuoteResponse)
+	FindShippingZone(requestId, idDesa string) (shippingZoneResponse response.FindShippingZoneResponse)
+	UpdateShippingZone(requestId, idDesa string, updateShippingZoneRequest *request.UpdateShippingZoneRequest)
+}
+
+type ShippingServiceImplementation struct {
+	DB                                     *gorm.DB
+	Validate                               *validator.Validate
+	Logger                                 *logrus.Logger
+	ShippingZoneRepositoryInterface        repository.ShippingZoneRepositoryInterface
+	DesaRepositoryInterface                repository.DesaRepositoryInterface
+	UserShippingAddressRepositoryInterface repository.UserShippingAddressRepositoryInterface
+}
+
+func NewShippingService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	shippingZoneRepositoryInterface repository.ShippingZoneRepositoryInterface,
+	desaRepositoryInterface repository.DesaRepositoryInterface,
+	userShippingAddressRepositoryInterface repository.UserShippingAddressRepositoryInterface,
+) ShippingServiceInterface {
+	return &ShippingServiceImplementation{
+		DB:                                     db,
+		Validate:                               validate,
+		Logger:                                 logger,
+		ShippingZoneRepositoryInterface:        shippingZoneRepositoryInterface,
+		DesaRepositoryInterface:                desaRepositoryInterface,
+		UserShippingAddressRepositoryInterface: userShippingAddressRepositoryInterface,
+	}
+}
+
+func (service *ShippingServiceImplementation) QuoteShippingCost(requestId, idDesa string, userShippingAddress *entity.UserShippingAddress) (shippingQuoteResponse response.FindShippingQuoteResponse, err error) {
+	shippingZone, err := service.ShippingZoneRepositoryInterface.FindShippingZoneByIdDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	if userShippingAddress != nil {
+		shippingQuoteResponse.IdAddress = userShippingAddress.Id
+	}
+
+	// Desa tanpa depot tetap memakai ongkir flat
+	if len(shippingZone.IdDesa) == 0 {
+		desa, err := service.DesaRepositoryInterface.FindDesaById(service.DB, idDesa)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		shippingQuoteResponse.ShippingCost = desa.Ongkir
+		shippingQuoteResponse.Flat = true
+		return shippingQuoteResponse, nil
+	}
+
+	shippingQuoteResponse.FreeKm = shippingZone.FreeKm
+	shippingQuoteResponse.MaxKm = shippingZone.MaxKm
+
+	if userShippingAddress == nil || len(userShippingAddress.Id) == 0 {
+		return shippingQuoteResponse, errShippingAddressNotFound
+	}
+
+	distance := utilities.HaversineKm(shippingZone.DepotLatitude, shippingZone.DepotLongitude, userShippingAddress.Latitude, userShippingAddress.Longitude)
+	shippingQuoteResponse.DistanceKm = math.Round(distance*100) / 100
+
+	if shippingZone.MaxKm > 0 && distance > shippingZone.MaxKm {
+		return shippingQuoteResponse, errShippingOutOfArea
+	}
+
+	if distance > shippingZone.FreeKm {
+		shippingQuoteResponse.ShippingCost = shippingZone.BaseCost + math.Ceil(distance-shippingZone.FreeKm)*shippingZone.RatePerKm
+	}
+	return shippingQuoteResponse, nil
+}
+
+// CalculateShippingCost ongkir untuk checkout, alamat di luar area layanan ditolak
+func (service *ShippingServiceImplementation) CalculateShippingCost(requestId, idDesa string, userShippingAddress *entity.UserShippingAddress) float64 {
+	shippingQuoteResponse, err := service.QuoteShippingCost(requestId, idDesa, userShippingAddress)
+	if err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{service.shippingErrorMessage(err, shippingQuoteResponse)}, service.Logger)
+	}
+	return shippingQuoteResponse.ShippingCost
+}
+
+// FindUserShippingAddress tanpa id_address memakai alamat utama user
+func (service *ShippingServiceImplementation) FindUserShippingAddress(requestId, idUser, idAddress string) (userShippingAddress *entity.UserShippingAddress) {
+	var err error
+	if len(idAddress) != 0 {
+		userShippingAddress, err = service.UserShippingAddressRepositoryInterface.FindUserShippingAddressByIdAndIdUser(service.DB, idAddress, idUser)
+	} else {
+		userShippingAddress, err = service.UserShippingAddressRepositoryInterface.FindPrimaryUserShippingAddressByIdUser(service.DB, idUser)
+	}
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	return userShippingAddress
+}
+
+func (service *ShippingServiceImplementation) FindShippingQuote(requestId, idUser, idDesa, idAddress string) (shippingQuoteResponse response.FindShippingQuoteResponse) {
+	userShippingAddress := service.FindUserShippingAddress(requestId, idUser, idAddress)
+	shippingQuoteResponse, err := service.QuoteShippingCost(requestId, idDesa, userShippingAddress)
+	if err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{service.shippingErrorMessage(err, shippingQuoteResponse)}, service.Logger)
+	}
+	return shippingQuoteResponse
+}
+
+func (service *ShippingServiceImplementation) FindShippingZone(requestId, idDesa string) (shippingZoneResponse response.FindShippingZoneResponse) {
+	shippingZone, err := service.ShippingZoneRepositoryInterface.FindShippingZoneByIdDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(shippingZone.IdDesa) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("shipping zone not found"), requestId, []string{"shipping zone belum diatur"}, service.Logger)
+	}
+	return response.ToFindShippingZoneResponse(shippingZone)
+}
+
+func (service *ShippingServiceImplementation) UpdateShippingZone(requestId, idDesa string, updateShippingZoneRequest *request.UpdateShippingZoneRequest) {
+	request.ValidateRequest(service.Validate, updateShippingZoneRequest, requestId, service.Logger)
+
+	if updateShippingZoneRequest.MaxKm > 0 && updateShippingZoneRequest.MaxKm < updateShippingZoneRequest.FreeKm {
+		exceptions.PanicIfBadRequest(errors.New("max km less than free km"), requestId, []string{"max_km tidak boleh lebih kecil dari free_km"}, service.Logger)
+	}
+
+	err := service.ShippingZoneRepositoryInterface.SaveShippingZone(service.DB, &entity.ShippingZone{
+		IdDesa:         idDesa,
+		DepotLatitude:  updateShippingZoneRequest.DepotLatitude,
+		DepotLongitude: updateShippingZoneRequest.DepotLongitude,
+		FreeKm:         updateShippingZoneRequest.FreeKm,
+		BaseCost:       updateShippingZoneRequest.BaseCost,
+		RatePerKm:      updateShippingZoneRequest.RatePerKm,
+		MaxKm:          updateShippingZoneRequest.MaxKm,
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *ShippingServiceImplementation) shippingErrorMessage(err error, shippingQuoteResponse response.FindShippingQuoteResponse) string {
+	if err == errShippingOutOfArea {
+		return err.Error() + " (" + strconv.FormatFloat(shippingQuoteResponse.DistanceKm, 'f', 1, 64) + " km, maksimal " + strconv.FormatFloat(shippingQuoteResponse.MaxKm, 'f', 1, 64) + " km)"
+	}
+	return err.Error()
+}
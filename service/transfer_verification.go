@@ -0,0 +1,410 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	invelirepository "github.com/tensuqiuwulu/be-service-bupda-bali/repository/inveli_repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/storage"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+// Source mutasi bank
+const (
+	BankMutationSourceCsv    = "csv"
+	BankMutationSourceInveli = "inveli"
+)
+
+// Alias header csv export mutasi bank, dicek berurutan
+var (
+	bankMutationCsvDate        = []string{"tanggal", "tgl", "date", "transaction_date"}
+	bankMutationCsvDescription = []string{"keterangan", "deskripsi", "description", "remark"}
+	bankMutationCsvAmount      = []string{"kredit", "credit", "cr", "amount", "nominal", "jumlah", "mutasi"}
+	bankMutationCsvType        = []string{"db/cr", "cr/db", "d/k", "tipe", "type", "jenis"}
+	bankMutationCsvRef         = []string{"referensi", "reference", "ref", "no_ref", "id"}
+)
+
+type TransferVerificationServiceInterface interface {
+	SubmitBuktiBayar(requestId, idUser, idDesa string, submitBuktiBayarRequest *request.SubmitBuktiBayarRequest)
+	FindTransferVerifications(requestId, idDesa string) (transferVerificationResponses []response.FindTransferVerificationResponse)
+	VerifyTransfer(requestId, idDesa, idUser string, verifyTransferRequest *request.VerifyTransferRequest)
+	ImportBankMutationCsv(requestId, idDesa string, file io.Reader) (importResponse response.ImportBankMutationResponse)
+	ImportInveliMutation(requestId, idDesa, idUser string, importInveliMutationRequest *request.ImportInveliMutationRequest) (importResponse response.ImportBankMutationResponse)
+}
+
+type TransferVerificationServiceImplementation struct {
+	DB                                      *gorm.DB
+	Validate                                *validator.Validate
+	Logger                                  *logrus.Logger
+	ConfigStorage                           config.Storage
+	Storage                                 storage.Storage
+	OrderRepositoryInterface                repository.OrderRepositoryInterface
+	TransferUniqueAmountRepositoryInterface repository.TransferUniqueAmountRepositoryInterface
+	BankMutationRepositoryInterface         repository.BankMutationRepositoryInterface
+	DesaRepositoryInterface                 repository.DesaRepositoryInterface
+	UserRepositoryInterface                 repository.UserRepositoryInterface
+	InveliAPIRepositoryInterface            invelirepository.InveliAPIRepositoryInterface
+	OrderServiceInterface                   OrderServiceInterface
+}
+
+func NewTransferVerificationService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	configStorage config.Storage,
+	fileStorage storage.Storage,
+	orderRepositoryInterface repository.OrderRepositoryInterface,
+	transferUniqueAmountRepositoryInterface repository.TransferUniqueAmountRepositoryInterface,
+	bankMutationRepositoryInterface repository.BankMutationRepositoryInterface,
+	desaRepositoryInterface repository.DesaRepositoryInterface,
+	userRepositoryInterface repository.UserRepositoryInterface,
+	inveliAPIRepositoryInterface invelirepository.InveliAPIRepositoryInterface,
+	orderServiceInterface OrderServiceInterface,
+) TransferVerificationServiceInterface {
+	return &TransferVerificationServiceImplementation{
+		DB:                                      db,
+		Validate:                                validate,
+		Logger:                                  logger,
+		ConfigStorage:                           configStorage,
+		Storage:                                 fileStorage,
+		OrderRepositoryInterface:                orderRepositoryInterface,
+		TransferUniqueAmountRepositoryInterface: transferUniqueAmountRepositoryInterface,
+		BankMutationRepositoryInterface:         bankMutationRepositoryInterface,
+		DesaRepositoryInterface:                 desaRepositoryInterface,
+		UserRepositoryInterface:                 userRepositoryInterface,
+		InveliAPIRepositoryInterface:            inveliAPIRepositoryInterface,
+		OrderServiceInterface:                   orderServiceInterface,
+	}
+}
+
+// SubmitBuktiBayar customer kirim bukti transfer, order masuk antrian verifikasi admin desa
+func (service *TransferVerificationServiceImplementation) SubmitBuktiBayar(requestId, idUser, idDesa string, submitBuktiBayarRequest *request.SubmitBuktiBayarRequest) {
+	request.ValidateRequest(service.Validate, submitBuktiBayarRequest, requestId, service.Logger)
+
+	if !strings.HasPrefix(submitBuktiBayarRequest.FotoBuktiBayar, storage.PrefixPrivate+UploadCategoryBuktiBayar+"/") {
+		exceptions.PanicIfBadRequest(errors.New("invalid foto bukti bayar"), requestId, []string{"foto bukti bayar tidak valid"}, service.Logger)
+	}
+
+	order, err := service.OrderRepositoryInterface.FindOrderByIdUser(service.DB, submitBuktiBayarRequest.IdOrder, idUser, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(order.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
+	}
+
+	if order.PaymentMethod != "trf" || order.OrderStatus != 0 || order.PaymentStatus != 0 {
+		exceptions.PanicIfBadRequest(errors.New("order not waiting transfer"), requestId, []string{"order tidak menunggu pembayaran transfer"}, service.Logger)
+	}
+
+	// Nominal unik bisa sudah dipakai order lain setelah jatuh tempo
+	if order.PaymentDueDate.Valid && time.Now().After(order.PaymentDueDate.Time) {
+		exceptions.PanicIfBadRequest(errors.New("payment due date passed"), requestId, []string{"batas waktu pembayaran sudah lewat"}, service.Logger)
+	}
+
+	rowsAffected, err := service.OrderRepositoryInterface.UpdateOrderByIdOrderAndStatus(service.DB, order.Id, 0, &entity.Order{
+		FotoBuktiBayar: submitBuktiBayarRequest.FotoBuktiBayar,
+		BuktiBayarDate: null.NewTime(time.Now(), true),
+	})
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if rowsAffected == 0 {
+		exceptions.PanicIfBadRequest(errors.New("order status changed"), requestId, []string{"status order sudah berubah"}, service.Logger)
+	}
+
+	desa, err := service.DesaRepositoryInterface.FindDesaById(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	mssg := "Bukti Transfer Order " + order.NumberOrder + " Dari " + order.NamaLengkap + " Sebesar " + utilities.FormatRupiah(order.PaymentCash) + " Menunggu Verifikasi"
+	go service.OrderServiceInterface.SendMessageToTelegram(mssg, desa.ChatIdTelegram, desa.TokenBot)
+}
+
+func (service *TransferVerificationServiceImplementation) FindTransferVerifications(requestId, idDesa string) (transferVerificationResponses []response.FindTransferVerificationResponse) {
+	orders, err := service.OrderRepositoryInterface.FindOrdersTransferPendingByDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	fotoBuktiBayar := map[string]string{}
+	for _, order := range orders {
+		fotoBuktiBayar[order.Id] = storage.ResolveUrl(service.Storage, order.FotoBuktiBayar, storage.SignedUrlExpiry(service.ConfigStorage))
+	}
+	return response.ToFindTransferVerificationResponses(orders, fotoBuktiBayar)
+}
+
+// VerifyTransfer approve memproses order seperti pembayaran sukses, reject menghapus bukti bayar supaya customer upload ulang
+func (service *TransferVerificationServiceImplementation) VerifyTransfer(requestId, idDesa, idUser string, verifyTransferRequest *request.VerifyTransferRequest) {
+	request.ValidateRequest(service.Validate, verifyTransferRequest, requestId, service.Logger)
+
+	order, err := service.OrderRepositoryInterface.FindOrderByIdDesa(service.DB, verifyTransferRequest.IdOrder, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(order.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("order not found"), requestId, []string{"order not found"}, service.Logger)
+	}
+
+	if order.PaymentMethod != "trf" || order.OrderStatus != 0 || order.PaymentStatus != 0 {
+		exceptions.PanicIfBadRequest(errors.New("order not waiting transfer"), requestId, []string{"order tidak menunggu pembayaran transfer"}, service.Logger)
+	}
+
+	if verifyTransferRequest.Status == "reject" {
+		if len(verifyTransferRequest.Note) == 0 {
+			exceptions.PanicIfBadRequest(errors.New("note required"), requestId, []string{"alasan penolakan wajib diisi"}, service.Logger)
+		}
+		err = service.OrderRepositoryInterface.RejectOrderBuktiBayar(service.DB, order.Id, verifyTransferRequest.Note)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		return
+	}
+
+	// Bukti bayar yang dikirim sebelum jatuh tempo tetap boleh disetujui meski verifikasi terlambat
+	if order.PaymentDueDate.Valid && time.Now().After(order.PaymentDueDate.Time) && (!order.BuktiBayarDate.Valid || order.BuktiBayarDate.Time.After(order.PaymentDueDate.Time)) {
+		exceptions.PanicIfBadRequest(errors.New("payment due date passed"), requestId, []string{"batas waktu pembayaran sudah lewat"}, service.Logger)
+	}
+
+	service.OrderServiceInterface.ConfirmOrderPayment(requestId, order, idUser)
+}
+
+// ImportBankMutationCsv baca export mutasi bank, baris debit dilewati dan kredit dicocokkan ke nominal unik order
+func (service *TransferVerificationServiceImplementation) ImportBankMutationCsv(requestId, idDesa string, file io.Reader) (importResponse response.ImportBankMutationResponse) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"invalid csv file"}, service.Logger)
+	}
+	if len(records) < 2 {
+		exceptions.PanicIfBadRequest(errors.New("empty csv file"), requestId, []string{"empty csv file"}, service.Logger)
+	}
+
+	columns := map[string]int{}
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	dateColumn := bankMutationCsvColumn(columns, bankMutationCsvDate)
+	amountColumn := bankMutationCsvColumn(columns, bankMutationCsvAmount)
+	if dateColumn < 0 || amountColumn < 0 {
+		exceptions.PanicIfBadRequest(errors.New("column tanggal and kredit required"), requestId, []string{"kolom tanggal dan kredit wajib ada"}, service.Logger)
+	}
+	descriptionColumn := bankMutationCsvColumn(columns, bankMutationCsvDescription)
+	typeColumn := bankMutationCsvColumn(columns, bankMutationCsvType)
+	refColumn := bankMutationCsvColumn(columns, bankMutationCsvRef)
+
+	importResponse.MatchedOrders = []string{}
+	importResponse.Skipped = []string{}
+
+	for i, record := range records[1:] {
+		line := "baris " + strconv.Itoa(i+2) + ": "
+		value := func(column int) string {
+			if column < 0 || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		// Baris debit dan kredit kosong bukan transfer masuk
+		if strings.HasPrefix(strings.ToUpper(value(typeColumn)), "D") || len(value(amountColumn)) == 0 {
+			continue
+		}
+		amount, err := parseBankMutationAmount(value(amountColumn))
+		if err != nil {
+			importResponse.Skipped = append(importResponse.Skipped, line+err.Error())
+			continue
+		}
+		// Kolom nominal tunggal, debit ditandai akhiran DB/DR atau tanda minus
+		if amount < 0 {
+			continue
+		}
+
+		// Tanpa nomor referensi, baris yang sama diidentifikasi dari isinya
+		ref := value(refColumn)
+		if len(ref) == 0 {
+			hash := sha256.Sum256([]byte(value(dateColumn) + "|" + value(descriptionColumn) + "|" + strconv.FormatFloat(amount, 'f', 2, 64)))
+			ref = hex.EncodeToString(hash[:])
+		}
+
+		service.matchBankMutation(requestId, &entity.BankMutation{
+			IdDesa:          idDesa,
+			Source:          BankMutationSourceCsv,
+			MutationRef:     ref,
+			TransactionDate: value(dateColumn),
+			Description:     value(descriptionColumn),
+			Amount:          amount,
+		}, &importResponse)
+	}
+	return importResponse
+}
+
+// ImportInveliMutation tarik mutasi rekening desa dari inveli memakai token admin yang login
+func (service *TransferVerificationServiceImplementation) ImportInveliMutation(requestId, idDesa, idUser string, importInveliMutationRequest *request.ImportInveliMutationRequest) (importResponse response.ImportBankMutationResponse) {
+	request.ValidateRequest(service.Validate, importInveliMutationRequest, requestId, service.Logger)
+
+	desa, err := service.DesaRepositoryInterface.FindDesaById(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(desa.IdRekeningInveli) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("desa inveli account not set"), requestId, []string{"rekening inveli desa belum diatur"}, service.Logger)
+	}
+
+	user, err := service.UserRepositoryInterface.FindUserById(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(user.User.InveliAccessToken) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("inveli token not found"), requestId, []string{"akun admin belum terhubung ke inveli"}, service.Logger)
+	}
+
+	mutations, err := service.InveliAPIRepositoryInterface.GetMutation(user.User.InveliAccessToken, desa.IdRekeningInveli, importInveliMutationRequest.StartDate, importInveliMutationRequest.EndDate)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	importResponse.MatchedOrders = []string{}
+	importResponse.Skipped = []string{}
+	for _, mutation := range mutations {
+		if mutation.CreditAmount <= 0 {
+			continue
+		}
+		service.matchBankMutation(requestId, &entity.BankMutation{
+			IdDesa:          idDesa,
+			Source:          BankMutationSourceInveli,
+			MutationRef:     mutation.ID,
+			TransactionDate: mutation.TransactionDate,
+			Description:     mutation.Description,
+			Amount:          mutation.CreditAmount,
+		}, &importResponse)
+	}
+	return importResponse
+}
+
+// matchBankMutation cocokkan mutasi dengan reservasi nominal unik, hanya nominal yang persis sama yang dianggap cocok
+func (service *TransferVerificationServiceImplementation) matchBankMutation(requestId string, bankMutation *entity.BankMutation, importResponse *response.ImportBankMutationResponse) {
+	importResponse.Total++
+
+	existing, err := service.BankMutationRepositoryInterface.FindBankMutationByRef(service.DB, bankMutation.IdDesa, bankMutation.Source, bankMutation.MutationRef)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(existing.Id) != 0 {
+		importResponse.Duplicate++
+		return
+	}
+
+	numberOrder := ""
+	transferUniqueAmount, err := service.TransferUniqueAmountRepositoryInterface.FindTransferUniqueAmount(service.DB, bankMutation.IdDesa, bankMutation.Amount)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(transferUniqueAmount.IdOrder) != 0 {
+		order, err := service.OrderRepositoryInterface.FindOrderByIdDesa(service.DB, transferUniqueAmount.IdOrder, bankMutation.IdDesa)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(order.Id) != 0 && order.PaymentMethod == "trf" && order.OrderStatus == 0 && order.PaymentCash == bankMutation.Amount && !bankMutationBeforeOrder(bankMutation, order) {
+			// Mutasi baru disimpan setelah transaksi pembayaran order commit, jika gagal mutasi bisa diimport ulang
+			service.OrderServiceInterface.ConfirmOrderPayment(requestId, order, "mutasi_"+bankMutation.Source)
+			bankMutation.IdOrder = order.Id
+			bankMutation.Status = 1
+			numberOrder = order.NumberOrder
+		}
+	}
+
+	bankMutation.Id = utilities.RandomUUID()
+	bankMutation.CreatedAt = time.Now()
+	err = service.BankMutationRepositoryInterface.CreateBankMutation(service.DB, bankMutation)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	if bankMutation.Status == 1 {
+		importResponse.Matched++
+		importResponse.MatchedOrders = append(importResponse.MatchedOrders, numberOrder)
+	} else {
+		importResponse.Unmatched++
+	}
+}
+
+func bankMutationCsvColumn(columns map[string]int, aliases []string) int {
+	for _, alias := range aliases {
+		if i, ok := columns[alias]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseBankMutationAmount terima format 1.250.123,00 / 1,250,123.00 / Rp 1250123 / 1250123 CR.
+// Nominal debit (akhiran DB/DR/D atau tanda minus) dikembalikan negatif.
+func parseBankMutationAmount(value string) (float64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	debit := false
+	for _, suffix := range []string{"DB", "DR", "DEBIT", "D", "-"} {
+		if strings.HasSuffix(upper, suffix) {
+			debit = true
+			break
+		}
+	}
+	if i := strings.IndexAny(upper, "0123456789"); i > 0 && strings.Contains(upper[:i], "-") {
+		debit = true
+	}
+
+	cleaned := strings.Builder{}
+	for _, char := range upper {
+		if (char >= '0' && char <= '9') || char == '.' || char == ',' {
+			cleaned.WriteRune(char)
+		}
+	}
+	number := cleaned.String()
+	if len(number) == 0 {
+		return 0, errors.New("nominal tidak valid " + value)
+	}
+
+	// Pemisah terakhir yang diikuti tepat 2 digit adalah desimal, sisanya pemisah ribuan
+	decimal := ""
+	if i := strings.LastIndexAny(number, ".,"); i >= 0 && len(number)-i-1 == 2 {
+		decimal = number[i+1:]
+		number = number[:i]
+	}
+	number = strings.NewReplacer(".", "", ",", "").Replace(number)
+	if len(decimal) != 0 {
+		number = number + "." + decimal
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil || amount <= 0 {
+		return 0, errors.New("nominal tidak valid " + value)
+	}
+	if debit {
+		return -amount, nil
+	}
+	return amount, nil
+}
+
+// Format tanggal mutasi yang dikenali, export bank umumnya hanya berisi tanggal
+var bankMutationDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/2006",
+	"02-01-2006",
+	"02/01/06",
+}
+
+func parseBankMutationDate(value string) (time.Time, error) {
+	for _, layout := range bankMutationDateLayouts {
+		if date, err := time.ParseInLocation(layout, strings.TrimSpace(value), time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, errors.New("tanggal tidak valid " + value)
+}
+
+// bankMutationBeforeOrder mutasi sebelum order dibuat bukan pembayaran order tersebut meski nominalnya sama.
+// Dibandingkan per tanggal karena export bank tidak selalu mencantumkan jam, tanggal yang tidak dikenali dianggap tidak cocok.
+func bankMutationBeforeOrder(bankMutation *entity.BankMutation, order *entity.Order) bool {
+	transactionDate, err := parseBankMutationDate(bankMutation.TransactionDate)
+	if err != nil {
+		return true
+	}
+	orderedDate := order.OrderedDate.In(time.Local)
+	return transactionDate.Before(time.Date(orderedDate.Year(), orderedDate.Month(), orderedDate.Day(), 0, 0, 0, 0, time.Local))
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/storage"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+)
+
+// Kategori upload, menentukan folder, tipe file dan apakah file private
+const (
+	UploadCategoryProduct      = "product"
+	UploadCategoryBanner       = "banner"
+	UploadCategoryInfoDesa     = "info_desa"
+	UploadCategoryBuktiBayar   = "bukti_bayar"
+	UploadCategoryBarangSampai = "barang_sampai"
+)
+
+const (
+	uploadDefaultMaxSize   = 5 * 1024 * 1024
+	uploadThumbnailWidth   = 300
+	uploadThumbnailQuality = 80
+)
+
+type uploadCategory struct {
+	private      bool
+	thumbnail    bool
+	admin        bool
+	contentTypes []string
+}
+
+var uploadCategories = map[string]uploadCategory{
+	UploadCategoryProduct:      {thumbnail: true, admin: true, contentTypes: []string{"image/jpeg", "image/png"}},
+	UploadCategoryBanner:       {thumbnail: true, admin: true, contentTypes: []string{"image/jpeg", "image/png", "image/gif"}},
+	UploadCategoryInfoDesa:     {thumbnail: true, admin: true, contentTypes: []string{"image/jpeg", "image/png", "application/pdf"}},
+	UploadCategoryBuktiBayar:   {private: true, contentTypes: []string{"image/jpeg", "image/png"}},
+	UploadCategoryBarangSampai: {private: true, contentTypes: []string{"image/jpeg", "image/png"}},
+}
+
+var uploadExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+type UploadServiceInterface interface {
+	UploadAdminFile(requestId, idUser, category string, file io.Reader) (uploadFileResponse response.UploadFileResponse)
+	UploadFile(requestId, idUser, category string, file io.Reader) (uploadFileResponse response.UploadFileResponse)
+	FindPrivateFile(requestId, key string, expires int64, signature string) (body []byte, contentType string)
+}
+
+type UploadServiceImplementation struct {
+	Logger        *logrus.Logger
+	ConfigStorage config.Storage
+	Storage       storage.Storage
+}
+
+func NewUploadService(
+	logger *logrus.Logger,
+	configStorage config.Storage,
+	fileStorage storage.Storage,
+) UploadServiceInterface {
+	return &UploadServiceImplementation{
+		Logger:        logger,
+		ConfigStorage: configStorage,
+		Storage:       fileStorage,
+	}
+}
+
+// UploadAdminFile upload gambar katalog, banner dan lampiran info desa oleh admin
+func (service *UploadServiceImplementation) UploadAdminFile(requestId, idUser, category string, file io.Reader) (uploadFileResponse response.UploadFileResponse) {
+	if !uploadCategories[category].admin {
+		exceptions.PanicIfBadRequest(errors.New("invalid upload category"), requestId, []string{"category harus product, banner atau info_desa"}, service.Logger)
+	}
+	return service.UploadFile(requestId, idUser, category, file)
+}
+
+func (service *UploadServiceImplementation) UploadFile(requestId, idUser, category string, file io.Reader) (uploadFileResponse response.UploadFileResponse) {
+	uploadCategory, ok := uploadCategories[category]
+	if !ok {
+		exceptions.PanicIfBadRequest(errors.New("invalid upload category"), requestId, []string{"invalid upload category"}, service.Logger)
+	}
+
+	maxSize := int64(service.ConfigStorage.MaxSize)
+	if maxSize == 0 {
+		maxSize = uploadDefaultMaxSize
+	}
+	body, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(body) == 0 {
+		exceptions.PanicIfBadRequest(errors.New("empty file"), requestId, []string{"file kosong"}, service.Logger)
+	}
+	if int64(len(body)) > maxSize {
+		exceptions.PanicIfBadRequest(errors.New("file too large"), requestId, []string{"ukuran file maksimal " + strconv.FormatInt(maxSize/1024, 10) + " KB"}, service.Logger)
+	}
+
+	// Tipe file dari isi, bukan dari header atau ekstensi yang dikirim client
+	contentType := http.DetectContentType(body)
+	if !uploadContentTypeAllowed(uploadCategory, contentType) {
+		exceptions.PanicIfBadRequest(errors.New("content type not allowed"), requestId, []string{"tipe file tidak diizinkan"}, service.Logger)
+	}
+	isImage := contentType != "application/pdf"
+	if isImage {
+		if _, _, _, err := utilities.ImageConfig(body); err != nil {
+			exceptions.PanicIfBadRequest(err, requestId, []string{"file gambar tidak valid"}, service.Logger)
+		}
+	}
+
+	prefix := storage.PrefixPublic
+	if uploadCategory.private {
+		prefix = storage.PrefixPrivate
+	}
+	name := prefix + category + "/" + time.Now().Format("2006/01") + "/" + utilities.RandomUUID()
+
+	uploadFileResponse.Key = name + uploadExtensions[contentType]
+	uploadFileResponse.ContentType = contentType
+	uploadFileResponse.Size = len(body)
+
+	err = service.Storage.Put(uploadFileResponse.Key, body, contentType)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	if uploadCategory.thumbnail && isImage {
+		thumbnail, err := utilities.ResizeImageJpeg(body, uploadThumbnailWidth, uploadThumbnailQuality)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		err = service.Storage.Put(name+"_thumb.jpg", thumbnail, "image/jpeg")
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		uploadFileResponse.ThumbnailUrl = service.Storage.Url(name + "_thumb.jpg")
+	}
+
+	if uploadCategory.private {
+		uploadFileResponse.Url = service.Storage.SignedUrl(uploadFileResponse.Key, storage.SignedUrlExpiry(service.ConfigStorage))
+	} else {
+		uploadFileResponse.Url = service.Storage.Url(uploadFileResponse.Key)
+	}
+
+	service.Logger.WithFields(logrus.Fields{"request_id": requestId, "id_user": idUser, "key": uploadFileResponse.Key}).Info("upload file")
+	return uploadFileResponse
+}
+
+// FindPrivateFile sajikan file private driver local setelah signature dicek
+func (service *UploadServiceImplementation) FindPrivateFile(requestId, key string, expires int64, signature string) (body []byte, contentType string) {
+	if !storage.IsPrivate(key) || !storage.VerifySignature(service.ConfigStorage.SigningKey, key, expires, signature) {
+		exceptions.PanicIfUnauthorized(errors.New("invalid signature"), requestId, []string{"link file tidak valid atau sudah kedaluwarsa"}, service.Logger)
+	}
+
+	body, err := service.Storage.Get(key)
+	if err == storage.ErrNotFound {
+		exceptions.PanicIfRecordNotFound(err, requestId, []string{"file not found"}, service.Logger)
+	}
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	return body, http.DetectContentType(body)
+}
+
+func uploadContentTypeAllowed(uploadCategory uploadCategory, contentType string) bool {
+	for _, allowed := range uploadCategory.contentTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
@@ -36,6 +36,7 @@ type UserServiceInterface interface {
 	CreateUserSuveyed(requestId string, createUserSurveyedRequest *request.CreateUserSurveyedRequest)
 	FindUserById(requestId string, idUser string) (userResponse response.FindUserIdResponse)
 	DeleteUserById(requestId string, idUser string)
+	CancelDeleteUser(requestId string, cancelDeleteUserRequest *request.CancelDeleteUserRequest)
 	UpdateUserPassword(reqeustId string, idUser string, updateUserPasswordRequest *request.UpdateUserPasswordRequest)
 	UpdateUserForgotPassword(reqeustId string, updateUserForgotPasswordRequest *request.UpdateUserForgotPasswordRequest)
 	UpdateUserProfile(requestId string, idUser string, updateUserProfileRequest *request.UpdateUserProfileRequest)
@@ -61,6 +62,9 @@ type UserServiceImplementation struct {
 	DesaRepositoryInterface        repository.DesaRepositoryInterface
 	InveliRepositoryInterface      invelirepository.InveliAPIRepositoryInterface
 	AuthServiceInterface           AuthServiceInterface
+	FormTokenRepositoryInterface   repository.FormTokenRepositoryInterface
+	OtpManagerRepositoryInterface  repository.OtpManagerRepositoryInterface
+	ReferralServiceInterface       ReferralServiceInterface
 }
 
 func NewUserService(
@@ -74,6 +78,9 @@ func NewUserService(
 	desaRepositoryInterface repository.DesaRepositoryInterface,
 	inveliRepositoryInterface invelirepository.InveliAPIRepositoryInterface,
 	authServiceInterface AuthServiceInterface,
+	formTokenRepositoryInterface repository.FormTokenRepositoryInterface,
+	otpManagerRepositoryInterface repository.OtpManagerRepositoryInterface,
+	referralServiceInterface ReferralServiceInterface,
 ) UserServiceInterface {
 	return &UserServiceImplementation{
 		DB:                             db,
@@ -86,6 +93,9 @@ func NewUserService(
 		DesaRepositoryInterface:        desaRepositoryInterface,
 		InveliRepositoryInterface:      inveliRepositoryInterface,
 		AuthServiceInterface:           authServiceInterface,
+		FormTokenRepositoryInterface:   formTokenRepositoryInterface,
+		OtpManagerRepositoryInterface:  otpManagerRepositoryInterface,
+		ReferralServiceInterface:       referralServiceInterface,
 	}
 }
 
@@ -374,30 +384,92 @@ func (service *UserServiceImplementation) FindUserFromBigis(requestId string, re
 	return userBigisResponse
 }
 
-func (service *UserServiceImplementation) VerifyFormToken(requestId, token string) {
-	tokenParse, err := jwt.ParseWithClaims(token, &modelService.TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+// VerifyFormToken memvalidasi form token hasil verifikasi OTP, token harus sesuai tujuan dan nomor hp.
+// Token baru ditandai terpakai oleh useFormToken di transaksi operasi yang dilindungi.
+func (service *UserServiceImplementation) VerifyFormToken(requestId, token string, typeOtp int, phone string) *entity.FormToken {
+	claims := &modelService.TokenClaims{}
+	tokenParse, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
 		return []byte(service.ConfigJwt.FormKey), nil
 	})
 
-	if !tokenParse.Valid {
-		exceptions.PanicIfUnauthorized(err, requestId, []string{"invalid token"}, service.Logger)
-	} else if ve, ok := err.(*jwt.ValidationError); ok {
-		if ve.Errors&jwt.ValidationErrorMalformed != 0 {
-			exceptions.PanicIfUnauthorized(err, requestId, []string{"invalid token"}, service.Logger)
-		} else if ve.Errors&(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet) != 0 {
-			exceptions.PanicIfUnauthorized(err, requestId, []string{"invalid token"}, service.Logger)
-		} else {
-			exceptions.PanicIfUnauthorized(err, requestId, []string{"invalid token"}, service.Logger)
-		}
+	if err != nil || !tokenParse.Valid {
+		exceptions.PanicIfUnauthorized(errors.New("invalid form token"), requestId, []string{"invalid token"}, service.Logger)
+	}
+
+	if claims.TypeOtp != typeOtp || claims.Phone != phone || len(claims.StandardClaims.Id) == 0 {
+		exceptions.PanicIfUnauthorized(errors.New("form token purpose not match"), requestId, []string{"invalid token"}, service.Logger)
+	}
+
+	formToken, err := service.FormTokenRepositoryInterface.FindFormTokenById(service.DB, claims.StandardClaims.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(formToken.Id) == 0 || formToken.TypeOtp != typeOtp || formToken.Phone != phone || time.Now().After(formToken.ExpiredAt) {
+		exceptions.PanicIfUnauthorized(errors.New("form token not found"), requestId, []string{"invalid token"}, service.Logger)
+	}
+
+	otp, err := service.OtpManagerRepositoryInterface.FindOtpById(service.DB, formToken.IdOtpManager)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(otp.Id) == 0 || otp.Phone != phone || otp.TypeOtp != typeOtp {
+		exceptions.PanicIfUnauthorized(errors.New("form token otp not match"), requestId, []string{"invalid token"}, service.Logger)
+	}
+
+	if formToken.UsedAt.Valid {
+		exceptions.PanicIfUnauthorized(errors.New("form token already used"), requestId, []string{"invalid token"}, service.Logger)
+	}
+
+	return formToken
+}
+
+// useFormToken menandai form token terpakai di dalam transaksi, jika transaksi rollback token masih bisa dipakai lagi
+func (service *UserServiceImplementation) useFormToken(requestId string, tx *gorm.DB, formToken *entity.FormToken) {
+	rowsAffected, err := service.FormTokenRepositoryInterface.UseFormToken(tx, formToken.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error use form token"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfUnauthorized(errors.New("form token already used"), requestId, []string{"invalid token"}, service.Logger)
 	}
 }
 
+// DeleteUserById memulai masa tenggang penghapusan akun, data pribadi dianonimkan oleh PrivacyService
+// setelah masa tenggang berakhir
 func (service *UserServiceImplementation) DeleteUserById(requestId string, idUser string) {
 	err := service.UserRepositoryInterface.UpdateUser(service.DB, idUser, &entity.User{
 		IsDelete:     1,
 		IsDeleteDate: null.NewTime(time.Now(), true),
 	})
 	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	err = service.UserRepositoryInterface.RevokeUserSession(service.DB, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+// CancelDeleteUser membatalkan penghapusan akun selama masa tenggang, user login ulang dengan password lama
+func (service *UserServiceImplementation) CancelDeleteUser(requestId string, cancelDeleteUserRequest *request.CancelDeleteUserRequest) {
+	request.ValidateRequest(service.Validate, cancelDeleteUserRequest, requestId, service.Logger)
+
+	user, err := service.UserRepositoryInterface.FindUserPendingDeleteByPhone(service.DB, cancelDeleteUserRequest.Phone)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(user.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("user not found"), requestId, []string{"not found"}, service.Logger)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(cancelDeleteUserRequest.Password))
+	exceptions.PanicIfBadRequest(err, requestId, []string{"Invalid Credentials"}, service.Logger)
+
+	// Nomor hp bisa sudah dipakai registrasi baru selama masa tenggang
+	phoneCheck, err := service.UserRepositoryInterface.FindUserByPhone(service.DB, user.Phone)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(phoneCheck.Id) != 0 {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("phone already exist"), requestId, []string{"phone sudah digunakan"}, service.Logger)
+	}
+
+	rowsAffected, err := service.UserRepositoryInterface.RestoreUserPendingDelete(service.DB, user.Id)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if rowsAffected == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("user already anonymized"), requestId, []string{"not found"}, service.Logger)
+	}
 }
 
 func (service *UserServiceImplementation) CreateUserSuveyed(requestId string, createUserRequest *request.CreateUserSurveyedRequest) {
@@ -432,6 +504,10 @@ func (service *UserServiceImplementation) CreateUserSuveyed(requestId string, cr
 		exceptions.PanicIfRecordAlreadyExists(errors.New("phone already exist"), requestId, []string{"phone sudah digunakan"}, service.Logger)
 	}
 
+	// Check kode referral
+	referrer := service.ReferralServiceInterface.FindReferrer(requestId, createUserRequest.ReferralCode, createUserRequest.Phone, createUserRequest.NoIdentitas)
+	referralCode := service.ReferralServiceInterface.GenerateReferralCode(requestId)
+
 	// Hash password
 	password := strings.ReplaceAll(createUserRequest.Password, " ", "")
 	bcryptPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -461,6 +537,7 @@ func (service *UserServiceImplementation) CreateUserSuveyed(requestId string, cr
 		AccountType:     1,                                      // 1 Normal 2 Merchant
 		StatusSurvey:    1,                                      // 0 Blum survey 1 sudah survey
 		IsPaylater:      isPaylater,
+		ReferralCode:    referralCode,
 		CreatedDate:     time.Now(),
 	}
 
@@ -494,6 +571,10 @@ func (service *UserServiceImplementation) CreateUserSuveyed(requestId string, cr
 	err = service.PointRepositoryInterface.CreatePoint(tx, pointEntity)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create point"}, service.Logger, tx)
 
+	if referrer != nil {
+		service.ReferralServiceInterface.CreateReferral(requestId, tx, referrer, userEntity.Id, createUserRequest.NoIdentitas, createUserRequest.DeviceId)
+	}
+
 	commit := tx.Commit()
 	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
 }
@@ -504,7 +585,7 @@ func (service *UserServiceImplementation) CreateUserNonSuveyed(requestId string,
 	request.ValidateRequest(service.Validate, createUserRequest, requestId, service.Logger)
 
 	// validate token
-	service.VerifyFormToken(requestId, createUserRequest.FormToken)
+	formToken := service.VerifyFormToken(requestId, createUserRequest.FormToken, OtpTypeRegistration, createUserRequest.Phone)
 
 	// Check No Identitas
 	NoIdentitasCheck, err := service.UserProfileRepositoryInterface.FindUserByNoIdentitas(service.DB, createUserRequest.NoIdentitas)
@@ -533,6 +614,10 @@ func (service *UserServiceImplementation) CreateUserNonSuveyed(requestId string,
 		exceptions.PanicIfRecordAlreadyExists(errors.New("phone already exist"), requestId, []string{"phone sudah digunakan"}, service.Logger)
 	}
 
+	// Check kode referral
+	referrer := service.ReferralServiceInterface.FindReferrer(requestId, createUserRequest.ReferralCode, createUserRequest.Phone, createUserRequest.NoIdentitas)
+	referralCode := service.ReferralServiceInterface.GenerateReferralCode(requestId)
+
 	// Hash password
 	password := strings.ReplaceAll(createUserRequest.Password, " ", "")
 	bcryptPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -561,6 +646,7 @@ func (service *UserServiceImplementation) CreateUserNonSuveyed(requestId string,
 		AccountType:     1,                                      // 1 Normal 2 Merchant
 		StatusSurvey:    0,
 		IsPaylater:      isPaylater, // 0 Blum survey 1 sudah survey
+		ReferralCode:    referralCode,
 		CreatedDate:     time.Now(),
 	}
 
@@ -593,6 +679,12 @@ func (service *UserServiceImplementation) CreateUserNonSuveyed(requestId string,
 	err = service.PointRepositoryInterface.CreatePoint(tx, pointEntity)
 	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create point"}, service.Logger, tx)
 
+	if referrer != nil {
+		service.ReferralServiceInterface.CreateReferral(requestId, tx, referrer, userEntity.Id, createUserRequest.NoIdentitas, createUserRequest.DeviceId)
+	}
+
+	service.useFormToken(requestId, tx, formToken)
+
 	commit := tx.Commit()
 	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
 }
@@ -687,12 +779,11 @@ func (service *UserServiceImplementation) UpdateUserPassword(requestId string, i
 func (service *UserServiceImplementation) UpdateUserForgotPassword(requestId string, updateUserForgotPasswordRequest *request.UpdateUserForgotPasswordRequest) {
 	var err error
 
-	tx := service.DB.Begin()
 	// validate request
 	request.ValidateRequest(service.Validate, updateUserForgotPasswordRequest, requestId, service.Logger)
 
 	// validate form token
-	service.VerifyFormToken(requestId, updateUserForgotPasswordRequest.FormToken)
+	formToken := service.VerifyFormToken(requestId, updateUserForgotPasswordRequest.FormToken, OtpTypeForgotPassword, updateUserForgotPasswordRequest.Phone)
 
 	user, err := service.UserRepositoryInterface.FindUserByPhone(service.DB, updateUserForgotPasswordRequest.Phone)
 	exceptions.PanicIfError(err, requestId, service.Logger)
@@ -708,10 +799,13 @@ func (service *UserServiceImplementation) UpdateUserForgotPassword(requestId str
 		Password: string(bcryptPassword),
 	}
 
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
 	err = service.UserRepositoryInterface.UpdateUser(tx, user.Id, userEntity)
-	if err != nil {
-		exceptions.PanicIfError(err, requestId, service.Logger)
-	}
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update user"}, service.Logger, tx)
+
+	service.useFormToken(requestId, tx, formToken)
 
 	commit := tx.Commit()
 	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
@@ -738,13 +832,28 @@ func (service *UserServiceImplementation) UpdateUserPhone(requestId string, idUs
 	// validate reqeust
 	request.ValidateRequest(service.Validate, updateUserPhoneRequest, requestId, service.Logger)
 
-	// validate form token
-	service.VerifyFormToken(requestId, updateUserPhoneRequest.FormToken)
+	// validate form token, OTP harus dikirim ke nomor hp yang baru
+	formToken := service.VerifyFormToken(requestId, updateUserPhoneRequest.FormToken, OtpTypeChangePhone, updateUserPhoneRequest.Phone)
+
+	// Check No Hp
+	phoneCheck, err := service.UserRepositoryInterface.FindUserByPhone(service.DB, updateUserPhoneRequest.Phone)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(phoneCheck.Id) != 0 {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("phone already exist"), requestId, []string{"phone sudah digunakan"}, service.Logger)
+	}
 
 	userEntity := &entity.User{
 		Phone: updateUserPhoneRequest.Phone,
 	}
 
-	err = service.UserRepositoryInterface.UpdateUser(service.DB, idUser, userEntity)
-	exceptions.PanicIfError(err, requestId, service.Logger)
+	tx := service.DB.Begin()
+	exceptions.PanicIfError(tx.Error, requestId, service.Logger)
+
+	err = service.UserRepositoryInterface.UpdateUser(tx, idUser, userEntity)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update user"}, service.Logger, tx)
+
+	service.useFormToken(requestId, tx, formToken)
+
+	commit := tx.Commit()
+	exceptions.PanicIfError(commit.Error, requestId, service.Logger)
 }
@@ -2,7 +2,6 @@ package service
 
 import (
 	"errors"
-	"math"
 
 	"github.com/go-playground/validator"
 	"github.com/sirupsen/logrus"
@@ -18,7 +17,9 @@ import (
 type UserShippingAddressServiceInterface interface {
 	FindUserShippingAddressByIdUser(requestId string, idUser string) (userAShippingddressResponses []response.FindUserShippingAddress)
 	CreateUserShippingAddress(requestId string, idUser string, userShippingAddressRequest *request.CreateUserShippingAddressRequest)
-	DeleteUserShippingAddress(requestId string, userShippingAddressRequest *request.DeleteUserShippingAddressRequest)
+	UpdateUserShippingAddress(requestId string, idUser string, updateUserShippingAddressRequest *request.UpdateUserShippingAddressRequest)
+	SetPrimaryUserShippingAddress(requestId string, idUser string, setPrimaryUserShippingAddressRequest *request.SetPrimaryUserShippingAddressRequest)
+	DeleteUserShippingAddress(requestId string, idUser string, userShippingAddressRequest *request.DeleteUserShippingAddressRequest)
 }
 
 type UserShippingAddressServiceImplementation struct {
@@ -42,36 +43,41 @@ func NewUserShippingAddressService(
 	}
 }
 
-func (service *UserShippingAddressServiceImplementation) DeleteUserShippingAddress(requestId string, userShippingAddressRequest *request.DeleteUserShippingAddressRequest) {
+func (service *UserShippingAddressServiceImplementation) DeleteUserShippingAddress(requestId string, idUser string, userShippingAddressRequest *request.DeleteUserShippingAddressRequest) {
 	var err error
 	// validate
 	request.ValidateRequest(service.Validate, userShippingAddressRequest, requestId, service.Logger)
 
-	shippingAddress, err := service.UserShippingAddressRepositoryInterface.FindUserShippingAddressById(service.DB, userShippingAddressRequest.IdUserShippingAddress)
+	// alamat user lain diperlakukan sama dengan alamat yang tidak ada
+	shippingAddress := service.findOwnedUserShippingAddress(requestId, idUser, userShippingAddressRequest.IdUserShippingAddress)
+
+	tx := service.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = service.UserShippingAddressRepositoryInterface.DeleteUserShippingAddress(tx, shippingAddress.Id)
 	exceptions.PanicIfError(err, requestId, service.Logger)
-	if len(shippingAddress.Id) == 0 {
-		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestId, []string{"addres not found"}, service.Logger)
+
+	// Alamat utama yang dihapus digantikan alamat lain milik user
+	if shippingAddress.StatusPrimary == 1 {
+		userShippingAddresss, err := service.UserShippingAddressRepositoryInterface.FindUserShippingAddressByIdUser(tx, idUser)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if len(userShippingAddresss) != 0 {
+			err = service.UserShippingAddressRepositoryInterface.UpdateUserShippingAddressPrimary(tx, idUser, userShippingAddresss[0].Id)
+			exceptions.PanicIfError(err, requestId, service.Logger)
+		}
 	}
 
-	err = service.UserShippingAddressRepositoryInterface.DeleteUserShippingAddress(service.DB, userShippingAddressRequest.IdUserShippingAddress)
+	err = tx.Commit().Error
 	exceptions.PanicIfError(err, requestId, service.Logger)
 }
 
 func (service *UserShippingAddressServiceImplementation) CreateUserShippingAddress(requestId string, idUser string, createUserShippingAddressRequest *request.CreateUserShippingAddressRequest) {
 	// validate request
-	var radius float64
-
-	radius = createUserShippingAddressRequest.Radius
-
-	if radius == 0 {
-		radius = radius + 0.01
-	}
-
-	_, frac := math.Modf(createUserShippingAddressRequest.Radius)
-	if frac == 0 {
-		radius = radius + 0.01
-	}
-
 	request.ValidateRequest(service.Validate, createUserShippingAddressRequest, requestId, service.Logger)
 
 	userShippingAddressEntity := &entity.UserShippingAddress{}
@@ -80,10 +86,58 @@ func (service *UserShippingAddressServiceImplementation) CreateUserShippingAddre
 	userShippingAddressEntity.AlamatPengiriman = createUserShippingAddressRequest.AlamatPengiriman
 	userShippingAddressEntity.Latitude = createUserShippingAddressRequest.Latitude
 	userShippingAddressEntity.Longitude = createUserShippingAddressRequest.Longitude
-	userShippingAddressEntity.Radius = radius
-	userShippingAddressEntity.StatusPrimary = createUserShippingAddressRequest.StatusPrimary
+	userShippingAddressEntity.Radius = createUserShippingAddressRequest.Radius
 	userShippingAddressEntity.Catatan = createUserShippingAddressRequest.Catatan
-	_, err := service.UserShippingAddressRepositoryInterface.CreateUserShippingAddress(service.DB, userShippingAddressEntity)
+
+	tx := service.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// Alamat pertama otomatis menjadi alamat utama
+	primaryAddress, err := service.UserShippingAddressRepositoryInterface.FindPrimaryUserShippingAddressByIdUser(tx, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	isPrimary := createUserShippingAddressRequest.StatusPrimary == 1 || len(primaryAddress.Id) == 0
+
+	_, err = service.UserShippingAddressRepositoryInterface.CreateUserShippingAddress(tx, userShippingAddressEntity)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+
+	if isPrimary {
+		err = service.UserShippingAddressRepositoryInterface.UpdateUserShippingAddressPrimary(tx, idUser, userShippingAddressEntity.Id)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+	}
+
+	err = tx.Commit().Error
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *UserShippingAddressServiceImplementation) UpdateUserShippingAddress(requestId string, idUser string, updateUserShippingAddressRequest *request.UpdateUserShippingAddressRequest) {
+	request.ValidateRequest(service.Validate, updateUserShippingAddressRequest, requestId, service.Logger)
+
+	shippingAddress := service.findOwnedUserShippingAddress(requestId, idUser, updateUserShippingAddressRequest.IdUserShippingAddress)
+
+	userShippingAddressEntity := &entity.UserShippingAddress{}
+	userShippingAddressEntity.AlamatPengiriman = updateUserShippingAddressRequest.AlamatPengiriman
+	userShippingAddressEntity.Latitude = updateUserShippingAddressRequest.Latitude
+	userShippingAddressEntity.Longitude = updateUserShippingAddressRequest.Longitude
+	userShippingAddressEntity.Radius = updateUserShippingAddressRequest.Radius
+	userShippingAddressEntity.Catatan = updateUserShippingAddressRequest.Catatan
+	err := service.UserShippingAddressRepositoryInterface.UpdateUserShippingAddress(service.DB, shippingAddress.Id, userShippingAddressEntity)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+// SetPrimaryUserShippingAddress satu user hanya punya satu alamat utama
+func (service *UserShippingAddressServiceImplementation) SetPrimaryUserShippingAddress(requestId string, idUser string, setPrimaryUserShippingAddressRequest *request.SetPrimaryUserShippingAddressRequest) {
+	request.ValidateRequest(service.Validate, setPrimaryUserShippingAddressRequest, requestId, service.Logger)
+
+	shippingAddress := service.findOwnedUserShippingAddress(requestId, idUser, setPrimaryUserShippingAddressRequest.IdUserShippingAddress)
+
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		return service.UserShippingAddressRepositoryInterface.UpdateUserShippingAddressPrimary(tx, idUser, shippingAddress.Id)
+	})
 	exceptions.PanicIfError(err, requestId, service.Logger)
 }
 
@@ -96,3 +150,12 @@ func (service *UserShippingAddressServiceImplementation) FindUserShippingAddress
 	userShippingAddressResponses = response.ToFindUserShippingAddressResponse(userShippingAddresss)
 	return userShippingAddressResponses
 }
+
+func (service *UserShippingAddressServiceImplementation) findOwnedUserShippingAddress(requestId, idUser, idUserShippingAddress string) *entity.UserShippingAddress {
+	shippingAddress, err := service.UserShippingAddressRepositoryInterface.FindUserShippingAddressByIdAndIdUser(service.DB, idUserShippingAddress, idUser)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(shippingAddress.Id) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("not found"), requestId, []string{"addres not found"}, service.Logger)
+	}
+	return shippingAddress
+}
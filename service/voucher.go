@@ -0,0 +1,244 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/exceptions"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/entity"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/request"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/model/response"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/repository"
+	"github.com/tensuqiuwulu/be-service-bupda-bali/utilities"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/gorm"
+)
+
+const voucherDateLayout = "2006-01-02 15:04:05"
+
+type VoucherServiceInterface interface {
+	CreateVoucher(requestId string, idDesa string, createVoucherRequest *request.CreateVoucherRequest)
+	UpdateVoucher(requestId string, idDesa string, updateVoucherRequest *request.UpdateVoucherRequest)
+	FindVouchersByDesa(requestId string, idDesa string) (voucherResponses []response.FindVoucherResponse)
+	CheckVoucher(requestId string, idUser string, idDesa string, checkVoucherRequest *request.CheckVoucherRequest) (checkVoucherResponse response.CheckVoucherResponse)
+	CalculateVoucher(requestId string, idUser string, idDesa string, voucherCode string, subTotal float64, shippingCost float64) (voucher *entity.Voucher, discount float64, shippingDiscount float64)
+	UseVoucher(requestId string, tx *gorm.DB, voucher *entity.Voucher, idUser string, idOrder string, discount float64, shippingDiscount float64)
+	ReleaseVoucher(requestId string, tx *gorm.DB, idOrder string)
+}
+
+type VoucherServiceImplementation struct {
+	DB                         *gorm.DB
+	Validate                   *validator.Validate
+	Logger                     *logrus.Logger
+	VoucherRepositoryInterface repository.VoucherRepositoryInterface
+}
+
+func NewVoucherService(
+	db *gorm.DB,
+	validate *validator.Validate,
+	logger *logrus.Logger,
+	voucherRepositoryInterface repository.VoucherRepositoryInterface,
+) VoucherServiceInterface {
+	return &VoucherServiceImplementation{
+		DB:                         db,
+		Validate:                   validate,
+		Logger:                     logger,
+		VoucherRepositoryInterface: voucherRepositoryInterface,
+	}
+}
+
+func (service *VoucherServiceImplementation) CreateVoucher(requestId string, idDesa string, createVoucherRequest *request.CreateVoucherRequest) {
+	request.ValidateRequest(service.Validate, createVoucherRequest, requestId, service.Logger)
+
+	voucherEntity := service.toVoucherEntity(requestId, createVoucherRequest)
+
+	voucher, err := service.VoucherRepositoryInterface.FindVoucherByCode(service.DB, voucherEntity.Code)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(voucher.Id) != 0 {
+		exceptions.PanicIfRecordAlreadyExists(errors.New("voucher code already exist"), requestId, []string{"voucher code already exist"}, service.Logger)
+	}
+
+	voucherEntity.Id = utilities.RandomUUID()
+	voucherEntity.IdDesa = idDesa
+	voucherEntity.CreatedAt = time.Now()
+	err = service.VoucherRepositoryInterface.CreateVoucher(service.DB, voucherEntity)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+// UpdateVoucher kode voucher tidak bisa diubah karena sudah tersimpan di order
+func (service *VoucherServiceImplementation) UpdateVoucher(requestId string, idDesa string, updateVoucherRequest *request.UpdateVoucherRequest) {
+	request.ValidateRequest(service.Validate, updateVoucherRequest, requestId, service.Logger)
+
+	voucher, err := service.VoucherRepositoryInterface.FindVoucherById(service.DB, updateVoucherRequest.IdVoucher)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(voucher.Id) == 0 || voucher.IdDesa != idDesa {
+		exceptions.PanicIfRecordNotFound(errors.New("voucher not found"), requestId, []string{"voucher not found"}, service.Logger)
+	}
+
+	voucherEntity := service.toVoucherEntity(requestId, &updateVoucherRequest.CreateVoucherRequest)
+	voucherEntity.UpdatedAt = null.NewTime(time.Now(), true)
+	err = service.VoucherRepositoryInterface.UpdateVoucher(service.DB, voucher.Id, voucherEntity)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+}
+
+func (service *VoucherServiceImplementation) FindVouchersByDesa(requestId string, idDesa string) (voucherResponses []response.FindVoucherResponse) {
+	vouchers, err := service.VoucherRepositoryInterface.FindVouchersByDesa(service.DB, idDesa)
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(vouchers) == 0 {
+		exceptions.PanicIfRecordNotFound(errors.New("voucher not found"), requestId, []string{"data not found"}, service.Logger)
+	}
+	voucherResponses = response.ToFindVoucherResponses(vouchers)
+	return voucherResponses
+}
+
+func (service *VoucherServiceImplementation) CheckVoucher(requestId string, idUser string, idDesa string, checkVoucherRequest *request.CheckVoucherRequest) (checkVoucherResponse response.CheckVoucherResponse) {
+	request.ValidateRequest(service.Validate, checkVoucherRequest, requestId, service.Logger)
+
+	voucher, discount, shippingDiscount := service.CalculateVoucher(requestId, idUser, idDesa, checkVoucherRequest.VoucherCode, checkVoucherRequest.SubTotal, checkVoucherRequest.ShippingCost)
+	checkVoucherResponse = response.ToCheckVoucherResponse(voucher, checkVoucherRequest.SubTotal, checkVoucherRequest.ShippingCost, discount, shippingDiscount)
+	return checkVoucherResponse
+}
+
+// CalculateVoucher validasi voucher untuk user dan desa lalu menghitung potongan sub total dan ongkir
+func (service *VoucherServiceImplementation) CalculateVoucher(requestId string, idUser string, idDesa string, voucherCode string, subTotal float64, shippingCost float64) (voucher *entity.Voucher, discount float64, shippingDiscount float64) {
+	voucher, err := service.VoucherRepositoryInterface.FindVoucherByCode(service.DB, normalizeVoucherCode(voucherCode))
+	exceptions.PanicIfError(err, requestId, service.Logger)
+	if len(voucher.Id) == 0 || voucher.IsActive != 1 || (len(voucher.IdDesa) != 0 && voucher.IdDesa != idDesa) {
+		exceptions.PanicIfRecordNotFound(errors.New("voucher not found"), requestId, []string{"voucher tidak ditemukan"}, service.Logger)
+	}
+
+	now := time.Now()
+	if now.Before(voucher.StartDate) || now.After(voucher.EndDate) {
+		exceptions.PanicIfBadRequest(errors.New("voucher not in period"), requestId, []string{"voucher tidak dalam periode berlaku"}, service.Logger)
+	}
+
+	if voucher.QuotaTotal > 0 && voucher.UsedCount >= voucher.QuotaTotal {
+		exceptions.PanicIfBadRequest(errors.New("voucher quota exceeded"), requestId, []string{"kuota voucher sudah habis"}, service.Logger)
+	}
+
+	if voucher.QuotaPerUser > 0 {
+		usedByUser, err := service.VoucherRepositoryInterface.CountVoucherUsageByUser(service.DB, voucher.Id, idUser)
+		exceptions.PanicIfError(err, requestId, service.Logger)
+		if usedByUser >= int64(voucher.QuotaPerUser) {
+			exceptions.PanicIfBadRequest(errors.New("voucher user quota exceeded"), requestId, []string{"voucher sudah mencapai batas pemakaian"}, service.Logger)
+		}
+	}
+
+	if subTotal < voucher.MinSpend {
+		exceptions.PanicIfBadRequest(errors.New("voucher min spend"), requestId, []string{"belanja belum mencapai minimal pemakaian voucher"}, service.Logger)
+	}
+
+	switch voucher.DiscountType {
+	case 1:
+		discount = math.Floor(subTotal * voucher.DiscountValue / 100)
+		if voucher.MaxDiscount > 0 && discount > voucher.MaxDiscount {
+			discount = voucher.MaxDiscount
+		}
+	case 2:
+		discount = math.Min(voucher.DiscountValue, subTotal)
+	case 3:
+		shippingDiscount = shippingCost
+		if voucher.MaxDiscount > 0 && shippingDiscount > voucher.MaxDiscount {
+			shippingDiscount = voucher.MaxDiscount
+		}
+	}
+
+	return voucher, discount, shippingDiscount
+}
+
+// UseVoucher dipanggil sebelum pembayaran order, quota dicek ulang dengan baris voucher terkunci
+// sehingga pemakaian bersamaan oleh user yang sama tidak bisa melewati quota per user
+func (service *VoucherServiceImplementation) UseVoucher(requestId string, tx *gorm.DB, voucher *entity.Voucher, idUser string, idOrder string, discount float64, shippingDiscount float64) {
+	_, err := service.VoucherRepositoryInterface.FindVoucherByIdForUpdate(tx, voucher.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find voucher"}, service.Logger, tx)
+
+	if voucher.QuotaPerUser > 0 {
+		usedByUser, err := service.VoucherRepositoryInterface.CountVoucherUsageByUser(tx, voucher.Id, idUser)
+		exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error count voucher usage"}, service.Logger, tx)
+		if usedByUser >= int64(voucher.QuotaPerUser) {
+			tx.Rollback()
+			exceptions.PanicIfBadRequest(errors.New("voucher user quota exceeded"), requestId, []string{"voucher sudah mencapai batas pemakaian"}, service.Logger)
+		}
+	}
+
+	rowsAffected, err := service.VoucherRepositoryInterface.IncreaseVoucherUsedCount(tx, voucher.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update voucher"}, service.Logger, tx)
+	if rowsAffected == 0 {
+		tx.Rollback()
+		exceptions.PanicIfBadRequest(errors.New("voucher quota exceeded"), requestId, []string{"kuota voucher sudah habis"}, service.Logger)
+	}
+
+	err = service.VoucherRepositoryInterface.CreateVoucherUsage(tx, &entity.VoucherUsage{
+		Id:               utilities.RandomUUID(),
+		IdVoucher:        voucher.Id,
+		IdUser:           idUser,
+		IdOrder:          idOrder,
+		Discount:         discount,
+		ShippingDiscount: shippingDiscount,
+		CreatedAt:        time.Now(),
+	})
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error create voucher usage"}, service.Logger, tx)
+}
+
+// ReleaseVoucher mengembalikan quota voucher di transaksi pembatalan order
+func (service *VoucherServiceImplementation) ReleaseVoucher(requestId string, tx *gorm.DB, idOrder string) {
+	voucherUsage, err := service.VoucherRepositoryInterface.FindVoucherUsageByIdOrder(tx, idOrder)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error find voucher usage"}, service.Logger, tx)
+	if len(voucherUsage.Id) == 0 {
+		return
+	}
+
+	err = service.VoucherRepositoryInterface.DeleteVoucherUsage(tx, voucherUsage.Id)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error delete voucher usage"}, service.Logger, tx)
+
+	err = service.VoucherRepositoryInterface.DecreaseVoucherUsedCount(tx, voucherUsage.IdVoucher)
+	exceptions.PanicIfErrorWithRollback(err, requestId, []string{"error update voucher"}, service.Logger, tx)
+}
+
+func (service *VoucherServiceImplementation) toVoucherEntity(requestId string, createVoucherRequest *request.CreateVoucherRequest) *entity.Voucher {
+	startDate, err := time.ParseInLocation(voucherDateLayout, createVoucherRequest.StartDate, time.Local)
+	if err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"format start date harus " + voucherDateLayout}, service.Logger)
+	}
+	endDate, err := time.ParseInLocation(voucherDateLayout, createVoucherRequest.EndDate, time.Local)
+	if err != nil {
+		exceptions.PanicIfBadRequest(err, requestId, []string{"format end date harus " + voucherDateLayout}, service.Logger)
+	}
+	if !endDate.After(startDate) {
+		exceptions.PanicIfBadRequest(errors.New("invalid voucher period"), requestId, []string{"end date harus setelah start date"}, service.Logger)
+	}
+
+	switch createVoucherRequest.DiscountType {
+	case 1:
+		if createVoucherRequest.DiscountValue <= 0 || createVoucherRequest.DiscountValue > 100 {
+			exceptions.PanicIfBadRequest(errors.New("invalid discount value"), requestId, []string{"persentase diskon harus 1 - 100"}, service.Logger)
+		}
+	case 2:
+		if createVoucherRequest.DiscountValue <= 0 {
+			exceptions.PanicIfBadRequest(errors.New("invalid discount value"), requestId, []string{"nominal diskon harus lebih dari 0"}, service.Logger)
+		}
+	}
+
+	return &entity.Voucher{
+		Code:          normalizeVoucherCode(createVoucherRequest.Code),
+		Title:         createVoucherRequest.Title,
+		Description:   createVoucherRequest.Description,
+		DiscountType:  createVoucherRequest.DiscountType,
+		DiscountValue: createVoucherRequest.DiscountValue,
+		MaxDiscount:   createVoucherRequest.MaxDiscount,
+		MinSpend:      createVoucherRequest.MinSpend,
+		QuotaTotal:    createVoucherRequest.QuotaTotal,
+		QuotaPerUser:  createVoucherRequest.QuotaPerUser,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		IsActive:      createVoucherRequest.IsActive,
+	}
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
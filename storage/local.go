@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+)
+
+// LocalStorage simpan file di disk server. File publik disajikan static di /files/public,
+// file private lewat endpoint /api/v1/file/private dengan signature.
+type LocalStorage struct {
+	dir        string
+	baseUrl    string
+	signingKey string
+}
+
+func NewLocalStorage(configStorage config.Storage) Storage {
+	dir := configStorage.LocalDir
+	if len(dir) == 0 {
+		dir = "./uploads"
+	}
+	return &LocalStorage{
+		dir:        dir,
+		baseUrl:    strings.TrimSuffix(configStorage.BaseUrl, "/"),
+		signingKey: configStorage.SigningKey,
+	}
+}
+
+func (storage *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("storage: invalid key")
+	}
+	return filepath.Join(storage.dir, clean), nil
+}
+
+func (storage *LocalStorage) Put(key string, body []byte, contentType string) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0644)
+}
+
+func (storage *LocalStorage) Get(key string) ([]byte, error) {
+	path, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return body, err
+}
+
+func (storage *LocalStorage) Delete(key string) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (storage *LocalStorage) Url(key string) string {
+	return storage.baseUrl + "/files/" + key
+}
+
+func (storage *LocalStorage) SignedUrl(key string, expiry time.Duration) string {
+	expires := time.Now().Add(expiry).Unix()
+	query := url.Values{}
+	query.Set("key", key)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", Signature(storage.signingKey, key, expires))
+	return storage.baseUrl + "/api/v1/file/private?" + query.Encode()
+}
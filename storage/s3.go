@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+)
+
+const (
+	s3Service       = "s3"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3Timeout       = 30 * time.Second
+	s3UnsignedBody  = "UNSIGNED-PAYLOAD"
+	s3MaxSignExpiry = 7 * 24 * time.Hour
+)
+
+// S3Storage client minimal S3-compatible (AWS S3, MinIO, DigitalOcean Spaces) dengan signature v4,
+// bucket diakses dengan path style
+type S3Storage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicUrl string
+	client    *http.Client
+}
+
+func NewS3Storage(configStorage config.Storage) Storage {
+	endpoint, err := url.Parse(strings.TrimSuffix(configStorage.S3Endpoint, "/"))
+	if err != nil || len(endpoint.Host) == 0 {
+		panic("storage: invalid s3 endpoint " + configStorage.S3Endpoint)
+	}
+	region := configStorage.S3Region
+	if len(region) == 0 {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    configStorage.S3Bucket,
+		accessKey: configStorage.S3AccessKey,
+		secretKey: configStorage.S3SecretKey,
+		publicUrl: strings.TrimSuffix(configStorage.S3PublicUrl, "/"),
+		client:    &http.Client{Timeout: s3Timeout},
+	}
+}
+
+func (storage *S3Storage) objectPath(key string) string {
+	return storage.endpoint.Path + "/" + storage.bucket + "/" + s3EscapePath(key)
+}
+
+func (storage *S3Storage) Put(key string, body []byte, contentType string) error {
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if !IsPrivate(key) {
+		header.Set("X-Amz-Acl", "public-read")
+	}
+	_, err := storage.do(http.MethodPut, key, header, body)
+	return err
+}
+
+func (storage *S3Storage) Get(key string) ([]byte, error) {
+	return storage.do(http.MethodGet, key, http.Header{}, nil)
+}
+
+func (storage *S3Storage) Delete(key string) error {
+	_, err := storage.do(http.MethodDelete, key, http.Header{}, nil)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (storage *S3Storage) Url(key string) string {
+	if len(storage.publicUrl) != 0 {
+		return storage.publicUrl + "/" + s3EscapePath(key)
+	}
+	return storage.endpoint.Scheme + "://" + storage.endpoint.Host + storage.objectPath(key)
+}
+
+// SignedUrl presigned GET dengan query string signature v4
+func (storage *S3Storage) SignedUrl(key string, expiry time.Duration) string {
+	if expiry > s3MaxSignExpiry {
+		expiry = s3MaxSignExpiry
+	}
+	now := time.Now().UTC()
+	date := now.Format("20060102")
+	scope := date + "/" + storage.region + "/" + s3Service + "/aws4_request"
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", storage.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		storage.objectPath(key),
+		canonicalQuery,
+		"host:" + storage.endpoint.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+	signature := storage.sign(now, scope, canonicalRequest)
+
+	return storage.endpoint.Scheme + "://" + storage.endpoint.Host + storage.objectPath(key) + "?" + canonicalQuery + "&X-Amz-Signature=" + signature
+}
+
+func (storage *S3Storage) do(method string, key string, header http.Header, body []byte) ([]byte, error) {
+	now := time.Now().UTC()
+	date := now.Format("20060102")
+	scope := date + "/" + storage.region + "/" + s3Service + "/aws4_request"
+	payloadHash := sha256.Sum256(body)
+
+	header.Set("Host", storage.endpoint.Host)
+	header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	names := []string{}
+	for name := range header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + strings.TrimSpace(header.Get(name)) + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		storage.objectPath(key),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	signature := storage.sign(now, scope, canonicalRequest)
+
+	request, err := http.NewRequest(method, storage.endpoint.Scheme+"://"+storage.endpoint.Host+storage.objectPath(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name := range header {
+		if name != "Host" {
+			request.Header.Set(name, header.Get(name))
+		}
+	}
+	request.Header.Set("Authorization", s3Algorithm+" Credential="+storage.accessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+
+	response, err := storage.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if response.StatusCode >= 300 {
+		return nil, errors.New("storage: s3 " + method + " " + key + " status " + strconv.Itoa(response.StatusCode) + ": " + string(responseBody))
+	}
+	return responseBody, nil
+}
+
+func (storage *S3Storage) sign(now time.Time, scope string, canonicalRequest string) string {
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3Algorithm + "\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	signingKey := s3Hmac([]byte("AWS4"+storage.secretKey), now.Format("20060102"))
+	signingKey = s3Hmac(signingKey, storage.region)
+	signingKey = s3Hmac(signingKey, s3Service)
+	signingKey = s3Hmac(signingKey, "aws4_request")
+	return hex.EncodeToString(s3Hmac(signingKey, stringToSign))
+}
+
+func s3Hmac(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath encode per segmen sesuai aturan uri encode signature v4
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func s3Escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		pairs = append(pairs, s3Escape(key)+"="+s3Escape(query.Get(key)))
+	}
+	return strings.Join(pairs, "&")
+}
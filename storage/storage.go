@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tensuqiuwulu/be-service-bupda-bali/config"
+)
+
+// Prefix key, file di bawah PrefixPrivate hanya bisa dibuka lewat signed url
+const (
+	PrefixPublic  = "public/"
+	PrefixPrivate = "private/"
+)
+
+var ErrNotFound = errors.New("storage: file not found")
+
+// Storage penyimpanan file upload. Key selalu diawali PrefixPublic atau PrefixPrivate.
+type Storage interface {
+	Put(key string, body []byte, contentType string) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	// Url alamat permanen file publik
+	Url(key string) string
+	// SignedUrl alamat sementara untuk file private
+	SignedUrl(key string, expiry time.Duration) string
+}
+
+func NewStorage(configStorage config.Storage) Storage {
+	if configStorage.Driver == "s3" {
+		return NewS3Storage(configStorage)
+	}
+	return NewLocalStorage(configStorage)
+}
+
+// IsPrivate key file private disimpan di database, bukan url
+func IsPrivate(key string) bool {
+	return strings.HasPrefix(key, PrefixPrivate)
+}
+
+// ResolveUrl ubah nilai kolom foto menjadi url yang bisa dibuka client. Nilai lama berupa url dikembalikan apa adanya.
+func ResolveUrl(s Storage, value string, expiry time.Duration) string {
+	if IsPrivate(value) {
+		return s.SignedUrl(value, expiry)
+	}
+	return value
+}
+
+// SignedUrlExpiry masa berlaku signed url dari konfigurasi (detik), default 15 menit
+func SignedUrlExpiry(configStorage config.Storage) time.Duration {
+	if configStorage.SignedUrlExpiry == 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(configStorage.SignedUrlExpiry) * time.Second
+}
+
+// Signature hmac key dan waktu kedaluwarsa untuk signed url driver local
+func Signature(signingKey string, key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(key + "|" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature cek signature dan masa berlaku signed url driver local
+func VerifySignature(signingKey string, key string, expires int64, signature string) bool {
+	if len(signingKey) == 0 || time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(Signature(signingKey, key, expires)), []byte(signature))
+}
@@ -0,0 +1,16 @@
+package utilities
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// HaversineKm jarak garis lurus dua koordinat dalam km
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadian := func(degree float64) float64 { return degree * math.Pi / 180 }
+
+	dLat := toRadian(lat2 - lat1)
+	dLon := toRadian(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadian(lat1))*math.Cos(toRadian(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
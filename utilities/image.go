@@ -0,0 +1,85 @@
+package utilities
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// decoder format upload yang diterima
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Batas dimensi gambar upload, mencegah decompression bomb
+const MaxImagePixels = 40000000
+
+// ImageConfig baca format dan dimensi tanpa decode seluruh gambar
+func ImageConfig(body []byte) (format string, width int, height int, err error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxImagePixels {
+		return "", 0, 0, errors.New("image dimension not allowed")
+	}
+	return format, config.Width, config.Height, nil
+}
+
+// ResizeImageJpeg perkecil gambar ke lebar maksimal (rasio dijaga) dengan rata-rata area, hasil jpeg.
+// Gambar yang lebih kecil dari maxWidth tidak diperbesar.
+func ResizeImageJpeg(body []byte, maxWidth int, quality int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			// Latar putih untuk area transparan karena jpeg tidak punya alpha
+			alpha := a / n
+			dst.Set(x, y, color.RGBA{
+				R: uint8((r/n + (0xffff - alpha)) >> 8),
+				G: uint8((g/n + (0xffff - alpha)) >> 8),
+				B: uint8((b/n + (0xffff - alpha)) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := jpeg.Encode(buffer, dst, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
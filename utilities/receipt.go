@@ -0,0 +1,124 @@
+package utilities
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ReceiptLine satu baris struk, teks sudah dipad sesuai lebar struk
+type ReceiptLine struct {
+	Text string
+	Bold bool
+}
+
+// FormatRupiah format nominal tanpa desimal, contoh Rp10.000
+func FormatRupiah(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%.0f", math.Round(amount))
+	var formatted strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			formatted.WriteByte('.')
+		}
+		formatted.WriteRune(digit)
+	}
+	return sign + "Rp" + formatted.String()
+}
+
+func ReceiptToText(lines []ReceiptLine) []byte {
+	var buffer bytes.Buffer
+	for _, line := range lines {
+		buffer.WriteString(line.Text)
+		buffer.WriteByte('\n')
+	}
+	return buffer.Bytes()
+}
+
+// ReceiptToEscPos perintah ESC/POS untuk printer thermal: init, teks, feed lalu potong kertas
+func ReceiptToEscPos(lines []ReceiptLine) []byte {
+	var buffer bytes.Buffer
+	buffer.Write([]byte{0x1b, 0x40})
+	for _, line := range lines {
+		if line.Bold {
+			buffer.Write([]byte{0x1b, 0x45, 0x01})
+		}
+		buffer.WriteString(receiptAscii(line.Text))
+		if line.Bold {
+			buffer.Write([]byte{0x1b, 0x45, 0x00})
+		}
+		buffer.WriteByte('\n')
+	}
+	buffer.Write([]byte{0x1b, 0x64, 0x04})
+	buffer.Write([]byte{0x1d, 0x56, 0x01})
+	return buffer.Bytes()
+}
+
+// ReceiptToPdf satu halaman pdf dengan font Courier, lebar halaman mengikuti jumlah karakter per baris
+func ReceiptToPdf(lines []ReceiptLine, width int) []byte {
+	const fontSize = 9.0
+	const lineHeight = 12.0
+	const margin = 18.0
+
+	pageWidth := float64(width)*fontSize*0.6 + 2*margin
+	pageHeight := float64(len(lines))*lineHeight + 2*margin
+
+	var content bytes.Buffer
+	content.WriteString("BT\n")
+	fmt.Fprintf(&content, "%.2f TL\n", lineHeight)
+	fmt.Fprintf(&content, "%.2f %.2f Td\n", margin, pageHeight-margin-fontSize)
+	for _, line := range lines {
+		font := "/F1"
+		if line.Bold {
+			font = "/F2"
+		}
+		fmt.Fprintf(&content, "%s %.1f Tf (%s) Tj T*\n", font, fontSize, pdfEscape(line.Text))
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+func pdfEscape(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+	return replacer.Replace(receiptAscii(text))
+}
+
+// receiptAscii font standar pdf dan printer thermal hanya aman untuk ascii
+func receiptAscii(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, text)
+}
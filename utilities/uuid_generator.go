@@ -1,7 +1,9 @@
 package utilities
 
 import (
+	cryptorand "crypto/rand"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"time"
 
@@ -26,9 +28,30 @@ func GenerateEmail() (email string) {
 	return email
 }
 
+func GenerateMerchantCode(kodeTrx string) (merchantCode string) {
+	rand.Seed(time.Now().UTC().UnixNano())
+	generateCode := 100000 + rand.Intn(999999-100000)
+	merchantCode = "MRC" + kodeTrx + fmt.Sprint(generateCode)
+	return merchantCode
+}
+
 func GenerateNoTagihan() (refId string) {
 	rand.Seed(time.Now().UTC().UnixNano())
 	generateCode := 100000 + rand.Intn(999999-100000)
 	refId = "TAGIHAN/" + fmt.Sprint(generateCode)
 	return refId
 }
+
+// GenerateReferralCode memakai crypto/rand supaya kode tidak bisa ditebak dari waktu generate
+func GenerateReferralCode() (referralCode string) {
+	const charset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	code := make([]byte, 8)
+	for i := range code {
+		n, err := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			panic(err)
+		}
+		code[i] = charset[n.Int64()]
+	}
+	return string(code)
+}